@@ -13,6 +13,8 @@ func buildCmd(args []string) {
 	fs := flag.NewFlagSet("build", flag.ExitOnError)
 	outputDir := fs.String("o", "output", i18n.T(i18n.MsgBuildOptOutput))
 	verbose := fs.Bool("v", false, i18n.T(i18n.MsgBuildOptVerbose))
+	optimize := fs.Bool("opt", false, i18n.T(i18n.MsgBuildOptOptimize))
+	werror := fs.Bool("werror", false, i18n.T(i18n.MsgBuildOptWerror))
 
 	fs.Usage = func() {
 		fmt.Println(i18n.T(i18n.MsgBuildUsage))
@@ -38,7 +40,7 @@ func buildCmd(args []string) {
 
 	input := fs.Arg(0)
 
-	if err := transpileInput(input, *outputDir, *verbose); err != nil {
+	if err := transpileInput(input, *outputDir, *verbose, *optimize, *werror); err != nil {
 		printError("Error: " + err.Error())
 		os.Exit(1)
 	}
@@ -15,6 +15,8 @@ import (
 func runCmd(args []string) {
 	fs := flag.NewFlagSet("run", flag.ExitOnError)
 	verbose := fs.Bool("v", false, i18n.T(i18n.MsgRunOptVerbose))
+	optimize := fs.Bool("opt", false, i18n.T(i18n.MsgRunOptOptimize))
+	werror := fs.Bool("werror", false, i18n.T(i18n.MsgRunOptWerror))
 
 	fs.Usage = func() {
 		fmt.Println(i18n.T(i18n.MsgRunUsage))
@@ -57,7 +59,7 @@ func runCmd(args []string) {
 	}
 
 	// 转译
-	if err := transpileInput(input, outputDir, *verbose); err != nil {
+	if err := transpileInput(input, outputDir, *verbose, *optimize, *werror); err != nil {
 		printError("Error: " + err.Error())
 		os.Exit(1)
 	}
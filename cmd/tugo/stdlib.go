@@ -121,11 +121,6 @@ func preloadStdlibClasses(stdlibDir string, tugoImports map[string]bool, verbose
 			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tugo") {
 				continue
 			}
-			
-			// TODO: 暂时跳过有泛型静态方法问题的文件
-			if entry.Name() == "query_builder.tugo" {
-				continue
-			}
 
 			srcFile := filepath.Join(srcDir, entry.Name())
 			source, err := os.ReadFile(srcFile)
@@ -211,14 +206,6 @@ func transpileStdlib(stdlibDir, outputDir string, tugoImports map[string]bool, v
 			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tugo") {
 				continue
 			}
-			
-			// TODO: 暂时跳过有泛型静态方法问题的文件
-			if entry.Name() == "query_builder.tugo" {
-				if verbose {
-					printWarning("跳过 query_builder.tugo（泛型静态方法暂不支持）")
-				}
-				continue
-			}
 
 			srcFile := filepath.Join(srcDir, entry.Name())
 			source, err := os.ReadFile(srcFile)
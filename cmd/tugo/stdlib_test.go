@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tangzhangming/tugo/internal/config"
+)
+
+// TestStdlibBuilds 转译 src/core 下的每个标准库包，非 -short 模式下再用 go build 编译生成的代码
+// 标准库只在用户导入时才被转译，这里保证修改编译器后标准库本身仍然可用
+func TestStdlibBuilds(t *testing.T) {
+	stdlibDir := filepath.Join("..", "..", stdlibRelDir)
+	coreDir := filepath.Join(stdlibDir, stdlibCoreDir)
+
+	imports := map[string]bool{stdlibPkgPrefix + stdlibRuntimeDir: true}
+	err := filepath.WalkDir(coreDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".tugo") {
+			return nil
+		}
+		rel, err := filepath.Rel(coreDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		imports[stdlibPkgPrefix+strings.ReplaceAll(filepath.ToSlash(rel), "/", ".")] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := t.TempDir()
+	if err := transpileStdlib(stdlibDir, outputDir, imports, false, config.DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+
+	if testing.Short() {
+		return
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+
+	// 用一个导入全部标准库包的程序编译，go.mod 与用户项目的生成方式相同
+	var pkgs []string
+	for pkg := range imports {
+		pkgs = append(pkgs, strings.ReplaceAll(pkg, ".", "/"))
+	}
+	sort.Strings(pkgs)
+	var main strings.Builder
+	main.WriteString("package main\n\nimport (\n")
+	for _, pkg := range pkgs {
+		main.WriteString("\t_ \"" + pkg + "\"\n")
+	}
+	main.WriteString(")\n\nfunc main() {}\n")
+
+	files := map[string]string{
+		"go.mod":  generateGoMod("stdlibcheck", imports),
+		"main.go": main.String(),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(outputDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "build", "-o", os.DevNull, ".")
+	cmd.Dir = outputDir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("stdlib does not compile: %v\n%s", err, out)
+	}
+}
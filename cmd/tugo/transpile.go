@@ -15,7 +15,9 @@ import (
 )
 
 // transpileInput 转译输入文件或目录
-func transpileInput(input, output string, verbose bool) error {
+// optimize 为 true 时开启常量折叠和死分支消除（也可以在 tugo.toml 的 [build] 中配置）
+// werror 为 true 时把检查规则的警告当作错误（也可以在 tugo.toml 的 [lint] 中配置）
+func transpileInput(input, output string, verbose, optimize, werror bool) error {
 	info, err := os.Stat(input)
 	if err != nil {
 		return &accessError{err: err}
@@ -32,6 +34,16 @@ func transpileInput(input, output string, verbose bool) error {
 		return &configError{err: err}
 	}
 
+	if optimize {
+		cfg.Build.Optimize = true
+	}
+	if werror {
+		cfg.Lint.Werror = true
+	}
+	if err := transpiler.CheckLintConfig(cfg.Lint); err != nil {
+		return &configError{err: err}
+	}
+
 	if verbose {
 		if configPath != "" {
 			printInfo(i18n.T(i18n.MsgUsingConfig, configPath, cfg.Project.Module))
@@ -159,6 +171,7 @@ func transpileDir(inputDir, outputDir string, verbose bool, cfg *config.Config)
 		if err != nil {
 			return &transpileError{path: path, err: err}
 		}
+		printTranspileWarnings(path, t.Warnings())
 		i++
 
 		// 写入输出文件
@@ -246,6 +259,7 @@ func transpileFile(inputFile, outputPath string, verbose bool, cfg *config.Confi
 	if err != nil {
 		return &transpileError{path: inputFile, err: err}
 	}
+	printTranspileWarnings(inputFile, t.Warnings())
 
 	// 确定输出路径
 	finalOutput := outputPath
@@ -309,6 +323,13 @@ func transpileFile(inputFile, outputPath string, verbose bool, cfg *config.Confi
 	return nil
 }
 
+// printTranspileWarnings 输出转译警告
+func printTranspileWarnings(path string, warnings []string) {
+	for _, w := range warnings {
+		printWarning(path + ": " + w)
+	}
+}
+
 // 错误类型定义
 type accessError struct {
 	err error
@@ -2,4 +2,4 @@ module github.com/tangzhangming/tugo
 
 go 1.23.4
 
-require github.com/BurntSushi/toml v1.6.0 // indirect
+require github.com/BurntSushi/toml v1.6.0
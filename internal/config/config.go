@@ -10,6 +10,8 @@ import (
 // Config tugo 项目配置
 type Config struct {
 	Project ProjectConfig `toml:"project"`
+	Build   BuildConfig   `toml:"build"`
+	Lint    LintConfig    `toml:"lint"`
 }
 
 // ProjectConfig 项目配置
@@ -17,6 +19,24 @@ type ProjectConfig struct {
 	Module string `toml:"module"` // 项目模块名，如 "com.company.demo"
 }
 
+// BuildConfig 构建配置
+type BuildConfig struct {
+	Optimize bool `toml:"optimize"` // 启用常量折叠和死分支消除（等同于 --opt）
+}
+
+// LintConfig 检查规则配置
+//
+//	[lint]
+//	werror = true
+//
+//	[lint.rules]
+//	unused-local = "off"
+//	TG0306 = "error"
+type LintConfig struct {
+	Werror bool              `toml:"werror"` // 把警告当作错误（等同于 --werror）
+	Rules  map[string]string `toml:"rules"`  // 规则名或编号 -> off/warning/error
+}
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
@@ -9,19 +9,38 @@ var enMessages = map[string]string{
 	// Interface implementation errors
 	ErrInterfaceNotFound:       "class %s: interface %s not found",
 	ErrMissingMethod:           "class %s does not implement interface %s: missing method %s",
-	ErrParamCountMismatch:      "class %s method %s: parameter count mismatch (got %d, interface %s requires %d)",
-	ErrReturnCountMismatch:     "class %s method %s: return value count mismatch (got %d, interface %s requires %d)",
 	ErrStructInterfaceNotFound: "struct %s: interface %s not found",
 	ErrStructMissingMethod:     "struct %s does not implement interface %s: missing method %s",
-	ErrStructParamMismatch:     "struct %s method %s: parameter count mismatch (got %d, interface %s requires %d)",
-	ErrStructReturnMismatch:    "struct %s method %s: return value count mismatch (got %d, interface %s requires %d)",
 
 	// Extends/inheritance errors
-	ErrParentClassNotFound:    "class %s: parent class %s not found",
-	ErrExtendNonAbstract:      "class %s: cannot extend non-abstract class %s",
-	ErrAbstractMethodMissing:  "class %s does not implement abstract method %s from parent class %s",
-	ErrAbstractParamMismatch:  "class %s method %s: parameter count mismatch (got %d, abstract method in %s requires %d)",
-	ErrAbstractReturnMismatch: "class %s method %s: return value count mismatch (got %d, abstract method in %s requires %d)",
+	ErrParentClassNotFound:   "class %s: parent class %s not found",
+	ErrExtendNonAbstract:     "class %s: cannot extend non-abstract class %s",
+	ErrAbstractMethodMissing: "class %s does not implement abstract method %s from parent class %s",
+	ErrSealedExtends:         "class %s: sealed class %s can only be extended by classes in package %s",
+
+	// Override/super errors
+	ErrOverrideNotFound:          "class %s: method %s is marked override, but parent class %s has no such method",
+	ErrOverrideNoParent:          "class %s: method %s is marked override, but the class does not extend any class",
+	ErrOverrideStatic:            "class %s: static method %s cannot be marked override",
+	ErrOverrideSignatureMismatch: "class %s method %s does not match the overridden method in %s: got %s, want %s (%s)",
+	ErrSuperOutsideSubclass:      "class %s: super can only be used in instance methods and init of a class that extends another class",
+	ErrSuperUsage:                "super can only be used as super.method(...) or super.init(...)",
+	ErrSuperMethodNotFound:       "super.%s: parent class %s has no such method",
+	ErrSuperAbstractMethod:       "super.%s: method is abstract in parent class %s and has no implementation",
+	ErrSuperInitPosition:         "class %s: super.init(...) must be the first statement of init",
+	ErrSuperInitAbstract:         "class %s: abstract class %s has no init, super.init(...) cannot be called",
+	ErrSuperInitRequired:         "class %s: the init of parent class %s has parameters, call super.init(...) as the first statement of init",
+
+	// Signature type mismatch errors
+	ErrSignatureMismatch:         "class %s method %s does not match interface %s: got %s, want %s (%s)",
+	ErrStructSignatureMismatch:   "struct %s method %s does not match interface %s: got %s, want %s (%s)",
+	ErrAbstractSignatureMismatch: "class %s method %s does not match abstract method in %s: got %s, want %s (%s)",
+	ErrSigParamType:              "parameter %d (%s) has type %s, expected %s",
+	ErrSigResultType:             "result %d has type %s, expected %s",
+	ErrSigResultCount:            "returns %d values, expected %d",
+	ErrSigParamCount:             "has %d parameters, expected %d",
+	ErrSigUnexpectedErrable:      "method is errable (!) but the expected signature has no error result",
+	ErrSigMissingErrable:         "expected signature returns an error but the method is not errable (!)",
 
 	// Static class errors
 	ErrStaticClassInit: "static class %s cannot have init constructor",
@@ -51,21 +70,126 @@ var enMessages = map[string]string{
 	ErrUndefinedType: "undefined type '%s': not imported or defined",
 	ErrUnusedImport:  "imported type '%s' is not used (from %s)",
 
+	// Reserved name errors
+	ErrReservedIdentifier:    "identifier '%s' is reserved: names starting with '%s' are used by the compiler",
+	ErrGeneratedNameConflict: "'%s' conflicts with compiler-generated name '%s' for class '%s'",
+	ErrMemberNameConflict:    "members '%s' and '%s' of class '%s' both generate Go name '%s', rename one of them",
+
 	// Codegen errors
 	ErrTooManyVariables:           "too many variables: function returns %d values but trying to assign to %d variables",
 	ErrErrableMultiReturnNoAssign: "errable function with %d return values cannot be used as expression statement, must assign to variables",
+	ErrInternalCodegen:            "internal compiler error: generated invalid Go code (output line %d): %s",
 
 	// Overload errors
 	ErrDuplicateOverloadSignature: "class %s method %s: duplicate overload signature '%s'",
 	ErrOverloadOnlyReturnDiffers:  "class %s method %s: overloaded methods cannot differ only by return type",
+	ErrOverloadNameCollision:      "class %s method %s: overloads %s and %s both translate to %s",
+
+	// Call resolution errors
+	ErrAmbiguousCall:        "ambiguous call to %s%s, candidates:\n%s",
+	ErrNoMatchingOverload:   "no overload of %s accepts %s, candidates:\n%s",
+	ErrUnknownNamedArg:      "%s has no parameter named '%s'",
+	ErrDuplicateNamedArg:    "argument '%s' of %s is passed more than once",
+	ErrPositionalAfterNamed: "positional arguments cannot follow named arguments in call to %s",
+	ErrMissingArgument:      "missing argument for parameter '%s' of %s",
+	ErrTooManyArguments:     "too many arguments in call to %s: expected %d, got %d",
+	ErrNamedArgNotSupported: "named argument '%s' can only be passed to tugo functions, methods and constructors",
+
+	// Null-safety errors
+	ErrNullableDereference: "%s may be nil here; use ?. or check it against nil first",
+	ErrNilToNonNullable:    "cannot use possibly-nil value %s as non-nullable %s",
+	ErrNullableValueType:   "nullable type %s? requires a class or pointer type",
+	ErrNonNullFieldUninit:  "class %s: non-null field %s of type %s must have an initializer or be assigned in every init (declare it as %[3]s? if it may be nil)",
 
 	// Visibility errors
-	ErrPrivateMethodAccess: "%s: cannot access %s's private method '%s'",
-	ErrPrivateFieldAccess:  "%s: cannot access %s's private field '%s'",
+	ErrPrivateMethodAccess:   "%s: cannot access %s's private method '%s'",
+	ErrPrivateFieldAccess:    "%s: cannot access %s's private field '%s'",
+	ErrProtectedMethodAccess: "%s: cannot access %s's protected method '%s' (only %[2]s and its subclasses can)",
+	ErrProtectedFieldAccess:  "%s: cannot access %s's protected field '%s' (only %[2]s and its subclasses can)",
+	ErrMissingReturn:         "%s: missing return at end of function (not every path returns or throws)",
+
+	// Generic errors
+	ErrNotGenericClass:    "class %s is not generic and cannot take type arguments",
+	ErrTypeArgCount:       "class %s expects %d type argument(s), got %d",
+	ErrCannotInferTypeArg: "cannot infer type argument %s for %s::%s; specify it explicitly, e.g. %[2]s[...]::%[3]s()",
+
+	// Generic method errors
+	ErrMethodTypeArgCount:       "method %s.%s expects %d type argument(s), got %d",
+	ErrCannotInferMethodTypeArg: "cannot infer type argument %s for method %s.%s; specify it explicitly, e.g. obj.%[3]s[...]()",
+	ErrAmbiguousGenericMethod:   "cannot determine the receiver type of generic method '%s' (declared in %s); declare the variable with an explicit type",
+	ErrGenericMethodAbstract:    "%s: abstract method '%s' cannot have type parameters",
+	ErrGenericMethodDefaults:    "%s: generic method '%s' cannot have default parameter values",
 
 	// Ternary expression errors
 	ErrTernaryTypeMismatch: "ternary expression type mismatch: true branch is '%s', false branch is '%s'",
 
+	// try-catch errors
+	ErrUncaughtError:       "uncaught error in a function that is not errable: add a catch clause without a type, or make the function errable (!)",
+	ErrUnreachableCatch:    "unreachable catch clause: a previous catch clause already catches all errors",
+	ErrRethrowOutsideCatch: "throw without a value (rethrow) can only be used inside a catch block",
+
+	// Enum errors
+	ErrEnumDuplicateCase:  "enum %s: duplicate case %s",
+	ErrEnumDuplicateValue: "enum %s: cases %s and %s have the same value",
+	ErrEnumReservedCase:   "enum %s: case %s conflicts with the generated %s",
+	ErrEnumCaseValue:      "enum %s: case %s needs a value, cases of a %s enum are not numbered automatically",
+	ErrEnumStaticMember:   "enum %s: member %s cannot be static",
+	ErrEnumFieldCount:     "enum %s: case %s has %d field value(s), but the enum declares %d field(s)",
+	ErrEnumUnknownField:   "enum %s has no field %s",
+	ErrEnumMissingField:   "enum %s: case %s has no value for field %s and the field has no default",
+	ErrEnumUnknownMember:  "enum %s has no case or member %s",
+
+	// Match errors
+	ErrMatchNotExhaustive:      "match is not exhaustive, missing %s (add the arms or a default arm)",
+	ErrMatchNestedTypePattern:  "nested pattern %s cannot test the type of a field of type %s, only destructure it",
+	ErrMatchAlternativeBinding: "an arm with several patterns cannot bind variables",
+	ErrMatchBlockValue:         "a block arm of a match expression must end with an expression (its value) or with return/throw",
+
+	// Property errors
+	ErrPropertyReadOnly:        "cannot assign to read-only property '%s'",
+	ErrPropertyMultiAssign:     "property '%s' cannot be assigned in a multiple assignment",
+	ErrPrivatePropertyAccess:   "%s: cannot access %s's private property '%s'",
+	ErrProtectedPropertyAccess: "%s: cannot access %s's protected property '%s' (only %[2]s and its subclasses can)",
+	ErrPrivatePropertySet:      "%s: cannot set %s's property '%s' (its setter is private)",
+	ErrProtectedPropertySet:    "%s: cannot set %s's property '%s' (its setter is protected, only %[2]s and its subclasses can)",
+
+	// Extension errors
+	ErrExtensionNotImported:     "extension method %s of %s is declared in package %s and must be imported with use",
+	ErrAmbiguousExtension:       "ambiguous extension method %s for %s, candidates: %s",
+	ErrDuplicateExtension:       "extension method %[2]s is declared more than once for %[1]s",
+	ErrExtensionReceiverUnknown: "cannot resolve extension receiver: the type of the receiver of %s is unknown, assign it to a variable with a declared type",
+
+	// Operator overloading errors
+	ErrOperatorNotDefined:    "operator %s is not defined for %s",
+	ErrOperatorIndexReadOnly: "operator [] of %s is read-only, its elements cannot be assigned",
+	ErrOperatorAssignTarget:  "compound assignment %s= with an overloaded operator needs a variable, field or index as its target",
+
+	// Lambda errors
+	ErrLambdaParamType:  "cannot infer the type of lambda parameter %[1]s, declare it as (%[1]s: Type) => ...",
+	ErrLambdaResultType: "cannot infer the return type of the lambda, use a func literal with an explicit return type",
+	ErrLambdaNoValue:    "lambda body has no value but a result of type %s is expected, use a block body with return",
+
+	// Data class errors
+	ErrDestructureCount: "cannot destructure data class %s with %d fields into %d variables",
+
+	// Optimizer warnings
+	WarnDeadIfBranch:   "condition is always false, branch removed",
+	WarnDeadElseBranch: "condition is always true, else branch removed",
+	WarnDeadMatchArm:   "match subject %s can never match this arm, arm removed",
+
+	// Lint warnings
+	WarnUnusedPrivateMethod: "private method '%s.%s' is never used",
+	WarnUnusedLocal:         "local variable '%s' is declared but never used",
+	WarnShadowedField:       "local variable '%s' shadows field of class %s (use this.%[1]s to access the field)",
+	WarnUnusedCatchError:    "catch parameter '%s' is never used, the error is silently discarded",
+	WarnMatchWithoutDefault: "match has no default arm, unmatched values panic at runtime",
+	WarnUnreachableCode:     "unreachable code",
+	WarnUseBeforeAssign:     "variable '%s' may be read before it is assigned on some path (it holds the zero value there)",
+	WarnOverrideMissing:     "class %s: method %s overrides a method of parent class %s and should be marked override",
+	ErrLintUnknownRule:      "unknown lint rule '%s' in [lint.rules]",
+	ErrLintInvalidLevel:     "invalid level '%s' for lint rule '%s' (expected off, warning or error)",
+	ErrLintFixedRule:        "lint rule '%s' is always reported as %s and cannot be reconfigured (the generated Go code would not compile)",
+
 	// CLI - Usage and help
 	MsgUsage:          "Usage: tugo <command> [arguments]",
 	MsgCommands:       "Commands:",
@@ -81,6 +205,8 @@ var enMessages = map[string]string{
 	MsgRunDescription: "Transpile tugo source files to Go and run them.\nOutput is placed in .output directory (auto-cleaned).",
 	MsgRunArgInput:    "  <input>    Input file or directory",
 	MsgRunOptVerbose:  "Verbose output",
+	MsgRunOptOptimize: "Enable constant folding and dead-branch elimination",
+	MsgRunOptWerror:   "Treat lint warnings as errors",
 
 	// CLI - Build command
 	MsgBuildUsage:       "Usage: tugo build [options] <input>",
@@ -88,6 +214,8 @@ var enMessages = map[string]string{
 	MsgBuildArgInput:    "  <input>    Input file or directory",
 	MsgBuildOptOutput:   "Output directory",
 	MsgBuildOptVerbose:  "Verbose output",
+	MsgBuildOptOptimize: "Enable constant folding and dead-branch elimination",
+	MsgBuildOptWerror:   "Treat lint warnings as errors",
 	MsgBuildCompleted:   "Build completed: %s",
 	MsgBuildCompletedV:  "Build completed. Output: %s",
 
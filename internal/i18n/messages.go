@@ -12,19 +12,38 @@ const (
 	// Interface implementation errors
 	ErrInterfaceNotFound        = "transpiler.interface_not_found"          // args: className, interfaceName
 	ErrMissingMethod            = "transpiler.missing_method"               // args: className, interfaceName, methodName
-	ErrParamCountMismatch       = "transpiler.param_count_mismatch"         // args: className, methodName, got, interfaceName, expected
-	ErrReturnCountMismatch      = "transpiler.return_count_mismatch"        // args: className, methodName, got, interfaceName, expected
 	ErrStructInterfaceNotFound  = "transpiler.struct_interface_not_found"   // args: structName, interfaceName
 	ErrStructMissingMethod      = "transpiler.struct_missing_method"        // args: structName, interfaceName, methodName
-	ErrStructParamMismatch      = "transpiler.struct_param_mismatch"        // args: structName, methodName, got, interfaceName, expected
-	ErrStructReturnMismatch     = "transpiler.struct_return_mismatch"       // args: structName, methodName, got, interfaceName, expected
 
 	// Extends/inheritance errors
 	ErrParentClassNotFound    = "transpiler.parent_class_not_found"     // args: className, parentName
 	ErrExtendNonAbstract      = "transpiler.extend_non_abstract"        // args: className, parentName
 	ErrAbstractMethodMissing  = "transpiler.abstract_method_missing"    // args: className, methodName, parentName
-	ErrAbstractParamMismatch  = "transpiler.abstract_param_mismatch"    // args: className, methodName, got, parentName, expected
-	ErrAbstractReturnMismatch = "transpiler.abstract_return_mismatch"   // args: className, methodName, got, parentName, expected
+	ErrSealedExtends          = "transpiler.sealed_extends"             // args: className, parentName, packageName
+
+	// Override/super errors
+	ErrOverrideNotFound          = "transpiler.override_not_found"          // args: className, methodName, parentName
+	ErrOverrideNoParent          = "transpiler.override_no_parent"          // args: className, methodName
+	ErrOverrideStatic            = "transpiler.override_static"             // args: className, methodName
+	ErrOverrideSignatureMismatch = "transpiler.override_signature_mismatch" // args: className, methodName, parentName, got, expected, detail
+	ErrSuperOutsideSubclass      = "transpiler.super_outside_subclass"      // args: className
+	ErrSuperUsage                = "transpiler.super_usage"                 // no args
+	ErrSuperMethodNotFound       = "transpiler.super_method_not_found"      // args: methodName, parentName
+	ErrSuperAbstractMethod       = "transpiler.super_abstract_method"       // args: methodName, parentName
+	ErrSuperInitPosition         = "transpiler.super_init_position"         // args: className
+	ErrSuperInitAbstract         = "transpiler.super_init_abstract"         // args: className, parentName
+	ErrSuperInitRequired         = "transpiler.super_init_required"         // args: className, parentName
+
+	// Signature type mismatch errors
+	ErrSignatureMismatch         = "transpiler.signature_mismatch"          // args: className, methodName, interfaceName, got, expected, detail
+	ErrStructSignatureMismatch   = "transpiler.struct_signature_mismatch"   // args: structName, methodName, interfaceName, got, expected, detail
+	ErrAbstractSignatureMismatch = "transpiler.abstract_signature_mismatch" // args: className, methodName, parentName, got, expected, detail
+	ErrSigParamType              = "transpiler.sig_param_type"              // args: index, paramName, got, expected
+	ErrSigResultType             = "transpiler.sig_result_type"             // args: index, got, expected
+	ErrSigResultCount            = "transpiler.sig_result_count"            // args: got, expected
+	ErrSigParamCount             = "transpiler.sig_param_count"             // args: got, expected
+	ErrSigUnexpectedErrable      = "transpiler.sig_unexpected_errable"      // no args
+	ErrSigMissingErrable         = "transpiler.sig_missing_errable"         // no args
 
 	// Static class errors
 	ErrStaticClassInit     = "transpiler.static_class_init"      // args: className
@@ -54,20 +73,125 @@ const (
 	ErrUndefinedType   = "transpiler.undefined_type"    // args: typeName
 	ErrUnusedImport    = "transpiler.unused_import"     // args: typeName, path
 
+	// Reserved name errors
+	ErrReservedIdentifier    = "transpiler.reserved_identifier"     // args: name, prefix
+	ErrGeneratedNameConflict = "transpiler.generated_name_conflict" // args: userName, generatedName, className
+	ErrMemberNameConflict    = "transpiler.member_name_conflict"    // args: name1, name2, className, goName
+
 	// Codegen errors
 	ErrTooManyVariables           = "codegen.too_many_variables"            // args: returnCount, assignCount
 	ErrErrableMultiReturnNoAssign = "codegen.errable_multi_return_no_assign" // args: returnCount
+	ErrInternalCodegen            = "codegen.internal_error"                 // args: goLine, message
 
 	// Overload errors
 	ErrDuplicateOverloadSignature = "transpiler.duplicate_overload_signature" // args: className, methodName, signature
 	ErrOverloadOnlyReturnDiffers  = "transpiler.overload_only_return_differs" // args: className, methodName
+	ErrOverloadNameCollision      = "transpiler.overload_name_collision"      // args: className, methodName, first, second, generatedName
+
+	// Call resolution errors
+	ErrAmbiguousCall        = "codegen.ambiguous_call"         // args: methodName, argTypes, candidates
+	ErrNoMatchingOverload   = "codegen.no_matching_overload"   // args: methodName, argTypes, candidates
+	ErrUnknownNamedArg      = "codegen.unknown_named_arg"      // args: methodName, argName
+	ErrDuplicateNamedArg    = "codegen.duplicate_named_arg"    // args: argName, methodName
+	ErrPositionalAfterNamed = "codegen.positional_after_named" // args: methodName
+	ErrMissingArgument      = "codegen.missing_argument"       // args: paramName, methodName
+	ErrTooManyArguments     = "codegen.too_many_arguments"     // args: methodName, expected, got
+	ErrNamedArgNotSupported = "codegen.named_arg_not_supported" // args: argName
+
+	// Null-safety errors
+	ErrNullableDereference = "codegen.nullable_dereference" // args: expr
+	ErrNilToNonNullable    = "codegen.nil_to_non_nullable"  // args: expr, type
+	ErrNullableValueType   = "codegen.nullable_value_type"  // args: type
+	ErrNonNullFieldUninit  = "codegen.non_null_field_uninit" // args: className, fieldName, type
 
 	// Visibility errors
 	ErrPrivateMethodAccess = "transpiler.private_method_access" // args: callerClass, targetClass, methodName
 	ErrPrivateFieldAccess  = "transpiler.private_field_access"  // args: callerClass, targetClass, fieldName
+	ErrProtectedMethodAccess = "transpiler.protected_method_access" // args: callerClass, targetClass, methodName
+	ErrProtectedFieldAccess  = "transpiler.protected_field_access"  // args: callerClass, targetClass, fieldName
+	ErrMissingReturn         = "transpiler.missing_return"          // args: funcName
+
+	// Generic errors
+	ErrNotGenericClass    = "codegen.not_generic_class"      // args: className
+	ErrTypeArgCount       = "codegen.type_arg_count"         // args: className, expected, got
+	ErrCannotInferTypeArg = "codegen.cannot_infer_type_arg"  // args: typeParam, className, member
+
+	// Generic method errors
+	ErrMethodTypeArgCount       = "codegen.method_type_arg_count"        // args: className, methodName, expected, got
+	ErrCannotInferMethodTypeArg = "codegen.cannot_infer_method_type_arg" // args: typeParam, className, methodName
+	ErrAmbiguousGenericMethod   = "codegen.ambiguous_generic_method"     // args: methodName, classNames
+	ErrGenericMethodAbstract    = "transpiler.generic_method_abstract"   // args: className, methodName
+	ErrGenericMethodDefaults    = "transpiler.generic_method_defaults"   // args: className, methodName
 
 	// Ternary expression errors
 	ErrTernaryTypeMismatch = "codegen.ternary_type_mismatch" // args: trueType, falseType
+
+	// try-catch errors
+	ErrUncaughtError       = "codegen.uncaught_error"
+	ErrUnreachableCatch    = "codegen.unreachable_catch"
+	ErrRethrowOutsideCatch = "codegen.rethrow_outside_catch"
+
+	// Enum errors
+	ErrEnumDuplicateCase  = "codegen.enum_duplicate_case"  // args: enumName, caseName
+	ErrEnumDuplicateValue = "codegen.enum_duplicate_value" // args: enumName, firstCase, secondCase
+	ErrEnumReservedCase   = "codegen.enum_reserved_case"   // args: enumName, caseName, member
+	ErrEnumCaseValue      = "codegen.enum_case_value"      // args: enumName, caseName, type
+	ErrEnumStaticMember   = "codegen.enum_static_member"   // args: enumName, memberName
+	ErrEnumFieldCount     = "codegen.enum_field_count"     // args: enumName, caseName, got, expected
+	ErrEnumUnknownField   = "codegen.enum_unknown_field"   // args: enumName, fieldName
+	ErrEnumMissingField   = "codegen.enum_missing_field"   // args: enumName, caseName, fieldName
+	ErrEnumUnknownMember  = "codegen.enum_unknown_member"  // args: enumName, memberName
+
+	// Match errors
+	ErrMatchNotExhaustive      = "codegen.match_not_exhaustive"       // args: missingCases
+	ErrMatchNestedTypePattern  = "codegen.match_nested_type_pattern"  // args: typeName, fieldType
+	ErrMatchAlternativeBinding = "codegen.match_alternative_binding"  // args: none
+	ErrMatchBlockValue         = "codegen.match_block_value"          // args: none
+
+	// Property errors
+	ErrPropertyReadOnly        = "codegen.property_read_only"         // args: propName
+	ErrPropertyMultiAssign     = "codegen.property_multi_assign"      // args: propName
+	ErrPrivatePropertyAccess   = "transpiler.private_property_access"   // args: callerClass, targetClass, propName
+	ErrProtectedPropertyAccess = "transpiler.protected_property_access" // args: callerClass, targetClass, propName
+	ErrPrivatePropertySet      = "transpiler.private_property_set"      // args: callerClass, targetClass, propName
+	ErrProtectedPropertySet    = "transpiler.protected_property_set"    // args: callerClass, targetClass, propName
+
+	// Extension errors
+	ErrExtensionNotImported     = "codegen.extension_not_imported"     // args: methodName, typeName, pkgName
+	ErrAmbiguousExtension       = "codegen.ambiguous_extension"        // args: methodName, typeName, candidates
+	ErrDuplicateExtension       = "codegen.duplicate_extension"        // args: typeName, methodName
+	ErrExtensionReceiverUnknown = "codegen.extension_receiver_unknown" // args: methodName
+
+	// Operator overloading errors
+	ErrOperatorNotDefined    = "codegen.operator_not_defined"     // args: operator, typeName
+	ErrOperatorIndexReadOnly = "codegen.operator_index_read_only" // args: typeName
+	ErrOperatorAssignTarget  = "codegen.operator_assign_target"   // args: operator
+
+	// Lambda errors
+	ErrLambdaParamType  = "codegen.lambda_param_type"  // args: param
+	ErrLambdaResultType = "codegen.lambda_result_type" // args: none
+	ErrLambdaNoValue    = "codegen.lambda_no_value"    // args: resultType
+
+	// Data class errors
+	ErrDestructureCount = "codegen.destructure_count" // args: class, fields, vars
+
+	// Optimizer warnings
+	WarnDeadIfBranch   = "optimizer.dead_if_branch"
+	WarnDeadElseBranch = "optimizer.dead_else_branch"
+	WarnDeadMatchArm   = "optimizer.dead_match_arm" // args: subject
+
+	// Lint warnings
+	WarnUnusedPrivateMethod = "lint.unused_private_method"   // args: className, methodName
+	WarnUnusedLocal         = "lint.unused_local"            // args: name
+	WarnShadowedField       = "lint.shadowed_field"          // args: name, className
+	WarnUnusedCatchError    = "lint.unused_catch_error"      // args: param
+	WarnMatchWithoutDefault = "lint.match_without_default"
+	WarnUnreachableCode     = "lint.unreachable_code"
+	WarnUseBeforeAssign     = "lint.use_before_assign" // args: varName
+	WarnOverrideMissing     = "lint.override_missing"  // args: className, methodName, parentName
+	ErrLintUnknownRule      = "lint.unknown_rule"            // args: rule
+	ErrLintInvalidLevel     = "lint.invalid_level"           // args: level, rule
+	ErrLintFixedRule        = "lint.fixed_rule"              // args: rule, level
 )
 
 // Message keys for CLI
@@ -87,6 +211,8 @@ const (
 	MsgRunDescription   = "cli.run_description"
 	MsgRunArgInput      = "cli.run_arg_input"
 	MsgRunOptVerbose    = "cli.run_opt_verbose"
+	MsgRunOptOptimize   = "cli.run_opt_optimize"
+	MsgRunOptWerror     = "cli.run_opt_werror"
 
 	// Build command
 	MsgBuildUsage       = "cli.build_usage"
@@ -94,6 +220,8 @@ const (
 	MsgBuildArgInput    = "cli.build_arg_input"
 	MsgBuildOptOutput   = "cli.build_opt_output"
 	MsgBuildOptVerbose  = "cli.build_opt_verbose"
+	MsgBuildOptOptimize = "cli.build_opt_optimize"
+	MsgBuildOptWerror   = "cli.build_opt_werror"
 	MsgBuildCompleted   = "cli.build_completed"          // args: outputDir
 	MsgBuildCompletedV  = "cli.build_completed_verbose"  // args: outputDir
 
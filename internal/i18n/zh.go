@@ -9,19 +9,38 @@ var zhMessages = map[string]string{
 	// Interface implementation errors
 	ErrInterfaceNotFound:       "类 %s: 接口 %s 未找到",
 	ErrMissingMethod:           "类 %s 未实现接口 %s: 缺少方法 %s",
-	ErrParamCountMismatch:      "类 %s 方法 %s: 参数数量不匹配 (实际 %d 个, 接口 %s 要求 %d 个)",
-	ErrReturnCountMismatch:     "类 %s 方法 %s: 返回值数量不匹配 (实际 %d 个, 接口 %s 要求 %d 个)",
 	ErrStructInterfaceNotFound: "结构体 %s: 接口 %s 未找到",
 	ErrStructMissingMethod:     "结构体 %s 未实现接口 %s: 缺少方法 %s",
-	ErrStructParamMismatch:     "结构体 %s 方法 %s: 参数数量不匹配 (实际 %d 个, 接口 %s 要求 %d 个)",
-	ErrStructReturnMismatch:    "结构体 %s 方法 %s: 返回值数量不匹配 (实际 %d 个, 接口 %s 要求 %d 个)",
 
 	// Extends/inheritance errors
-	ErrParentClassNotFound:    "类 %s: 父类 %s 未找到",
-	ErrExtendNonAbstract:      "类 %s: 不能继承非抽象类 %s",
-	ErrAbstractMethodMissing:  "类 %s 未实现父类 %s 的抽象方法 %s",
-	ErrAbstractParamMismatch:  "类 %s 方法 %s: 参数数量不匹配 (实际 %d 个, 父类 %s 抽象方法要求 %d 个)",
-	ErrAbstractReturnMismatch: "类 %s 方法 %s: 返回值数量不匹配 (实际 %d 个, 父类 %s 抽象方法要求 %d 个)",
+	ErrParentClassNotFound:   "类 %s: 父类 %s 未找到",
+	ErrExtendNonAbstract:     "类 %s: 不能继承非抽象类 %s",
+	ErrAbstractMethodMissing: "类 %s 未实现父类 %s 的抽象方法 %s",
+	ErrSealedExtends:         "类 %s: 密封类 %s 只能被包 %s 中的类继承",
+
+	// Override/super errors
+	ErrOverrideNotFound:          "类 %s: 方法 %s 声明了 override，但父类 %s 中没有该方法",
+	ErrOverrideNoParent:          "类 %s: 方法 %s 声明了 override，但该类没有继承父类",
+	ErrOverrideStatic:            "类 %s: 静态方法 %s 不能声明 override",
+	ErrOverrideSignatureMismatch: "类 %s 方法 %s 与父类 %s 中被重写的方法不匹配: 实际 %s, 期望 %s (%s)",
+	ErrSuperOutsideSubclass:      "类 %s: super 只能用于继承了父类的类的实例方法和 init 中",
+	ErrSuperUsage:                "super 只能以 super.method(...) 或 super.init(...) 的形式使用",
+	ErrSuperMethodNotFound:       "super.%s: 父类 %s 中没有该方法",
+	ErrSuperAbstractMethod:       "super.%s: 该方法在父类 %s 中是抽象方法，没有实现",
+	ErrSuperInitPosition:         "类 %s: super.init(...) 必须是 init 的第一条语句",
+	ErrSuperInitAbstract:         "类 %s: 抽象类 %s 没有 init，不能调用 super.init(...)",
+	ErrSuperInitRequired:         "类 %s: 父类 %s 的构造方法需要参数，请在 init 的第一条语句调用 super.init(...)",
+
+	// Signature type mismatch errors
+	ErrSignatureMismatch:         "类 %s 方法 %s 与接口 %s 不匹配: 实际 %s, 期望 %s (%s)",
+	ErrStructSignatureMismatch:   "结构体 %s 方法 %s 与接口 %s 不匹配: 实际 %s, 期望 %s (%s)",
+	ErrAbstractSignatureMismatch: "类 %s 方法 %s 与父类 %s 的抽象方法不匹配: 实际 %s, 期望 %s (%s)",
+	ErrSigParamType:              "第 %d 个参数 (%s) 类型为 %s, 期望 %s",
+	ErrSigResultType:             "第 %d 个返回值类型为 %s, 期望 %s",
+	ErrSigResultCount:            "返回 %d 个值, 期望 %d 个",
+	ErrSigParamCount:             "有 %d 个参数, 期望 %d 个",
+	ErrSigUnexpectedErrable:      "方法标记了 errable (!)，但期望的签名没有 error 返回值",
+	ErrSigMissingErrable:         "期望的签名返回 error，但方法没有标记 errable (!)",
 
 	// Static class errors
 	ErrStaticClassInit: "静态类 %s 不能有 init 构造函数",
@@ -51,21 +70,126 @@ var zhMessages = map[string]string{
 	ErrUndefinedType: "未定义的类型 '%s': 未导入或未定义",
 	ErrUnusedImport:  "导入的类型 '%s' 未被使用 (来自 %s)",
 
+	// Reserved name errors
+	ErrReservedIdentifier:    "标识符 '%s' 是保留名称: 以 '%s' 开头的名称由编译器使用",
+	ErrGeneratedNameConflict: "'%s' 与编译器生成的名称 '%s' 冲突（类 '%s'）",
+	ErrMemberNameConflict:    "类 '%[3]s' 的成员 '%[1]s' 和 '%[2]s' 生成相同的 Go 名称 '%[4]s'，请重命名其中一个",
+
 	// Codegen errors
 	ErrTooManyVariables:           "变量太多: 函数返回 %d 个值，但尝试赋值给 %d 个变量",
 	ErrErrableMultiReturnNoAssign: "返回 %d 个值的 errable 函数不能直接作为表达式使用，必须赋值给变量",
+	ErrInternalCodegen:            "内部编译器错误：生成了无效的 Go 代码（输出第 %d 行）：%s",
 
 	// Overload errors
 	ErrDuplicateOverloadSignature: "类 %s 方法 %s: 重载签名重复 '%s'",
 	ErrOverloadOnlyReturnDiffers:  "类 %s 方法 %s: 重载方法不能仅通过返回类型区分",
+	ErrOverloadNameCollision:      "类 %s 方法 %s: 重载 %s 和 %s 都会翻译为 %s",
+
+	// Call resolution errors
+	ErrAmbiguousCall:        "调用 %s%s 有歧义，候选:\n%s",
+	ErrNoMatchingOverload:   "%s 没有接受 %s 的重载，候选:\n%s",
+	ErrUnknownNamedArg:      "%s 没有名为 '%s' 的参数",
+	ErrDuplicateNamedArg:    "参数 '%s' 在调用 %s 时重复传入",
+	ErrPositionalAfterNamed: "调用 %s 时位置参数不能出现在命名参数之后",
+	ErrMissingArgument:      "缺少参数 '%s'（%s）",
+	ErrTooManyArguments:     "调用 %s 的参数过多: 需要 %d 个, 实际 %d 个",
+	ErrNamedArgNotSupported: "命名参数 '%s' 只能传给 tugo 函数、方法和构造函数",
+
+	// Null-safety errors
+	ErrNullableDereference: "%s 此处可能为 nil，请使用 ?. 或先判断是否为 nil",
+	ErrNilToNonNullable:    "不能将可能为 nil 的值 %s 用作非空类型 %s",
+	ErrNullableValueType:   "可空类型 %s? 只能用于类或指针类型",
+	ErrNonNullFieldUninit:  "类 %s: 非空字段 %s（类型 %s）需要默认值，或在每个 init 中赋值（可能为 nil 时声明为 %[3]s?）",
 
 	// Visibility errors
-	ErrPrivateMethodAccess: "%s: 无法访问 %s 的私有方法 '%s'",
-	ErrPrivateFieldAccess:  "%s: 无法访问 %s 的私有字段 '%s'",
+	ErrPrivateMethodAccess:   "%s: 无法访问 %s 的私有方法 '%s'",
+	ErrPrivateFieldAccess:    "%s: 无法访问 %s 的私有字段 '%s'",
+	ErrProtectedMethodAccess: "%s: 无法访问 %s 的受保护方法 '%s'（只有 %[2]s 及其子类可以访问）",
+	ErrProtectedFieldAccess:  "%s: 无法访问 %s 的受保护字段 '%s'（只有 %[2]s 及其子类可以访问）",
+	ErrMissingReturn:         "%s: 函数末尾缺少 return（并非所有路径都有 return 或 throw）",
+
+	// Generic errors
+	ErrNotGenericClass:    "类 %s 不是泛型类，不能指定类型实参",
+	ErrTypeArgCount:       "类 %s 需要 %d 个类型实参，实际提供了 %d 个",
+	ErrCannotInferTypeArg: "无法推断 %[2]s::%[3]s 的类型参数 %[1]s，请显式指定，例如 %[2]s[...]::%[3]s()",
+
+	// Generic method errors
+	ErrMethodTypeArgCount:       "方法 %s.%s 需要 %d 个类型实参，实际提供了 %d 个",
+	ErrCannotInferMethodTypeArg: "无法推断方法 %[2]s.%[3]s 的类型参数 %[1]s，请显式指定，例如 obj.%[3]s[...]()",
+	ErrAmbiguousGenericMethod:   "无法确定泛型方法 '%s' 的接收者类型（在 %s 中声明），请为变量显式声明类型",
+	ErrGenericMethodAbstract:    "%s: 抽象方法 '%s' 不能带类型参数",
+	ErrGenericMethodDefaults:    "%s: 泛型方法 '%s' 不能有默认参数值",
 
 	// Ternary expression errors
 	ErrTernaryTypeMismatch: "三元表达式类型不匹配: true分支是 '%s', false分支是 '%s'",
 
+	// try-catch 错误
+	ErrUncaughtError:       "非 errable 函数中的错误没有被捕获: 请添加不带类型的 catch 子句，或将函数声明为 errable (!)",
+	ErrUnreachableCatch:    "catch 子句不可达: 前面的 catch 子句已经捕获了所有错误",
+	ErrRethrowOutsideCatch: "不带值的 throw（重新抛出）只能在 catch 块中使用",
+
+	// Enum errors
+	ErrEnumDuplicateCase:  "枚举 %s: 成员 %s 重复",
+	ErrEnumDuplicateValue: "枚举 %s: 成员 %s 和 %s 的值相同",
+	ErrEnumReservedCase:   "枚举 %s: 成员 %s 与生成的 %s 冲突",
+	ErrEnumCaseValue:      "枚举 %s: 成员 %s 需要指定值，底层类型为 %s 的枚举不会自动编号",
+	ErrEnumStaticMember:   "枚举 %s: 成员 %s 不能是静态的",
+	ErrEnumFieldCount:     "枚举 %s: 成员 %s 提供了 %d 个字段值，但枚举声明了 %d 个字段",
+	ErrEnumUnknownField:   "枚举 %s 没有字段 %s",
+	ErrEnumMissingField:   "枚举 %s: 成员 %s 没有提供字段 %s 的值，且该字段没有默认值",
+	ErrEnumUnknownMember:  "枚举 %s 没有成员 %s",
+
+	// Match errors
+	ErrMatchNotExhaustive:      "match 没有覆盖所有情况，缺少 %s（补充这些分支或添加 default 分支）",
+	ErrMatchNestedTypePattern:  "嵌套模式 %s 不能检查 %s 类型字段的类型，只能解构",
+	ErrMatchAlternativeBinding: "有多个模式的分支不能绑定变量",
+	ErrMatchBlockValue:         "match 表达式的块分支必须以表达式（分支的值）或 return/throw 结束",
+
+	// Property errors
+	ErrPropertyReadOnly:        "不能给只读属性 '%s' 赋值",
+	ErrPropertyMultiAssign:     "属性 '%s' 不能在多重赋值中赋值",
+	ErrPrivatePropertyAccess:   "%s: 无法访问 %s 的私有属性 '%s'",
+	ErrProtectedPropertyAccess: "%s: 无法访问 %s 的受保护属性 '%s'（只有 %[2]s 及其子类可以访问）",
+	ErrPrivatePropertySet:      "%s: 无法设置 %s 的属性 '%s'（setter 是私有的）",
+	ErrProtectedPropertySet:    "%s: 无法设置 %s 的属性 '%s'（setter 是受保护的，只有 %[2]s 及其子类可以设置）",
+
+	// Extension errors
+	ErrExtensionNotImported:     "%[2]s 的扩展方法 %[1]s 在包 %[3]s 中声明，需要使用 use 导入",
+	ErrAmbiguousExtension:       "%[2]s 的扩展方法 %[1]s 有歧义，候选: %[3]s",
+	ErrDuplicateExtension:       "%s 的扩展方法 %s 重复声明",
+	ErrExtensionReceiverUnknown: "无法解析扩展方法 %s 的接收者类型，请先赋给声明了类型的变量",
+
+	// Operator overloading errors
+	ErrOperatorNotDefined:    "%[2]s 没有定义运算符 %[1]s",
+	ErrOperatorIndexReadOnly: "%s 的运算符 [] 是只读的，不能给元素赋值",
+	ErrOperatorAssignTarget:  "使用重载运算符的复合赋值 %s= 的左侧必须是变量、字段或下标",
+
+	// Lambda errors
+	ErrLambdaParamType:  "无法推断 lambda 参数 %[1]s 的类型，请写成 (%[1]s: Type) => ...",
+	ErrLambdaResultType: "无法推断 lambda 的返回值类型，请使用声明了返回值类型的 func 字面量",
+	ErrLambdaNoValue:    "lambda 的函数体没有值，但期望返回 %s，请使用带 return 的块函数体",
+
+	// Data class errors
+	ErrDestructureCount: "数据类 %s 有 %d 个字段，不能解构为 %d 个变量",
+
+	// Optimizer warnings
+	WarnDeadIfBranch:   "条件恒为 false，分支已删除",
+	WarnDeadElseBranch: "条件恒为 true，else 分支已删除",
+	WarnDeadMatchArm:   "match 主体 %s 不可能匹配此分支，分支已删除",
+
+	// Lint warnings
+	WarnUnusedPrivateMethod: "私有方法 '%s.%s' 从未被使用",
+	WarnUnusedLocal:         "局部变量 '%s' 声明后从未使用",
+	WarnShadowedField:       "局部变量 '%s' 遮蔽了类 %s 的字段（访问字段请使用 this.%[1]s）",
+	WarnUnusedCatchError:    "catch 参数 '%s' 从未使用，错误被静默丢弃",
+	WarnMatchWithoutDefault: "match 没有 default 分支，未匹配的值会在运行时 panic",
+	WarnUnreachableCode:     "不可达的代码",
+	WarnUseBeforeAssign:     "变量 '%s' 在某些路径上可能在赋值之前就被读取（此时为零值）",
+	WarnOverrideMissing:     "类 %s: 方法 %s 重写了父类 %s 的方法，应当声明 override",
+	ErrLintUnknownRule:      "[lint.rules] 中存在未知的检查规则 '%s'",
+	ErrLintInvalidLevel:     "检查规则 '%[2]s' 的级别 '%[1]s' 无效（应为 off、warning 或 error）",
+	ErrLintFixedRule:        "检查规则 '%s' 总是作为 %s 报告，不能调整级别（否则生成的 Go 代码无法编译）",
+
 	// CLI - Usage and help
 	MsgUsage:          "用法: tugo <命令> [参数]",
 	MsgCommands:       "命令:",
@@ -81,6 +205,8 @@ var zhMessages = map[string]string{
 	MsgRunDescription: "转译 tugo 源文件到 Go 并运行。\n输出放在 .output 目录（自动清理）。",
 	MsgRunArgInput:    "  <输入>    输入文件或目录",
 	MsgRunOptVerbose:  "详细输出",
+	MsgRunOptOptimize: "启用常量折叠和死分支消除",
+	MsgRunOptWerror:   "将检查警告视为错误",
 
 	// CLI - Build command
 	MsgBuildUsage:       "用法: tugo build [选项] <输入>",
@@ -88,6 +214,8 @@ var zhMessages = map[string]string{
 	MsgBuildArgInput:    "  <输入>    输入文件或目录",
 	MsgBuildOptOutput:   "输出目录",
 	MsgBuildOptVerbose:  "详细输出",
+	MsgBuildOptOptimize: "启用常量折叠和死分支消除",
+	MsgBuildOptWerror:   "将检查警告视为错误",
 	MsgBuildCompleted:   "构建完成: %s",
 	MsgBuildCompletedV:  "构建完成。输出: %s",
 
@@ -25,6 +25,17 @@ func New(input string) *Lexer {
 	return l
 }
 
+// NewAt 创建从指定行列开始计数的词法分析器（用于解析插值字符串中的表达式）
+func NewAt(input string, line, column int) *Lexer {
+	l := &Lexer{
+		input:  input,
+		line:   line,
+		column: column - 1,
+	}
+	l.readChar()
+	return l
+}
+
 // readChar 读取下一个字符
 func (l *Lexer) readChar() {
 	if l.readPos >= len(l.input) {
@@ -103,14 +114,10 @@ func (l *Lexer) NextToken() Token {
 		if l.peekChar() == '/' {
 			tok.Type = TOKEN_COMMENT
 			tok.Literal = l.readLineComment()
-			tok.Line = l.line
-			tok.Column = l.column
 			return tok
 		} else if l.peekChar() == '*' {
 			tok.Type = TOKEN_COMMENT
 			tok.Literal = l.readBlockComment()
-			tok.Line = l.line
-			tok.Column = l.column
 			return tok
 		} else if l.peekChar() == '=' {
 			l.readChar()
@@ -174,7 +181,15 @@ func (l *Lexer) NextToken() Token {
 	case '~':
 		tok = l.newToken(TOKEN_BIT_NOT, l.ch)
 	case '?':
-		tok = l.newToken(TOKEN_QUESTION, l.ch)
+		if l.peekChar() == '.' {
+			l.readChar()
+			tok = Token{Type: TOKEN_SAFE_DOT, Literal: "?.", Line: tok.Line, Column: tok.Column}
+		} else if l.peekChar() == '?' {
+			l.readChar()
+			tok = Token{Type: TOKEN_COALESCE, Literal: "??", Line: tok.Line, Column: tok.Column}
+		} else {
+			tok = l.newToken(TOKEN_QUESTION, l.ch)
+		}
 	case ',':
 		tok = l.newToken(TOKEN_COMMA, l.ch)
 	case ';':
@@ -196,7 +211,7 @@ func (l *Lexer) NextToken() Token {
 				l.readChar()
 				tok = Token{Type: TOKEN_ELLIPSIS, Literal: "...", Line: tok.Line, Column: tok.Column}
 			} else {
-				tok = Token{Type: TOKEN_ILLEGAL, Literal: "..", Line: tok.Line, Column: tok.Column}
+				tok = Token{Type: TOKEN_DOTDOT, Literal: "..", Line: tok.Line, Column: tok.Column}
 			}
 		} else {
 			tok = l.newToken(TOKEN_DOT, l.ch)
@@ -215,7 +230,11 @@ func (l *Lexer) NextToken() Token {
 		tok = l.newToken(TOKEN_RBRACE, l.ch)
 	case '"':
 		tok.Type = TOKEN_STRING
-		tok.Literal = l.readString()
+		var interpolated bool
+		tok.Literal, interpolated = l.readString()
+		if interpolated {
+			tok.Type = TOKEN_INTERP_STRING
+		}
 	case '\'':
 		tok.Type = TOKEN_CHAR
 		tok.Literal = l.readChar2()
@@ -329,8 +348,10 @@ func (l *Lexer) readNumber() (string, TokenType) {
 }
 
 // readString 读取双引号字符串
-func (l *Lexer) readString() string {
+// 字符串中含有 ${...} 插值或 \$ 转义时 interpolated 为 true
+func (l *Lexer) readString() (string, bool) {
 	pos := l.pos
+	interpolated := false
 	l.readChar() // 跳过开头的 "
 	for {
 		if l.ch == '"' {
@@ -338,14 +359,62 @@ func (l *Lexer) readString() string {
 		}
 		if l.ch == '\\' {
 			l.readChar() // 跳过转义字符
+			if l.ch == '$' {
+				interpolated = true
+			}
+		} else if l.ch == '$' && l.peekChar() == '{' {
+			// 跳过整个插值表达式，其中可以包含字符串和花括号
+			interpolated = true
+			end := InterpolationEnd(l.input, l.pos+2)
+			if end < 0 {
+				end = len(l.input)
+			}
+			for l.pos < end && l.ch != 0 {
+				l.readChar()
+			}
 		}
 		if l.ch == 0 {
 			break
 		}
 		l.readChar()
 	}
-	result := l.input[pos : l.pos+1]
-	return result
+	// 未闭合的字符串读到文件末尾为止
+	end := min(l.pos+1, len(l.input))
+	return l.input[pos:end], interpolated
+}
+
+// InterpolationEnd 返回从 start（${ 之后）开始的插值表达式对应的 } 的位置
+// 跳过嵌套的花括号、字符串和字符字面量；没有闭合时返回 -1
+func InterpolationEnd(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"', '\'':
+			quote := s[i]
+			for i++; i < len(s) && s[i] != quote; i++ {
+				if s[i] == '\\' {
+					i++
+				} else if quote == '"' && s[i] == '$' && i+1 < len(s) && s[i+1] == '{' {
+					end := InterpolationEnd(s, i+2)
+					if end < 0 {
+						return -1
+					}
+					i = end
+				}
+			}
+		case '`':
+			for i++; i < len(s) && s[i] != '`'; i++ {
+			}
+		}
+	}
+	return -1
 }
 
 // readChar2 读取单引号字符
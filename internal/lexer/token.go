@@ -15,6 +15,7 @@ const (
 	TOKEN_FLOAT  // 浮点数
 	TOKEN_STRING // 字符串
 	TOKEN_CHAR   // 字符
+	TOKEN_INTERP_STRING // 插值字符串 "a ${b}"
 
 	// 运算符
 	TOKEN_ASSIGN   // =
@@ -56,6 +57,8 @@ const (
 	TOKEN_FAT_ARROW    // =>
 	TOKEN_DOUBLE_COLON // ::
 	TOKEN_QUESTION     // ?
+	TOKEN_SAFE_DOT     // ?.
+	TOKEN_COALESCE     // ??
 
 	// 分隔符
 	TOKEN_COMMA     // ,
@@ -63,6 +66,7 @@ const (
 	TOKEN_COLON     // :
 	TOKEN_DOT       // .
 	TOKEN_ELLIPSIS  // ...
+	TOKEN_DOTDOT    // ..
 
 	TOKEN_LPAREN   // (
 	TOKEN_RPAREN   // )
@@ -117,15 +121,23 @@ const (
 	TOKEN_IMPLEMENTS // implements
 	TOKEN_ABSTRACT   // abstract
 	TOKEN_EXTENDS    // extends
+	TOKEN_ENUM       // enum
+	TOKEN_SEALED     // sealed
+	TOKEN_PROP       // prop
+	TOKEN_EXTEND     // extend
+	TOKEN_OPERATOR   // operator
+	TOKEN_OVERRIDE   // override
 	TOKEN_SELF       // self
+	TOKEN_SUPER      // super
 	TOKEN_FROM       // from (保留，向后兼容)
 	TOKEN_USE        // use (用于导入 tugo 包)
 	TOKEN_AS         // as (用于 use 别名)
 
 	// 错误处理关键字
-	TOKEN_TRY   // try
-	TOKEN_CATCH // catch
-	TOKEN_THROW // throw
+	TOKEN_TRY     // try
+	TOKEN_CATCH   // catch
+	TOKEN_FINALLY // finally
+	TOKEN_THROW   // throw
 
 	// 模式匹配
 	TOKEN_MATCH // match
@@ -186,12 +198,20 @@ var keywords = map[string]TokenType{
 	"implements": TOKEN_IMPLEMENTS,
 	"abstract":   TOKEN_ABSTRACT,
 	"extends":    TOKEN_EXTENDS,
+	"enum":       TOKEN_ENUM,
+	"sealed":     TOKEN_SEALED,
+	"prop":       TOKEN_PROP,
+	"extend":     TOKEN_EXTEND,
+	"operator":   TOKEN_OPERATOR,
+	"override":   TOKEN_OVERRIDE,
 	"self":       TOKEN_SELF,
+	"super":      TOKEN_SUPER,
 	"from":       TOKEN_FROM,
 	"use":        TOKEN_USE,
 	"as":         TOKEN_AS,
 	"try":        TOKEN_TRY,
 	"catch":      TOKEN_CATCH,
+	"finally":    TOKEN_FINALLY,
 	"throw":      TOKEN_THROW,
 	"match":      TOKEN_MATCH,
 }
@@ -204,6 +224,12 @@ func LookupIdent(ident string) TokenType {
 	return TOKEN_IDENT
 }
 
+// IsKeyword 检查标识符是否为 tugo 关键字
+func IsKeyword(ident string) bool {
+	_, ok := keywords[ident]
+	return ok
+}
+
 // TokenTypeName 返回 token 类型的名称
 func TokenTypeName(t TokenType) string {
 	names := map[TokenType]string{
@@ -215,6 +241,7 @@ func TokenTypeName(t TokenType) string {
 		TOKEN_FLOAT:     "FLOAT",
 		TOKEN_STRING:    "STRING",
 		TOKEN_CHAR:      "CHAR",
+		TOKEN_INTERP_STRING: "INTERP_STRING",
 		TOKEN_ASSIGN:    "=",
 		TOKEN_PLUS:      "+",
 		TOKEN_MINUS:     "-",
@@ -241,11 +268,14 @@ func TokenTypeName(t TokenType) string {
 		TOKEN_FAT_ARROW:    "=>",
 		TOKEN_DOUBLE_COLON: "::",
 		TOKEN_QUESTION:     "?",
+		TOKEN_SAFE_DOT:     "?.",
+		TOKEN_COALESCE:     "??",
 		TOKEN_COMMA:     ",",
 		TOKEN_SEMICOLON: ";",
 		TOKEN_COLON:     ":",
 		TOKEN_DOT:       ".",
 		TOKEN_ELLIPSIS:  "...",
+		TOKEN_DOTDOT:    "..",
 		TOKEN_LPAREN:    "(",
 		TOKEN_RPAREN:    ")",
 		TOKEN_LBRACKET:  "[",
@@ -286,12 +316,20 @@ func TokenTypeName(t TokenType) string {
 		TOKEN_IMPLEMENTS: "implements",
 		TOKEN_ABSTRACT:   "abstract",
 		TOKEN_EXTENDS:    "extends",
+		TOKEN_ENUM:       "enum",
+		TOKEN_SEALED:     "sealed",
+		TOKEN_PROP:       "prop",
+		TOKEN_EXTEND:     "extend",
+		TOKEN_OPERATOR:   "operator",
+		TOKEN_OVERRIDE:   "override",
 		TOKEN_SELF:       "self",
+		TOKEN_SUPER:      "super",
 		TOKEN_FROM:       "from",
 		TOKEN_USE:        "use",
 		TOKEN_AS:         "as",
 		TOKEN_TRY:        "try",
 		TOKEN_CATCH:      "catch",
+		TOKEN_FINALLY:    "finally",
 		TOKEN_THROW:      "throw",
 		TOKEN_MATCH:      "match",
 		TOKEN_TAG:        "TAG",
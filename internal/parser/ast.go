@@ -26,10 +26,18 @@ type File struct {
 	Package    string
 	Imports    []*ImportDecl
 	Statements []Statement
+	Comments   []*Comment // 源码中的注释（用于 tugo:ignore 等指令）
 }
 
 func (f *File) TokenLiteral() string { return "file" }
 
+// Comment 注释
+type Comment struct {
+	Line     int    // 注释起始行
+	Text     string // 注释原文，包含 // 或 /* */
+	Trailing bool   // 是否跟在同一行的代码后面
+}
+
 // ImportDecl 导入声明
 type ImportDecl struct {
 	Token lexer.Token // import token
@@ -88,6 +96,43 @@ type StructDecl struct {
 func (s *StructDecl) TokenLiteral() string { return s.Token.Literal }
 func (s *StructDecl) statementNode()       {}
 
+// EnumDecl 枚举声明
+// enum Color { Red, Green, Blue }
+// enum Status: string { Active = "active", Inactive = "inactive" }
+type EnumDecl struct {
+	Token   lexer.Token    // enum token
+	Public  bool           // 是否公开
+	Name    string         // 枚举名
+	Type    Expression     // 底层类型（可选，默认 int）
+	Cases   []*EnumCase    // 枚举成员
+	Fields  []*ClassField  // 每个成员携带的字段
+	Methods []*ClassMethod // 方法列表（复用 ClassMethod）
+}
+
+func (e *EnumDecl) TokenLiteral() string { return e.Token.Literal }
+func (e *EnumDecl) statementNode()       {}
+
+// EnumCase 枚举成员：Red、Active = "active"、Red("#ff0000")
+type EnumCase struct {
+	Token lexer.Token  // 成员名 token
+	Name  string       // 成员名
+	Value Expression   // 底层值（可选）
+	Args  []Expression // 字段的值（可选，按字段顺序或命名参数）
+}
+
+// ExtendDecl 扩展方法声明，为已有类型（string、[]T、Go 类型、tugo 类）添加方法
+// extend string { public func isBlank() bool { ... } }
+// extend[T any] []T { public func first() T { ... } }
+type ExtendDecl struct {
+	Token      lexer.Token    // extend token
+	TypeParams *TypeParamList // 泛型类型参数（可选）
+	Type       Expression     // 被扩展的类型
+	Methods    []*ClassMethod // 扩展方法（复用 ClassMethod）
+}
+
+func (e *ExtendDecl) TokenLiteral() string { return e.Token.Literal }
+func (e *ExtendDecl) statementNode()       {}
+
 // FieldTag 字段标签
 type FieldTag struct {
 	Key   string // 标签键 (如 "json", "validate")
@@ -109,7 +154,9 @@ type ClassDecl struct {
 	Token           lexer.Token    // class token
 	Public          bool           // 是否公开
 	Abstract        bool           // 是否抽象类
+	Sealed          bool           // 是否密封类（只能被同一个包中的类继承）
 	Static          bool           // 是否静态类
+	Data            bool           // 是否数据类（data class Name(x: int, ...)）
 	Name            string         // 类名
 	TypeParams      *TypeParamList // 泛型类型参数（可选）
 	Extends         string         // 继承的父类（可选）
@@ -117,8 +164,10 @@ type ClassDecl struct {
 	Fields          []*ClassField  // 字段列表
 	Methods         []*ClassMethod // 方法列表
 	AbstractMethods []*ClassMethod // 抽象方法列表
+	Properties      []*ClassProperty // 属性列表（后备字段和 getter/setter 也在 Fields/Methods 中）
 	InitMethod      *ClassMethod   // 构造方法（兼容旧代码，取 InitMethods[0]）
 	InitMethods     []*ClassMethod // 多个构造方法（重载）
+	DataParams      []*Field       // 数据类的主构造参数（同时是公开字段）
 }
 
 func (c *ClassDecl) TokenLiteral() string { return c.Token.Literal }
@@ -146,6 +195,34 @@ type ClassMethod struct {
 	Static     bool           // 是否静态
 	Abstract   bool           // 是否抽象方法
 	Errable    bool           // 是否可能抛出错误（返回类型带 ! 标记）
+	Operator   string         // 重载的运算符（operator 声明），普通方法为空
+	Derived    bool           // 数据类自动生成的方法（equals、hash、string、copy），方法体由代码生成器生成
+	Override   bool           // 是否用 override 声明（重写父类的方法）
+}
+
+// OperatorMethods 可以重载的运算符及对应的方法名
+// != 取 == 的反，> <= >= 由 < 交换操作数或取反得到
+var OperatorMethods = map[string]string{
+	"+":  "opAdd",
+	"-":  "opSub",
+	"*":  "opMul",
+	"/":  "opDiv",
+	"%":  "opMod",
+	"==": "opEquals",
+	"<":  "opLess",
+	"[]": "opIndex",
+}
+
+// ClassProperty 类属性，解析时展开为后备字段和 getter/setter 方法：
+// 读取 x.name 调用 name()，赋值 x.name = v 调用 setName(v)
+type ClassProperty struct {
+	Token      lexer.Token  // prop token
+	Name       string       // 属性名
+	Type       Expression   // 类型
+	Visibility string       // public/private/protected（getter 的可见性）
+	Getter     *ClassMethod // 读取方法
+	Setter     *ClassMethod // 写入方法（只读属性为 nil）
+	Field      *ClassField  // 后备字段（计算属性和抽象属性为 nil）
 }
 
 // ThisExpr this 表达式
@@ -156,6 +233,14 @@ type ThisExpr struct {
 func (t *ThisExpr) TokenLiteral() string { return t.Token.Literal }
 func (t *ThisExpr) expressionNode()      {}
 
+// SuperExpr 父类引用 super，只能用于 super.method(...) 和 super.init(...)
+type SuperExpr struct {
+	Token lexer.Token
+}
+
+func (s *SuperExpr) TokenLiteral() string { return s.Token.Literal }
+func (s *SuperExpr) expressionNode()      {}
+
 // SelfExpr self 表达式（用于静态类）
 type SelfExpr struct {
 	Token lexer.Token
@@ -181,6 +266,7 @@ type InterfaceDecl struct {
 	Name       string
 	TypeParams *TypeParamList // 泛型类型参数（可选）
 	Methods    []*FuncSignature
+	Properties []*ClassProperty // 属性（getter/setter 签名也在 Methods 中）
 }
 
 func (i *InterfaceDecl) TokenLiteral() string { return i.Token.Literal }
@@ -386,12 +472,12 @@ type FallthroughStmt struct {
 func (f *FallthroughStmt) TokenLiteral() string { return f.Token.Literal }
 func (f *FallthroughStmt) statementNode()       {}
 
-// TryStmt try-catch 语句
+// TryStmt try-catch-finally 语句
 type TryStmt struct {
 	Token   lexer.Token
-	Body    *BlockStmt   // try 块
-	Catch   *CatchClause // catch 子句
-	Finally *BlockStmt   // finally 子句（可选，后续扩展）
+	Body    *BlockStmt     // try 块
+	Catches []*CatchClause // catch 子句，按顺序匹配
+	Finally *BlockStmt     // finally 子句（可选）
 }
 
 func (t *TryStmt) TokenLiteral() string { return t.Token.Literal }
@@ -401,6 +487,7 @@ func (t *TryStmt) statementNode()       {}
 type CatchClause struct {
 	Token lexer.Token
 	Param string     // 异常参数名 (e)
+	Type  Expression // 捕获的错误类型 catch (e NotFoundError)，nil 表示捕获所有错误
 	Body  *BlockStmt // catch 块
 }
 
@@ -409,7 +496,7 @@ func (c *CatchClause) TokenLiteral() string { return c.Token.Literal }
 // ThrowStmt throw 语句
 type ThrowStmt struct {
 	Token lexer.Token
-	Value Expression // 错误值
+	Value Expression // 错误值，nil 表示在 catch 块中重新抛出捕获的错误
 }
 
 func (t *ThrowStmt) TokenLiteral() string { return t.Token.Literal }
@@ -481,6 +568,22 @@ type StringLiteral struct {
 func (s *StringLiteral) TokenLiteral() string { return s.Token.Literal }
 func (s *StringLiteral) expressionNode()      {}
 
+// InterpolatedString 插值字符串 "Hello ${name}"
+type InterpolatedString struct {
+	Token lexer.Token
+	Parts []*InterpolationPart
+}
+
+func (s *InterpolatedString) TokenLiteral() string { return s.Token.Literal }
+func (s *InterpolatedString) expressionNode()      {}
+
+// InterpolationPart 插值字符串的片段，Expr 为 nil 时是普通文本
+type InterpolationPart struct {
+	Text string     // 文本（Go 字符串字面量的内容，不含引号）
+	Expr Expression // 嵌入的表达式
+	Spec string     // 格式说明，如 ${price:.2f} 中的 .2f
+}
+
 // CharLiteral 字符字面量
 type CharLiteral struct {
 	Token lexer.Token
@@ -599,9 +702,41 @@ type MatchArm struct {
 	Token    lexer.Token  // => token
 	Patterns []Expression // 匹配模式（可以是多个值，用逗号分隔）
 	IsDefault bool        // 是否是 default 分支
+	Guard    Expression   // 守卫条件 pattern if cond（可选）
 	Body     Expression   // 分支结果表达式
+	Block    *BlockStmt   // 块分支 pattern => { ... }（此时 Body 为 nil），作为表达式时最后一个表达式是分支的值
+}
+
+// TypePattern 类型模式
+// c Circle、Point{x: 0, y}、p Point{x: 0}
+type TypePattern struct {
+	Token   lexer.Token     // 第一个 token
+	Binding string          // 绑定的变量名（可选）
+	Type    Expression      // 匹配的类型
+	Fields  []*FieldPattern // 解构的字段（可选）
+}
+
+func (t *TypePattern) TokenLiteral() string { return t.Token.Literal }
+func (t *TypePattern) expressionNode()      {}
+
+// FieldPattern 解构模式中的字段
+// x: 0 比较字段的值，y 把字段绑定到同名变量
+type FieldPattern struct {
+	Token lexer.Token // 字段名 token
+	Name  string      // 字段名
+	Value Expression  // 字段的模式（nil 表示绑定到同名变量）
 }
 
+// RangePattern 范围模式 low..high（包含两端）
+type RangePattern struct {
+	Token lexer.Token // .. token
+	Low   Expression
+	High  Expression
+}
+
+func (r *RangePattern) TokenLiteral() string { return r.Token.Literal }
+func (r *RangePattern) expressionNode()      {}
+
 // UnaryExpr 一元表达式
 type UnaryExpr struct {
 	Token    lexer.Token
@@ -649,6 +784,7 @@ type SelectorExpr struct {
 	Token lexer.Token
 	X     Expression // 对象
 	Sel   string     // 选择的成员
+	Safe  bool       // 安全导航 a?.b，a 为 nil 时不访问成员
 }
 
 func (s *SelectorExpr) TokenLiteral() string { return s.Token.Literal }
@@ -665,11 +801,16 @@ func (t *TypeAssertExpr) TokenLiteral() string { return t.Token.Literal }
 func (t *TypeAssertExpr) expressionNode()      {}
 
 // FuncLiteral 函数字面量（匿名函数）
+// 箭头 lambda x => expr、(a, b) => { ... } 的参数类型可以省略，由调用处期望的函数类型推断，
+// 返回值类型由函数体推断；表达式函数体解析为只有一条 return 的块
 type FuncLiteral struct {
-	Token   lexer.Token
-	Params  []*Field
-	Results []*Field
-	Body    *BlockStmt
+	Token    lexer.Token
+	Params   []*Field
+	Results  []*Field
+	Body     *BlockStmt
+	Arrow    bool // 是否是箭头 lambda
+	ExprBody bool // 是否是表达式函数体
+	StmtBody bool // 是否是赋值或自增语句函数体（x => total += x），没有值
 }
 
 func (f *FuncLiteral) TokenLiteral() string { return f.Token.Literal }
@@ -734,6 +875,15 @@ type PointerType struct {
 func (p *PointerType) TokenLiteral() string { return p.Token.Literal }
 func (p *PointerType) expressionNode()      {}
 
+// NullableType 可空类型 T?（T 为类或指针类型）
+type NullableType struct {
+	Token lexer.Token
+	Base  Expression
+}
+
+func (n *NullableType) TokenLiteral() string { return n.Token.Literal }
+func (n *NullableType) expressionNode()      {}
+
 // FuncType 函数类型
 type FuncType struct {
 	Token   lexer.Token
@@ -794,12 +944,22 @@ func (m *MakeExpr) expressionNode()      {}
 type NewExpr struct {
 	Token     lexer.Token
 	Type      Expression   // 类名
-	Arguments []Expression // 构造参数 (命名参数)
+	Arguments []Expression // 构造参数（可以包含 NamedArg）
 }
 
 func (n *NewExpr) TokenLiteral() string { return n.Token.Literal }
 func (n *NewExpr) expressionNode()      {}
 
+// NamedArg 命名参数 (name: value)，只出现在调用和 new 的参数列表中
+type NamedArg struct {
+	Token lexer.Token // 参数名 token
+	Name  string
+	Value Expression
+}
+
+func (n *NamedArg) TokenLiteral() string { return n.Token.Literal }
+func (n *NamedArg) expressionNode()      {}
+
 // LenExpr len 表达式
 type LenExpr struct {
 	Token lexer.Token
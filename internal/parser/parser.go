@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/tangzhangming/tugo/internal/i18n"
 	"github.com/tangzhangming/tugo/internal/lexer"
@@ -14,6 +15,8 @@ type Parser struct {
 	peekToken               lexer.Token
 	errors                  []string
 	disableStructLiteral    bool // 禁止解析结构体字面量（用于 switch/for 等语句）
+	disableLambda           bool // 禁止解析箭头 lambda（用于 match 模式和守卫，=> 是分支分隔符）
+	comments                []*Comment // 跳过的注释
 }
 
 // New 创建一个新的语法分析器
@@ -34,8 +37,13 @@ func (p *Parser) Errors() []string {
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
-	// 跳过注释
+	// 跳过注释（记录下来供 tugo:ignore 等指令使用）
 	for p.peekToken.Type == lexer.TOKEN_COMMENT {
+		p.comments = append(p.comments, &Comment{
+			Line:     p.peekToken.Line,
+			Text:     p.peekToken.Literal,
+			Trailing: p.curToken.Line == p.peekToken.Line,
+		})
 		p.peekToken = p.l.NextToken()
 	}
 	// 检查非法 token（排除初始化时的空 token）
@@ -59,6 +67,12 @@ func (p *Parser) isBuiltinFuncToken() bool {
 	return false
 }
 
+// isMemberNameToken 检查当前 token 是否可以作为成员名（字段名/选择器）
+// 成员名总是出现在 . 之后或声明位置，因此允许使用关键字（如 type、range），生成代码时会自动转义
+func (p *Parser) isMemberNameToken() bool {
+	return p.curTokenIs(lexer.TOKEN_IDENT) || p.isBuiltinFuncToken() || lexer.IsKeyword(p.curToken.Literal)
+}
+
 // peekTokenIs 检查下一个 token 类型
 func (p *Parser) peekTokenIs(t lexer.TokenType) bool {
 	return p.peekToken.Type == t
@@ -125,6 +139,7 @@ func (p *Parser) ParseFile() *File {
 		p.nextToken()
 	}
 
+	file.Comments = p.comments
 	return file
 }
 
@@ -344,6 +359,9 @@ func (p *Parser) parseFieldTag(literal string) *FieldTag {
 
 // parseStatement 解析语句
 func (p *Parser) parseStatement() Statement {
+	if p.dataClassAhead() {
+		return p.parseDataClassDecl(false)
+	}
 	switch p.curToken.Type {
 	case lexer.TOKEN_PUBLIC:
 		return p.parsePublicDecl()
@@ -355,13 +373,23 @@ func (p *Parser) parseStatement() Statement {
 		return p.parseFuncDecl(false, "private")
 	case lexer.TOKEN_STRUCT:
 		return p.parseStructDecl(false)
+	case lexer.TOKEN_ENUM:
+		return p.parseEnumDecl(false)
+	case lexer.TOKEN_EXTEND:
+		return p.parseExtendDecl()
 	case lexer.TOKEN_CLASS:
 		return p.parseClassDecl(false)
 	case lexer.TOKEN_ABSTRACT:
 		return p.parseAbstractClassDecl(false)
+	case lexer.TOKEN_SEALED:
+		return p.parseSealedClassDecl(false)
 	case lexer.TOKEN_STATIC:
 		return p.parseStaticClassDecl(false)
 	case lexer.TOKEN_TYPE:
+		// type 后面不是类型名时是名为 type 的变量（如 type = "x"）
+		if !p.peekTokenIs(lexer.TOKEN_IDENT) {
+			return p.parseExpressionStatement()
+		}
 		return p.parseTypeDecl(false)
 	case lexer.TOKEN_INTERFACE:
 		return p.parseInterfaceDecl(false)
@@ -403,15 +431,22 @@ func (p *Parser) parseStatement() Statement {
 // parsePublicDecl 解析 public 声明
 func (p *Parser) parsePublicDecl() Statement {
 	p.nextToken()
+	if p.dataClassAhead() {
+		return p.parseDataClassDecl(true)
+	}
 	switch p.curToken.Type {
 	case lexer.TOKEN_FUNC:
 		return p.parseFuncDecl(true, "public")
 	case lexer.TOKEN_STRUCT:
 		return p.parseStructDecl(true)
+	case lexer.TOKEN_ENUM:
+		return p.parseEnumDecl(true)
 	case lexer.TOKEN_CLASS:
 		return p.parseClassDecl(true)
 	case lexer.TOKEN_ABSTRACT:
 		return p.parseAbstractClassDecl(true)
+	case lexer.TOKEN_SEALED:
+		return p.parseSealedClassDecl(true)
 	case lexer.TOKEN_STATIC:
 		return p.parseStaticClassDecl(true)
 	case lexer.TOKEN_TYPE:
@@ -421,11 +456,16 @@ func (p *Parser) parsePublicDecl() Statement {
 	case lexer.TOKEN_VAR:
 		return p.parseVarDecl() // public var
 	default:
-		p.addError("expected func, struct, class, abstract, static, type or interface after public")
+		p.addError("expected func, struct, enum, class, abstract, sealed, static, type or interface after public")
 		return nil
 	}
 }
 
+// dataClassAhead 判断当前是否是 data class 声明（data 只在 class 前是关键字）
+func (p *Parser) dataClassAhead() bool {
+	return p.curTokenIs(lexer.TOKEN_IDENT) && p.curToken.Literal == "data" && p.peekTokenIs(lexer.TOKEN_CLASS)
+}
+
 // parsePrivateDecl 解析 private 声明
 func (p *Parser) parsePrivateDecl() Statement {
 	p.nextToken()
@@ -502,7 +542,7 @@ func (p *Parser) parseFuncDecl(public bool, visibility string) *FuncDecl {
 		// 单个返回值类型
 		p.nextToken()
 		typ := p.parseType()
-		if typ != nil {
+		if typ != nil && !isVoidType(typ) {
 			decl.Results = []*Field{{Type: typ}}
 		}
 	}
@@ -572,8 +612,8 @@ func (p *Parser) parseFieldList(end lexer.TokenType) []*Field {
 func (p *Parser) parseField() *Field {
 	field := &Field{}
 
-	// 解析名称
-	if p.curTokenIs(lexer.TOKEN_IDENT) && (p.peekTokenIs(lexer.TOKEN_COLON) || p.peekTokenIs(lexer.TOKEN_IDENT) || p.peekTokenIs(lexer.TOKEN_ASTERISK) || p.peekTokenIs(lexer.TOKEN_LBRACKET) || p.peekTokenIs(lexer.TOKEN_MAP) || p.peekTokenIs(lexer.TOKEN_CHAN) || p.peekTokenIs(lexer.TOKEN_FUNC) || p.peekTokenIs(lexer.TOKEN_INTERFACE) || p.peekTokenIs(lexer.TOKEN_STRUCT) || p.peekTokenIs(lexer.TOKEN_ELLIPSIS)) {
+	// 解析名称（type、range 可以作为参数名，生成代码时会自动转义）
+	if (p.curTokenIs(lexer.TOKEN_IDENT) || p.curTokenIs(lexer.TOKEN_TYPE) || p.curTokenIs(lexer.TOKEN_RANGE)) && (p.peekTokenIs(lexer.TOKEN_COLON) || p.peekTokenIs(lexer.TOKEN_IDENT) || p.peekTokenIs(lexer.TOKEN_ASTERISK) || p.peekTokenIs(lexer.TOKEN_LBRACKET) || p.peekTokenIs(lexer.TOKEN_MAP) || p.peekTokenIs(lexer.TOKEN_CHAN) || p.peekTokenIs(lexer.TOKEN_FUNC) || p.peekTokenIs(lexer.TOKEN_INTERFACE) || p.peekTokenIs(lexer.TOKEN_STRUCT) || p.peekTokenIs(lexer.TOKEN_ELLIPSIS)) {
 		field.Name = p.curToken.Literal
 		p.nextToken()
 
@@ -622,7 +662,12 @@ func (p *Parser) parseStructDecl(public bool) *StructDecl {
 				p.addError("expected interface name after implements")
 				return nil
 			}
-			decl.Implements = append(decl.Implements, p.curToken.Literal)
+			// 支持包限定的接口名，如 models.Shape、io.Reader
+			ifaceName, ok := p.parseQualifiedName("interface name")
+			if !ok {
+				return nil
+			}
+			decl.Implements = append(decl.Implements, ifaceName)
 			if !p.peekTokenIs(lexer.TOKEN_COMMA) {
 				break
 			}
@@ -660,6 +705,108 @@ func (p *Parser) parseStructDecl(public bool) *StructDecl {
 	return decl
 }
 
+// parseEnumDecl 解析枚举声明
+// enum Name[: type] { 成员, ... 字段和方法 }，成员列表在字段和方法之前，用逗号分隔
+func (p *Parser) parseEnumDecl(public bool) *EnumDecl {
+	decl := &EnumDecl{Token: p.curToken, Public: public}
+	if !p.expectPeek(lexer.TOKEN_IDENT) {
+		return nil
+	}
+	decl.Name = p.curToken.Literal
+
+	// 底层类型 enum Status: string
+	if p.peekTokenIs(lexer.TOKEN_COLON) {
+		p.nextToken()
+		p.nextToken()
+		decl.Type = p.parseType()
+	}
+
+	if !p.expectPeek(lexer.TOKEN_LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	// 成员：标识符后面是 , = ( 或 }（字段是 name type，方法以 func 开头）
+	for p.curTokenIs(lexer.TOKEN_IDENT) && (p.peekTokenIs(lexer.TOKEN_COMMA) || p.peekTokenIs(lexer.TOKEN_ASSIGN) ||
+		p.peekTokenIs(lexer.TOKEN_LPAREN) || p.peekTokenIs(lexer.TOKEN_RBRACE)) {
+		c := &EnumCase{Token: p.curToken, Name: p.curToken.Literal}
+		if p.peekTokenIs(lexer.TOKEN_LPAREN) {
+			p.nextToken() // 消费 (
+			c.Args = p.parseCallArguments()
+		}
+		if p.peekTokenIs(lexer.TOKEN_ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			c.Value = p.parseExpression(LOWEST)
+		}
+		decl.Cases = append(decl.Cases, c)
+		p.nextToken()
+		if !p.curTokenIs(lexer.TOKEN_COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	if len(decl.Cases) == 0 {
+		p.addError(fmt.Sprintf("enum %s must declare at least one case", decl.Name))
+	}
+
+	// 字段和方法
+	for !p.curTokenIs(lexer.TOKEN_RBRACE) && !p.curTokenIs(lexer.TOKEN_EOF) {
+		member := p.parseClassMember()
+		if member != nil {
+			switch m := member.(type) {
+			case *ClassField:
+				decl.Fields = append(decl.Fields, m)
+			case *ClassMethod:
+				decl.Methods = append(decl.Methods, m)
+			}
+		}
+		p.nextToken()
+	}
+
+	return decl
+}
+
+// parseExtendDecl 解析扩展方法声明
+// extend[类型参数] 类型 { 方法... }，只能声明实例方法
+func (p *Parser) parseExtendDecl() *ExtendDecl {
+	decl := &ExtendDecl{Token: p.curToken}
+	p.nextToken()
+
+	// 类型参数 extend[T any] []T（[] 或 [N] 开头的是类型本身）
+	if p.curTokenIs(lexer.TOKEN_LBRACKET) && p.peekTokenIs(lexer.TOKEN_IDENT) {
+		decl.TypeParams = p.parseTypeParams()
+		p.nextToken()
+	}
+	decl.Type = p.parseType()
+	if decl.Type == nil {
+		p.addError("expected type after extend")
+		return nil
+	}
+
+	if !p.expectPeek(lexer.TOKEN_LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	for !p.curTokenIs(lexer.TOKEN_RBRACE) && !p.curTokenIs(lexer.TOKEN_EOF) {
+		switch m := p.parseClassMember().(type) {
+		case nil:
+		case *ClassMethod:
+			if m != nil && (m.Static || m.Abstract || m.Operator != "") {
+				p.addError("extend blocks can only declare instance methods")
+			} else if m != nil {
+				decl.Methods = append(decl.Methods, m)
+			}
+		default:
+			p.addError("extend blocks can only declare instance methods")
+		}
+		p.nextToken()
+	}
+
+	return decl
+}
+
 // parseStructMember 解析结构体成员（字段、方法、嵌入）
 func (p *Parser) parseStructMember() interface{} {
 	// 检查非法 token，跳过直到找到有效的成员开始
@@ -695,6 +842,8 @@ func (p *Parser) parseStructMember() interface{} {
 		return field
 	case lexer.TOKEN_FUNC:
 		return p.parseStructMethod(visibility)
+	case lexer.TOKEN_OPERATOR:
+		return p.parseOperatorMethod(visibility, false, false)
 	case lexer.TOKEN_IDENT:
 		// 可能是嵌入类型或字段
 		result := p.parseStructFieldOrEmbed(visibility)
@@ -712,7 +861,7 @@ func (p *Parser) parseStructFieldWithVar(visibility string) *StructField {
 	field := &StructField{Visibility: visibility, Public: visibility == "public"}
 	p.nextToken() // 跳过 var
 
-	if !p.curTokenIs(lexer.TOKEN_IDENT) {
+	if !p.isMemberNameToken() {
 		return nil
 	}
 	field.Name = p.curToken.Literal
@@ -819,7 +968,7 @@ func (p *Parser) parseStructMethod(visibility string) *ClassMethod {
 		// 单个返回值类型
 		p.nextToken()
 		typ := p.parseType()
-		if typ != nil {
+		if typ != nil && !isVoidType(typ) {
 			method.Results = []*Field{{Type: typ}}
 		}
 	}
@@ -841,7 +990,7 @@ func (p *Parser) parseStructMethod(visibility string) *ClassMethod {
 
 // parseClassDecl 解析类声明
 func (p *Parser) parseClassDecl(public bool) *ClassDecl {
-	return p.parseClassDeclFull(public, false, false)
+	return p.parseClassDeclFull(public, false, false, false)
 }
 
 func (p *Parser) parseAbstractClassDecl(public bool) *ClassDecl {
@@ -851,7 +1000,22 @@ func (p *Parser) parseAbstractClassDecl(public bool) *ClassDecl {
 		p.addError("expected 'class' after 'abstract'")
 		return nil
 	}
-	return p.parseClassDeclFull(public, true, false)
+	return p.parseClassDeclFull(public, true, false, false)
+}
+
+// parseSealedClassDecl 解析 sealed abstract class
+func (p *Parser) parseSealedClassDecl(public bool) *ClassDecl {
+	// 已经在 sealed 关键字上，跳到 abstract
+	p.nextToken()
+	if !p.curTokenIs(lexer.TOKEN_ABSTRACT) {
+		p.addError("expected 'abstract class' after 'sealed'")
+		return nil
+	}
+	decl := p.parseAbstractClassDecl(public)
+	if decl != nil {
+		decl.Sealed = true
+	}
+	return decl
 }
 
 func (p *Parser) parseStaticClassDecl(public bool) *ClassDecl {
@@ -861,11 +1025,18 @@ func (p *Parser) parseStaticClassDecl(public bool) *ClassDecl {
 		p.addError("expected 'class' after 'static'")
 		return nil
 	}
-	return p.parseClassDeclFull(public, false, true)
+	return p.parseClassDeclFull(public, false, true, false)
 }
 
-func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool) *ClassDecl {
-	decl := &ClassDecl{Token: p.curToken, Public: public, Abstract: abstract, Static: static}
+// parseDataClassDecl 解析数据类 data class Name(x: int, y: int) { ... }，类体可以省略
+func (p *Parser) parseDataClassDecl(public bool) *ClassDecl {
+	// 已经在 data 上（上下文关键字），跳到 class
+	p.nextToken()
+	return p.parseClassDeclFull(public, false, false, true)
+}
+
+func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool, data bool) *ClassDecl {
+	decl := &ClassDecl{Token: p.curToken, Public: public, Abstract: abstract, Static: static, Data: data}
 	p.nextToken()
 
 	if !p.curTokenIs(lexer.TOKEN_IDENT) {
@@ -880,12 +1051,32 @@ func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool) *Cl
 		decl.TypeParams = p.parseTypeParams()
 	}
 
-	// 静态类不能有 extends
+	// 数据类的主构造参数
+	if data {
+		if decl.TypeParams != nil {
+			p.addError(fmt.Sprintf("data class %s cannot have type parameters", decl.Name))
+			return nil
+		}
+		if !p.expectPeek(lexer.TOKEN_LPAREN) {
+			return nil
+		}
+		decl.DataParams = p.parseFieldList(lexer.TOKEN_RPAREN)
+		if len(decl.DataParams) == 0 {
+			p.addError(fmt.Sprintf("data class %s must declare at least one field", decl.Name))
+			return nil
+		}
+	}
+
+	// 静态类和数据类不能有 extends
 	if p.peekTokenIs(lexer.TOKEN_EXTENDS) {
 		if static {
 			p.addError("static class cannot extend another class")
 			return nil
 		}
+		if data {
+			p.addError("data class cannot extend another class")
+			return nil
+		}
 		p.nextToken() // 移动到 extends
 		p.nextToken() // 移动到父类名
 		if !p.curTokenIs(lexer.TOKEN_IDENT) {
@@ -893,15 +1084,9 @@ func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool) *Cl
 			return nil
 		}
 		// 支持包限定的父类名，如 db.Model
-		parentName := p.curToken.Literal
-		for p.peekTokenIs(lexer.TOKEN_DOT) {
-			p.nextToken() // 移动到 .
-			p.nextToken() // 移动到下一个标识符
-			if !p.curTokenIs(lexer.TOKEN_IDENT) {
-				p.addError("expected identifier after '.' in parent class name")
-				return nil
-			}
-			parentName += "." + p.curToken.Literal
+		parentName, ok := p.parseQualifiedName("parent class name")
+		if !ok {
+			return nil
 		}
 		decl.Extends = parentName
 	}
@@ -919,7 +1104,12 @@ func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool) *Cl
 				p.addError("expected interface name after implements")
 				return nil
 			}
-			decl.Implements = append(decl.Implements, p.curToken.Literal)
+			// 支持包限定的接口名，如 models.Shape、io.Reader
+			ifaceName, ok := p.parseQualifiedName("interface name")
+			if !ok {
+				return nil
+			}
+			decl.Implements = append(decl.Implements, ifaceName)
 			if !p.peekTokenIs(lexer.TOKEN_COMMA) {
 				break
 			}
@@ -928,6 +1118,12 @@ func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool) *Cl
 		}
 	}
 
+	// 数据类没有类体时到此结束
+	if data && !p.peekTokenIs(lexer.TOKEN_LBRACE) {
+		p.addDataMembers(decl)
+		return decl
+	}
+
 	if !p.expectPeek(lexer.TOKEN_LBRACE) {
 		return nil
 	}
@@ -940,6 +1136,22 @@ func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool) *Cl
 			switch m := member.(type) {
 			case *ClassField:
 				decl.Fields = append(decl.Fields, m)
+			case *ClassProperty:
+				// 属性展开为后备字段和 getter/setter 方法
+				decl.Properties = append(decl.Properties, m)
+				if m.Field != nil {
+					decl.Fields = append(decl.Fields, m.Field)
+				}
+				for _, accessor := range []*ClassMethod{m.Getter, m.Setter} {
+					if accessor == nil {
+						continue
+					}
+					if accessor.Abstract {
+						decl.AbstractMethods = append(decl.AbstractMethods, accessor)
+					} else {
+						decl.Methods = append(decl.Methods, accessor)
+					}
+				}
 			case *ClassMethod:
 				if m.Name == "init" {
 					decl.InitMethods = append(decl.InitMethods, m)
@@ -957,9 +1169,72 @@ func (p *Parser) parseClassDeclFull(public bool, abstract bool, static bool) *Cl
 		p.nextToken()
 	}
 
+	if data {
+		p.addDataMembers(decl)
+	}
 	return decl
 }
 
+// addDataMembers 把数据类的主构造参数展开为公开字段和构造方法，并声明 equals、hash、string、copy 方法
+// 类中已声明的同名方法不再生成；copy 的参数默认取接收者的字段值
+func (p *Parser) addDataMembers(decl *ClassDecl) {
+	tok := decl.Token
+	if len(decl.InitMethods) > 0 {
+		p.addError(fmt.Sprintf("data class %s cannot declare init, its fields are initialized by the primary constructor", decl.Name))
+		return
+	}
+
+	self := &PointerType{Token: tok, Base: &Identifier{Token: tok, Value: decl.Name}}
+	init := &ClassMethod{Token: tok, Name: "init", Visibility: "public", Body: &BlockStmt{Token: tok}}
+	copyMethod := &ClassMethod{Token: tok, Name: "copy", Results: []*Field{{Type: self}}, Visibility: "public", Derived: true}
+	var fields []*ClassField
+	for _, param := range decl.DataParams {
+		if param.Name == "" {
+			p.addError(fmt.Sprintf("data class %s: field must have a name", decl.Name))
+			return
+		}
+		switch param.Name {
+		case "equals", "hash", "string", "copy":
+			p.addError(fmt.Sprintf("data class %s: field %s conflicts with the generated method %s()", decl.Name, param.Name, param.Name))
+			return
+		}
+		fields = append(fields, &ClassField{Name: param.Name, Type: param.Type, Visibility: "public"})
+		init.Params = append(init.Params, &Field{Name: param.Name, Type: param.Type, DefaultValue: param.DefaultValue})
+
+		assign := lexer.Token{Type: lexer.TOKEN_ASSIGN, Literal: "=", Line: tok.Line, Column: tok.Column}
+		target := &SelectorExpr{Token: tok, X: &ThisExpr{Token: tok}, Sel: param.Name}
+		value := &Identifier{Token: tok, Value: param.Name}
+		init.Body.Statements = append(init.Body.Statements, &AssignStmt{Token: assign, Left: []Expression{target}, Right: []Expression{value}})
+
+		copyMethod.Params = append(copyMethod.Params, &Field{Name: param.Name, Type: param.Type, DefaultValue: target})
+	}
+	decl.Fields = append(fields, decl.Fields...)
+	decl.InitMethods = []*ClassMethod{init}
+	decl.InitMethod = init
+
+	basic := func(name string) []*Field {
+		return []*Field{{Type: &Identifier{Token: tok, Value: name}}}
+	}
+	derived := []*ClassMethod{
+		{Token: tok, Name: "equals", Params: []*Field{{Name: "other", Type: self}}, Results: basic("bool"), Visibility: "public", Derived: true},
+		{Token: tok, Name: "hash", Results: basic("int"), Visibility: "public", Derived: true},
+		{Token: tok, Name: "string", Results: basic("string"), Visibility: "public", Derived: true},
+		copyMethod,
+	}
+	for _, method := range derived {
+		declared := false
+		for _, m := range decl.Methods {
+			if m.Name == method.Name {
+				declared = true
+				break
+			}
+		}
+		if !declared {
+			decl.Methods = append(decl.Methods, method)
+		}
+	}
+}
+
 // parseClassMember 解析类成员（字段或方法）
 func (p *Parser) parseClassMember() interface{} {
 	// 检查非法 token，跳过直到找到有效的成员开始
@@ -1002,6 +1277,30 @@ func (p *Parser) parseClassMember() interface{} {
 		p.nextToken()
 	}
 
+	// override 只能修饰方法和运算符
+	if p.curTokenIs(lexer.TOKEN_OVERRIDE) {
+		p.nextToken()
+		var method *ClassMethod
+		switch p.curToken.Type {
+		case lexer.TOKEN_FUNC:
+			method = p.parseClassMethodWithAbstract(visibility, isStatic, isAbstract)
+		case lexer.TOKEN_OPERATOR:
+			method = p.parseOperatorMethod(visibility, isStatic, isAbstract)
+		default:
+			p.addError(fmt.Sprintf("expected func or operator after override, got '%s'", p.curToken.Literal))
+			return nil
+		}
+		if method == nil {
+			return nil
+		}
+		if method.Name == "init" {
+			p.addError("init cannot be marked override, call the parent init with super.init(...)")
+			return nil
+		}
+		method.Override = true
+		return method
+	}
+
 	// 解析成员
 	switch p.curToken.Type {
 	case lexer.TOKEN_VAR:
@@ -1012,6 +1311,14 @@ func (p *Parser) parseClassMember() interface{} {
 		return field
 	case lexer.TOKEN_FUNC:
 		return p.parseClassMethodWithAbstract(visibility, isStatic, isAbstract)
+	case lexer.TOKEN_OPERATOR:
+		return p.parseOperatorMethod(visibility, isStatic, isAbstract)
+	case lexer.TOKEN_PROP:
+		prop := p.parseClassProperty(visibility, isStatic, isAbstract)
+		if prop != nil && prop.Field != nil {
+			prop.Field.Tags = tags
+		}
+		return prop
 	case lexer.TOKEN_IDENT:
 		// 可能是类型声明，如 "string title" 或 "name string"
 		field := p.parseClassFieldShort(visibility, isStatic)
@@ -1020,6 +1327,14 @@ func (p *Parser) parseClassMember() interface{} {
 		}
 		return field
 	default:
+		// 关键字作为字段名，如 "type string"
+		if lexer.IsKeyword(p.curToken.Literal) && p.peekTokenIs(lexer.TOKEN_IDENT) {
+			field := p.parseClassFieldShort(visibility, isStatic)
+			if field != nil {
+				field.Tags = tags
+			}
+			return field
+		}
 		return nil
 	}
 }
@@ -1029,7 +1344,7 @@ func (p *Parser) parseClassField(visibility string, isStatic bool) *ClassField {
 	field := &ClassField{Visibility: visibility, Static: isStatic}
 	p.nextToken() // 跳过 var
 
-	if !p.curTokenIs(lexer.TOKEN_IDENT) {
+	if !p.isMemberNameToken() {
 		return nil
 	}
 	field.Name = p.curToken.Literal
@@ -1073,6 +1388,11 @@ func (p *Parser) parseClassFieldShort(visibility string, isStatic bool) *ClassFi
 			// "name type" 形式: name string
 			field.Name = first
 			field.Type = &Identifier{Token: p.curToken, Value: second}
+			// name T? 可空类型
+			if p.peekTokenIs(lexer.TOKEN_QUESTION) {
+				p.nextToken()
+				field.Type = &NullableType{Token: p.curToken, Base: field.Type}
+			}
 		}
 	} else {
 		// 只有一个标识符，可能是复杂类型
@@ -1090,6 +1410,147 @@ func (p *Parser) parseClassFieldShort(visibility string, isStatic bool) *ClassFi
 	return field
 }
 
+// parseClassProperty 解析类属性：
+// - 自动属性: prop name string { get; private set; } = value
+// - 计算属性: prop fullName string => this.first + " " + this.last
+// 自动属性的值保存在后备字段 _name 中，getter 为 name()，setter 为 setName(value)
+func (p *Parser) parseClassProperty(visibility string, isStatic bool, isAbstract bool) *ClassProperty {
+	prop := &ClassProperty{Token: p.curToken, Visibility: visibility}
+	if isStatic {
+		p.addError("static properties are not supported")
+		return nil
+	}
+	p.nextToken() // 跳过 prop
+
+	if !p.isMemberNameToken() {
+		p.addError("expected property name")
+		return nil
+	}
+	prop.Name = p.curToken.Literal
+	p.nextToken()
+	prop.Type = p.parseType()
+
+	// 计算属性: => 表达式
+	if p.peekTokenIs(lexer.TOKEN_FAT_ARROW) {
+		if isAbstract {
+			p.addError(fmt.Sprintf("abstract property %s cannot have a value", prop.Name))
+			return nil
+		}
+		p.nextToken()
+		p.nextToken()
+		prop.Getter = propertyGetter(prop, visibility, p.parseExpression(LOWEST))
+		return prop
+	}
+
+	if !p.expectPeek(lexer.TOKEN_LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	// 访问器列表，访问器可以带自己的可见性: { get; private set; }
+	getVisibility, setVisibility := "", ""
+	for !p.curTokenIs(lexer.TOKEN_RBRACE) {
+		if p.curTokenIs(lexer.TOKEN_EOF) {
+			p.addError("expected } after property accessors")
+			return nil
+		}
+		if p.curTokenIs(lexer.TOKEN_SEMICOLON) || p.curTokenIs(lexer.TOKEN_COMMA) {
+			p.nextToken()
+			continue
+		}
+		accessorVisibility := visibility
+		switch p.curToken.Type {
+		case lexer.TOKEN_PUBLIC:
+			accessorVisibility = "public"
+			p.nextToken()
+		case lexer.TOKEN_PRIVATE:
+			accessorVisibility = "private"
+			p.nextToken()
+		case lexer.TOKEN_PROTECTED:
+			accessorVisibility = "protected"
+			p.nextToken()
+		}
+		switch {
+		case p.curTokenIs(lexer.TOKEN_IDENT) && p.curToken.Literal == "get" && getVisibility == "":
+			getVisibility = accessorVisibility
+		case p.curTokenIs(lexer.TOKEN_IDENT) && p.curToken.Literal == "set" && setVisibility == "":
+			setVisibility = accessorVisibility
+		default:
+			p.addError(fmt.Sprintf("expected get or set in accessors of property %s, got '%s'", prop.Name, p.curToken.Literal))
+			return nil
+		}
+		p.nextToken()
+	}
+	if getVisibility == "" {
+		p.addError(fmt.Sprintf("property %s must have a get accessor", prop.Name))
+		return nil
+	}
+
+	// 抽象属性只声明 getter/setter，由子类实现
+	if isAbstract {
+		prop.Getter = propertyGetter(prop, getVisibility, nil)
+		if setVisibility != "" {
+			prop.Setter = propertySetter(prop, setVisibility, "")
+		}
+		return prop
+	}
+
+	prop.Field = &ClassField{Name: "_" + prop.Name, Type: prop.Type, Visibility: "private"}
+	if p.peekTokenIs(lexer.TOKEN_ASSIGN) {
+		p.nextToken()
+		p.nextToken()
+		prop.Field.Value = p.parseExpression(LOWEST)
+	}
+	field := &SelectorExpr{Token: prop.Token, X: &ThisExpr{Token: prop.Token}, Sel: prop.Field.Name}
+	prop.Getter = propertyGetter(prop, getVisibility, field)
+	if setVisibility != "" {
+		prop.Setter = propertySetter(prop, setVisibility, prop.Field.Name)
+	}
+	return prop
+}
+
+// propertyGetter 生成属性的 getter: func name() T { return value }，value 为 nil 时是抽象方法
+func propertyGetter(prop *ClassProperty, visibility string, value Expression) *ClassMethod {
+	getter := &ClassMethod{
+		Token:      prop.Token,
+		Name:       prop.Name,
+		Results:    []*Field{{Type: prop.Type}},
+		Visibility: visibility,
+		Abstract:   value == nil,
+	}
+	if value != nil {
+		getter.Body = &BlockStmt{Token: prop.Token, Statements: []Statement{
+			&ReturnStmt{Token: prop.Token, Values: []Expression{value}},
+		}}
+	}
+	return getter
+}
+
+// propertySetter 生成属性的 setter: func setName(value T) { this.field = value }，field 为空时是抽象方法
+func propertySetter(prop *ClassProperty, visibility string, field string) *ClassMethod {
+	setter := &ClassMethod{
+		Token:      prop.Token,
+		Name:       PropertySetterName(prop.Name),
+		Params:     []*Field{{Name: "value", Type: prop.Type}},
+		Visibility: visibility,
+		Abstract:   field == "",
+	}
+	if field != "" {
+		assign := lexer.Token{Type: lexer.TOKEN_ASSIGN, Literal: "=", Line: prop.Token.Line, Column: prop.Token.Column}
+		target := &SelectorExpr{Token: prop.Token, X: &ThisExpr{Token: prop.Token}, Sel: field}
+		value := &Identifier{Token: prop.Token, Value: "value"}
+		setter.Body = &BlockStmt{Token: prop.Token, Statements: []Statement{
+			&AssignStmt{Token: assign, Left: []Expression{target}, Right: []Expression{value}},
+		}}
+	}
+	return setter
+}
+
+// PropertySetterName 返回属性 setter 的方法名: name -> setName
+func PropertySetterName(name string) string {
+	return "set" + strings.ToUpper(name[:1]) + name[1:]
+}
+
 // isBasicType 检查是否是基本类型
 func isBasicType(name string) bool {
 	basicTypes := map[string]bool{
@@ -1112,14 +1573,18 @@ func (p *Parser) parseClassMethodWithAbstract(visibility string, isStatic bool,
 	method := &ClassMethod{Token: p.curToken, Visibility: visibility, Static: isStatic, Abstract: isAbstract}
 	p.nextToken()
 
-	// 方法名 - 也允许某些内置函数名作为方法名
-	if !p.curTokenIs(lexer.TOKEN_IDENT) && !p.isBuiltinFuncToken() {
+	// 方法名 - 也允许内置函数名和关键字（如 map）作为方法名
+	if !p.isMemberNameToken() {
 		p.addError("expected method name")
 		return nil
 	}
 	method.Name = p.curToken.Literal
 	p.nextToken()
+	return p.parseMethodRest(method)
+}
 
+// parseMethodRest 从方法名之后解析泛型参数、参数、返回值和方法体
+func (p *Parser) parseMethodRest(method *ClassMethod) *ClassMethod {
 	// 解析泛型类型参数 [T any]
 	if p.curTokenIs(lexer.TOKEN_LBRACKET) {
 		method.TypeParams = p.parseTypeParams()
@@ -1141,7 +1606,7 @@ func (p *Parser) parseClassMethodWithAbstract(visibility string, isStatic bool,
 		// 单个返回值类型
 		p.nextToken()
 		typ := p.parseType()
-		if typ != nil {
+		if typ != nil && !isVoidType(typ) {
 			method.Results = []*Field{{Type: typ}}
 		}
 	}
@@ -1153,7 +1618,7 @@ func (p *Parser) parseClassMethodWithAbstract(visibility string, isStatic bool,
 	}
 
 	// 方法体（抽象方法没有方法体）
-	if !isAbstract && p.peekTokenIs(lexer.TOKEN_LBRACE) {
+	if !method.Abstract && p.peekTokenIs(lexer.TOKEN_LBRACE) {
 		p.nextToken()
 		method.Body = p.parseBlockStmt()
 	}
@@ -1161,6 +1626,51 @@ func (p *Parser) parseClassMethodWithAbstract(visibility string, isStatic bool,
 	return method
 }
 
+// parseOperatorMethod 解析运算符重载 operator +(other: T) T，解析为名为 opAdd 的方法（见 OperatorMethods）
+func (p *Parser) parseOperatorMethod(visibility string, isStatic bool, isAbstract bool) *ClassMethod {
+	method := &ClassMethod{Token: p.curToken, Visibility: visibility, Static: isStatic, Abstract: isAbstract}
+	p.nextToken()
+	method.Operator = p.curToken.Literal
+	if p.curTokenIs(lexer.TOKEN_LBRACKET) && p.peekTokenIs(lexer.TOKEN_RBRACKET) {
+		p.nextToken()
+		method.Operator = "[]"
+	}
+	method.Name = OperatorMethods[method.Operator]
+	p.nextToken()
+	if p.parseMethodRest(method) == nil {
+		return nil
+	}
+
+	// 签名不合法时仍返回方法，错误已经记录
+	fail := func(format string) *ClassMethod {
+		p.errorAt(method.Token.Line, method.Token.Column, fmt.Sprintf(format, method.Operator))
+		return method
+	}
+	switch {
+	case method.Name == "":
+		return fail("operator %s cannot be overloaded, supported operators are + - * / %% == < []")
+	case visibility != "public":
+		return fail("operator %s must be public")
+	case isStatic:
+		return fail("operator %s cannot be static")
+	case method.TypeParams != nil:
+		return fail("operator %s cannot have type parameters")
+	case len(method.Params) != 1 || method.Params[0].DefaultValue != nil:
+		return fail("operator %s must take exactly one parameter without a default value")
+	case len(method.Results) != 1:
+		return fail("operator %s must return exactly one value")
+	case method.Errable:
+		return fail("operator %s cannot be errable")
+	}
+	if _, ok := method.Params[0].Type.(*Ellipsis); ok {
+		return fail("operator %s must take exactly one parameter without a default value")
+	}
+	if result, ok := method.Results[0].Type.(*Identifier); (method.Operator == "==" || method.Operator == "<") && (!ok || result.Value != "bool") {
+		return fail("operator %s must return bool")
+	}
+	return method
+}
+
 // parseStructField 解析结构体字段
 func (p *Parser) parseStructField() *StructField {
 	field := &StructField{}
@@ -1217,6 +1727,19 @@ func (p *Parser) parseInterfaceDecl(public bool) *InterfaceDecl {
 
 	// 解析方法签名
 	for !p.curTokenIs(lexer.TOKEN_RBRACE) && !p.curTokenIs(lexer.TOKEN_EOF) {
+		if p.curTokenIs(lexer.TOKEN_PROP) {
+			// 接口属性展开为 getter/setter 签名
+			if prop := p.parseClassProperty("public", false, true); prop != nil {
+				decl.Properties = append(decl.Properties, prop)
+				for _, accessor := range []*ClassMethod{prop.Getter, prop.Setter} {
+					if accessor != nil {
+						decl.Methods = append(decl.Methods, &FuncSignature{Name: accessor.Name, Params: accessor.Params, Results: accessor.Results})
+					}
+				}
+			}
+			p.nextToken()
+			continue
+		}
 		sig := p.parseFuncSignature()
 		if sig != nil {
 			decl.Methods = append(decl.Methods, sig)
@@ -1252,7 +1775,7 @@ func (p *Parser) parseFuncSignature() *FuncSignature {
 		// 单个返回值类型
 		p.nextToken()
 		typ := p.parseType()
-		if typ != nil {
+		if typ != nil && !isVoidType(typ) {
 			sig.Results = []*Field{{Type: typ}}
 		}
 	}
@@ -1294,8 +1817,8 @@ func (p *Parser) parseVarDecl() *VarDecl {
 	decl := &VarDecl{Token: p.curToken}
 	p.nextToken()
 
-	// 解析变量名
-	for p.curTokenIs(lexer.TOKEN_IDENT) {
+	// 解析变量名（与字段名一样可以是关键字，如 type、range，生成代码时会自动转义）
+	for p.isMemberNameToken() {
 		decl.Names = append(decl.Names, p.curToken.Literal)
 		if p.peekTokenIs(lexer.TOKEN_COMMA) {
 			p.nextToken()
@@ -1357,7 +1880,9 @@ func (p *Parser) parseConstDecl() *ConstDecl {
 func (p *Parser) parseReturnStmt() *ReturnStmt {
 	stmt := &ReturnStmt{Token: p.curToken}
 
-	if p.peekTokenIs(lexer.TOKEN_RBRACE) || p.peekTokenIs(lexer.TOKEN_SEMICOLON) || p.peekTokenIs(lexer.TOKEN_EOF) {
+	// 返回值必须和 return 在同一行，否则 return 之后的语句会被当作返回值
+	if p.peekTokenIs(lexer.TOKEN_RBRACE) || p.peekTokenIs(lexer.TOKEN_SEMICOLON) || p.peekTokenIs(lexer.TOKEN_EOF) ||
+		p.peekToken.Line != p.curToken.Line {
 		return stmt
 	}
 
@@ -1674,7 +2199,8 @@ func (p *Parser) parseDeferStmt() *DeferStmt {
 func (p *Parser) parseBreakStmt() *BreakStmt {
 	stmt := &BreakStmt{Token: p.curToken}
 
-	if p.peekTokenIs(lexer.TOKEN_IDENT) {
+	// 标签必须在同一行，下一行的标识符是新的语句
+	if p.peekTokenIs(lexer.TOKEN_IDENT) && p.peekToken.Line == p.curToken.Line {
 		p.nextToken()
 		stmt.Label = p.curToken.Literal
 	}
@@ -1686,7 +2212,8 @@ func (p *Parser) parseBreakStmt() *BreakStmt {
 func (p *Parser) parseContinueStmt() *ContinueStmt {
 	stmt := &ContinueStmt{Token: p.curToken}
 
-	if p.peekTokenIs(lexer.TOKEN_IDENT) {
+	// 标签必须在同一行，下一行的标识符是新的语句
+	if p.peekTokenIs(lexer.TOKEN_IDENT) && p.peekToken.Line == p.curToken.Line {
 		p.nextToken()
 		stmt.Label = p.curToken.Literal
 	}
@@ -1699,7 +2226,7 @@ func (p *Parser) parseFallthroughStmt() *FallthroughStmt {
 	return &FallthroughStmt{Token: p.curToken}
 }
 
-// parseTryStmt 解析 try-catch 语句
+// parseTryStmt 解析 try-catch-finally 语句
 func (p *Parser) parseTryStmt() *TryStmt {
 	stmt := &TryStmt{Token: p.curToken}
 
@@ -1709,23 +2236,48 @@ func (p *Parser) parseTryStmt() *TryStmt {
 	}
 	stmt.Body = p.parseBlockStmt()
 
-	// 解析 catch 子句
-	if p.peekTokenIs(lexer.TOKEN_CATCH) {
+	// 解析 catch 子句（可以有多个）
+	for p.peekTokenIs(lexer.TOKEN_CATCH) {
 		p.nextToken()
-		stmt.Catch = p.parseCatchClause()
+		clause := p.parseCatchClause()
+		if clause == nil {
+			return nil
+		}
+		stmt.Catches = append(stmt.Catches, clause)
 	}
 
-	// TODO: 未来可以添加 finally 子句支持
+	// 解析 finally 子句
+	if p.peekTokenIs(lexer.TOKEN_FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(lexer.TOKEN_LBRACE) {
+			return nil
+		}
+		stmt.Finally = p.parseBlockStmt()
+	}
 
 	return stmt
 }
 
 // parseCatchClause 解析 catch 子句
+// 支持 catch { }、catch e { }、catch (e) { } 和带类型的 catch (e NotFoundError) { }
 func (p *Parser) parseCatchClause() *CatchClause {
 	clause := &CatchClause{Token: p.curToken}
 
-	// 解析异常参数名
-	if p.peekTokenIs(lexer.TOKEN_IDENT) {
+	if p.peekTokenIs(lexer.TOKEN_LPAREN) {
+		p.nextToken()
+		if !p.expectPeek(lexer.TOKEN_IDENT) {
+			return nil
+		}
+		clause.Param = p.curToken.Literal
+		if !p.peekTokenIs(lexer.TOKEN_RPAREN) {
+			p.nextToken()
+			clause.Type = p.parseType()
+		}
+		if !p.expectPeek(lexer.TOKEN_RPAREN) {
+			return nil
+		}
+	} else if p.peekTokenIs(lexer.TOKEN_IDENT) {
+		// 解析异常参数名
 		p.nextToken()
 		clause.Param = p.curToken.Literal
 	}
@@ -1740,12 +2292,13 @@ func (p *Parser) parseCatchClause() *CatchClause {
 }
 
 // parseThrowStmt 解析 throw 语句
+// 同一行没有表达式时是重新抛出（只能用在 catch 块中）
 func (p *Parser) parseThrowStmt() *ThrowStmt {
 	stmt := &ThrowStmt{Token: p.curToken}
 
-	if p.peekTokenIs(lexer.TOKEN_RBRACE) || p.peekTokenIs(lexer.TOKEN_SEMICOLON) || p.peekTokenIs(lexer.TOKEN_EOF) {
-		p.addError("throw statement requires an expression")
-		return nil
+	if p.peekTokenIs(lexer.TOKEN_RBRACE) || p.peekTokenIs(lexer.TOKEN_SEMICOLON) || p.peekTokenIs(lexer.TOKEN_EOF) ||
+		p.peekToken.Line != p.curToken.Line {
+		return stmt
 	}
 
 	p.nextToken()
@@ -1945,6 +2498,7 @@ const (
 	_ int = iota
 	LOWEST
 	TERNARY     // ? :
+	COALESCE    // ??
 	OR          // ||
 	AND         // &&
 	EQUALS      // == !=
@@ -1958,6 +2512,7 @@ const (
 
 var precedences = map[lexer.TokenType]int{
 	lexer.TOKEN_QUESTION: TERNARY,
+	lexer.TOKEN_COALESCE: COALESCE,
 	lexer.TOKEN_OR:       OR,
 	lexer.TOKEN_AND:      AND,
 	lexer.TOKEN_EQ:       EQUALS,
@@ -1979,6 +2534,7 @@ var precedences = map[lexer.TokenType]int{
 	lexer.TOKEN_LPAREN:       CALL,
 	lexer.TOKEN_LBRACKET:    INDEX,
 	lexer.TOKEN_DOT:          INDEX,
+	lexer.TOKEN_SAFE_DOT:     INDEX,
 	lexer.TOKEN_DOUBLE_COLON: INDEX,
 	lexer.TOKEN_ELLIPSIS:     INDEX, // 后缀 ... 用于展开参数
 }
@@ -2004,7 +2560,13 @@ func (p *Parser) parseExpression(precedence int) Expression {
 	var left Expression
 
 	switch p.curToken.Type {
-	case lexer.TOKEN_IDENT:
+	case lexer.TOKEN_IDENT, lexer.TOKEN_TYPE, lexer.TOKEN_RANGE:
+		// type、range 在表达式中只能是标识符（类型断言的 .(type) 在选择器中单独处理，
+		// for 语句中的 range 子句在进入表达式之前处理）
+		if p.peekTokenIs(lexer.TOKEN_FAT_ARROW) && !p.disableLambda {
+			left = p.parseLambda()
+			break
+		}
 		left = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	case lexer.TOKEN_INT:
 		left = &IntegerLiteral{Token: p.curToken, Value: p.curToken.Literal}
@@ -2012,6 +2574,8 @@ func (p *Parser) parseExpression(precedence int) Expression {
 		left = &FloatLiteral{Token: p.curToken, Value: p.curToken.Literal}
 	case lexer.TOKEN_STRING:
 		left = &StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+	case lexer.TOKEN_INTERP_STRING:
+		left = p.parseInterpolatedString()
 	case lexer.TOKEN_CHAR:
 		left = &CharLiteral{Token: p.curToken, Value: p.curToken.Literal}
 	case lexer.TOKEN_TRUE:
@@ -2022,9 +2586,15 @@ func (p *Parser) parseExpression(precedence int) Expression {
 		left = &NilLiteral{Token: p.curToken}
 	case lexer.TOKEN_THIS:
 		left = &ThisExpr{Token: p.curToken}
+	case lexer.TOKEN_SUPER:
+		left = &SuperExpr{Token: p.curToken}
 	case lexer.TOKEN_SELF:
 		left = &SelfExpr{Token: p.curToken}
 	case lexer.TOKEN_LPAREN:
+		if !p.disableLambda && p.lambdaAhead() {
+			left = p.parseLambda()
+			break
+		}
 		left = p.parseGroupedExpression()
 	case lexer.TOKEN_LBRACKET:
 		left = p.parseArrayOrSliceLiteral()
@@ -2069,7 +2639,7 @@ func (p *Parser) parseExpression(precedence int) Expression {
 			lexer.TOKEN_PERCENT, lexer.TOKEN_EQ, lexer.TOKEN_NOT_EQ, lexer.TOKEN_LT,
 			lexer.TOKEN_GT, lexer.TOKEN_LT_EQ, lexer.TOKEN_GT_EQ, lexer.TOKEN_AND,
 			lexer.TOKEN_OR, lexer.TOKEN_BIT_AND, lexer.TOKEN_BIT_OR, lexer.TOKEN_BIT_XOR,
-			lexer.TOKEN_SHL, lexer.TOKEN_SHR:
+			lexer.TOKEN_SHL, lexer.TOKEN_SHR, lexer.TOKEN_COALESCE:
 			p.nextToken()
 			left = p.parseInfixExpression(left)
 		case lexer.TOKEN_QUESTION:
@@ -2084,6 +2654,9 @@ func (p *Parser) parseExpression(precedence int) Expression {
 		case lexer.TOKEN_DOT:
 			p.nextToken()
 			left = p.parseSelectorExpression(left)
+		case lexer.TOKEN_SAFE_DOT:
+			p.nextToken()
+			left = p.parseSafeSelectorExpression(left)
 		case lexer.TOKEN_DOUBLE_COLON:
 			p.nextToken()
 			left = p.parseStaticAccessExpression(left)
@@ -2185,18 +2758,22 @@ func (p *Parser) parseTernaryExpression(condition Expression) Expression {
 func (p *Parser) parseMatchExpression() Expression {
 	expr := &MatchExpr{Token: p.curToken}
 
-	// 期望 (
-	if !p.expectPeek(lexer.TOKEN_LPAREN) {
-		return nil
-	}
-
-	// 解析被匹配的表达式
-	p.nextToken()
-	expr.Subject = p.parseExpression(LOWEST)
+	if p.peekTokenIs(lexer.TOKEN_LPAREN) {
+		// match(expr) { ... }
+		p.nextToken()
+		p.nextToken()
+		expr.Subject = p.parseExpression(LOWEST)
 
-	// 期望 )
-	if !p.expectPeek(lexer.TOKEN_RPAREN) {
-		return nil
+		// 期望 )
+		if !p.expectPeek(lexer.TOKEN_RPAREN) {
+			return nil
+		}
+	} else {
+		// match expr { ... }，主体后面的 { 不是结构体字面量
+		p.nextToken()
+		p.disableStructLiteral = true
+		expr.Subject = p.parseExpression(LOWEST)
+		p.disableStructLiteral = false
 	}
 
 	// 期望 {
@@ -2209,12 +2786,15 @@ func (p *Parser) parseMatchExpression() Expression {
 		arm := p.parseMatchArm()
 		if arm != nil {
 			expr.Arms = append(expr.Arms, arm)
-			// 检查是否有类型模式（首字母大写的标识符）
+			// 检查是否有类型模式（首字母大写的标识符或 c Circle 形式）
 			if !arm.IsDefault && len(arm.Patterns) > 0 {
-				if ident, ok := arm.Patterns[0].(*Identifier); ok {
-					if len(ident.Value) > 0 && ident.Value[0] >= 'A' && ident.Value[0] <= 'Z' {
+				switch pattern := arm.Patterns[0].(type) {
+				case *Identifier:
+					if len(pattern.Value) > 0 && pattern.Value[0] >= 'A' && pattern.Value[0] <= 'Z' {
 						expr.IsType = true
 					}
+				case *TypePattern:
+					expr.IsType = true
 				}
 			}
 		}
@@ -2242,8 +2822,12 @@ func (p *Parser) parseMatchArm() *MatchArm {
 	if p.curTokenIs(lexer.TOKEN_DEFAULT) {
 		arm.IsDefault = true
 	} else {
+		// 模式和守卫之后的 => 是分支分隔符，不能解析成 lambda
+		saved := p.disableLambda
+		p.disableLambda = true
+
 		// 解析模式列表（可以是多个值，用逗号分隔）
-		arm.Patterns = append(arm.Patterns, p.parseExpression(LOWEST))
+		arm.Patterns = append(arm.Patterns, p.parseMatchPattern())
 
 		for p.peekTokenIs(lexer.TOKEN_COMMA) {
 			// 检查逗号后面是不是 => 或 }，如果是则这个逗号是分隔符不是模式的一部分
@@ -2253,8 +2837,16 @@ func (p *Parser) parseMatchArm() *MatchArm {
 				break
 			}
 			p.nextToken()
-			arm.Patterns = append(arm.Patterns, p.parseExpression(LOWEST))
+			arm.Patterns = append(arm.Patterns, p.parseMatchPattern())
 		}
+
+		// 守卫条件 pattern if cond
+		if p.peekTokenIs(lexer.TOKEN_IF) {
+			p.nextToken()
+			p.nextToken()
+			arm.Guard = p.parseExpression(LOWEST)
+		}
+		p.disableLambda = saved
 	}
 
 	// 期望 =>
@@ -2264,13 +2856,113 @@ func (p *Parser) parseMatchArm() *MatchArm {
 	}
 	arm.Token = p.curToken
 
-	// 解析结果表达式
+	// 块分支 => { ... }
+	if p.peekTokenIs(lexer.TOKEN_LBRACE) {
+		p.nextToken()
+		arm.Block = p.parseBlockStmt()
+		return arm
+	}
+
+	// 解析结果表达式，或只有一条语句的分支（等同于只包含这条语句的块）
 	p.nextToken()
+	if stmt := p.parseMatchArmStmt(); stmt != nil {
+		arm.Block = &BlockStmt{Token: arm.Token, Statements: []Statement{stmt}}
+		return arm
+	}
 	arm.Body = p.parseExpression(LOWEST)
+	switch {
+	case p.peekTokenIs(lexer.TOKEN_ASSIGN) || p.peekTokenIs(lexer.TOKEN_PLUS_ASSIGN) ||
+		p.peekTokenIs(lexer.TOKEN_MINUS_ASSIGN) || p.peekTokenIs(lexer.TOKEN_ASTERISK_ASSIGN) ||
+		p.peekTokenIs(lexer.TOKEN_SLASH_ASSIGN) || p.peekTokenIs(lexer.TOKEN_PERCENT_ASSIGN):
+		arm.Block = &BlockStmt{Token: arm.Token, Statements: []Statement{p.parseAssignStmt(arm.Body)}}
+		arm.Body = nil
+	case p.peekTokenIs(lexer.TOKEN_INC) || p.peekTokenIs(lexer.TOKEN_DEC):
+		p.nextToken()
+		stmt := &IncDecStmt{Token: p.curToken, X: arm.Body, Inc: p.curTokenIs(lexer.TOKEN_INC)}
+		arm.Block = &BlockStmt{Token: arm.Token, Statements: []Statement{stmt}}
+		arm.Body = nil
+	}
 
 	return arm
 }
 
+// parseMatchArmStmt 解析不带花括号的语句分支 => return x、=> throw e、=> break、=> continue
+// 分支之间可以用逗号分隔，return 只取一个返回值，多个返回值需要写成块
+func (p *Parser) parseMatchArmStmt() Statement {
+	switch p.curToken.Type {
+	case lexer.TOKEN_RETURN:
+		stmt := &ReturnStmt{Token: p.curToken}
+		if !p.peekTokenIs(lexer.TOKEN_COMMA) && !p.peekTokenIs(lexer.TOKEN_RBRACE) && p.peekToken.Line == p.curToken.Line {
+			p.nextToken()
+			stmt.Values = []Expression{p.parseExpression(LOWEST)}
+		}
+		return stmt
+	case lexer.TOKEN_THROW:
+		return p.parseThrowStmt()
+	case lexer.TOKEN_BREAK:
+		return p.parseBreakStmt()
+	case lexer.TOKEN_CONTINUE:
+		return p.parseContinueStmt()
+	}
+	return nil
+}
+
+// parseMatchPattern 解析 match 分支的模式
+// c Circle（绑定 + 类型）、Point{x: 0, y}（解构）、1..9（范围），其他为普通表达式
+func (p *Parser) parseMatchPattern() Expression {
+	if p.curTokenIs(lexer.TOKEN_IDENT) && p.peekTokenIs(lexer.TOKEN_IDENT) {
+		pattern := &TypePattern{Token: p.curToken, Binding: p.curToken.Literal}
+		p.nextToken()
+		pattern.Type = p.parseType()
+		if p.peekTokenIs(lexer.TOKEN_LBRACE) {
+			p.nextToken()
+			pattern.Fields = p.parseFieldPatterns()
+		}
+		return pattern
+	}
+	if p.curTokenIs(lexer.TOKEN_IDENT) && p.peekTokenIs(lexer.TOKEN_LBRACE) {
+		pattern := &TypePattern{Token: p.curToken, Type: &Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+		p.nextToken()
+		pattern.Fields = p.parseFieldPatterns()
+		return pattern
+	}
+
+	expr := p.parseExpression(LOWEST)
+	if p.peekTokenIs(lexer.TOKEN_DOTDOT) {
+		p.nextToken()
+		pattern := &RangePattern{Token: p.curToken, Low: expr}
+		p.nextToken()
+		pattern.High = p.parseExpression(LOWEST)
+		return pattern
+	}
+	return expr
+}
+
+// parseFieldPatterns 解析解构模式的字段列表 {x: 0, y}，当前 token 为 {
+func (p *Parser) parseFieldPatterns() []*FieldPattern {
+	fields := []*FieldPattern{}
+	for !p.peekTokenIs(lexer.TOKEN_RBRACE) {
+		p.nextToken()
+		if !p.isMemberNameToken() {
+			p.addError(fmt.Sprintf("expected field name in pattern, got %s", p.curToken.Literal))
+			return fields
+		}
+		field := &FieldPattern{Token: p.curToken, Name: p.curToken.Literal}
+		if p.peekTokenIs(lexer.TOKEN_COLON) {
+			p.nextToken()
+			p.nextToken()
+			field.Value = p.parseMatchPattern()
+		}
+		fields = append(fields, field)
+		if !p.peekTokenIs(lexer.TOKEN_COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	p.expectPeek(lexer.TOKEN_RBRACE)
+	return fields
+}
+
 // parseCallExpression 解析函数调用表达式
 func (p *Parser) parseCallExpression(function Expression) Expression {
 	expr := &CallExpr{Token: p.curToken, Function: function}
@@ -2288,12 +2980,12 @@ func (p *Parser) parseCallArguments() []Expression {
 	}
 
 	p.nextToken()
-	args = append(args, p.parseExpression(LOWEST))
+	args = append(args, p.parseCallArgument())
 
 	for p.peekTokenIs(lexer.TOKEN_COMMA) {
 		p.nextToken()
 		p.nextToken()
-		args = append(args, p.parseExpression(LOWEST))
+		args = append(args, p.parseCallArgument())
 	}
 
 	if !p.expectPeek(lexer.TOKEN_RPAREN) {
@@ -2303,6 +2995,18 @@ func (p *Parser) parseCallArguments() []Expression {
 	return args
 }
 
+// parseCallArgument 解析单个调用参数，name: value 形式为命名参数
+func (p *Parser) parseCallArgument() Expression {
+	if p.curTokenIs(lexer.TOKEN_IDENT) && p.peekTokenIs(lexer.TOKEN_COLON) {
+		arg := &NamedArg{Token: p.curToken, Name: p.curToken.Literal}
+		p.nextToken() // 消费 :
+		p.nextToken()
+		arg.Value = p.parseExpression(LOWEST)
+		return arg
+	}
+	return p.parseExpression(LOWEST)
+}
+
 // parseIndexExpression 解析索引表达式
 func (p *Parser) parseIndexExpression(left Expression) Expression {
 	token := p.curToken
@@ -2320,6 +3024,20 @@ func (p *Parser) parseIndexExpression(left Expression) Expression {
 		return p.parseSliceExpression(left, token, index)
 	}
 
+	// 多个类型实参：Pair[string, int]（用于泛型类的静态访问 Pair[string, int]::of()）
+	if p.peekTokenIs(lexer.TOKEN_COMMA) {
+		typeArgs := []Expression{index}
+		for p.peekTokenIs(lexer.TOKEN_COMMA) {
+			p.nextToken()
+			p.nextToken()
+			typeArgs = append(typeArgs, p.parseType())
+		}
+		if !p.expectPeek(lexer.TOKEN_RBRACKET) {
+			return nil
+		}
+		return &GenericType{Token: token, Type: left, TypeArgs: typeArgs}
+	}
+
 	if !p.expectPeek(lexer.TOKEN_RBRACKET) {
 		return nil
 	}
@@ -2364,21 +3082,32 @@ func (p *Parser) parseSelectorExpression(left Expression) Expression {
 		return &TypeAssertExpr{Token: token, X: left, Type: typ}
 	}
 
-	// 允许标识符和某些内置函数关键字作为选择器
-	if !p.curTokenIs(lexer.TOKEN_IDENT) && !p.isBuiltinFuncToken() {
+	// 允许标识符和关键字作为选择器（如 obj.type）
+	if !p.isMemberNameToken() {
 		return nil
 	}
 
 	return &SelectorExpr{Token: token, X: left, Sel: p.curToken.Literal}
 }
 
+// parseSafeSelectorExpression 解析安全导航 a?.b
+func (p *Parser) parseSafeSelectorExpression(left Expression) Expression {
+	token := p.curToken
+	p.nextToken()
+	if !p.isMemberNameToken() {
+		p.addError("expected member name after ?.")
+		return nil
+	}
+	return &SelectorExpr{Token: token, X: left, Sel: p.curToken.Literal, Safe: true}
+}
+
 // parseStaticAccessExpression 解析静态访问表达式 (ClassName::member 或 self::member)
 func (p *Parser) parseStaticAccessExpression(left Expression) Expression {
 	token := p.curToken
 	p.nextToken()
 
-	// 允许标识符或 class 关键字（用于 ClassName::class 获取类信息）
-	if !p.curTokenIs(lexer.TOKEN_IDENT) && !p.curTokenIs(lexer.TOKEN_CLASS) {
+	// 允许成员名或 class 关键字（用于 ClassName::class 获取类信息）
+	if !p.isMemberNameToken() {
 		p.addError("expected member name after ::")
 		return nil
 	}
@@ -2386,6 +3115,150 @@ func (p *Parser) parseStaticAccessExpression(left Expression) Expression {
 	return &StaticAccessExpr{Token: token, Left: left, Member: p.curToken.Literal}
 }
 
+// parseInterpolatedString 解析插值字符串 "Hello ${name}"
+// 嵌入的表达式由完整的表达式解析器处理，${expr:spec} 中冒号后面是格式说明
+func (p *Parser) parseInterpolatedString() Expression {
+	tok := p.curToken
+	lit := &InterpolatedString{Token: tok}
+	s := tok.Literal
+	end := len(s)
+	if end > 1 && s[end-1] == '"' {
+		end--
+	}
+
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			lit.Parts = append(lit.Parts, &InterpolationPart{Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 1; i < end; {
+		switch {
+		case s[i] == '\\' && i+1 < end:
+			// \$ 表示字面量 $，其他转义原样保留
+			if s[i+1] == '$' {
+				text.WriteByte('$')
+			} else {
+				text.WriteString(s[i : i+2])
+			}
+			i += 2
+		case s[i] == '$' && i+1 < end && s[i+1] == '{':
+			line, col := literalPos(tok, i+2)
+			closing := lexer.InterpolationEnd(s, i+2)
+			if closing < 0 {
+				p.errorAt(line, col, "unterminated ${ in string literal")
+				return lit
+			}
+			flush()
+			src, spec := splitInterpolationSpec(s[i+2 : closing])
+			if strings.TrimSpace(src) == "" {
+				p.errorAt(line, col, "empty expression in string interpolation")
+			} else if spec != "" && !isFormatSpec(spec) {
+				p.errorAt(line, col, fmt.Sprintf("invalid format spec '%s' in string interpolation", spec))
+			} else {
+				lit.Parts = append(lit.Parts, &InterpolationPart{Expr: p.parseEmbeddedExpression(src, line, col), Spec: spec})
+			}
+			i = closing + 1
+		default:
+			text.WriteByte(s[i])
+			i++
+		}
+	}
+	flush()
+	return lit
+}
+
+// parseEmbeddedExpression 用独立的解析器解析插值中的表达式，错误位置对应原文件
+func (p *Parser) parseEmbeddedExpression(src string, line, col int) Expression {
+	sub := New(lexer.NewAt(src, line, col))
+	expr := sub.parseExpression(LOWEST)
+	if !sub.peekTokenIs(lexer.TOKEN_EOF) {
+		sub.errorAt(sub.peekToken.Line, sub.peekToken.Column,
+			fmt.Sprintf("unexpected '%s' in string interpolation", sub.peekToken.Literal))
+	}
+	p.errors = append(p.errors, sub.errors...)
+	return expr
+}
+
+// errorAt 在指定位置添加错误
+func (p *Parser) errorAt(line, col int, msg string) {
+	p.errors = append(p.errors, i18n.T(i18n.ErrGeneric, line, col, msg))
+}
+
+// literalPos 计算字面量 tok 中第 offset 个字节所在的行列
+func literalPos(tok lexer.Token, offset int) (int, int) {
+	line, col := tok.Line, tok.Column
+	for _, ch := range []byte(tok.Literal[:offset]) {
+		if ch == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+// splitInterpolationSpec 把插值内容拆分为表达式和格式说明
+// 格式说明在最外层的冒号之后；:: 静态访问和三元表达式 a ? b : c 中的冒号不算
+func splitInterpolationSpec(src string) (string, string) {
+	depth, ternary := 0, 0
+	for i := 0; i < len(src); i++ {
+		switch ch := src[i]; ch {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '"', '\'', '`':
+			for i++; i < len(src) && src[i] != ch; i++ {
+				if src[i] == '\\' && ch != '`' {
+					i++
+				}
+			}
+		case '?':
+			// ?. 和 ?? 不是三元运算符
+			if i+1 < len(src) && (src[i+1] == '.' || src[i+1] == '?') {
+				i++
+			} else if depth == 0 {
+				ternary++
+			}
+		case ':':
+			if i+1 < len(src) && src[i+1] == ':' {
+				i++
+			} else if depth == 0 {
+				if ternary == 0 {
+					return src[:i], strings.TrimSpace(src[i+1:])
+				}
+				ternary--
+			}
+		}
+	}
+	return src, ""
+}
+
+// isFormatSpec 检查格式说明是否形如 fmt 的 [flags][width][.precision][verb]，如 .2f、08d、-10s
+func isFormatSpec(spec string) bool {
+	i := 0
+	for i < len(spec) && strings.IndexByte("-+# 0", spec[i]) >= 0 {
+		i++
+	}
+	for i < len(spec) && spec[i] >= '0' && spec[i] <= '9' {
+		i++
+	}
+	if i < len(spec) && spec[i] == '.' {
+		i++
+		for i < len(spec) && spec[i] >= '0' && spec[i] <= '9' {
+			i++
+		}
+	}
+	if i < len(spec) && (spec[i] >= 'a' && spec[i] <= 'z' || spec[i] >= 'A' && spec[i] <= 'Z') {
+		i++
+	}
+	return i == len(spec)
+}
+
 // parseArrayOrSliceLiteral 解析数组或切片字面量
 func (p *Parser) parseArrayOrSliceLiteral() Expression {
 	token := p.curToken
@@ -2518,7 +3391,7 @@ func (p *Parser) parseStructLiteral(typeExpr Expression) Expression {
 
 	for !p.curTokenIs(lexer.TOKEN_RBRACE) && !p.curTokenIs(lexer.TOKEN_EOF) {
 		var fv *FieldValue
-		if p.curTokenIs(lexer.TOKEN_IDENT) && p.peekTokenIs(lexer.TOKEN_COLON) {
+		if p.isMemberNameToken() && p.peekTokenIs(lexer.TOKEN_COLON) {
 			// 命名字段
 			name := p.curToken.Literal
 			p.nextToken()
@@ -2563,7 +3436,7 @@ func (p *Parser) parseFuncLiteral() Expression {
 	} else if !p.peekTokenIs(lexer.TOKEN_LBRACE) {
 		p.nextToken()
 		typ := p.parseType()
-		if typ != nil {
+		if typ != nil && !isVoidType(typ) {
 			lit.Results = []*Field{{Type: typ}}
 		}
 	}
@@ -2576,6 +3449,90 @@ func (p *Parser) parseFuncLiteral() Expression {
 	return lit
 }
 
+// lambdaAhead 判断当前的 ( 是否开始箭头 lambda 的参数列表，即匹配的 ) 之后是 =>
+func (p *Parser) lambdaAhead() bool {
+	l := *p.l
+	tok := p.peekToken
+	for depth := 1; tok.Type != lexer.TOKEN_EOF; tok = l.NextToken() {
+		switch tok.Type {
+		case lexer.TOKEN_LPAREN:
+			depth++
+		case lexer.TOKEN_RPAREN:
+			depth--
+			if depth == 0 {
+				next := l.NextToken()
+				for next.Type == lexer.TOKEN_COMMENT {
+					next = l.NextToken()
+				}
+				return next.Type == lexer.TOKEN_FAT_ARROW
+			}
+		}
+	}
+	return false
+}
+
+// parseLambda 解析箭头 lambda：x => expr、(a, b: int) => expr、() => { ... }
+func (p *Parser) parseLambda() Expression {
+	lit := &FuncLiteral{Token: p.curToken, Arrow: true}
+	if p.curTokenIs(lexer.TOKEN_IDENT) {
+		lit.Params = []*Field{{Name: p.curToken.Literal}}
+	} else {
+		for !p.peekTokenIs(lexer.TOKEN_RPAREN) {
+			if !p.expectPeek(lexer.TOKEN_IDENT) {
+				return nil
+			}
+			param := &Field{Name: p.curToken.Literal}
+			if p.peekTokenIs(lexer.TOKEN_COLON) {
+				p.nextToken()
+				p.nextToken()
+				param.Type = p.parseType()
+			}
+			lit.Params = append(lit.Params, param)
+			if !p.peekTokenIs(lexer.TOKEN_COMMA) {
+				break
+			}
+			p.nextToken()
+		}
+		if !p.expectPeek(lexer.TOKEN_RPAREN) {
+			return nil
+		}
+	}
+	if !p.expectPeek(lexer.TOKEN_FAT_ARROW) {
+		return nil
+	}
+
+	// 函数体中的 => 属于 lambda 自身，match 模式中的限制不再适用
+	saved := p.disableLambda
+	p.disableLambda = false
+	defer func() { p.disableLambda = saved }()
+
+	if p.peekTokenIs(lexer.TOKEN_LBRACE) {
+		p.nextToken()
+		lit.Body = p.parseBlockStmt()
+		return lit
+	}
+	// 表达式函数体，也可以是一条赋值或自增语句 x => total += x
+	tok := p.curToken
+	p.nextToken()
+	expr := p.parseExpression(LOWEST)
+	switch {
+	case p.peekTokenIs(lexer.TOKEN_ASSIGN) || p.peekTokenIs(lexer.TOKEN_PLUS_ASSIGN) ||
+		p.peekTokenIs(lexer.TOKEN_MINUS_ASSIGN) || p.peekTokenIs(lexer.TOKEN_ASTERISK_ASSIGN) ||
+		p.peekTokenIs(lexer.TOKEN_SLASH_ASSIGN) || p.peekTokenIs(lexer.TOKEN_PERCENT_ASSIGN):
+		lit.StmtBody = true
+		lit.Body = &BlockStmt{Token: tok, Statements: []Statement{p.parseAssignStmt(expr)}}
+	case p.peekTokenIs(lexer.TOKEN_INC) || p.peekTokenIs(lexer.TOKEN_DEC):
+		p.nextToken()
+		stmt := &IncDecStmt{Token: p.curToken, X: expr, Inc: p.curTokenIs(lexer.TOKEN_INC)}
+		lit.StmtBody = true
+		lit.Body = &BlockStmt{Token: tok, Statements: []Statement{stmt}}
+	default:
+		lit.ExprBody = true
+		lit.Body = &BlockStmt{Token: tok, Statements: []Statement{&ReturnStmt{Token: tok, Values: []Expression{expr}}}}
+	}
+	return lit
+}
+
 // parseReceiveExpression 解析接收表达式
 func (p *Parser) parseReceiveExpression() Expression {
 	token := p.curToken
@@ -2823,8 +3780,41 @@ func (p *Parser) parseChanType() Expression {
 	return ct
 }
 
+// parseQualifiedName 解析可能带包限定的名称（如 Shape、models.Shape）
+// 调用时 curToken 是第一个标识符，返回时 curToken 是最后一个标识符
+func (p *Parser) parseQualifiedName(what string) (string, bool) {
+	name := p.curToken.Literal
+	for p.peekTokenIs(lexer.TOKEN_DOT) {
+		p.nextToken() // 移动到 .
+		p.nextToken() // 移动到下一个标识符
+		if !p.curTokenIs(lexer.TOKEN_IDENT) {
+			p.addError("expected identifier after '.' in " + what)
+			return "", false
+		}
+		name += "." + p.curToken.Literal
+	}
+	return name, true
+}
+
 // parseType 解析类型
+// isVoidType 判断返回值类型是否是 void（void! 表示只返回 error）
+func isVoidType(typ Expression) bool {
+	ident, ok := typ.(*Identifier)
+	return ok && ident.Value == "void"
+}
+
 func (p *Parser) parseType() Expression {
+	typ := p.parseBaseType()
+	// T? 可空类型
+	if typ != nil && p.peekTokenIs(lexer.TOKEN_QUESTION) {
+		p.nextToken()
+		return &NullableType{Token: p.curToken, Base: typ}
+	}
+	return typ
+}
+
+// parseBaseType 解析不带 ? 后缀的类型
+func (p *Parser) parseBaseType() Expression {
 	switch p.curToken.Type {
 	case lexer.TOKEN_IDENT:
 		var result Expression = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
@@ -2854,7 +3844,8 @@ func (p *Parser) parseType() Expression {
 	case lexer.TOKEN_ASTERISK:
 		token := p.curToken
 		p.nextToken()
-		return &PointerType{Token: token, Base: p.parseType()}
+		// *T? 表示可空的 *T，? 作用于整个指针类型
+		return &PointerType{Token: token, Base: p.parseBaseType()}
 	case lexer.TOKEN_LBRACKET:
 		return p.parseArrayOrSliceType()
 	case lexer.TOKEN_MAP:
@@ -2937,10 +3928,10 @@ func (p *Parser) parseFuncType() Expression {
 	if p.peekTokenIs(lexer.TOKEN_LPAREN) {
 		p.nextToken()
 		ft.Results = p.parseFieldList(lexer.TOKEN_RPAREN)
-	} else if !p.peekTokenIs(lexer.TOKEN_EOF) && !p.peekTokenIs(lexer.TOKEN_SEMICOLON) && !p.peekTokenIs(lexer.TOKEN_COMMA) && !p.peekTokenIs(lexer.TOKEN_RPAREN) && !p.peekTokenIs(lexer.TOKEN_RBRACE) {
+	} else if !p.peekTokenIs(lexer.TOKEN_EOF) && !p.peekTokenIs(lexer.TOKEN_SEMICOLON) && !p.peekTokenIs(lexer.TOKEN_COMMA) && !p.peekTokenIs(lexer.TOKEN_RPAREN) && !p.peekTokenIs(lexer.TOKEN_RBRACE) && !p.peekTokenIs(lexer.TOKEN_ASSIGN) {
 		p.nextToken()
 		typ := p.parseType()
-		if typ != nil {
+		if typ != nil && !isVoidType(typ) {
 			ft.Results = []*Field{{Type: typ}}
 		}
 	}
@@ -0,0 +1,47 @@
+package symbol
+
+// DollarVarPrefix $ 变量转换后的名称前缀（$name -> _tugo_name）
+// 该前缀保留给编译器使用，用户代码中的标识符不能以它开头
+const DollarVarPrefix = "_tugo_"
+
+// goKeywords Go 语言关键字
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
+
+// goPredeclared Go 预声明标识符（内置类型、常量和函数）
+var goPredeclared = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true,
+	"complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"true": true, "false": true, "iota": true, "nil": true,
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true,
+	"max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+}
+
+// IsGoKeyword 检查名称是否是 Go 关键字
+func IsGoKeyword(name string) bool {
+	return goKeywords[name]
+}
+
+// IsGoPredeclared 检查名称是否是 Go 预声明标识符
+func IsGoPredeclared(name string) bool {
+	return goPredeclared[name]
+}
+
+// EscapeKeyword 转义与 Go 关键字冲突的标识符（type -> type_）
+func EscapeKeyword(name string) string {
+	if goKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
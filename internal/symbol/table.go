@@ -1,6 +1,7 @@
 package symbol
 
 import (
+	"sort"
 	"strings"
 	"unicode"
 
@@ -20,6 +21,7 @@ const (
 	SymbolMethod
 	SymbolClass
 	SymbolClassMethod
+	SymbolEnum
 )
 
 // Symbol 表示一个符号
@@ -55,11 +57,12 @@ type OverloadedMethod struct {
 
 // InterfaceInfo 存储接口的完整信息
 type InterfaceInfo struct {
-	Name    string
-	GoName  string
-	Public  bool
-	Package string
-	Methods []*parser.FuncSignature
+	Name       string
+	GoName     string
+	Public     bool
+	Package    string
+	Methods    []*parser.FuncSignature
+	Properties []*parser.ClassProperty // 属性（getter/setter 签名也在 Methods 中）
 }
 
 // ClassInfo 存储类的完整信息
@@ -68,14 +71,31 @@ type ClassInfo struct {
 	GoName          string
 	Public          bool
 	Abstract        bool
+	Sealed          bool
 	Package         string
+	TypeParams      *parser.TypeParamList // 泛型类型参数（非泛型类为 nil）
 	Extends         string              // 父类名
+	ExtendsPkg      string              // 父类所在的包（按声明文件的 use 导入解析）
 	Implements      []string            // 实现的接口
 	Fields          []*parser.ClassField
 	Methods         []*parser.ClassMethod
 	AbstractMethods []*parser.ClassMethod
+	Properties      []*parser.ClassProperty // 属性（getter/setter 也在 Methods/AbstractMethods 中）
 	InitMethod      *parser.ClassMethod
+	InitMethods     []*parser.ClassMethod // 全部 init 方法（init 重载时有多个，否则为空）
+	DataParams      []*parser.Field     // 数据类的主构造参数（非数据类为 nil）
 	SelfMethods     map[string]bool     // 需要 self 传递的方法名（用于继承时的虚方法包装）
+	GoImports       map[string]string   // 声明文件导入的 Go 包（包名 -> 路径，用于其他包中子类的继承方法包装）
+}
+
+// ExtensionInfo 扩展方法信息（extend 声明中的一个方法）
+type ExtensionInfo struct {
+	Package    string                // 声明扩展的包
+	Type       parser.Expression     // 被扩展的类型
+	TypePkg    string                // 被扩展的类型名所在的 tugo 包
+	GoPath     string                // 被扩展的类型来自 Go 包时的导入路径
+	TypeParams *parser.TypeParamList // extend 声明的类型参数
+	Method     *parser.ClassMethod
 }
 
 // Table 符号表
@@ -85,6 +105,7 @@ type Table struct {
 	interfaces     map[string]*InterfaceInfo    // key: package.name
 	classes        map[string]*ClassInfo        // key: package.name
 	overloadGroups map[string]*OverloadGroup    // key: package.receiver.name（重载方法组）
+	extensions     map[string][]*ExtensionInfo  // key: 方法名（扩展方法）
 }
 
 // New 创建一个新的符号表
@@ -95,6 +116,7 @@ func New() *Table {
 		interfaces:     make(map[string]*InterfaceInfo),
 		classes:        make(map[string]*ClassInfo),
 		overloadGroups: make(map[string]*OverloadGroup),
+		extensions:     make(map[string][]*ExtensionInfo),
 	}
 }
 
@@ -172,6 +194,31 @@ func (t *Table) GetClass(pkg, name string) *ClassInfo {
 	return t.classes[key(pkg, name)]
 }
 
+// AddExtension 添加扩展方法
+func (t *Table) AddExtension(info *ExtensionInfo) {
+	t.extensions[info.Method.Name] = append(t.extensions[info.Method.Name], info)
+	t.packages[info.Package] = true
+}
+
+// GetExtensions 获取所有包中指定名称的扩展方法（按收集顺序）
+func (t *Table) GetExtensions(name string) []*ExtensionInfo {
+	return t.extensions[name]
+}
+
+// GetAllClasses 获取所有类信息（按包名和类名排序）
+func (t *Table) GetAllClasses() []*ClassInfo {
+	keys := make([]string, 0, len(t.classes))
+	for k := range t.classes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]*ClassInfo, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, t.classes[k])
+	}
+	return result
+}
+
 // GetAll 获取所有符号
 func (t *Table) GetAll() []*Symbol {
 	result := make([]*Symbol, 0, len(t.symbols))
@@ -196,15 +243,15 @@ func (t *Table) GetByPackage(pkg string) []*Symbol {
 func ToGoName(name string, public bool) string {
 	// 处理 $ 开头的变量名
 	if strings.HasPrefix(name, "$") {
-		name = "_tugo_" + name[1:]
+		name = DollarVarPrefix + name[1:]
 	}
 
 	if public {
 		// 首字母大写
 		return capitalize(name)
 	}
-	// 确保首字母小写
-	return uncapitalize(name)
+	// 确保首字母小写，并转义 Go 关键字（如 type、range）
+	return EscapeKeyword(uncapitalize(name))
 }
 
 // capitalize 首字母大写
@@ -230,9 +277,9 @@ func uncapitalize(s string) string {
 // TransformDollarVar 转换 $ 变量名
 func TransformDollarVar(name string) string {
 	if strings.HasPrefix(name, "$") {
-		return "_tugo_" + name[1:]
+		return DollarVarPrefix + name[1:]
 	}
-	return name
+	return EscapeKeyword(name)
 }
 
 // GenerateTypeSignature 生成类型的签名字符串（用于方法重载名称修饰）
@@ -245,6 +292,12 @@ func GenerateTypeSignature(typ parser.Expression) string {
 		return t.Value
 	case *parser.PointerType:
 		return "p" + GenerateTypeSignature(t.Base)
+	case *parser.NullableType:
+		// User? 与 *User 生成相同的代码，签名也相同
+		if _, ok := t.Base.(*parser.PointerType); ok {
+			return GenerateTypeSignature(t.Base)
+		}
+		return "p" + GenerateTypeSignature(t.Base)
 	case *parser.SliceType:
 		return "s" + GenerateTypeSignature(t.Elt)
 	case *parser.ArrayType:
@@ -336,33 +389,6 @@ func (t *Table) GetOverloadGroup(pkg, receiver, name string) *OverloadGroup {
 	return t.overloadGroups[overloadKey(pkg, receiver, name)]
 }
 
-// GetOverloadedMethod 根据参数类型获取匹配的重载方法
-func (t *Table) GetOverloadedMethod(pkg, receiver, name string, argTypes []string) *Symbol {
-	group := t.GetOverloadGroup(pkg, receiver, name)
-	if group == nil {
-		return nil
-	}
-
-	// 查找完全匹配的重载
-	for _, method := range group.Methods {
-		if len(method.ParamTypes) != len(argTypes) {
-			continue
-		}
-		match := true
-		for i, pt := range method.ParamTypes {
-			if pt != argTypes[i] {
-				match = false
-				break
-			}
-		}
-		if match {
-			return method.Symbol
-		}
-	}
-
-	return nil
-}
-
 // IsMethodOverloaded 检查方法是否有重载
 func (t *Table) IsMethodOverloaded(pkg, receiver, name string) bool {
 	group := t.GetOverloadGroup(pkg, receiver, name)
@@ -383,6 +409,8 @@ type Collector struct {
 	table   *Table
 	pkg     string
 	imports map[string]string // 导入的包别名 -> 路径
+	uses    map[string]string // 当前文件 use 导入的类型名（或别名） -> 包名
+	goImports map[string]string // 当前文件导入的 Go 包（包名 -> 路径）
 }
 
 // NewCollector 创建一个新的符号收集器
@@ -396,10 +424,19 @@ func NewCollector(table *Table) *Collector {
 // CollectFile 从文件 AST 收集符号
 func (c *Collector) CollectFile(file *parser.File) {
 	c.pkg = file.Package
+	c.uses = make(map[string]string)
+	c.goImports = make(map[string]string)
 
 	// 收集导入
 	for _, imp := range file.Imports {
 		for _, spec := range imp.Specs {
+			if !spec.IsGoImport {
+				if spec.Alias != "" {
+					c.uses[spec.Alias] = spec.PkgName
+				} else {
+					c.uses[spec.TypeName] = spec.PkgName
+				}
+			}
 			path := strings.Trim(spec.Path, "\"")
 			alias := spec.Alias
 			if alias == "" {
@@ -408,6 +445,9 @@ func (c *Collector) CollectFile(file *parser.File) {
 				alias = parts[len(parts)-1]
 			}
 			c.imports[alias] = path
+			if spec.IsGoImport {
+				c.goImports[alias] = path
+			}
 		}
 	}
 
@@ -417,6 +457,20 @@ func (c *Collector) CollectFile(file *parser.File) {
 	}
 }
 
+// typePackage 返回类型名所在的包：pkg.Type 取限定包名，use 导入的类型取导入的包，否则为当前包
+func (c *Collector) typePackage(name string) string {
+	if name == "" {
+		return ""
+	}
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		return name[:dot]
+	}
+	if pkg, ok := c.uses[name]; ok {
+		return pkg
+	}
+	return c.pkg
+}
+
 // collectStatement 从语句中收集符号
 func (c *Collector) collectStatement(stmt parser.Statement) {
 	switch s := stmt.(type) {
@@ -424,6 +478,10 @@ func (c *Collector) collectStatement(stmt parser.Statement) {
 		c.collectFunc(s)
 	case *parser.StructDecl:
 		c.collectStruct(s)
+	case *parser.EnumDecl:
+		c.collectEnum(s)
+	case *parser.ExtendDecl:
+		c.collectExtend(s)
 	case *parser.ClassDecl:
 		c.collectClass(s)
 	case *parser.InterfaceDecl:
@@ -538,6 +596,69 @@ func (c *Collector) collectStruct(decl *parser.StructDecl) {
 	}
 }
 
+// collectEnum 收集枚举符号，枚举的方法按结构体方法收集
+func (c *Collector) collectEnum(decl *parser.EnumDecl) {
+	c.table.Add(&Symbol{
+		Name:    decl.Name,
+		GoName:  ToGoName(decl.Name, decl.Public),
+		Kind:    SymbolEnum,
+		Public:  decl.Public,
+		Package: c.pkg,
+	})
+
+	for _, method := range decl.Methods {
+		isPublic := method.Visibility == "public"
+		goName := ToGoName(method.Name, isPublic)
+		c.table.Add(&Symbol{
+			Name:        method.Name,
+			GoName:      goName,
+			Kind:        SymbolMethod,
+			Public:      isPublic,
+			Package:     c.pkg,
+			Receiver:    decl.Name,
+			Errable:     method.Errable,
+			ResultCount: len(method.Results),
+			MangledName: goName,
+			ParamSig:    GenerateParamSignature(method.Params),
+		})
+	}
+}
+
+// collectExtend 收集扩展方法，记录被扩展类型的来源用于判断真实成员
+func (c *Collector) collectExtend(decl *parser.ExtendDecl) {
+	typePkg, goPath := "", ""
+	base := decl.Type
+	if ptr, ok := base.(*parser.PointerType); ok {
+		base = ptr.Base
+	}
+	if generic, ok := base.(*parser.GenericType); ok {
+		base = generic.Type
+	}
+	switch t := base.(type) {
+	case *parser.Identifier:
+		typePkg = c.typePackage(t.Value)
+	case *parser.SelectorExpr:
+		if qualifier, ok := t.X.(*parser.Identifier); ok {
+			if path, isImport := c.imports[qualifier.Value]; isImport {
+				goPath = path
+			} else {
+				typePkg = qualifier.Value
+			}
+		}
+	}
+
+	for _, method := range decl.Methods {
+		c.table.AddExtension(&ExtensionInfo{
+			Package:    c.pkg,
+			Type:       decl.Type,
+			TypePkg:    typePkg,
+			GoPath:     goPath,
+			TypeParams: decl.TypeParams,
+			Method:     method,
+		})
+	}
+}
+
 // collectClass 收集类符号
 func (c *Collector) collectClass(decl *parser.ClassDecl) {
 	// 收集类本身
@@ -556,13 +677,20 @@ func (c *Collector) collectClass(decl *parser.ClassDecl) {
 		GoName:          ToGoName(decl.Name, decl.Public),
 		Public:          decl.Public,
 		Abstract:        decl.Abstract,
+		Sealed:          decl.Sealed,
 		Package:         c.pkg,
+		TypeParams:      decl.TypeParams,
 		Extends:         decl.Extends,
+		ExtendsPkg:      c.typePackage(decl.Extends),
 		Implements:      decl.Implements,
 		Fields:          decl.Fields,
 		Methods:         decl.Methods,
 		AbstractMethods: decl.AbstractMethods,
+		Properties:      decl.Properties,
 		InitMethod:      decl.InitMethod,
+		InitMethods:     decl.InitMethods,
+		DataParams:      decl.DataParams,
+		GoImports:       c.goImports,
 	}
 	c.table.AddClass(classInfo)
 
@@ -670,11 +798,12 @@ func (c *Collector) collectInterface(decl *parser.InterfaceDecl) {
 
 	// 存储接口方法签名信息
 	info := &InterfaceInfo{
-		Name:    decl.Name,
-		GoName:  ToGoName(decl.Name, decl.Public),
-		Public:  decl.Public,
-		Package: c.pkg,
-		Methods: decl.Methods,
+		Name:       decl.Name,
+		GoName:     ToGoName(decl.Name, decl.Public),
+		Public:     decl.Public,
+		Package:    c.pkg,
+		Methods:    decl.Methods,
+		Properties: decl.Properties,
 	}
 	c.table.AddInterface(info)
 }
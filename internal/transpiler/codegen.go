@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/lexer"
 	"github.com/tangzhangming/tugo/internal/parser"
 	"github.com/tangzhangming/tugo/internal/symbol"
 )
@@ -20,20 +21,38 @@ type CodeGen struct {
 	currentStaticClass *parser.ClassDecl    // 当前静态类（用于翻译 self::）
 	currentStructDecl  *parser.StructDecl   // 当前结构体（用于字段名翻译）
 	currentClassDecl   *parser.ClassDecl    // 当前类（用于字段名翻译）
+	currentEnumDecl    *parser.EnumDecl     // 当前枚举（用于翻译 this 和 self::）
+	currentExtendType  string               // 当前扩展方法的接收者类型（用于推断 this 的类型）
 	typeToPackage      map[string]string    // 类型名到包名的映射 (User -> models)
 	goImports          map[string]bool      // Go 标准库导入
 	tugoImports        map[string]string    // tugo 包导入 (合并后) pkgPath -> pkgName
 	currentFuncErrable bool                 // 当前函数是否是 errable
 	currentFuncResults []*parser.Field      // 当前函数的返回值类型
-	tryCounter         int                  // try 块计数器（用于生成唯一标签）
 	inTryBlock         bool                 // 是否在 try 块内
+	tryCtx             *tryContext          // 当前的错误出口（nil 表示错误从函数返回）
+	caughtErr          string               // 当前 catch 块捕获的错误变量（用于不带值的 throw）
+	pendingFinally     []*finallyContext    // return 之前需要执行的 finally 块（由外到内）
+	branchTargets      []*branchTarget      // 当前所在的循环、switch 和 select（由外到内）
 	methodOverloads    map[string]bool      // 当前类/结构体的重载方法名 (key: methodName)
-	varTypes           map[string]string    // 变量名到类型名的映射（用于重载解析）
-	ternaryCounter     int                  // 三元表达式计数器（用于生成唯一临时变量名）
-	matchCounter       int                  // match 表达式计数器（用于生成唯一临时变量名）
+	varTypes           map[string]string    // 变量名到类型名的映射（用于查找方法接收者）
+	localTypes         map[string]string    // 参数和局部变量的规范类型（用于重载解析）
+	localNames         map[string]bool      // 当前函数中声明过的参数和局部变量（用于识别 match 的绑定模式）
+	matchBindings      map[string]*matchBinding // match 分支中的绑定变量（翻译为断言结果或字段）
+	nullableVars       map[string]bool      // 声明为可空（T?）的参数和局部变量
+	lateVars           map[string]bool      // 没有初始值的非空类指针局部变量（赋值之前按可空处理）
+	nonNil             map[string]bool      // 当前位置已确定非 nil 的路径（空安全收窄）
+	nullReported       map[parser.Node]bool // 已报告过空引用的成员访问
+	names              *nameAllocator       // 编译器生成名称的分配器（临时变量、标签等）
 	pendingStatements  []string             // 需要在当前语句前插入的代码
 	currentFuncParams  map[string]bool      // 当前函数的参数名（用于标识符解析）
 	selfReplaceMode    bool                 // 是否处于 self 替换模式（用于虚方法包装）
+	selfPass           map[*symbol.ClassInfo]map[*parser.ClassMethod]bool // 类中需要 self 传递的方法
+	callee             parser.Expression    // 正在生成的调用的被调用表达式（x.name() 直接调用属性的 getter）
+	lambdaTypes        map[*parser.FuncLiteral]*lambdaSig // 箭头 lambda 期望的函数类型
+	lambdaReturns      *lambdaReturns       // 正在推断返回值类型的 lambda 的 return 记录
+	sourceMap          []sourceMapping      // 生成代码行到 tugo 源码位置的映射
+	scannedLen         int                  // 已统计行数的输出长度
+	scannedLines       int                  // 已统计的输出行数
 }
 
 // NewCodeGen 创建一个新的代码生成器
@@ -45,6 +64,14 @@ func NewCodeGen(t *Transpiler) *CodeGen {
 		tugoImports:     make(map[string]string),
 		methodOverloads: make(map[string]bool),
 		varTypes:        make(map[string]string),
+		localTypes:      make(map[string]string),
+		localNames:      make(map[string]bool),
+		nullableVars:    make(map[string]bool),
+		lateVars:        make(map[string]bool),
+		nonNil:          make(map[string]bool),
+		nullReported:    make(map[parser.Node]bool),
+		lambdaTypes:     make(map[*parser.FuncLiteral]*lambdaSig),
+		selfPass:        make(map[*symbol.ClassInfo]map[*parser.ClassMethod]bool),
 	}
 }
 
@@ -53,6 +80,12 @@ func (g *CodeGen) Generate(file *parser.File) string {
 	g.builder.Reset()
 
 	// 重置状态
+	g.sourceMap = nil
+	g.scannedLen = 0
+	g.scannedLines = 0
+	g.names = newNameAllocator()
+	g.names.collectFile(file)
+	g.names.collectTable(g.transpiler.table, g.transpiler.pkg)
 	g.typeToPackage = make(map[string]string)
 	g.goImports = make(map[string]bool)
 	g.tugoImports = make(map[string]string)
@@ -64,6 +97,7 @@ func (g *CodeGen) Generate(file *parser.File) string {
 			g.processImportSpec(spec)
 		}
 	}
+	g.useBuiltinClasses()
 
 	// 预扫描以确定需要哪些导入
 	g.prescan(file)
@@ -219,8 +253,10 @@ func (g *CodeGen) analyzeStmtForThisPass(stmt parser.Statement) bool {
 		if s.Body != nil && g.analyzeBlockForThisPass(s.Body) {
 			return true
 		}
-		if s.Catch != nil && s.Catch.Body != nil && g.analyzeBlockForThisPass(s.Catch.Body) {
-			return true
+		for _, clause := range s.Catches {
+			if clause.Body != nil && g.analyzeBlockForThisPass(clause.Body) {
+				return true
+			}
 		}
 		if s.Finally != nil && g.analyzeBlockForThisPass(s.Finally) {
 			return true
@@ -261,6 +297,13 @@ func (g *CodeGen) analyzeExprForThisPass(expr parser.Expression) bool {
 		}
 	case *parser.BinaryExpr:
 		return g.analyzeExprForThisPass(e.Left) || g.analyzeExprForThisPass(e.Right)
+	case *parser.InterpolatedString:
+		// 插值中的 this 作为 fmt.Sprintf 的参数传递
+		for _, part := range e.Parts {
+			if part.Expr != nil && (g.isThisExpr(part.Expr) || g.analyzeExprForThisPass(part.Expr)) {
+				return true
+			}
+		}
 	case *parser.UnaryExpr:
 		return g.analyzeExprForThisPass(e.Operand)
 	case *parser.IndexExpr:
@@ -285,6 +328,18 @@ func (g *CodeGen) analyzeExprForThisPass(expr parser.Expression) bool {
 		return g.analyzeExprForThisPass(e.Condition) ||
 			g.analyzeExprForThisPass(e.TrueExpr) ||
 			g.analyzeExprForThisPass(e.FalseExpr)
+	case *parser.MatchExpr:
+		if g.analyzeExprForThisPass(e.Subject) {
+			return true
+		}
+		for _, arm := range e.Arms {
+			if g.analyzeExprForThisPass(arm.Guard) || arm.Body != nil && (g.isThisExpr(arm.Body) || g.analyzeExprForThisPass(arm.Body)) {
+				return true
+			}
+			if arm.Block != nil && g.analyzeBlockForThisPass(arm.Block) {
+				return true
+			}
+		}
 	case *parser.StructLiteral:
 		for _, f := range e.Fields {
 			if g.analyzeExprForThisPass(f.Value) {
@@ -337,6 +392,12 @@ func (g *CodeGen) prescan(file *parser.File) {
 					g.prescanBlock(method.Body)
 				}
 			}
+			if classDecl.Data {
+				g.prescanDataClass(classDecl)
+			}
+			if !classDecl.Static && !classDecl.Abstract && classDecl.Extends != "" {
+				g.prescanInheritedWrappers(classDecl)
+			}
 		}
 
 		// 扫描结构体的方法
@@ -347,7 +408,30 @@ func (g *CodeGen) prescan(file *parser.File) {
 				}
 			}
 		}
+
+		// 扫描扩展方法
+		if extendDecl, ok := stmt.(*parser.ExtendDecl); ok {
+			for _, method := range extendDecl.Methods {
+				if method.Body != nil {
+					g.prescanBlock(method.Body)
+				}
+			}
+		}
 	}
+
+	inspect(file, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.InterpolatedString:
+			// 插值字符串翻译为 fmt.Sprintf
+			g.transpiler.SetNeedFmt(true)
+		case *parser.CatchClause:
+			// 带类型的 catch 翻译为 errors.As
+			if n.Type != nil {
+				g.goImports["errors"] = true
+			}
+		}
+		return true
+	})
 }
 
 // prescanStatement 预扫描语句
@@ -407,8 +491,13 @@ func (g *CodeGen) prescanStatement(stmt parser.Statement) {
 		if s.Body != nil {
 			g.prescanBlock(s.Body)
 		}
-		if s.Catch != nil && s.Catch.Body != nil {
-			g.prescanBlock(s.Catch.Body)
+		for _, clause := range s.Catches {
+			if clause.Body != nil {
+				g.prescanBlock(clause.Body)
+			}
+		}
+		if s.Finally != nil {
+			g.prescanBlock(s.Finally)
 		}
 	}
 }
@@ -452,6 +541,19 @@ func (g *CodeGen) prescanExpression(expr parser.Expression) {
 		g.prescanExpression(e.Low)
 		g.prescanExpression(e.High)
 		g.prescanExpression(e.Max)
+	case *parser.MatchExpr:
+		g.prescanExpression(e.Subject)
+		for _, arm := range e.Arms {
+			g.prescanExpression(arm.Guard)
+			g.prescanExpression(arm.Body)
+			if arm.Block != nil {
+				g.prescanBlock(arm.Block)
+			}
+		}
+	case *parser.FuncLiteral:
+		if e.Body != nil {
+			g.prescanBlock(e.Body)
+		}
 	}
 }
 
@@ -500,13 +602,66 @@ func (g *CodeGen) generateImports() {
 	g.writeLine("")
 }
 
+// markSource 记录当前输出位置对应的 tugo 源码位置
+func (g *CodeGen) markSource(tok lexer.Token) {
+	if tok.Line == 0 {
+		return
+	}
+	out := g.builder.String()
+	g.scannedLines += strings.Count(out[g.scannedLen:], "\n")
+	g.scannedLen = len(out)
+	g.sourceMap = append(g.sourceMap, sourceMapping{
+		goLine:   g.scannedLines + 1,
+		tugoLine: tok.Line,
+		tugoCol:  tok.Column,
+	})
+}
+
+// SourcePos 根据生成代码的行号查找对应的 tugo 源码位置
+func (g *CodeGen) SourcePos(goLine int) (int, int) {
+	line, col := 0, 0
+	for _, m := range g.sourceMap {
+		if m.goLine > goLine {
+			break
+		}
+		line, col = m.tugoLine, m.tugoCol
+	}
+	return line, col
+}
+
 // generateStatement 生成语句
 func (g *CodeGen) generateStatement(stmt parser.Statement) {
+	if tok, ok := statementToken(stmt); ok {
+		g.markSource(tok)
+	}
+	if lowered, ok := g.lowerPropertyAssign(stmt); ok {
+		if lowered != nil {
+			g.generateStatement(lowered)
+		}
+		return
+	}
+	if lowered, ok := g.lowerOperatorAssign(stmt); ok {
+		if lowered != nil {
+			g.generateStatement(lowered)
+		}
+		return
+	}
+	switch stmt.(type) {
+	case *parser.ForStmt, *parser.RangeStmt, *parser.SwitchStmt, *parser.SelectStmt, *parser.TryStmt:
+		// 语句体可能执行多次或从任意位置离开，其中赋值过的路径不再视为非 nil
+		g.forgetAssigned(stmt)
+		saved := g.copyNonNil()
+		defer func() { g.nonNil = saved }()
+	}
 	switch s := stmt.(type) {
 	case *parser.FuncDecl:
 		g.generateFuncDecl(s)
 	case *parser.StructDecl:
 		g.generateStructDecl(s)
+	case *parser.EnumDecl:
+		g.generateEnumDecl(s)
+	case *parser.ExtendDecl:
+		g.generateExtendDecl(s)
 	case *parser.ClassDecl:
 		g.generateClassDecl(s)
 	case *parser.InterfaceDecl:
@@ -550,9 +705,17 @@ func (g *CodeGen) generateStatement(stmt parser.Statement) {
 	case *parser.BlockStmt:
 		g.generateBlockStmt(s)
 	case *parser.ExpressionStmt:
+		// 语句位置的 match，分支作为语句执行
+		if m, ok := s.Expression.(*parser.MatchExpr); ok {
+			g.generateMatchStmt(s, m)
+			break
+		}
 		// 如果在 errable 函数中且表达式是 errable 调用，自动添加错误检查
-		if g.currentFuncErrable && g.isErrableCall(s.Expression) {
+		call, _ := s.Expression.(*parser.CallExpr)
+		if (g.currentFuncErrable || g.tryCtx != nil) && g.isErrableCall(s.Expression) {
 			g.generateErrableCallStmt(s.Expression)
+		} else if sel := safeSelector(s.Expression); call != nil && sel != nil {
+			g.generateSafeCallStmt(call, sel)
 		} else {
 			exprStr := g.generateExpression(s.Expression)
 			g.flushPendingStatements()
@@ -636,6 +799,7 @@ func (g *CodeGen) generateFuncDecl(decl *parser.FuncDecl) {
 		// 设置当前函数是否是 errable 及返回类型
 		g.currentFuncErrable = decl.Errable
 		g.currentFuncResults = decl.Results
+		g.trackParamTypes(decl.Params)
 		g.generateBlockStmtInline(decl.Body)
 		g.currentFuncErrable = false
 		g.currentFuncResults = nil
@@ -691,7 +855,8 @@ func (g *CodeGen) generateFuncWithDefaults(decl *parser.FuncDecl) {
 	}
 
 	g.write(funcName)
-	g.write(fmt.Sprintf("(opts %s)", optsName))
+	opts := g.names.name("opts")
+	g.write(fmt.Sprintf("(%s %s)", opts, optsName))
 
 	// 返回值
 	if len(decl.Results) > 0 {
@@ -712,11 +877,12 @@ func (g *CodeGen) generateFuncWithDefaults(decl *parser.FuncDecl) {
 	for _, param := range decl.Params {
 		varName := symbol.TransformDollarVar(param.Name)
 		fieldName := symbol.ToGoName(param.Name, true)
-		g.writeLine(fmt.Sprintf("%s := opts.%s", varName, fieldName))
+		g.writeLine(fmt.Sprintf("%s := %s.%s", varName, opts, fieldName))
 	}
 
 	// 生成函数体
 	if decl.Body != nil {
+		g.trackParamTypes(decl.Params)
 		for _, stmt := range decl.Body.Statements {
 			g.generateStatement(stmt)
 		}
@@ -864,7 +1030,8 @@ func (g *CodeGen) generateStructConstructor(decl *parser.StructDecl, structName
 		g.writeLine("")
 
 		// 生成构造函数（使用 Opts）
-		g.writeLine(fmt.Sprintf("func New__%s(opts %s) *%s {", structName, optsName, structName))
+		opts := g.names.name("opts")
+		g.writeLine(fmt.Sprintf("func New__%s(%s %s) *%s {", structName, opts, optsName, structName))
 		g.indent++
 		g.writeLine(fmt.Sprintf("s := &%s{}", structName))
 
@@ -872,7 +1039,7 @@ func (g *CodeGen) generateStructConstructor(decl *parser.StructDecl, structName
 		for _, param := range init.Params {
 			paramName := param.Name
 			goParamName := symbol.ToGoName(param.Name, true)
-			g.writeLine(fmt.Sprintf("%s := opts.%s", paramName, goParamName))
+			g.writeLine(fmt.Sprintf("%s := %s.%s", paramName, opts, goParamName))
 		}
 
 		// 生成 init 方法体（翻译 this 为 s）
@@ -916,6 +1083,8 @@ func (g *CodeGen) generateStructConstructor(decl *parser.StructDecl, structName
 
 // generateStructMethod 生成结构体方法（指针接收者）
 func (g *CodeGen) generateStructMethod(decl *parser.StructDecl, structName string, method *parser.ClassMethod) {
+	g.markSource(method.Token)
+
 	isPublic := method.Visibility == "public"
 	
 	// 使用原始结构体名进行重载查找
@@ -977,16 +1146,21 @@ func (g *CodeGen) generateClassDecl(decl *parser.ClassDecl) {
 		g.generateStaticClass(decl, className)
 		return
 	}
+	g.checkFieldInit(decl)
 
 	if decl.Abstract {
 		// 抽象类：生成接口 + 基础结构体
+		g.currentClassDecl = decl
 		g.generateAbstractClass(decl, className)
+		g.currentClassDecl = nil
 		return
 	}
 
 	if decl.Extends != "" {
 		// 子类：嵌入父类基础结构体
+		g.currentClassDecl = decl
 		g.generateChildClass(decl, className)
+		g.currentClassDecl = nil
 		return
 	}
 
@@ -1003,14 +1177,7 @@ func (g *CodeGen) generateStaticClass(decl *parser.ClassDecl, className string)
 
 	// 1. 生成静态字段（包级变量）
 	for _, field := range decl.Fields {
-		isPublic := field.Visibility == "public"
-		// 命名规则: 公开字段 -> ClassName + FieldName, 私有字段 -> _className_fieldName
-		var varName string
-		if isPublic {
-			varName = className + symbol.ToGoName(field.Name, true)
-		} else {
-			varName = "_" + strings.ToLower(decl.Name) + "_" + field.Name
-		}
+		varName := staticFieldName(className, field)
 		typeName := g.generateType(field.Type)
 		if field.Value != nil {
 			g.writeLine(fmt.Sprintf("var %s %s = %s", varName, typeName, g.generateExpression(field.Value)))
@@ -1032,9 +1199,21 @@ func (g *CodeGen) generateStaticClass(decl *parser.ClassDecl, className string)
 	g.currentStaticClass = nil
 }
 
+// staticFieldName 返回静态字段生成的包级变量名
+// 命名规则: 公开和受保护字段 -> ClassName + FieldName（子类可能在其他包中），私有字段 -> _ClassName_fieldName
+func staticFieldName(className string, field *parser.ClassField) string {
+	if field.Visibility == "public" || field.Visibility == "protected" {
+		return className + symbol.ToGoName(field.Name, true)
+	}
+	return fmt.Sprintf("_%s_%s", className, field.Name)
+}
+
 // generateStaticClassMethod 生成静态类方法（包级函数）
 func (g *CodeGen) generateStaticClassMethod(decl *parser.ClassDecl, className string, method *parser.ClassMethod) {
-	isPublic := method.Visibility == "public"
+	g.markSource(method.Token)
+
+	// protected 静态方法同样导出，供其他包中的子类调用
+	isPublic := method.Visibility == "public" || method.Visibility == "protected"
 
 	// 检查是否是重载方法
 	isOverloaded := g.transpiler.table.IsMethodOverloaded(g.transpiler.pkg, decl.Name, method.Name)
@@ -1058,11 +1237,19 @@ func (g *CodeGen) generateStaticClassMethod(decl *parser.ClassDecl, className st
 	}
 
 	// 生成泛型类型参数字符串
+	// 泛型类的静态方法是包级函数，类的类型参数在前，方法自身的类型参数在后
+	var allParams []*parser.TypeParam
+	if decl.TypeParams != nil {
+		allParams = append(allParams, decl.TypeParams.Params...)
+	}
+	if method.TypeParams != nil {
+		allParams = append(allParams, method.TypeParams.Params...)
+	}
 	var typeParams string
-	if method.TypeParams != nil && len(method.TypeParams.Params) > 0 {
+	if len(allParams) > 0 {
 		var sb strings.Builder
 		sb.WriteString("[")
-		for i, param := range method.TypeParams.Params {
+		for i, param := range allParams {
 			if i > 0 {
 				sb.WriteString(", ")
 			}
@@ -1083,13 +1270,21 @@ func (g *CodeGen) generateStaticClassMethod(decl *parser.ClassDecl, className st
 	// 返回值
 	if len(method.Results) > 0 {
 		g.write(" ")
-		if len(method.Results) == 1 && method.Results[0].Name == "" {
+		if method.Errable {
+			// errable 方法：追加 error 返回值
+			g.write("(")
+			g.generateParams(method.Results)
+			g.write(", error)")
+		} else if len(method.Results) == 1 && method.Results[0].Name == "" {
 			g.write(g.generateType(method.Results[0].Type))
 		} else {
 			g.write("(")
 			g.generateParams(method.Results)
 			g.write(")")
 		}
+	} else if method.Errable {
+		// 无返回值但是 errable：只返回 error
+		g.write(" error")
 	}
 
 	g.write(" ")
@@ -1098,17 +1293,33 @@ func (g *CodeGen) generateStaticClassMethod(decl *parser.ClassDecl, className st
 	savedReceiver := g.currentReceiver
 	savedClassDecl := g.currentClassDecl
 	savedStaticClass := g.currentStaticClass
+	savedResults := g.currentFuncResults
 	
 	g.currentReceiver = className
 	g.currentClassDecl = decl
 	g.currentStaticClass = nil // 这不是纯静态类
+	g.currentFuncErrable = method.Errable
+	g.currentFuncResults = method.Results
+	g.trackParamTypes(method.Params)
 	
-	g.generateBlockStmt(method.Body)
+	g.writeLine("{")
+	g.indent++
+	for _, s := range method.Body.Statements {
+		g.generateStatement(s)
+	}
+	// 对于 errable 方法，如果函数体不是以终止语句结束，添加 return nil
+	if method.Errable && len(method.Results) == 0 && !terminates(method.Body.Statements) {
+		g.writeLine("return nil")
+	}
+	g.indent--
+	g.writeLine("}")
 	
 	// 恢复上下文
 	g.currentReceiver = savedReceiver
 	g.currentClassDecl = savedClassDecl
 	g.currentStaticClass = savedStaticClass
+	g.currentFuncErrable = false
+	g.currentFuncResults = savedResults
 }
 
 func (g *CodeGen) generateNormalClass(decl *parser.ClassDecl, className string) {
@@ -1131,7 +1342,7 @@ func (g *CodeGen) generateNormalClass(decl *parser.ClassDecl, className string)
 	// 1. 生成静态字段（包级变量）
 	for _, field := range decl.Fields {
 		if field.Static {
-			varName := fmt.Sprintf("_%s_%s", className, field.Name)
+			varName := staticFieldName(className, field)
 			typeName := g.generateType(field.Type)
 			if field.Value != nil {
 				g.writeLine(fmt.Sprintf("var %s %s = %s", varName, typeName, g.generateExpression(field.Value)))
@@ -1204,6 +1415,12 @@ func (g *CodeGen) generateNormalClass(decl *parser.ClassDecl, className string)
 		if isEntryClass && method == mainMethod {
 			continue
 		}
+		// 数据类自动声明的方法
+		if method.Derived {
+			g.generateDataMethod(decl, className, method)
+			g.writeLine("")
+			continue
+		}
 		g.generateClassMethod(decl, className, method)
 		g.writeLine("")
 	}
@@ -1212,6 +1429,12 @@ func (g *CodeGen) generateNormalClass(decl *parser.ClassDecl, className string)
 	g.generateClassMethod_Class(decl, className)
 	g.writeLine("")
 
+	// 4.6 implements error 的类生成 As 方法，供子类的 As 方法沿继承链匹配
+	if g.transpiler.isErrorClass(g.transpiler.table.GetClass(g.transpiler.pkg, decl.Name)) {
+		g.generateErrorAs(decl, className, "")
+		g.writeLine("")
+	}
+
 	// 5. 入口类：生成 Go 的 func main()
 	if isEntryClass && mainMethod != nil {
 		g.generateGoMainFunc(decl, mainMethod)
@@ -1231,12 +1454,12 @@ func (g *CodeGen) generateClassInfo(decl *parser.ClassDecl, className string) {
 	g.tugoImports["tugo/runtime"] = "runtime"
 
 	// 生成类信息变量（使用公开格式用于跨包访问）
-	infoVarName := fmt.Sprintf("X%s_ClassInfo", className)
+	infoVarName := classInfoName(className)
 
 	// 检查是否有父类
 	if decl.Extends != "" {
-		parentClassName := symbol.ToGoName(decl.Extends, true)
-		parentInfoVar := fmt.Sprintf("X%s_ClassInfo", parentClassName)
+		parentClassName := g.classGoName(decl.Extends)
+		parentInfoVar := classInfoName(parentClassName)
 		// 如果父类在其他包，需要加包前缀
 		if pkg, ok := g.typeToPackage[decl.Extends]; ok {
 			parentInfoVar = pkg + "." + parentInfoVar
@@ -1251,7 +1474,7 @@ func (g *CodeGen) generateClassInfo(decl *parser.ClassDecl, className string) {
 
 // generateClassMethod_Class 生成 Class() 方法
 func (g *CodeGen) generateClassMethod_Class(decl *parser.ClassDecl, className string) {
-	infoVarName := fmt.Sprintf("X%s_ClassInfo", className)
+	infoVarName := classInfoName(className)
 	typeParamsUse := g.getTypeParamsUse(decl.TypeParams)
 	g.writeLine(fmt.Sprintf("func (this *%s%s) Class() *runtime.ClassInfo {", className, typeParamsUse))
 	g.indent++
@@ -1262,12 +1485,12 @@ func (g *CodeGen) generateClassMethod_Class(decl *parser.ClassDecl, className st
 
 // generateAbstractClass 生成抽象类（接口 + 基础结构体）
 func (g *CodeGen) generateAbstractClass(decl *parser.ClassDecl, className string) {
-	baseName := symbol.ToGoName(decl.Name, false) + "Base"
+	baseName := abstractBaseName(decl.Name, decl.Public)
 
 	// 1. 生成静态字段（包级变量）
 	for _, field := range decl.Fields {
 		if field.Static {
-			varName := fmt.Sprintf("_%s_%s", className, field.Name)
+			varName := staticFieldName(className, field)
 			typeName := g.generateType(field.Type)
 			if field.Value != nil {
 				g.writeLine(fmt.Sprintf("var %s %s = %s", varName, typeName, g.generateExpression(field.Value)))
@@ -1277,6 +1500,10 @@ func (g *CodeGen) generateAbstractClass(decl *parser.ClassDecl, className string
 		}
 	}
 
+	// 1.5 生成类信息变量，子类的类信息引用它作为父类
+	g.generateClassInfo(decl, className)
+	g.writeLine("")
+
 	// 2. 生成接口（包含抽象方法）
 	g.writeLine(fmt.Sprintf("type %s interface {", className))
 	g.indent++
@@ -1286,16 +1513,7 @@ func (g *CodeGen) generateAbstractClass(decl *parser.ClassDecl, className string
 		g.write(methodName + "(")
 		g.generateParams(method.Params)
 		g.write(")")
-		if len(method.Results) > 0 {
-			g.write(" ")
-			if len(method.Results) == 1 && method.Results[0].Name == "" {
-				g.write(g.generateType(method.Results[0].Type))
-			} else {
-				g.write("(")
-				g.generateParams(method.Results)
-				g.write(")")
-			}
-		}
+		g.generateMethodReturnSignature(method)
 		g.writeLine("")
 	}
 	g.indent--
@@ -1326,8 +1544,7 @@ func (g *CodeGen) generateAbstractClass(decl *parser.ClassDecl, className string
 
 // generateChildClass 生成子类（嵌入父类基础结构体）
 func (g *CodeGen) generateChildClass(decl *parser.ClassDecl, className string) {
-	parentName := symbol.ToGoName(decl.Extends, true)
-	parentBaseName := symbol.ToGoName(decl.Extends, false) + "Base"
+	parentName := g.classGoName(decl.Extends)
 
 	// 获取父类信息
 	parentInfo := g.transpiler.table.GetClass(g.transpiler.pkg, decl.Extends)
@@ -1351,12 +1568,17 @@ func (g *CodeGen) generateChildClass(decl *parser.ClassDecl, className string) {
 				}
 			}
 		}
+		// 内置类（如 Exception）
+		if pkg := g.typeToPackage[decl.Extends]; externalParentPkg == "" && pkg == builtinPkg {
+			externalParentPkg = pkg
+		}
+		parentInfo = g.lookupClass(decl.Extends)
 	}
 
 	// 1. 生成静态字段（包级变量）
 	for _, field := range decl.Fields {
 		if field.Static {
-			varName := fmt.Sprintf("_%s_%s", className, field.Name)
+			varName := staticFieldName(className, field)
 			typeName := g.generateType(field.Type)
 			if field.Value != nil {
 				g.writeLine(fmt.Sprintf("var %s %s = %s", varName, typeName, g.generateExpression(field.Value)))
@@ -1376,15 +1598,20 @@ func (g *CodeGen) generateChildClass(decl *parser.ClassDecl, className string) {
 
 	// 处理父类嵌入
 	if isExternalParent {
-		// 外部包的父类：嵌入指针
+		prefix := ""
 		if externalParentPkg != "" {
-			g.writeLine("*" + externalParentPkg + "." + parentName)
+			prefix = externalParentPkg + "."
+		}
+		if parentInfo != nil && parentInfo.Abstract {
+			// 外部包的抽象类：嵌入导出的基础结构体
+			g.writeLine(prefix + abstractBaseName(decl.Extends, parentInfo.Public))
 		} else {
-			g.writeLine("*" + parentName)
+			// 外部包的父类：嵌入指针
+			g.writeLine("*" + prefix + parentName)
 		}
 	} else if parentInfo != nil && parentInfo.Abstract {
 		// 抽象类：嵌入基础结构体
-		g.writeLine(parentBaseName)
+		g.writeLine(abstractBaseName(decl.Extends, parentInfo.Public))
 	} else {
 		// 普通类：嵌入指针
 		g.writeLine("*" + parentName)
@@ -1423,11 +1650,22 @@ func (g *CodeGen) generateChildClass(decl *parser.ClassDecl, className string) {
 	// 4.6 生成 Class() 方法
 	g.generateClassMethod_Class(decl, className)
 	g.writeLine("")
+
+	// 4.7 错误类生成 As 方法，父类是错误类时交给父类继续匹配
+	if classInfo := g.transpiler.table.GetClass(g.transpiler.pkg, decl.Name); g.transpiler.isErrorClass(classInfo) {
+		parentField := ""
+		if parentInfo := g.lookupClass(decl.Extends); parentInfo != nil && !parentInfo.Abstract && g.transpiler.isErrorClass(parentInfo) {
+			parentField = parentName
+		}
+		g.generateErrorAs(decl, className, parentField)
+		g.writeLine("")
+	}
 }
 
 // InheritedMethodInfo 继承方法信息
 type InheritedMethodInfo struct {
 	Method      *parser.ClassMethod
+	Owner       *symbol.ClassInfo // 声明方法的类
 	AccessPath  string // 访问路径，如 "User.Model" 或 "db.Model"
 	ImplClass   string // 实现类名
 	ImplPkg     string // 实现类所在包（如果是外部包）
@@ -1436,20 +1674,103 @@ type InheritedMethodInfo struct {
 
 // generateInheritedMethodWrappers 生成继承的需要 self 传递的方法的包装版本
 func (g *CodeGen) generateInheritedMethodWrappers(decl *parser.ClassDecl, className string, parentInfo *symbol.ClassInfo, externalParentPkg string, isExternalParent bool) {
-	// 收集子类已重写的方法名
+	for _, info := range g.inheritedWrappers(decl) {
+		g.generateInheritedMethodWrapperWithPath(decl, className, info)
+		g.writeLine("")
+	}
+}
+
+// inheritedWrappers 返回子类需要生成包装的继承方法：继承链上需要 self 传递、子类没有重写的方法
+func (g *CodeGen) inheritedWrappers(decl *parser.ClassDecl) []*InheritedMethodInfo {
 	overriddenMethods := make(map[string]bool)
 	for _, method := range decl.Methods {
 		overriddenMethods[method.Name] = true
 	}
+	return g.collectInheritedSelfMethods(decl, overriddenMethods)
+}
+
+// prescanInheritedWrappers 添加其他包中父类方法的包装在签名中用到的 Go 包导入（如 *gorm.DB）
+func (g *CodeGen) prescanInheritedWrappers(decl *parser.ClassDecl) {
+	for _, info := range g.inheritedWrappers(decl) {
+		if !info.IsExternal {
+			continue
+		}
+		for _, typ := range signatureTypes(info.Method) {
+			qualifiers, _ := typeNames(typ)
+			for _, q := range qualifiers {
+				if path, ok := info.Owner.GoImports[q]; ok {
+					g.goImports[path] = true
+				}
+			}
+		}
+	}
+}
 
-	// 收集整个继承链上需要 self 传递的方法
-	methodsToWrap := g.collectInheritedSelfMethods(decl, overriddenMethods)
+// qualifyOwnerTypes 生成其他包中父类方法的包装时，签名中父类所在包的类型按导入的类型生成（models.Post），
+// 返回恢复函数
+func (g *CodeGen) qualifyOwnerTypes(info *InheritedMethodInfo) func() {
+	var added []string
+	for _, typ := range signatureTypes(info.Method) {
+		_, names := typeNames(typ)
+		for _, name := range names {
+			if _, ok := g.typeToPackage[name]; ok {
+				continue
+			}
+			sym := g.transpiler.table.Get(info.Owner.Package, name)
+			if sym == nil || !sym.Public {
+				continue
+			}
+			switch sym.Kind {
+			case symbol.SymbolClass, symbol.SymbolStruct, symbol.SymbolInterface, symbol.SymbolType, symbol.SymbolEnum:
+				g.typeToPackage[name] = info.ImplPkg
+				added = append(added, name)
+			}
+		}
+	}
+	return func() {
+		for _, name := range added {
+			delete(g.typeToPackage, name)
+		}
+	}
+}
 
-	// 生成包装方法
-	for _, info := range methodsToWrap {
-		g.generateInheritedMethodWrapperWithPath(decl, className, info)
-		g.writeLine("")
+// signatureTypes 返回方法参数和返回值的类型
+func signatureTypes(method *parser.ClassMethod) []parser.Expression {
+	var types []parser.Expression
+	for _, param := range method.Params {
+		types = append(types, param.Type)
+	}
+	for _, result := range method.Results {
+		types = append(types, result.Type)
 	}
+	return types
+}
+
+// typeNames 返回类型文本中的包限定（gorm.DB 中的 gorm）和不带包限定的名字
+func typeNames(typ parser.Expression) (qualifiers, names []string) {
+	text := typeText(typ)
+	word := func(b byte) bool {
+		return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+	}
+	for i := 0; i < len(text); {
+		if !word(text[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(text) && word(text[j]) {
+			j++
+		}
+		switch {
+		case j < len(text) && text[j] == '.':
+			qualifiers = append(qualifiers, text[i:j])
+		case i > 0 && text[i-1] == '.':
+		default:
+			names = append(names, text[i:j])
+		}
+		i = j
+	}
+	return qualifiers, names
 }
 
 // collectInheritedSelfMethods 收集继承链上所有需要 self 传递的方法
@@ -1499,9 +1820,9 @@ func (g *CodeGen) collectFromAncestors(className, accessPath string, overriddenM
 
 	// 构建当前类的访问路径
 	var currentPath string
-	goClassName := symbol.ToGoName(className, true)
+	goClassName := symbol.ToGoName(className, classInfo.Public)
 	if classInfo.Abstract {
-		goClassName = symbol.ToGoName(className, false) + "Base"
+		goClassName = abstractBaseName(className, classInfo.Public)
 	}
 
 	// 构建访问路径 - 注意：Go 嵌入字段访问不需要包名前缀
@@ -1526,14 +1847,15 @@ func (g *CodeGen) collectFromAncestors(className, accessPath string, overriddenM
 		if method.Visibility == "private" {
 			continue
 		}
-		// 跳过静态方法
-		if method.Static {
+		// 跳过静态方法和泛型方法（生成为包级函数）
+		if method.Static || isGenericMethod(method) {
 			continue
 		}
 		// 检查是否需要 self 传递
-		if g.needsSelfPass(method) {
+		if g.methodNeedsSelf(classInfo, method) {
 			*result = append(*result, &InheritedMethodInfo{
 				Method:     method,
+				Owner:      classInfo,
 				AccessPath: currentPath,
 				ImplClass:  className,
 				ImplPkg:    externalPkg,
@@ -1552,6 +1874,9 @@ func (g *CodeGen) collectFromAncestors(className, accessPath string, overriddenM
 // generateInheritedMethodWrapperWithPath 生成带完整路径的继承方法包装
 func (g *CodeGen) generateInheritedMethodWrapperWithPath(decl *parser.ClassDecl, className string, info *InheritedMethodInfo) {
 	method := info.Method
+	if info.IsExternal {
+		defer g.qualifyOwnerTypes(info)()
+	}
 	isPublic := method.Visibility == "public" || method.Visibility == "protected"
 	methodName := symbol.ToGoName(method.Name, isPublic)
 
@@ -1572,14 +1897,16 @@ func (g *CodeGen) generateInheritedMethodWrapperWithPath(decl *parser.ClassDecl,
 
 	if hasDefault {
 		// 带默认参数的包装方法
-		implClassName := symbol.ToGoName(info.ImplClass, true)
+		// Opts 结构体以实现类的 Go 类型名（访问路径的最后一段）命名
+		implClassName := info.AccessPath[strings.LastIndex(info.AccessPath, ".")+1:]
 		optsName := fmt.Sprintf("%s__%s__Opts", implClassName, methodName)
 		if info.IsExternal && info.ImplPkg != "" {
 			optsName = info.ImplPkg + "." + optsName
 		}
 
+		opts := g.names.name("opts")
 		g.writeIndent()
-		g.write(fmt.Sprintf("func (%s *%s) %s(opts %s)", receiverName, className, methodName, optsName))
+		g.write(fmt.Sprintf("func (%s *%s) %s(%s %s)", receiverName, className, methodName, opts, optsName))
 		g.generateMethodReturnSignature(method)
 		g.writeLine(" {")
 		g.indent++
@@ -1589,7 +1916,7 @@ func (g *CodeGen) generateInheritedMethodWrapperWithPath(decl *parser.ClassDecl,
 		if len(method.Results) > 0 || method.Errable {
 			g.write("return ")
 		}
-		g.write(fmt.Sprintf("%s.%s.%s(%s, opts)", receiverName, info.AccessPath, implMethodName, receiverName))
+		g.write(fmt.Sprintf("%s.%s.%s(%s, %s)", receiverName, info.AccessPath, implMethodName, receiverName, opts))
 		g.writeLine("")
 
 		g.indent--
@@ -1642,10 +1969,8 @@ func (g *CodeGen) getPackageAliasFromUsePath(usePath string) string {
 	parts := strings.Split(usePath, ".")
 	if len(parts) >= 2 {
 		// tugo.db.model -> db 是包名
-		pkgPart := parts[len(parts)-2]
-		// 确保 tugo 包被导入
-		g.tugoImports["tugo/"+pkgPart] = pkgPart
-		return pkgPart
+		// use 导入的包已经由 processImportSpec 导入
+		return parts[len(parts)-2]
 	}
 	return ""
 }
@@ -1690,7 +2015,9 @@ func (g *CodeGen) generateChildClassConstructorSimple(decl *parser.ClassDecl, cl
 	g.indent++
 
 	// 创建实例
-	g.writeLine(fmt.Sprintf("t := &%s{}", className))
+	recv := g.names.name("t")
+	g.writeLine(fmt.Sprintf("%s := &%s{}", recv, className))
+	g.generateParentInit(decl, recv)
 
 	// 设置父类字段默认值
 	if parentInfo != nil && parentInfo.Abstract {
@@ -1698,7 +2025,7 @@ func (g *CodeGen) generateChildClassConstructorSimple(decl *parser.ClassDecl, cl
 			if !field.Static && field.Value != nil {
 				isPublic := field.Visibility == "public" || field.Visibility == "protected"
 				fieldName := symbol.ToGoName(field.Name, isPublic)
-				g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+				g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 			}
 		}
 	}
@@ -1708,20 +2035,20 @@ func (g *CodeGen) generateChildClassConstructorSimple(decl *parser.ClassDecl, cl
 		if !field.Static && field.Value != nil {
 			isPublic := field.Visibility == "public" || field.Visibility == "protected"
 			fieldName := symbol.ToGoName(field.Name, isPublic)
-			g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+			g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 		}
 	}
 
 	// 执行 init 方法体
 	if init.Body != nil {
-		g.currentReceiver = "t"
+		g.currentReceiver = recv
 		for _, stmt := range init.Body.Statements {
 			g.generateStatement(stmt)
 		}
 		g.currentReceiver = ""
 	}
 
-	g.writeLine("return t")
+	g.writeLine("return " + recv)
 	g.indent--
 	g.writeLine("}")
 }
@@ -1760,11 +2087,14 @@ func (g *CodeGen) generateChildClassConstructorWithDefaults(decl *parser.ClassDe
 	g.writeLine("")
 
 	// 生成构造函数
-	g.writeLine(fmt.Sprintf("func New__%s(opts %s) *%s {", className, optsName, className))
+	opts := g.names.name("opts")
+	g.writeLine(fmt.Sprintf("func New__%s(%s %s) *%s {", className, opts, optsName, className))
 	g.indent++
 
 	// 创建实例
-	g.writeLine(fmt.Sprintf("t := &%s{}", className))
+	recv := g.names.name("t")
+	g.writeLine(fmt.Sprintf("%s := &%s{}", recv, className))
+	g.generateParentInit(decl, recv)
 
 	// 设置父类字段默认值
 	if parentInfo != nil && parentInfo.Abstract {
@@ -1772,7 +2102,7 @@ func (g *CodeGen) generateChildClassConstructorWithDefaults(decl *parser.ClassDe
 			if !field.Static && field.Value != nil {
 				isPublic := field.Visibility == "public" || field.Visibility == "protected"
 				fieldName := symbol.ToGoName(field.Name, isPublic)
-				g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+				g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 			}
 		}
 	}
@@ -1782,7 +2112,7 @@ func (g *CodeGen) generateChildClassConstructorWithDefaults(decl *parser.ClassDe
 		if !field.Static && field.Value != nil {
 			isPublic := field.Visibility == "public" || field.Visibility == "protected"
 			fieldName := symbol.ToGoName(field.Name, isPublic)
-			g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+			g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 		}
 	}
 
@@ -1790,19 +2120,19 @@ func (g *CodeGen) generateChildClassConstructorWithDefaults(decl *parser.ClassDe
 	for _, param := range init.Params {
 		varName := symbol.TransformDollarVar(param.Name)
 		fieldName := symbol.ToGoName(param.Name, true)
-		g.writeLine(fmt.Sprintf("%s := opts.%s", varName, fieldName))
+		g.writeLine(fmt.Sprintf("%s := %s.%s", varName, opts, fieldName))
 	}
 
 	// 执行 init 方法体
 	if init.Body != nil {
-		g.currentReceiver = "t"
+		g.currentReceiver = recv
 		for _, stmt := range init.Body.Statements {
 			g.generateStatement(stmt)
 		}
 		g.currentReceiver = ""
 	}
 
-	g.writeLine("return t")
+	g.writeLine("return " + recv)
 	g.indent--
 	g.writeLine("}")
 }
@@ -1811,7 +2141,9 @@ func (g *CodeGen) generateChildClassConstructorWithDefaults(decl *parser.ClassDe
 func (g *CodeGen) generateChildClassDefaultConstructor(decl *parser.ClassDecl, className string, parentInfo *symbol.ClassInfo) {
 	g.writeLine(fmt.Sprintf("func New__%s() *%s {", className, className))
 	g.indent++
-	g.writeLine(fmt.Sprintf("t := &%s{}", className))
+	recv := g.names.name("t")
+	g.writeLine(fmt.Sprintf("%s := &%s{}", recv, className))
+	g.generateParentInit(decl, recv)
 
 	// 设置父类字段默认值
 	if parentInfo != nil && parentInfo.Abstract {
@@ -1819,7 +2151,7 @@ func (g *CodeGen) generateChildClassDefaultConstructor(decl *parser.ClassDecl, c
 			if !field.Static && field.Value != nil {
 				isPublic := field.Visibility == "public" || field.Visibility == "protected"
 				fieldName := symbol.ToGoName(field.Name, isPublic)
-				g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+				g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 			}
 		}
 	}
@@ -1829,15 +2161,62 @@ func (g *CodeGen) generateChildClassDefaultConstructor(decl *parser.ClassDecl, c
 		if !field.Static && field.Value != nil {
 			isPublic := field.Visibility == "public" || field.Visibility == "protected"
 			fieldName := symbol.ToGoName(field.Name, isPublic)
-			g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+			g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 		}
 	}
 
-	g.writeLine("return t")
+	g.writeLine("return " + recv)
 	g.indent--
 	g.writeLine("}")
 }
 
+// generateParentInit 初始化嵌入的父类指针
+// 父类的构造函数不需要实参时（没有 init、init 没有参数或参数都有默认值）调用它；
+// init 以 super.init(...) 开始时由该语句初始化
+func (g *CodeGen) generateParentInit(decl *parser.ClassDecl, recv string) {
+	if decl.InitMethod != nil && callsSuperInit(decl.InitMethod) {
+		return
+	}
+	// 父类可能来自其他包，按符号表中的类信息生成
+	pkg := g.getClassPackage(decl.Extends)
+	parent := g.lookupClass(decl.Extends)
+	if parent == nil || parent.Abstract || parent.TypeParams != nil {
+		return
+	}
+	prefix := ""
+	if pkg != g.transpiler.pkg {
+		prefix = pkg + "."
+	}
+	parentName := symbol.ToGoName(decl.Extends, parent.Public)
+
+	inits := classInits(parent)
+	call := ""
+	for _, init := range inits {
+		if len(init.Params) == 0 {
+			call = fmt.Sprintf("%sNew__%s()", prefix, parentName)
+		}
+	}
+	switch {
+	case len(inits) == 0:
+		call = fmt.Sprintf("%sNew__%s()", prefix, parentName)
+	case call == "" && len(inits) == 1 && allParamsDefaulted(inits[0]):
+		call = fmt.Sprintf("%sNew__%s(%sNewDefault__%s__InitOpts())", prefix, parentName, prefix, parentName)
+	}
+	if call != "" {
+		g.writeLine(fmt.Sprintf("%s.%s = %s", recv, parentName, call))
+	}
+}
+
+// allParamsDefaulted 判断方法的参数是否都有默认值
+func allParamsDefaulted(method *parser.ClassMethod) bool {
+	for _, p := range method.Params {
+		if p.DefaultValue == nil {
+			return false
+		}
+	}
+	return true
+}
+
 // generateClassConstructorForInit 为指定的 init 方法生成构造函数
 func (g *CodeGen) generateClassConstructorForInit(decl *parser.ClassDecl, className string, init *parser.ClassMethod) {
 	// 检查是否有默认参数
@@ -1874,8 +2253,8 @@ func (g *CodeGen) generateClassConstructorSimpleForInit(decl *parser.ClassDecl,
 
 	// 生成构造函数名（支持重载）
 	constructorName := "New__" + className
-	if len(init.Params) > 0 {
-		// 有参数时使用修饰名避免重复定义
+	if len(decl.InitMethods) > 1 && len(init.Params) > 0 {
+		// init 重载且有参数时使用修饰名避免重复定义（与 generateInitCall 保持一致）
 		constructorName = symbol.GenerateMangledName("New__"+className, init.Params, true)
 	}
 
@@ -1896,22 +2275,24 @@ func (g *CodeGen) generateClassConstructorSimpleForInit(decl *parser.ClassDecl,
 	g.indent++
 
 	// 创建实例
-	g.writeLine(fmt.Sprintf("t := &%s%s{}", className, typeParamsUse))
+	recv := g.names.name("t")
+	g.writeLine(fmt.Sprintf("%s := &%s%s{}", recv, className, typeParamsUse))
 
 	// 设置字段默认值
 	for _, field := range decl.Fields {
 		if !field.Static && field.Value != nil {
 			isPublic := field.Visibility == "public" || field.Visibility == "protected"
 			fieldName := symbol.ToGoName(field.Name, isPublic)
-			g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+			g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 		}
 	}
 
 	// 执行 init 方法体（替换 this 为 t）
 	if init.Body != nil {
-		g.currentReceiver = "t"
+		g.currentReceiver = recv
 		// 设置当前函数参数名
 		g.currentFuncParams = make(map[string]bool)
+		g.trackParamTypes(init.Params)
 		for _, param := range init.Params {
 			g.currentFuncParams[param.Name] = true
 		}
@@ -1922,7 +2303,7 @@ func (g *CodeGen) generateClassConstructorSimpleForInit(decl *parser.ClassDecl,
 		g.currentFuncParams = nil
 	}
 
-	g.writeLine("return t")
+	g.writeLine("return " + recv)
 	g.indent--
 	g.writeLine("}")
 }
@@ -1965,18 +2346,20 @@ func (g *CodeGen) generateClassConstructorWithDefaultsForInit(decl *parser.Class
 	g.writeLine("")
 
 	// 生成构造函数
-	g.writeLine(fmt.Sprintf("func New__%s(opts %s) *%s {", className, optsName, className))
+	opts := g.names.name("opts")
+	g.writeLine(fmt.Sprintf("func New__%s(%s %s) *%s {", className, opts, optsName, className))
 	g.indent++
 
 	// 创建实例
-	g.writeLine(fmt.Sprintf("t := &%s{}", className))
+	recv := g.names.name("t")
+	g.writeLine(fmt.Sprintf("%s := &%s{}", recv, className))
 
 	// 设置字段默认值
 	for _, field := range decl.Fields {
 		if !field.Static && field.Value != nil {
 			isPublic := field.Visibility == "public" || field.Visibility == "protected"
 			fieldName := symbol.ToGoName(field.Name, isPublic)
-			g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+			g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 		}
 	}
 
@@ -1984,14 +2367,15 @@ func (g *CodeGen) generateClassConstructorWithDefaultsForInit(decl *parser.Class
 	for _, param := range init.Params {
 		varName := symbol.TransformDollarVar(param.Name)
 		fieldName := symbol.ToGoName(param.Name, true)
-		g.writeLine(fmt.Sprintf("%s := opts.%s", varName, fieldName))
+		g.writeLine(fmt.Sprintf("%s := %s.%s", varName, opts, fieldName))
 	}
 
 	// 执行 init 方法体
 	if init.Body != nil {
-		g.currentReceiver = "t"
+		g.currentReceiver = recv
 		// 设置当前函数参数名
 		g.currentFuncParams = make(map[string]bool)
+		g.trackParamTypes(init.Params)
 		for _, param := range init.Params {
 			g.currentFuncParams[param.Name] = true
 		}
@@ -2002,7 +2386,7 @@ func (g *CodeGen) generateClassConstructorWithDefaultsForInit(decl *parser.Class
 		g.currentFuncParams = nil
 	}
 
-	g.writeLine("return t")
+	g.writeLine("return " + recv)
 	g.indent--
 	g.writeLine("}")
 }
@@ -2026,40 +2410,52 @@ func (g *CodeGen) generateGoMainFunc(decl *parser.ClassDecl, method *parser.Clas
 
 // generateDefaultConstructor 生成默认构造函数
 func (g *CodeGen) generateDefaultConstructor(decl *parser.ClassDecl, className string) {
-	g.writeLine(fmt.Sprintf("func New__%s() *%s {", className, className))
+	typeParamsDef := g.getTypeParamsDef(decl.TypeParams)
+	typeParamsUse := g.getTypeParamsUse(decl.TypeParams)
+
+	g.writeLine(fmt.Sprintf("func New__%s%s() *%s%s {", className, typeParamsDef, className, typeParamsUse))
 	g.indent++
-	g.writeLine(fmt.Sprintf("t := &%s{}", className))
+	recv := g.names.name("t")
+	g.writeLine(fmt.Sprintf("%s := &%s%s{}", recv, className, typeParamsUse))
 
 	// 设置字段默认值
 	for _, field := range decl.Fields {
 		if !field.Static && field.Value != nil {
 			isPublic := field.Visibility == "public" || field.Visibility == "protected"
 			fieldName := symbol.ToGoName(field.Name, isPublic)
-			g.writeLine(fmt.Sprintf("t.%s = %s", fieldName, g.generateExpression(field.Value)))
+			g.writeLine(fmt.Sprintf("%s.%s = %s", recv, fieldName, g.generateExpression(field.Value)))
 		}
 	}
 
-	g.writeLine("return t")
+	g.writeLine("return " + recv)
 	g.indent--
 	g.writeLine("}")
 }
 
 // generateClassMethod 生成类方法
 func (g *CodeGen) generateClassMethod(decl *parser.ClassDecl, className string, method *parser.ClassMethod) {
+	g.markSource(method.Token)
+
 	// 静态方法生成为包级函数
 	if method.Static {
 		g.generateStaticClassMethod(decl, className, method)
 		return
 	}
 
+	// 带类型参数的方法生成为包级泛型函数
+	if isGenericMethod(method) {
+		g.generateGenericClassMethod(decl, className, method)
+		return
+	}
+
 	isPublic := method.Visibility == "public" || method.Visibility == "protected"
 	methodName := symbol.ToGoName(method.Name, isPublic)
 
 	// 获取类型参数使用字符串（用于接收者类型）
 	typeParamsUse := g.getTypeParamsUse(decl.TypeParams)
 
-	// 检查是否需要 self 传递（方法体中 this 被传递给外部函数）
-	needsSelf := g.needsSelfPass(method)
+	// 检查是否需要 self 传递（方法体中 this 被传递给外部函数，或调用了被子类重写的方法）
+	needsSelf := g.methodNeedsSelf(g.transpiler.table.GetClass(g.transpiler.pkg, decl.Name), method)
 
 	// 检查是否有默认参数
 	hasDefault := false
@@ -2142,12 +2538,13 @@ func (g *CodeGen) generateClassMethodImpl(className, methodName string, method *
 
 	// 获取接收者名称（避免和参数名冲突，也要避免和 _self 冲突）
 	receiverName := getReceiverName(method.Params, "t")
-	if receiverName == "_self" {
+	selfName := g.names.name("_self")
+	if receiverName == selfName {
 		receiverName = "_recv"
 	}
 
 	g.writeIndent()
-	g.write(fmt.Sprintf("func (%s *%s) %s(_self interface{}", receiverName, className, implMethodName))
+	g.write(fmt.Sprintf("func (%s *%s) %s(%s interface{}", receiverName, className, implMethodName, selfName))
 
 	// 原有参数
 	for _, param := range method.Params {
@@ -2171,7 +2568,8 @@ func (g *CodeGen) generateClassMethodImpl(className, methodName string, method *
 		g.currentFuncResults = method.Results
 		// 设置当前函数参数名
 		g.currentFuncParams = make(map[string]bool)
-		g.currentFuncParams["_self"] = true
+		g.currentFuncParams[selfName] = true
+		g.trackParamTypes(method.Params)
 		for _, param := range method.Params {
 			g.currentFuncParams[param.Name] = true
 		}
@@ -2183,8 +2581,8 @@ func (g *CodeGen) generateClassMethodImpl(className, methodName string, method *
 		}
 		g.selfReplaceMode = false
 
-		// 对于 errable 方法，如果最后一条语句不是 return/throw，添加 return nil
-		if method.Errable && !g.lastStmtIsReturnOrThrow(method.Body.Statements) {
+		// 对于 errable 方法，如果函数体不是以终止语句结束，添加 return nil
+		if method.Errable && !terminates(method.Body.Statements) {
 			g.writeLine("return nil")
 		}
 
@@ -2273,7 +2671,8 @@ func (g *CodeGen) generateClassMethodImplWithDefaults(className, methodName stri
 
 	// 获取接收者名称
 	receiverName := getReceiverName(method.Params, "t")
-	if receiverName == "_self" {
+	selfName := g.names.name("_self")
+	if receiverName == selfName {
 		receiverName = "_recv"
 	}
 
@@ -2306,8 +2705,9 @@ func (g *CodeGen) generateClassMethodImplWithDefaults(className, methodName stri
 	g.writeLine("")
 
 	// 生成 impl 方法
+	opts := g.names.name("opts")
 	g.writeIndent()
-	g.write(fmt.Sprintf("func (%s *%s) %s(_self interface{}, opts %s)", receiverName, className, implMethodName, optsName))
+	g.write(fmt.Sprintf("func (%s *%s) %s(%s interface{}, %s %s)", receiverName, className, implMethodName, selfName, opts, optsName))
 
 	// 返回值
 	g.generateMethodReturnSignature(method)
@@ -2319,7 +2719,7 @@ func (g *CodeGen) generateClassMethodImplWithDefaults(className, methodName stri
 	for _, param := range method.Params {
 		varName := symbol.TransformDollarVar(param.Name)
 		fieldName := symbol.ToGoName(param.Name, true)
-		g.writeLine(fmt.Sprintf("%s := opts.%s", varName, fieldName))
+		g.writeLine(fmt.Sprintf("%s := %s.%s", varName, opts, fieldName))
 	}
 
 	// 方法体
@@ -2328,7 +2728,8 @@ func (g *CodeGen) generateClassMethodImplWithDefaults(className, methodName stri
 		g.currentFuncErrable = method.Errable
 		g.currentFuncResults = method.Results
 		g.currentFuncParams = make(map[string]bool)
-		g.currentFuncParams["_self"] = true
+		g.currentFuncParams[selfName] = true
+		g.trackParamTypes(method.Params)
 		for _, param := range method.Params {
 			g.currentFuncParams[param.Name] = true
 		}
@@ -2339,7 +2740,7 @@ func (g *CodeGen) generateClassMethodImplWithDefaults(className, methodName stri
 		}
 		g.selfReplaceMode = false
 
-		if method.Errable && !g.lastStmtIsReturnOrThrow(method.Body.Statements) {
+		if method.Errable && !terminates(method.Body.Statements) {
 			g.writeLine("return nil")
 		}
 
@@ -2372,8 +2773,9 @@ func (g *CodeGen) generateClassMethodWrapperWithDefaults(className, methodName s
 	// 获取接收者名称
 	receiverName := getReceiverName(method.Params, "t")
 
+	opts := g.names.name("opts")
 	g.writeIndent()
-	g.write(fmt.Sprintf("func (%s *%s) %s(opts %s)", receiverName, className, actualMethodName, optsName))
+	g.write(fmt.Sprintf("func (%s *%s) %s(%s %s)", receiverName, className, actualMethodName, opts, optsName))
 
 	// 返回值
 	g.generateMethodReturnSignature(method)
@@ -2386,7 +2788,7 @@ func (g *CodeGen) generateClassMethodWrapperWithDefaults(className, methodName s
 	if len(method.Results) > 0 || method.Errable {
 		g.write("return ")
 	}
-	g.write(fmt.Sprintf("%s.%s(%s, opts)", receiverName, implMethodName, receiverName))
+	g.write(fmt.Sprintf("%s.%s(%s, %s)", receiverName, implMethodName, receiverName, opts))
 	g.writeLine("")
 
 	g.indent--
@@ -2395,40 +2797,47 @@ func (g *CodeGen) generateClassMethodWrapperWithDefaults(className, methodName s
 
 // generateMethodReturnSignature 生成方法返回值签名
 func (g *CodeGen) generateMethodReturnSignature(method *parser.ClassMethod) {
+	g.write(g.methodResultSignature(method))
+}
+
+// methodResultSignature 返回方法签名中的返回值部分（含前导空格），没有返回值时返回空串
+func (g *CodeGen) methodResultSignature(method *parser.ClassMethod) string {
+	var sb strings.Builder
 	if len(method.Results) > 0 {
-		g.write(" ")
+		sb.WriteString(" ")
 		if method.Errable {
-			g.write("(")
+			sb.WriteString("(")
 			for i, r := range method.Results {
 				if i > 0 {
-					g.write(", ")
+					sb.WriteString(", ")
 				}
 				if r.Name != "" {
-					g.write(symbol.TransformDollarVar(r.Name))
-					g.write(" ")
+					sb.WriteString(symbol.TransformDollarVar(r.Name))
+					sb.WriteString(" ")
 				}
-				g.write(g.generateType(r.Type))
+				sb.WriteString(g.generateType(r.Type))
 			}
-			g.write(", error)")
+			sb.WriteString(", error)")
 		} else if len(method.Results) == 1 && method.Results[0].Name == "" {
-			g.write(g.generateType(method.Results[0].Type))
+			sb.WriteString(g.generateType(method.Results[0].Type))
 		} else {
-			g.write("(")
+			sb.WriteString("(")
 			for i, r := range method.Results {
 				if i > 0 {
-					g.write(", ")
+					sb.WriteString(", ")
 				}
 				if r.Name != "" {
-					g.write(symbol.TransformDollarVar(r.Name))
-					g.write(" ")
+					sb.WriteString(symbol.TransformDollarVar(r.Name))
+					sb.WriteString(" ")
 				}
-				g.write(g.generateType(r.Type))
+				sb.WriteString(g.generateType(r.Type))
 			}
-			g.write(")")
+			sb.WriteString(")")
 		}
 	} else if method.Errable {
-		g.write(" error")
+		sb.WriteString(" error")
 	}
+	return sb.String()
 }
 
 // ========== 普通方法生成 ==========
@@ -2510,6 +2919,7 @@ func (g *CodeGen) generateClassMethodSimple(className, methodName string, method
 		g.currentFuncResults = method.Results
 		// 设置当前函数参数名
 		g.currentFuncParams = make(map[string]bool)
+		g.trackParamTypes(method.Params)
 		for _, param := range method.Params {
 			g.currentFuncParams[param.Name] = true
 		}
@@ -2517,8 +2927,8 @@ func (g *CodeGen) generateClassMethodSimple(className, methodName string, method
 			g.generateStatement(stmt)
 		}
 		
-		// 对于 errable 方法，如果最后一条语句不是 return/throw，添加 return nil
-		if method.Errable && !g.lastStmtIsReturnOrThrow(method.Body.Statements) {
+		// 对于 errable 方法，如果函数体不是以终止语句结束，添加 return nil
+		if method.Errable && !terminates(method.Body.Statements) {
 			g.writeLine("return nil")
 		}
 		
@@ -2577,8 +2987,9 @@ func (g *CodeGen) generateClassMethodWithDefaults(className, methodName string,
 	g.writeLine("")
 
 	// 生成方法
+	opts := g.names.name("opts")
 	g.writeIndent()
-	g.write(fmt.Sprintf("func (%s *%s) %s(opts %s)", receiverName, className, actualMethodName, optsName))
+	g.write(fmt.Sprintf("func (%s *%s) %s(%s %s)", receiverName, className, actualMethodName, opts, optsName))
 
 	// 返回值
 	if len(method.Results) > 0 {
@@ -2625,7 +3036,7 @@ func (g *CodeGen) generateClassMethodWithDefaults(className, methodName string,
 	for _, param := range method.Params {
 		varName := symbol.TransformDollarVar(param.Name)
 		fieldName := symbol.ToGoName(param.Name, true)
-		g.writeLine(fmt.Sprintf("%s := opts.%s", varName, fieldName))
+		g.writeLine(fmt.Sprintf("%s := %s.%s", varName, opts, fieldName))
 		// 添加 _ = varName 以避免未使用变量的编译错误
 		g.writeLine(fmt.Sprintf("_ = %s", varName))
 	}
@@ -2637,6 +3048,7 @@ func (g *CodeGen) generateClassMethodWithDefaults(className, methodName string,
 		g.currentFuncResults = method.Results
 		// 设置当前函数参数名
 		g.currentFuncParams = make(map[string]bool)
+		g.trackParamTypes(method.Params)
 		for _, param := range method.Params {
 			g.currentFuncParams[param.Name] = true
 		}
@@ -2644,8 +3056,8 @@ func (g *CodeGen) generateClassMethodWithDefaults(className, methodName string,
 			g.generateStatement(stmt)
 		}
 		
-		// 对于 errable 方法，如果最后一条语句不是 return/throw，添加 return nil
-		if method.Errable && !g.lastStmtIsReturnOrThrow(method.Body.Statements) {
+		// 对于 errable 方法，如果函数体不是以终止语句结束，添加 return nil
+		if method.Errable && !terminates(method.Body.Statements) {
 			g.writeLine("return nil")
 		}
 		
@@ -2688,16 +3100,8 @@ func (g *CodeGen) generateInterfaceDecl(decl *parser.InterfaceDecl) {
 		g.write(methodName + "(")
 		g.generateParams(method.Params)
 		g.write(")")
-		if len(method.Results) > 0 {
-			g.write(" ")
-			if len(method.Results) == 1 && method.Results[0].Name == "" {
-				g.write(g.generateType(method.Results[0].Type))
-			} else {
-				g.write("(")
-				g.generateParams(method.Results)
-				g.write(")")
-			}
-		}
+		// errable 接口方法同样追加 error 返回值，与实现方法保持一致
+		g.generateMethodReturnSignature(&parser.ClassMethod{Results: method.Results, Errable: method.Errable})
 		g.writeLine("")
 	}
 	g.indent--
@@ -2746,9 +3150,35 @@ func (g *CodeGen) generateVarDecl(decl *parser.VarDecl) {
 		sb.WriteString(g.generateType(decl.Type))
 	}
 
+	// 跟踪变量类型（用于重载解析）
+	for _, name := range decl.Names {
+		switch {
+		case decl.Type != nil:
+			g.setLocalType(name, canonicalType(decl.Type))
+		case len(decl.Names) == 1 && decl.Value != nil:
+			g.setLocalType(name, g.exprType(decl.Value))
+		default:
+			g.setLocalType(name, "")
+		}
+	}
+
 	valueStr := ""
 	if decl.Value != nil {
+		g.expectLambdaType(decl.Value, decl.Type)
 		valueStr = g.generateExpression(decl.Value)
+		g.checkNonNullDecl(decl.Value, decl.Type)
+	}
+	for _, name := range decl.Names {
+		if len(decl.Names) == 1 {
+			g.declareNullable(name, decl.Type, decl.Value)
+		} else {
+			g.declareNullable(name, decl.Type, nil)
+		}
+		// 没有初始值的非空类指针在赋值之前是 nil，按可空处理，赋值后收窄为非空
+		if decl.Value == nil && g.isNonNullRef(decl.Type) {
+			g.nullableVars[name] = true
+			g.lateVars[name] = true
+		}
 	}
 
 	g.flushPendingStatements()
@@ -2782,6 +3212,11 @@ func (g *CodeGen) generateConstDecl(decl *parser.ConstDecl) {
 
 // generateShortVarDecl 生成短变量声明
 func (g *CodeGen) generateShortVarDecl(decl *parser.ShortVarDecl) {
+	// 数据类解构 x, y := p
+	if len(decl.Names) > 1 && g.generateDestructure(decl) {
+		return
+	}
+
 	names := make([]string, len(decl.Names))
 	for i, name := range decl.Names {
 		names[i] = symbol.TransformDollarVar(name)
@@ -2790,6 +3225,10 @@ func (g *CodeGen) generateShortVarDecl(decl *parser.ShortVarDecl) {
 	// 跟踪变量类型（用于重载解析）
 	if len(decl.Names) == 1 {
 		g.trackVarType(decl.Names[0], decl.Value)
+	} else {
+		for _, name := range decl.Names {
+			g.setLocalType(name, "")
+		}
 	}
 
 	// 检查Value是否是临时的ArrayLiteral（表示多值）
@@ -2799,26 +3238,71 @@ func (g *CodeGen) generateShortVarDecl(decl *parser.ShortVarDecl) {
 		for _, elem := range arrLit.Elements {
 			values = append(values, g.generateExpression(elem))
 		}
+		for i, name := range decl.Names {
+			if i < len(arrLit.Elements) && len(arrLit.Elements) == len(decl.Names) {
+				g.declareNullable(name, nil, arrLit.Elements[i])
+			} else {
+				g.declareNullable(name, nil, nil)
+			}
+		}
 		g.flushPendingStatements()
 		line := fmt.Sprintf("%s := %s", strings.Join(names, ", "), strings.Join(values, ", "))
 		g.writeLine(line)
 	} else {
 		// 单值或函数调用
 		valueStr := g.generateExpression(decl.Value)
+		for _, name := range decl.Names {
+			if len(decl.Names) == 1 {
+				g.declareNullable(name, nil, decl.Value)
+			} else {
+				g.declareNullable(name, nil, nil)
+			}
+		}
 		g.flushPendingStatements()
 		line := fmt.Sprintf("%s := %s", strings.Join(names, ", "), valueStr)
 		g.writeLine(line)
 	}
 }
 
+// trackParamTypes 跟踪参数类型，类型为类的参数（*ClassName、ClassName[T] 等）同时记录接收者类型
+func (g *CodeGen) trackParamTypes(params []*parser.Field) {
+	// 新函数开始，清空上一个函数的空安全状态和局部变量
+	g.nullableVars = make(map[string]bool)
+	g.lateVars = make(map[string]bool)
+	g.nonNil = make(map[string]bool)
+	g.localNames = make(map[string]bool)
+	for _, param := range params {
+		g.setLocalType(param.Name, paramType(param.Type))
+		typ := param.Type
+		if nullable, ok := typ.(*parser.NullableType); ok {
+			g.nullableVars[param.Name] = true
+			typ = nullable.Base
+		}
+		if ptr, ok := typ.(*parser.PointerType); ok {
+			typ = ptr.Base
+		}
+		if generic, ok := typ.(*parser.GenericType); ok {
+			typ = generic.Type
+		}
+		ident, ok := typ.(*parser.Identifier)
+		if !ok {
+			continue
+		}
+		if g.transpiler.table.GetClass(g.getClassPackage(ident.Value), ident.Value) != nil {
+			g.varTypes[param.Name] = ident.Value
+		}
+	}
+}
+
 // trackVarType 跟踪变量类型
 func (g *CodeGen) trackVarType(varName string, value parser.Expression) {
+	g.setLocalType(varName, g.exprType(value))
 	switch v := value.(type) {
 	case *parser.IntegerLiteral:
 		g.varTypes[varName] = "int"
 	case *parser.FloatLiteral:
 		g.varTypes[varName] = "float64"
-	case *parser.StringLiteral:
+	case *parser.StringLiteral, *parser.InterpolatedString:
 		g.varTypes[varName] = "string"
 	case *parser.CharLiteral:
 		g.varTypes[varName] = "rune"
@@ -2829,6 +3313,12 @@ func (g *CodeGen) trackVarType(varName string, value parser.Expression) {
 		if ident, ok := v.Type.(*parser.Identifier); ok {
 			g.varTypes[varName] = ident.Value
 		}
+		// new ClassName[T]() -> 类型是 ClassName
+		if generic, ok := v.Type.(*parser.GenericType); ok {
+			if ident, ok := generic.Type.(*parser.Identifier); ok {
+				g.varTypes[varName] = ident.Value
+			}
+		}
 	case *parser.CallExpr:
 		// NewClassName() 或 NewStructName() -> 类型是类名/结构体名
 		if ident, ok := v.Function.(*parser.Identifier); ok {
@@ -2856,6 +3346,12 @@ func (g *CodeGen) trackVarType(varName string, value parser.Expression) {
 
 // generateAssignStmt 生成赋值语句
 func (g *CodeGen) generateAssignStmt(stmt *parser.AssignStmt) {
+	if stmt.Token.Literal == "=" && len(stmt.Left) == len(stmt.Right) {
+		for i, target := range stmt.Left {
+			g.expectLambdaText(stmt.Right[i], g.exprType(target))
+		}
+	}
+
 	var left []string
 	for _, expr := range stmt.Left {
 		left = append(left, g.generateExpression(expr))
@@ -2866,12 +3362,34 @@ func (g *CodeGen) generateAssignStmt(stmt *parser.AssignStmt) {
 		right = append(right, g.generateExpression(expr))
 	}
 
+	for i, target := range stmt.Left {
+		switch {
+		case stmt.Token.Literal != "=":
+		case len(stmt.Left) == len(stmt.Right):
+			g.assignNullable(target, stmt.Right[i])
+		default:
+			if path := stablePath(target); path != "" {
+				g.forgetPath(path)
+			}
+		}
+	}
+
 	g.flushPendingStatements()
 	g.writeLine(strings.Join(left, ", ") + " " + stmt.Token.Literal + " " + strings.Join(right, ", "))
 }
 
 // generateReturnStmt 生成 return 语句
 func (g *CodeGen) generateReturnStmt(stmt *parser.ReturnStmt) {
+	for i, v := range stmt.Values {
+		if i < len(g.currentFuncResults) {
+			g.expectLambdaType(v, g.currentFuncResults[i].Type)
+		}
+	}
+	g.noteLambdaReturn(stmt)
+	if len(g.pendingFinally) > 0 {
+		g.generateReturnWithFinally(stmt)
+		return
+	}
 	if len(stmt.Values) == 0 {
 		if g.currentFuncErrable {
 			// errable 函数无返回值：return nil
@@ -2883,8 +3401,11 @@ func (g *CodeGen) generateReturnStmt(stmt *parser.ReturnStmt) {
 	}
 
 	var values []string
-	for _, v := range stmt.Values {
+	for i, v := range stmt.Values {
 		values = append(values, g.generateExpression(v))
+		if i < len(g.currentFuncResults) {
+			g.checkNonNullDecl(v, g.currentFuncResults[i].Type)
+		}
 	}
 
 	g.flushPendingStatements()
@@ -2904,41 +3425,19 @@ func (g *CodeGen) generateReturnStmt(stmt *parser.ReturnStmt) {
 
 // generateThrowStmt 生成 throw 语句
 func (g *CodeGen) generateThrowStmt(stmt *parser.ThrowStmt) {
-	// throw expr 翻译为 return zeroValues, expr
-	// 需要根据当前函数的返回值数量生成零值
-	errorExpr := g.generateExpression(stmt.Value)
-	
-	// 简化处理：假设当前在 errable 函数中
-	// 生成零值（可以根据实际返回类型优化）
-	g.writeLine("return " + g.generateZeroValues() + errorExpr)
-}
-
-// lastStmtIsReturnOrThrow 检查语句列表的最后一条语句是否是 return 或 throw
-func (g *CodeGen) lastStmtIsReturnOrThrow(stmts []parser.Statement) bool {
-	if len(stmts) == 0 {
-		return false
-	}
-	lastStmt := stmts[len(stmts)-1]
-	switch s := lastStmt.(type) {
-	case *parser.ReturnStmt:
-		return true
-	case *parser.ThrowStmt:
-		return true
-	case *parser.IfStmt:
-		// 检查if和else分支是否都以return/throw结束
-		if s.Consequence != nil && g.lastStmtIsReturnOrThrow(s.Consequence.Statements) {
-			if s.Alternative != nil {
-				if alt, ok := s.Alternative.(*parser.BlockStmt); ok {
-					return g.lastStmtIsReturnOrThrow(alt.Statements)
-				}
-				if alt, ok := s.Alternative.(*parser.IfStmt); ok {
-					return g.lastStmtIsReturnOrThrow([]parser.Statement{alt})
-				}
-			}
+	// throw expr 在 try 块内翻译为设置错误变量并 break，否则翻译为 return zeroValues, expr
+	if stmt.Value == nil {
+		// 不带值的 throw 重新抛出当前 catch 块捕获的错误
+		if g.caughtErr == "" {
+			g.transpiler.AddError(stmt.Token.Line, stmt.Token.Column, i18n.T(i18n.ErrRethrowOutsideCatch))
+			return
 		}
-		return false
+		g.raiseError(g.caughtErr, stmt.Token.Line, stmt.Token.Column)
+		return
 	}
-	return false
+	errorExpr := g.generateExpression(stmt.Value)
+	g.flushPendingStatements()
+	g.raiseError(errorExpr, stmt.Token.Line, stmt.Token.Column)
 }
 
 // generateZeroValues 生成零值列表（用于 throw）
@@ -2985,18 +3484,192 @@ func (g *CodeGen) getTypeZeroValue(typ parser.Expression) string {
 	case "byte", "rune":
 		return "0"
 	default:
+		// 泛型类型参数的零值只能通过 *new(T) 表示
+		if g.isTypeParam(typeName) {
+			return "*new(" + typeName + ")"
+		}
 		// 指针、接口、切片、map 等引用类型
 		return "nil"
 	}
 }
 
-// generateTryStmt 生成 try-catch 语句
-func (g *CodeGen) generateTryStmt(stmt *parser.TryStmt) {
-	// 检测 try 块内是否有需要错误处理的代码
-	needsErrorHandling := g.tryBlockNeedsErrorHandling(stmt.Body.Statements)
-
-	if !needsErrorHandling {
-		// 简化模式：try 块内没有 throw 或 errable 调用，直接生成普通代码块
+// isTypeParam 检查名称是否是当前类或结构体的泛型类型参数
+func (g *CodeGen) isTypeParam(name string) bool {
+	var params *parser.TypeParamList
+	if g.currentClassDecl != nil {
+		params = g.currentClassDecl.TypeParams
+	} else if g.currentStructDecl != nil {
+		params = g.currentStructDecl.TypeParams
+	}
+	if params == nil {
+		return false
+	}
+	for _, param := range params.Params {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// tryContext 是 try 块（以及带 finally 时的 catch 块）的错误出口：
+// 抛出的错误赋给 errVar，然后跳出 label 标记的单次 for 循环
+type tryContext struct {
+	label  string
+	errVar string
+}
+
+// finallyContext 是 return、break、continue 之前需要先执行的 finally 块
+type finallyContext struct {
+	block  *parser.BlockStmt
+	tryCtx *tryContext // finally 块所在位置的错误出口
+}
+
+// branchTarget 是不带标签的 break / continue 的目标（循环、switch 或 select）
+type branchTarget struct {
+	loop         bool   // 是否是循环（continue 只能以循环为目标）
+	label        string // 语句体的 try 块中有以它为目标的 break / continue 时生成的标签
+	finallyDepth int    // 进入时外层 try 中待执行的 finally 块数
+}
+
+// enterBranchTarget 进入循环（loop）或 switch、select，body 是语句体
+// try 块翻译为单次 for 循环，其中的 break / continue 需要带标签才能跳到这条语句
+func (g *CodeGen) enterBranchTarget(loop bool, body []parser.Statement) {
+	target := &branchTarget{loop: loop, finallyDepth: len(g.pendingFinally)}
+	if branchesInTry(body, true, loop) {
+		if loop {
+			target.label = g.names.fresh("_Loop_%d")
+		} else {
+			target.label = g.names.fresh("_Switch_%d")
+		}
+		g.writeLine(target.label + ":")
+	}
+	g.branchTargets = append(g.branchTargets, target)
+}
+
+// leaveBranchTarget 离开循环、switch 或 select
+func (g *CodeGen) leaveBranchTarget() {
+	g.branchTargets = g.branchTargets[:len(g.branchTargets)-1]
+}
+
+// generateBranch 生成不带标签的 break / continue：先由内到外执行目标之内的 finally 块，再跳转到目标
+func (g *CodeGen) generateBranch(keyword string, loop bool) {
+	for i := len(g.branchTargets) - 1; i >= 0; i-- {
+		target := g.branchTargets[i]
+		if loop && !target.loop {
+			continue
+		}
+		g.runPendingFinally(target.finallyDepth)
+		if target.label != "" {
+			keyword += " " + target.label
+		}
+		break
+	}
+	g.writeLine(keyword)
+}
+
+// branchesInTry 判断语句中是否有位于 try 或 catch 块中、不带标签的 break（breaks）或 continue（continues），
+// 嵌套的循环、switch 和 select 中以它们自身为目标的不算
+func branchesInTry(stmts []parser.Statement, breaks, continues bool) bool {
+	found := false
+	for _, stmt := range stmts {
+		inspect(stmt, func(node parser.Node) bool {
+			switch n := node.(type) {
+			case *parser.TryStmt:
+				blocks := []*parser.BlockStmt{n.Body}
+				for _, clause := range n.Catches {
+					blocks = append(blocks, clause.Body)
+				}
+				for _, block := range blocks {
+					if block != nil && hasBranch(block.Statements, breaks, continues) {
+						found = true
+					}
+				}
+				if n.Finally != nil && branchesInTry(n.Finally.Statements, breaks, continues) {
+					found = true
+				}
+				return false
+			case *parser.SwitchStmt, *parser.SelectStmt:
+				if continues && branchesInTry(caseBodies(n), false, true) {
+					found = true
+				}
+				return false
+			case *parser.ForStmt, *parser.RangeStmt, *parser.FuncLiteral:
+				return false
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// hasBranch 判断语句中是否有不带标签的 break（breaks）或 continue（continues），规则同 branchesInTry
+func hasBranch(stmts []parser.Statement, breaks, continues bool) bool {
+	found := false
+	for _, stmt := range stmts {
+		inspect(stmt, func(node parser.Node) bool {
+			switch n := node.(type) {
+			case *parser.BreakStmt:
+				found = found || breaks && n.Label == ""
+			case *parser.ContinueStmt:
+				found = found || continues && n.Label == ""
+			case *parser.SwitchStmt, *parser.SelectStmt:
+				if continues && hasBranch(caseBodies(n), false, true) {
+					found = true
+				}
+				return false
+			case *parser.ForStmt, *parser.RangeStmt, *parser.FuncLiteral:
+				return false
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// caseBodies 返回 switch 或 select 所有分支的语句
+func caseBodies(node parser.Node) []parser.Statement {
+	var body []parser.Statement
+	switch n := node.(type) {
+	case *parser.SwitchStmt:
+		for _, c := range n.Cases {
+			body = append(body, c.Body...)
+		}
+	case *parser.SelectStmt:
+		for _, c := range n.Cases {
+			body = append(body, c.Body...)
+		}
+	}
+	return body
+}
+
+// generateTryStmt 生成 try-catch-finally 语句
+//
+// try 块翻译为只执行一次的 labeled for 循环，throw 和失败的 errable 调用把错误赋给错误变量后跳出循环：
+//
+//	{
+//		var _tryErr_1 error
+//	_TryBlock_1:
+//		for _once := true; _once; _once = false { ... }
+//		if _tryErr_1 != nil {
+//			if e := (*notFound)(nil); errors.As(_tryErr_1, &e) { ... } else { e := _tryErr_1; ... }
+//		}
+//	}
+//
+// 带类型的 catch 用 errors.As 匹配，按书写顺序选择第一个匹配的子句；没有子句匹配时错误继续抛出
+// （跳转到外层 try，或从 errable 函数返回）。有 finally 时先清除错误变量再执行 catch 块，
+// catch 块中抛出的错误同样先记录下来，执行完 finally 后再继续抛出。
+// try 和 catch 块中的 return、break、continue 在跳转前先执行 finally；panic 不会触发 finally。
+func (g *CodeGen) generateTryStmt(stmt *parser.TryStmt) {
+	catches := g.reachableCatches(stmt.Catches)
+	outerCtx := g.tryCtx
+	if stmt.Finally != nil {
+		g.pendingFinally = append(g.pendingFinally, &finallyContext{block: stmt.Finally, tryCtx: outerCtx})
+	}
+
+	// 检测 try 块内是否有需要错误处理的代码
+	if !g.tryBlockNeedsErrorHandling(stmt.Body.Statements) {
+		// 简化模式：try 块内没有 throw 或 errable 调用，直接生成普通代码块，catch 块不会被执行，可以省略
 		g.writeLine("{")
 		g.indent++
 		for _, s := range stmt.Body.Statements {
@@ -3004,14 +3677,18 @@ func (g *CodeGen) generateTryStmt(stmt *parser.TryStmt) {
 		}
 		g.indent--
 		g.writeLine("}")
-		// catch 块不会被执行，可以省略
+		if stmt.Finally != nil {
+			g.generateFinally(stmt.Finally)
+			if tryTerminates(stmt) {
+				g.writeLine(`panic("unreachable")`)
+			}
+		}
 		return
 	}
 
 	// 完整模式：需要错误处理机制
-	g.tryCounter++
-	labelName := fmt.Sprintf("_TryBlock_%d", g.tryCounter)
-	errVarName := fmt.Sprintf("_tryErr_%d", g.tryCounter)
+	labelName := g.names.fresh("_TryBlock_%d")
+	errVarName := g.names.fresh("_tryErr_%d")
 
 	// 开始一个新的作用域
 	g.writeLine("{")
@@ -3022,46 +3699,297 @@ func (g *CodeGen) generateTryStmt(stmt *parser.TryStmt) {
 
 	// 生成 labeled for 循环
 	g.writeLine(fmt.Sprintf("%s:", labelName))
-	g.writeLine("for _once := true; _once; _once = false {")
+	once := g.names.name("_once")
+	g.writeLine(fmt.Sprintf("for %s := true; %s; %s = false {", once, once, once))
 	g.indent++
 
 	// 设置 inTryBlock 标志
 	oldInTryBlock := g.inTryBlock
 	g.inTryBlock = true
+	g.tryCtx = &tryContext{label: labelName, errVar: errVarName}
 
 	// 生成 try 块中的语句
 	// 这里需要特殊处理，为 errable 调用插入错误检查
-	tmpCounter := 0
-	g.generateTryBlockStatements(stmt.Body.Statements, labelName, errVarName, &tmpCounter)
+	g.generateTryBlockStatements(stmt.Body.Statements, labelName, errVarName)
 
+	g.tryCtx = outerCtx
 	g.inTryBlock = oldInTryBlock
 	g.indent--
 	g.writeLine("}")
 
-	// 生成 catch 块
-	if stmt.Catch != nil {
+	// 生成 catch 块，没有 catch 和 finally 的 try 忽略错误
+	propagate := false
+	if len(catches) > 0 {
+		propagate = g.generateCatchClauses(stmt, catches, errVarName)
+	} else {
+		propagate = stmt.Finally != nil
+	}
+
+	if stmt.Finally != nil {
+		g.generateFinally(stmt.Finally)
+		if propagate {
+			g.writeLine(fmt.Sprintf("if %s != nil {", errVarName))
+			g.indent++
+			g.raiseError(errVarName, stmt.Token.Line, stmt.Token.Column)
+			g.indent--
+			g.writeLine("}")
+		}
+	}
+
+	// try 块和 catch 块都终止时，Go 无法从 labeled for 循环推断出这一点，
+	// 补上 panic 使生成的代码块同样是终止语句（否则 Go 报告 missing return）
+	if tryTerminates(stmt) {
+		g.writeLine(`panic("unreachable")`)
+	}
+
+	g.indent--
+	g.writeLine("}")
+}
+
+// reachableCatches 返回可达的 catch 子句，捕获所有错误的子句之后的子句不可达
+func (g *CodeGen) reachableCatches(catches []*parser.CatchClause) []*parser.CatchClause {
+	for i, clause := range catches {
+		if isCatchAll(clause) && i+1 < len(catches) {
+			next := catches[i+1]
+			g.transpiler.AddError(next.Token.Line, next.Token.Column, i18n.T(i18n.ErrUnreachableCatch))
+			return catches[:i+1]
+		}
+	}
+	return catches
+}
+
+// isCatchAll 判断 catch 子句是否捕获所有错误（没有类型或类型为 error）
+func isCatchAll(clause *parser.CatchClause) bool {
+	if clause.Type == nil {
+		return true
+	}
+	ident, ok := clause.Type.(*parser.Identifier)
+	return ok && ident.Value == "error"
+}
+
+// generateCatchClauses 生成 catch 子句，返回错误变量在 catch 之后是否可能仍然非 nil（需要继续抛出）
+func (g *CodeGen) generateCatchClauses(stmt *parser.TryStmt, catches []*parser.CatchClause, errVarName string) bool {
+	catchAll := isCatchAll(catches[len(catches)-1])
+	outerCtx := g.tryCtx
+
+	// 没有 finally 时 catch 块直接在外层的错误出口中执行，未匹配的错误立即继续抛出
+	if stmt.Finally == nil {
 		g.writeLine(fmt.Sprintf("if %s != nil {", errVarName))
 		g.indent++
+		g.generateCatchChain(catches, errVarName, "", func() {
+			g.raiseError(errVarName, stmt.Token.Line, stmt.Token.Column)
+		})
+		g.indent--
+		g.writeLine("}")
+		return false
+	}
 
-		// 将错误赋值给 catch 参数
-		if stmt.Catch.Param != "" {
-			g.writeLine(fmt.Sprintf("%s := %s", stmt.Catch.Param, errVarName))
+	// 有 finally 时先取出错误，catch 块抛出的错误记录在错误变量中，执行完 finally 再继续抛出
+	catchRaises := false
+	for _, clause := range catches {
+		if clause.Body != nil && g.tryBlockNeedsErrorHandling(clause.Body.Statements) {
+			catchRaises = true
 		}
+	}
+	label := ""
+	if catchRaises {
+		label = g.names.fresh("_Catch_%d")
+		g.writeLine(fmt.Sprintf("%s:", label))
+		once := g.names.name("_once")
+		g.writeLine(fmt.Sprintf("for %s := true; %s; %s = false {", once, once, once))
+		g.indent++
+		g.tryCtx = &tryContext{label: label, errVar: errVarName}
+	}
 
-		// 生成 catch 块体
-		if stmt.Catch.Body != nil {
-			for _, s := range stmt.Catch.Body.Statements {
-				g.generateStatement(s)
-			}
-		}
+	caught := g.names.fresh("_caught_%d")
+	g.writeLine(fmt.Sprintf("if %s != nil {", errVarName))
+	g.indent++
+	g.writeLine(fmt.Sprintf("%s := %s", caught, errVarName))
+	g.writeLine(fmt.Sprintf("%s = nil", errVarName))
+	g.generateCatchChain(catches, caught, label, func() {
+		g.writeLine(fmt.Sprintf("%s = %s", errVarName, caught))
+	})
+	g.indent--
+	g.writeLine("}")
 
+	if catchRaises {
+		g.tryCtx = outerCtx
 		g.indent--
 		g.writeLine("}")
 	}
+	return !catchAll || catchRaises
+}
+
+// generateCatchChain 生成按顺序匹配 caught 的 if-else 链，没有捕获所有错误的子句时在最后的 else 中调用 unmatched
+// label 非空时 catch 块中的语句按 try 块的方式生成（错误跳出 label 标记的循环）
+func (g *CodeGen) generateCatchChain(catches []*parser.CatchClause, caught string, label string, unmatched func()) {
+	oldCaught := g.caughtErr
+	g.caughtErr = caught
+	defer func() { g.caughtErr = oldCaught }()
+
+	open := false
+	for _, clause := range catches {
+		if isCatchAll(clause) {
+			if open {
+				g.indent--
+				g.writeLine("} else {")
+				g.indent++
+			}
+			if clause.Param != "" && clause.Param != "_" {
+				g.setLocalType(clause.Param, "")
+				g.writeLine(fmt.Sprintf("%s := %s", clause.Param, caught))
+				// catch 块没有使用参数时避免 Go 报告变量未使用（由 unused-catch-error 规则提示）
+				if !usesName(clause.Body, clause.Param) {
+					g.writeLine(fmt.Sprintf("_ = %s", clause.Param))
+				}
+			}
+			g.generateCatchBody(clause, label)
+			if open {
+				g.indent--
+				g.writeLine("}")
+			}
+			return
+		}
+
+		target := clause.Param
+		if target == "" || target == "_" {
+			target = g.names.fresh("_target%d")
+		} else {
+			g.setLocalType(target, "")
+		}
+		cond := fmt.Sprintf("%s := %s; errors.As(%s, &%s)", target, g.catchTargetZero(clause.Type), caught, target)
+		if open {
+			g.indent--
+			g.writeLine(fmt.Sprintf("} else if %s {", cond))
+		} else {
+			g.writeLine(fmt.Sprintf("if %s {", cond))
+			open = true
+		}
+		g.indent++
+		g.generateCatchBody(clause, label)
+	}
+
+	g.indent--
+	g.writeLine("} else {")
+	g.indent++
+	unmatched()
+	g.indent--
+	g.writeLine("}")
+}
+
+// catchTargetZero 返回 errors.As 目标变量的初始值，tugo 类按指针匹配
+func (g *CodeGen) catchTargetZero(typ parser.Expression) string {
+	goType := g.generateType(typ)
+	if g.isClassRef("*" + canonicalType(typ)) {
+		goType = "*" + goType
+	}
+	if strings.HasPrefix(goType, "*") {
+		return "(" + goType + ")(nil)"
+	}
+	return "*new(" + goType + ")"
+}
 
+// generateCatchBody 生成 catch 块
+func (g *CodeGen) generateCatchBody(clause *parser.CatchClause, label string) {
+	if clause.Body == nil {
+		return
+	}
+	if label != "" {
+		g.generateTryBlockStatements(clause.Body.Statements, label, g.tryCtx.errVar)
+		return
+	}
+	for _, s := range clause.Body.Statements {
+		g.generateStatement(s)
+	}
+}
+
+// generateFinally 生成 finally 块，finally 块在 try 语句所在位置的错误出口中执行
+func (g *CodeGen) generateFinally(block *parser.BlockStmt) {
+	g.pendingFinally = g.pendingFinally[:len(g.pendingFinally)-1]
+	g.writeLine("{")
+	g.indent++
+	for _, s := range block.Statements {
+		g.generateStatement(s)
+	}
 	g.indent--
 	g.writeLine("}")
-	// tryCounter持续递增，不需要递减
+}
+
+// raiseError 生成抛出错误的代码：在 try 块内跳转到 catch，否则从 errable 函数返回
+func (g *CodeGen) raiseError(errExpr string, line, column int) {
+	switch {
+	case g.tryCtx != nil:
+		g.writeLine(fmt.Sprintf("%s = %s", g.tryCtx.errVar, errExpr))
+		g.writeLine(fmt.Sprintf("break %s", g.tryCtx.label))
+	case g.currentFuncErrable:
+		g.writeLine("return " + g.generateZeroValues() + errExpr)
+	default:
+		g.transpiler.AddError(line, column, i18n.T(i18n.ErrUncaughtError))
+		g.writeLine("panic(" + errExpr + ")")
+	}
+}
+
+// generateReturnWithFinally 生成外层 try 带有 finally 时的 return：
+// 返回值先保存到临时变量，然后由内到外执行 finally 块，最后返回
+func (g *CodeGen) generateReturnWithFinally(stmt *parser.ReturnStmt) {
+	var names []string
+	switch {
+	case len(stmt.Values) == 0:
+	case len(stmt.Values) == len(g.currentFuncResults):
+		for i, v := range stmt.Values {
+			name := g.names.fresh("_ret%d")
+			value := g.generateExpression(v)
+			g.checkNonNullDecl(v, g.currentFuncResults[i].Type)
+			g.flushPendingStatements()
+			g.writeLine(fmt.Sprintf("var %s %s = %s", name, g.generateType(g.currentFuncResults[i].Type), value))
+			names = append(names, name)
+		}
+		if g.currentFuncErrable {
+			names = append(names, "nil")
+		}
+	default:
+		// 返回多值函数调用的结果（包括 errable 调用自带的 error）
+		count := len(g.currentFuncResults)
+		if g.currentFuncErrable {
+			count++
+		}
+		for i := 0; i < count; i++ {
+			names = append(names, g.names.fresh("_ret%d"))
+		}
+		value := g.generateExpression(stmt.Values[0])
+		g.flushPendingStatements()
+		g.writeLine(fmt.Sprintf("%s := %s", strings.Join(names, ", "), value))
+	}
+
+	g.runPendingFinally(0)
+
+	switch {
+	case len(names) > 0:
+		g.writeLine("return " + strings.Join(names, ", "))
+	case g.currentFuncErrable:
+		g.writeLine("return nil")
+	default:
+		g.writeLine("return")
+	}
+}
+
+// runPendingFinally 由内到外执行外层 try 中第 depth 个及以内的 finally 块（用于 return、break、continue）
+func (g *CodeGen) runPendingFinally(depth int) {
+	pending := g.pendingFinally
+	outerCtx := g.tryCtx
+	for i := len(pending) - 1; i >= depth; i-- {
+		g.pendingFinally = pending[:i]
+		g.tryCtx = pending[i].tryCtx
+		g.writeLine("{")
+		g.indent++
+		for _, s := range pending[i].block.Statements {
+			g.generateStatement(s)
+		}
+		g.indent--
+		g.writeLine("}")
+	}
+	g.pendingFinally = pending
+	g.tryCtx = outerCtx
 }
 
 // tryBlockNeedsErrorHandling 检测 try 块内是否有需要错误处理的代码
@@ -3130,19 +4058,47 @@ func (g *CodeGen) statementNeedsErrorHandling(stmt parser.Statement) bool {
 		if g.tryBlockNeedsErrorHandling(s.Statements) {
 			return true
 		}
+	case *parser.TryStmt:
+		// 嵌套 try 没有捕获的错误和 catch、finally 块中抛出的错误继续向外抛出
+		if s.Finally != nil && g.tryBlockNeedsErrorHandling(s.Finally.Statements) {
+			return true
+		}
+		if len(s.Catches) == 0 && s.Finally == nil || !g.tryBlockNeedsErrorHandling(s.Body.Statements) {
+			return false
+		}
+		catchAll := false
+		for _, clause := range s.Catches {
+			if clause.Body != nil && g.tryBlockNeedsErrorHandling(clause.Body.Statements) {
+				return true
+			}
+			catchAll = catchAll || isCatchAll(clause)
+		}
+		return !catchAll
 	}
 	return false
 }
 
 // generateTryBlockStatements 生成 try 块中的语句，为 errable 调用插入错误检查
-func (g *CodeGen) generateTryBlockStatements(statements []parser.Statement, labelName string, errVarName string, tmpCounter *int) {
+func (g *CodeGen) generateTryBlockStatements(statements []parser.Statement, labelName string, errVarName string) {
 	for _, stmt := range statements {
-		g.generateTryBlockStatement(stmt, labelName, errVarName, tmpCounter)
+		g.generateTryBlockStatement(stmt, labelName, errVarName)
 	}
 }
 
 // generateTryBlockStatement 生成 try 块中的单个语句
-func (g *CodeGen) generateTryBlockStatement(stmt parser.Statement, labelName string, errVarName string, tmpCounter *int) {
+func (g *CodeGen) generateTryBlockStatement(stmt parser.Statement, labelName string, errVarName string) {
+	if lowered, ok := g.lowerPropertyAssign(stmt); ok {
+		if lowered != nil {
+			g.generateTryBlockStatement(lowered, labelName, errVarName)
+		}
+		return
+	}
+	if lowered, ok := g.lowerOperatorAssign(stmt); ok {
+		if lowered != nil {
+			g.generateTryBlockStatement(lowered, labelName, errVarName)
+		}
+		return
+	}
 	switch s := stmt.(type) {
 	case *parser.ShortVarDecl:
 		// 短变量声明：a, b := errableFunc()
@@ -3169,14 +4125,15 @@ func (g *CodeGen) generateTryBlockStatement(stmt parser.Statement, labelName str
 				names[i] = symbol.TransformDollarVar(name)
 			}
 			
-			// 追加 _err
-			line := fmt.Sprintf("%s, _err := %s", strings.Join(names, ", "), g.generateExpression(s.Value))
+			// 追加错误变量
+			errName := g.names.fresh("_err%d")
+			line := fmt.Sprintf("%s, %s := %s", strings.Join(names, ", "), errName, g.generateExpression(s.Value))
 			g.writeLine(line)
 
 			// 插入错误检查
-			g.writeLine("if _err != nil {")
+			g.writeLine(fmt.Sprintf("if %s != nil {", errName))
 			g.indent++
-			g.writeLine(fmt.Sprintf("%s = _err", errVarName))
+			g.writeLine(fmt.Sprintf("%s = %s", errVarName, errName))
 			g.writeLine(fmt.Sprintf("break %s", labelName))
 			g.indent--
 			g.writeLine("}")
@@ -3200,9 +4157,14 @@ func (g *CodeGen) generateTryBlockStatement(stmt parser.Statement, labelName str
 				left = append(left, g.generateExpression(expr))
 			}
 
+			// 普通赋值时错误变量需要先声明
+			errName := g.names.fresh("_err%d")
+			if s.Token.Literal != ":=" {
+				g.writeLine(fmt.Sprintf("var %s error", errName))
+			}
 			g.writeIndent()
 			g.write(strings.Join(left, ", "))
-			g.write(", _err ")
+			g.write(", " + errName + " ")
 			g.write(s.Token.Literal)
 			g.write(" ")
 
@@ -3214,9 +4176,9 @@ func (g *CodeGen) generateTryBlockStatement(stmt parser.Statement, labelName str
 			g.write("\n")
 
 			// 插入错误检查
-			g.writeLine("if _err != nil {")
+			g.writeLine(fmt.Sprintf("if %s != nil {", errName))
 			g.indent++
-			g.writeLine(fmt.Sprintf("%s = _err", errVarName))
+			g.writeLine(fmt.Sprintf("%s = %s", errVarName, errName))
 			g.writeLine(fmt.Sprintf("break %s", labelName))
 			g.indent--
 			g.writeLine("}")
@@ -3239,25 +4201,25 @@ func (g *CodeGen) generateTryBlockStatement(stmt parser.Statement, labelName str
 			}
 			
 			// 单返回值，生成带错误检查的调用
+			errName := g.names.fresh("_err%d")
 			g.writeIndent()
 			if resultCount == 1 {
-				g.write("_, _err := ")
-			} else {
-				g.write("_err := ")
+				g.write("_, ")
 			}
+			g.write(errName + " := ")
 			g.write(g.generateExpression(s.Expression))
 			g.write("\n")
 
 			// 插入错误检查
-			g.writeLine("if _err != nil {")
+			g.writeLine(fmt.Sprintf("if %s != nil {", errName))
 			g.indent++
-			g.writeLine(fmt.Sprintf("%s = _err", errVarName))
+			g.writeLine(fmt.Sprintf("%s = %s", errVarName, errName))
 			g.writeLine(fmt.Sprintf("break %s", labelName))
 			g.indent--
 			g.writeLine("}")
 		} else if g.containsErrableCall(s.Expression) {
 			// 包含嵌套的 errable 调用，需要提取（包括多返回值的情况）
-			newExpr := g.extractErrableCalls(s.Expression, labelName, errVarName, tmpCounter)
+			newExpr := g.extractErrableCalls(s.Expression, labelName, errVarName)
 			// 生成替换后的表达式语句
 			g.writeIndent()
 			g.write(g.generateExpressionFromExtracted(newExpr))
@@ -3267,9 +4229,7 @@ func (g *CodeGen) generateTryBlockStatement(stmt parser.Statement, labelName str
 		}
 	case *parser.ThrowStmt:
 		// 在 try 块内的 throw，设置错误变量并 break
-		errorExpr := g.generateExpression(s.Value)
-		g.writeLine(fmt.Sprintf("%s = %s", errVarName, errorExpr))
-		g.writeLine(fmt.Sprintf("break %s", labelName))
+		g.generateThrowStmt(s)
 	default:
 		// 其他语句直接生成
 		g.generateStatement(s)
@@ -3285,7 +4245,10 @@ func (g *CodeGen) isErrableCall(expr parser.Expression) bool {
 			if sym != nil && sym.Errable {
 				return true
 			}
-		} else if sel, ok := call.Function.(*parser.SelectorExpr); ok {
+		} else if sel, _ := methodCallSelector(call.Function); sel != nil {
+			if ext := g.extensionFor(sel); ext != nil {
+				return ext.Method.Errable
+			}
 			// 方法调用（不区分大小写，因为tugo的getName会变成Go的GetName）
 			methodName := sel.Sel
 			methodNameLower := strings.ToLower(methodName)
@@ -3295,6 +4258,8 @@ func (g *CodeGen) isErrableCall(expr parser.Expression) bool {
 					return true
 				}
 			}
+		} else if access, ok := call.Function.(*parser.StaticAccessExpr); ok && g.transpiler.isErrableStaticCall(access, g.currentClassDecl) {
+			return true
 		}
 	}
 	return false
@@ -3322,6 +4287,12 @@ func (g *CodeGen) containsErrableCall(expr parser.Expression) bool {
 		}
 	case *parser.BinaryExpr:
 		return g.containsErrableCall(e.Left) || g.containsErrableCall(e.Right)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			if part.Expr != nil && g.containsErrableCall(part.Expr) {
+				return true
+			}
+		}
 	case *parser.UnaryExpr:
 		return g.containsErrableCall(e.Operand)
 	case *parser.IndexExpr:
@@ -3343,7 +4314,7 @@ func (e *extractedExpr) expressionNode()      {}
 
 // extractErrableCalls 递归提取 errable 调用，生成临时变量和错误检查
 // 返回替换后的表达式（errable 调用被替换为临时变量标识符）
-func (g *CodeGen) extractErrableCalls(expr parser.Expression, labelName, errVarName string, tmpCounter *int) parser.Expression {
+func (g *CodeGen) extractErrableCalls(expr parser.Expression, labelName, errVarName string) parser.Expression {
 	if expr == nil {
 		return nil
 	}
@@ -3358,25 +4329,24 @@ func (g *CodeGen) extractErrableCalls(expr parser.Expression, labelName, errVarN
 			// 为每个返回值生成临时变量
 			var tmpVars []string
 			for i := 0; i < resultCount; i++ {
-				*tmpCounter++
-				tmpVars = append(tmpVars, fmt.Sprintf("_tmp%d", *tmpCounter))
+				tmpVars = append(tmpVars, g.names.fresh("_tmp%d"))
 			}
 			
 			// 生成提取代码
+			errName := g.names.fresh("_err%d")
 			g.writeIndent()
 			if len(tmpVars) > 0 {
 				g.write(strings.Join(tmpVars, ", "))
-				g.write(", _err := ")
-			} else {
-				g.write("_err := ")
+				g.write(", ")
 			}
+			g.write(errName + " := ")
 			g.write(g.generateExpression(e))
 			g.write("\n")
 			
 			// 生成错误检查
-			g.writeLine("if _err != nil {")
+			g.writeLine(fmt.Sprintf("if %s != nil {", errName))
 			g.indent++
-			g.writeLine(fmt.Sprintf("%s = _err", errVarName))
+			g.writeLine(fmt.Sprintf("%s = %s", errVarName, errName))
 			g.writeLine(fmt.Sprintf("break %s", labelName))
 			g.indent--
 			g.writeLine("}")
@@ -3396,7 +4366,7 @@ func (g *CodeGen) extractErrableCalls(expr parser.Expression, labelName, errVarN
 		// 不是 errable，但参数中可能有 errable 调用
 		newArgs := make([]parser.Expression, len(e.Arguments))
 		for i, arg := range e.Arguments {
-			newArgs[i] = g.extractErrableCalls(arg, labelName, errVarName, tmpCounter)
+			newArgs[i] = g.extractErrableCalls(arg, labelName, errVarName)
 		}
 		
 		// 返回新的 CallExpr（参数被替换）
@@ -3407,16 +4377,26 @@ func (g *CodeGen) extractErrableCalls(expr parser.Expression, labelName, errVarN
 		
 	case *parser.BinaryExpr:
 		return &parser.BinaryExpr{
-			Left:     g.extractErrableCalls(e.Left, labelName, errVarName, tmpCounter),
+			Left:     g.extractErrableCalls(e.Left, labelName, errVarName),
 			Operator: e.Operator,
-			Right:    g.extractErrableCalls(e.Right, labelName, errVarName, tmpCounter),
+			Right:    g.extractErrableCalls(e.Right, labelName, errVarName),
 		}
 		
 	case *parser.UnaryExpr:
 		return &parser.UnaryExpr{
 			Operator: e.Operator,
-			Operand:  g.extractErrableCalls(e.Operand, labelName, errVarName, tmpCounter),
+			Operand:  g.extractErrableCalls(e.Operand, labelName, errVarName),
+		}
+		
+	case *parser.InterpolatedString:
+		lit := &parser.InterpolatedString{Token: e.Token}
+		for _, part := range e.Parts {
+			if part.Expr != nil {
+				part = &parser.InterpolationPart{Expr: g.extractErrableCalls(part.Expr, labelName, errVarName), Spec: part.Spec}
+			}
+			lit.Parts = append(lit.Parts, part)
 		}
+		return lit
 		
 	default:
 		// 其他类型的表达式直接返回
@@ -3491,22 +4471,17 @@ func (g *CodeGen) generateErrableCallStmt(expr parser.Expression) {
 	for i := 0; i < resultCount; i++ {
 		g.write("_, ")
 	}
-	g.write("err := ")
+	errName := g.names.fresh("_err%d")
+	g.write(errName + " := ")
 	g.write(g.generateExpression(call))
 	g.write("\n")
 	
 	// 生成错误检查
-	g.writeLine("if err != nil {")
+	g.writeLine(fmt.Sprintf("if %s != nil {", errName))
 	g.indent++
 	
-	// 返回零值 + error
-	g.writeIndent()
-	g.write("return ")
-	zeroVals := g.generateZeroValues()
-	if zeroVals != "" {
-		g.write(zeroVals)
-	}
-	g.write("err\n")
+	// 跳转到 catch 或返回零值 + error
+	g.raiseError(errName, call.Token.Line, call.Token.Column)
 	
 	g.indent--
 	g.writeLine("}")
@@ -3536,6 +4511,12 @@ func (g *CodeGen) hasMultiValueErrableCall(expr parser.Expression) bool {
 		return g.hasMultiValueErrableCall(e.Left) || g.hasMultiValueErrableCall(e.Right)
 	case *parser.UnaryExpr:
 		return g.hasMultiValueErrableCall(e.Operand)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			if part.Expr != nil && g.hasMultiValueErrableCall(part.Expr) {
+				return true
+			}
+		}
 	}
 	
 	return false
@@ -3552,6 +4533,9 @@ func (g *CodeGen) getErrableFuncResultCount(call *parser.CallExpr) int {
 		}
 	} else if sel, ok := call.Function.(*parser.SelectorExpr); ok {
 		// 方法调用 obj.method()
+		if ext := g.extensionFor(sel); ext != nil && ext.Method.Errable {
+			return len(ext.Method.Results)
+		}
 		methodName := sel.Sel
 		
 		// 尝试从所有类方法中查找（不区分大小写）
@@ -3581,21 +4565,7 @@ func (g *CodeGen) generateIfStmt(stmt *parser.IfStmt) {
 		}
 	}
 
-	g.write(g.generateExpression(stmt.Condition))
-	g.write(" ")
-	g.generateBlockStmtInline(stmt.Consequence)
-
-	if stmt.Alternative != nil {
-		g.write(" else ")
-		switch alt := stmt.Alternative.(type) {
-		case *parser.BlockStmt:
-			g.generateBlockStmtInline(alt)
-		case *parser.IfStmt:
-			g.generateIfStmtInline(alt)
-			g.builder.WriteString("\n")
-			return
-		}
-	}
+	g.generateIfBranches(stmt)
 	g.builder.WriteString("\n")
 }
 
@@ -3610,23 +4580,13 @@ func (g *CodeGen) generateIfStmtInline(stmt *parser.IfStmt) {
 		}
 	}
 
-	g.write(g.generateExpression(stmt.Condition))
-	g.write(" ")
-	g.generateBlockStmtInline(stmt.Consequence)
-
-	if stmt.Alternative != nil {
-		g.write(" else ")
-		switch alt := stmt.Alternative.(type) {
-		case *parser.BlockStmt:
-			g.generateBlockStmtInline(alt)
-		case *parser.IfStmt:
-			g.generateIfStmtInline(alt)
-		}
-	}
+	g.generateIfBranches(stmt)
 }
 
 // generateForStmt 生成 for 语句
 func (g *CodeGen) generateForStmt(stmt *parser.ForStmt) {
+	g.enterBranchTarget(true, stmt.Body.Statements)
+	defer g.leaveBranchTarget()
 	g.writeIndent()
 	g.write("for ")
 
@@ -3652,12 +4612,18 @@ func (g *CodeGen) generateForStmt(stmt *parser.ForStmt) {
 	}
 
 	g.write(" ")
+	if stmt.Condition != nil {
+		g.narrow(stmt.Condition, true)
+	}
 	g.generateBlockStmtInline(stmt.Body)
 	g.builder.WriteString("\n")
 }
 
 // generateRangeStmt 生成 range 语句
 func (g *CodeGen) generateRangeStmt(stmt *parser.RangeStmt) {
+	g.trackRangeTypes(stmt)
+	g.enterBranchTarget(true, stmt.Body.Statements)
+	defer g.leaveBranchTarget()
 	g.writeIndent()
 	g.write("for ")
 
@@ -3679,6 +4645,8 @@ func (g *CodeGen) generateRangeStmt(stmt *parser.RangeStmt) {
 
 // generateSwitchStmt 生成 switch 语句
 func (g *CodeGen) generateSwitchStmt(stmt *parser.SwitchStmt) {
+	g.enterBranchTarget(false, caseBodies(stmt))
+	defer g.leaveBranchTarget()
 	g.write("switch ")
 
 	if stmt.Init != nil {
@@ -3720,6 +4688,8 @@ func (g *CodeGen) generateCaseClause(clause *parser.CaseClause) {
 
 // generateSelectStmt 生成 select 语句
 func (g *CodeGen) generateSelectStmt(stmt *parser.SelectStmt) {
+	g.enterBranchTarget(false, caseBodies(stmt))
+	defer g.leaveBranchTarget()
 	g.writeLine("select {")
 	for _, c := range stmt.Cases {
 		g.generateCommClause(c)
@@ -3791,7 +4761,7 @@ func (g *CodeGen) generateBreakStmt(stmt *parser.BreakStmt) {
 	if stmt.Label != "" {
 		g.writeLine("break " + stmt.Label)
 	} else {
-		g.writeLine("break")
+		g.generateBranch("break", false)
 	}
 }
 
@@ -3800,7 +4770,7 @@ func (g *CodeGen) generateContinueStmt(stmt *parser.ContinueStmt) {
 	if stmt.Label != "" {
 		g.writeLine("continue " + stmt.Label)
 	} else {
-		g.writeLine("continue")
+		g.generateBranch("continue", true)
 	}
 }
 
@@ -3862,6 +4832,8 @@ func (g *CodeGen) generateExpression(expr parser.Expression) string {
 		return e.Value
 	case *parser.StringLiteral:
 		return e.Value
+	case *parser.InterpolatedString:
+		return g.generateInterpolatedString(e)
 	case *parser.CharLiteral:
 		return e.Value
 	case *parser.BoolLiteral:
@@ -3878,6 +4850,8 @@ func (g *CodeGen) generateExpression(expr parser.Expression) string {
 		return "this" // 不应该发生
 	case *parser.SelfExpr:
 		return "self" // 只会在 StaticAccessExpr 中使用
+	case *parser.SuperExpr:
+		return g.superReceiver() // 只会在 super.m(...) 中使用，其他用法在校验时报错
 	case *parser.StaticAccessExpr:
 		return g.generateStaticAccessExpr(e)
 	case *parser.BinaryExpr:
@@ -3895,6 +4869,14 @@ func (g *CodeGen) generateExpression(expr parser.Expression) string {
 	case *parser.SliceExpr:
 		return g.generateSliceExpr(e)
 	case *parser.SelectorExpr:
+		// 属性读取 x.name 改写为 getter 调用
+		if call := g.propertyGet(e); call != nil {
+			return g.generateCallExpr(call)
+		}
+		if e.Safe {
+			return g.generateSafeAccess(e, e)
+		}
+		g.checkDereference(e)
 		return g.generateSelectorExpr(e)
 	case *parser.TypeAssertExpr:
 		return g.generateTypeAssertExpr(e)
@@ -3916,6 +4898,9 @@ func (g *CodeGen) generateExpression(expr parser.Expression) string {
 		return g.generateMakeExpr(e)
 	case *parser.NewExpr:
 		return g.generateNewExpr(e)
+	case *parser.NamedArg:
+		// 命名参数在重载解析时按形参顺序展开，这里只在无法解析的调用中出现（已报错）
+		return g.generateExpression(e.Value)
 	case *parser.LenExpr:
 		return "len(" + g.generateExpression(e.X) + ")"
 	case *parser.CapExpr:
@@ -3936,6 +4921,8 @@ func (g *CodeGen) generateExpression(expr parser.Expression) string {
 		return g.generateChanType(e)
 	case *parser.PointerType:
 		return "*" + g.generateType(e.Base)
+	case *parser.NullableType:
+		return g.generateNullableType(e)
 	case *parser.FuncType:
 		return g.generateFuncType(e)
 	case *parser.InterfaceType:
@@ -3973,6 +4960,12 @@ func (g *CodeGen) generateExpression(expr parser.Expression) string {
 func (g *CodeGen) generateIdentifier(ident *parser.Identifier) string {
 	name := ident.Value
 
+	// match 分支中的绑定变量
+	if binding, ok := g.matchBindings[name]; ok {
+		binding.used = true
+		return binding.expr
+	}
+
 	// 处理 $ 开头的变量名
 	if strings.HasPrefix(name, "$") {
 		return symbol.TransformDollarVar(name)
@@ -3980,7 +4973,7 @@ func (g *CodeGen) generateIdentifier(ident *parser.Identifier) string {
 
 	// 优先检查是否是当前函数的参数名（参数名不应被转换）
 	if g.currentFuncParams != nil && g.currentFuncParams[name] {
-		return name
+		return symbol.EscapeKeyword(name)
 	}
 
 	// 检查是否是导入的类型名
@@ -3991,18 +4984,37 @@ func (g *CodeGen) generateIdentifier(ident *parser.Identifier) string {
 	}
 
 	// 查找符号表
+	// 类方法只能通过 this. / self:: 访问，裸标识符与方法同名时是局部变量
 	sym := g.transpiler.LookupSymbol(name)
-	if sym != nil {
+	if sym != nil && sym.Kind != symbol.SymbolClassMethod {
 		return sym.GoName
 	}
 
-	return name
+	return symbol.EscapeKeyword(name)
 }
 
 // generateBinaryExpr 生成二元表达式
 func (g *CodeGen) generateBinaryExpr(expr *parser.BinaryExpr) string {
+	if expr.Operator == "??" {
+		return g.generateCoalesce(expr)
+	}
+	// 重载的运算符改写为方法调用
+	if use := g.lookupOperator(expr.Operator, expr.Left, expr.Right); use != nil {
+		return g.generateOperatorCall(expr, use)
+	}
+	g.checkOperatorDefined(expr)
 	left := g.generateExpression(expr.Left)
+
+	// && 的右侧在左侧成立时求值，|| 的右侧在左侧不成立时求值
+	saved := g.copyNonNil()
+	switch expr.Operator {
+	case "&&":
+		g.narrow(expr.Left, true)
+	case "||":
+		g.narrow(expr.Left, false)
+	}
 	right := g.generateExpression(expr.Right)
+	g.nonNil = saved
 	return left + " " + expr.Operator + " " + right
 }
 
@@ -4022,8 +5034,13 @@ func (g *CodeGen) generateTernaryExpr(expr *parser.TernaryExpr) string {
 
 	// 生成条件和分支表达式
 	condExpr := g.generateExpression(expr.Condition)
+	saved := g.copyNonNil()
+	g.narrow(expr.Condition, true)
 	trueExprStr := g.generateExpression(expr.TrueExpr)
+	g.nonNil = saved
+	g.narrow(expr.Condition, false)
 	falseExprStr := g.generateExpression(expr.FalseExpr)
+	g.nonNil = saved
 
 	// 确定最终类型
 	resultType := trueType
@@ -4056,57 +5073,106 @@ func (g *CodeGen) flushPendingStatements() {
 	g.pendingStatements = nil
 }
 
+// captureOutput 把 fn 生成的代码收集为文本（缩进从 0 开始），用于放入 pendingStatements
+// fn 中产生的待处理语句在 fn 结束前需要自行输出
+func (g *CodeGen) captureOutput(fn func()) string {
+	saved, savedIndent, savedPending := g.builder, g.indent, g.pendingStatements
+	savedLen, savedLines, savedMap := g.scannedLen, g.scannedLines, len(g.sourceMap)
+	g.builder = strings.Builder{}
+	g.indent, g.pendingStatements = 0, nil
+	g.scannedLen, g.scannedLines = 0, 0
+
+	fn()
+	g.flushPendingStatements()
+	text := g.builder.String()
+
+	// 收集的代码插入位置未知，丢弃其中的源码位置记录
+	g.builder, g.indent, g.pendingStatements = saved, savedIndent, savedPending
+	g.scannedLen, g.scannedLines, g.sourceMap = savedLen, savedLines, g.sourceMap[:savedMap]
+	return text
+}
+
 // generateMatchExpr 生成 match 表达式
 // 将 match(expr) { pattern => result, ... } 展开为临时变量 + switch 语句
 func (g *CodeGen) generateMatchExpr(expr *parser.MatchExpr) string {
-	g.matchCounter++
-	varName := fmt.Sprintf("__match_%d", g.matchCounter)
+	varName := g.names.fresh("__match_%d")
+	code := g.generateMatch(expr, varName)
+	g.pendingStatements = append(g.pendingStatements, code)
+	return varName
+}
 
-	// 推断结果类型（从第一个非 default 分支）
+// generateMatchStmt 生成语句位置的 match，分支作为语句执行，不需要临时变量
+func (g *CodeGen) generateMatchStmt(stmt *parser.ExpressionStmt, expr *parser.MatchExpr) {
+	// 和 switch 一样，分支中赋值过的路径不再视为非 nil
+	g.forgetAssigned(stmt)
+	saved := g.copyNonNil()
+	code := g.generateMatch(expr, "")
+	g.pendingStatements = append(g.pendingStatements, code)
+	g.flushPendingStatements()
+	g.nonNil = saved
+}
+
+// generateMatch 生成 match 的 switch 语句，分支结果赋给 varName，varName 为空时是 match 语句
+func (g *CodeGen) generateMatch(expr *parser.MatchExpr, varName string) string {
+	// 推断结果类型（从第一个有值的非 default 分支，都没有时再看 default 分支）
 	resultType := "any"
-	for _, arm := range expr.Arms {
-		if !arm.IsDefault && arm.Body != nil {
-			inferredType := g.inferExprType(arm.Body)
-			if inferredType != "any" && inferredType != "" {
-				resultType = inferredType
-				break
+	for _, wantDefault := range []bool{false, true} {
+		for _, arm := range expr.Arms {
+			if value := matchArmValue(arm); arm.IsDefault == wantDefault && value != nil {
+				inferredType := g.inferExprType(value)
+				if inferredType != "any" && inferredType != "" {
+					resultType = inferredType
+					break
+				}
 			}
 		}
+		if resultType != "any" {
+			break
+		}
+	}
+
+	// 解析器把首字母大写的模式当作类型，主体是枚举时这些模式是成员名
+	enum := g.transpiler.lookupEnum(typeBaseName(g.exprType(expr.Subject)))
+
+	// 有绑定、守卫条件、范围、解构或跳出循环的 break 时翻译为 if / else if 链
+	if g.needsMatchChain(expr, enum) {
+		return g.generateMatchChain(expr, enum, varName, resultType)
 	}
 
 	subject := g.generateExpression(expr.Subject)
 	
 	var sb strings.Builder
 	
-	// 声明临时变量
-	sb.WriteString(fmt.Sprintf("var %s %s\n", varName, resultType))
-	
-	if expr.IsType {
-		// 类型匹配 switch
-		sb.WriteString(fmt.Sprintf("switch __v := %s.(type) {\n", subject))
+	var inferred string
+	if expr.IsType && enum == nil {
+		// 类型匹配 switch（分支只有类型名，不绑定变量）
+		sb.WriteString(fmt.Sprintf("switch %s.(type) {\n", subject))
 		
+		seen := make(map[string]bool)
 		for _, arm := range expr.Arms {
 			if arm.IsDefault {
 				sb.WriteString("default:\n")
 			} else {
-				sb.WriteString("case ")
-				for i, pattern := range arm.Patterns {
-					if i > 0 {
-						sb.WriteString(", ")
+				// 前面的分支已经覆盖的类型不再重复（Go 不允许重复的 case）
+				var types []string
+				for _, pattern := range arm.Patterns {
+					for _, typeName := range g.typePatternCases(pattern) {
+						if !seen[typeName] {
+							seen[typeName] = true
+							types = append(types, typeName)
+						}
 					}
-					// 类型匹配，需要加 * 前缀（假设是指针类型）
-					typeName := g.generateExpression(pattern)
-					sb.WriteString("*" + typeName)
 				}
-				sb.WriteString(":\n")
+				if len(types) == 0 {
+					continue
+				}
+				sb.WriteString("case " + strings.Join(types, ", ") + ":\n")
 			}
 			
-			// 生成分支体，类型匹配时使用 __v 代替原始变量
-			bodyExpr := g.generateMatchArmBody(arm.Body, subject, "__v")
-			sb.WriteString(fmt.Sprintf("\t%s = %s\n", varName, bodyExpr))
+			sb.WriteString(indentLines(g.matchArmCode(arm, varName, &inferred)))
 		}
 	} else {
-		// 值匹配 switch
+		// 值匹配 switch，匹配枚举时分支可以直接写成员名
 		sb.WriteString(fmt.Sprintf("switch %s {\n", subject))
 		
 		for _, arm := range expr.Arms {
@@ -4118,31 +5184,34 @@ func (g *CodeGen) generateMatchExpr(expr *parser.MatchExpr) string {
 					if i > 0 {
 						sb.WriteString(", ")
 					}
+					if member, ok := g.enumMatchPattern(enum, pattern); ok {
+						sb.WriteString(member)
+						continue
+					}
 					sb.WriteString(g.generateExpression(pattern))
 				}
 				sb.WriteString(":\n")
 			}
 			
-			bodyExpr := g.generateExpression(arm.Body)
-			sb.WriteString(fmt.Sprintf("\t%s = %s\n", varName, bodyExpr))
+			sb.WriteString(indentLines(g.matchArmCode(arm, varName, &inferred)))
 		}
 	}
+
+	// 没有 default 分支时检查是否穷尽，并在未匹配时 panic 而不是得到零值
+	if !hasDefaultArm(expr) && g.checkMatchExhaustive(expr, enum, varName == "") {
+		sb.WriteString(g.matchPanicArm(expr))
+	}
 	
 	sb.WriteString("}")
 	
-	// 将 switch 语句添加到待处理语句
-	g.pendingStatements = append(g.pendingStatements, sb.String())
-	
-	return varName
-}
-
-// generateMatchArmBody 生成 match 分支体
-// 对于类型匹配，需要将 subject 替换为类型转换后的变量
-func (g *CodeGen) generateMatchArmBody(body parser.Expression, subject, typedVar string) string {
-	// 简单实现：直接生成表达式
-	// 如果表达式中引用了 subject，应该替换为 typedVar
-	// 这里先用简单实现，后续可以增强
-	return g.generateExpression(body)
+	if varName == "" {
+		return sb.String()
+	}
+	// 声明临时变量，静态推断不出类型时使用分支生成时得到的类型（块分支中的局部变量）
+	if resultType == "any" && inferred != "" {
+		resultType = inferred
+	}
+	return fmt.Sprintf("var %s %s\n", varName, resultType) + sb.String()
 }
 
 // generateUnaryExpr 生成一元表达式
@@ -4153,6 +5222,36 @@ func (g *CodeGen) generateUnaryExpr(expr *parser.UnaryExpr) string {
 
 // generateCallExpr 生成函数调用表达式
 func (g *CodeGen) generateCallExpr(expr *parser.CallExpr) string {
+	savedCallee := g.callee
+	g.callee = expr.Function
+	defer func() { g.callee = savedCallee }()
+	g.expectCallLambdas(expr)
+
+	// 方法调用可能修改接收者的字段，调用之后 x.f 的收窄失效
+	if sel, _ := methodCallSelector(expr.Function); sel != nil {
+		defer g.forgetFields(sel.X)
+	}
+
+	// super.m(...) 和 super.init(...)
+	if sel := superSelector(expr.Function); sel != nil {
+		return g.generateSuperCall(expr, sel)
+	}
+
+	// 安全调用 a?.m(...)，以及普通方法调用的接收者是否可能为 nil
+	if sel, _ := methodCallSelector(expr.Function); sel != nil {
+		if sel.Safe {
+			return g.generateSafeAccess(expr, sel)
+		}
+		g.checkDereference(sel)
+	}
+
+	// self 传递的方法体中 this.m(...) 按实际类型分派
+	if g.selfReplaceMode {
+		if call, ok := g.generateDispatchCall(expr); ok {
+			return call
+		}
+	}
+
 	// 检查是否是全局函数
 	if ident, ok := expr.Function.(*parser.Identifier); ok {
 		switch ident.Value {
@@ -4182,38 +5281,38 @@ func (g *CodeGen) generateCallExpr(expr *parser.CallExpr) string {
 		// 检查是否是带默认参数的函数
 		sym := g.transpiler.LookupSymbol(ident.Value)
 		if sym != nil && sym.HasDefault {
-			return g.generateDefaultParamCall(sym, expr.Arguments)
+			return g.generateDefaultParamCall(sym, expr)
 		}
 	}
 
-	// 检查是否是方法调用（obj.method()）且方法有重载
-	if sel, ok := expr.Function.(*parser.SelectorExpr); ok {
-		methodName := sel.Sel
-		
-		// 尝试获取接收者类型来查找重载
-		receiverType := g.getReceiverType(sel.X)
-		if receiverType != "" {
-			// 确定类所在的包（可能是当前包或导入的包）
-			classPkg := g.getClassPackage(receiverType)
-			
-			// 检查是否有重载
-			if g.transpiler.table.IsMethodOverloaded(classPkg, receiverType, methodName) {
-				// 解析重载方法，同时获取方法信息
-				mangledName, overloadMethod := g.resolveOverloadedMethodWithInfo(classPkg, receiverType, methodName, expr.Arguments)
-				x := g.generateExpression(sel.X)
-				
-				// 检查是否需要使用 Opts 模式
-				if overloadMethod != nil && overloadMethod.HasDefaults {
-					// 生成 Opts 结构体调用
-					return g.generateOptsCall(x, classPkg, receiverType, mangledName, overloadMethod, expr.Arguments)
-				}
-				
-				var args []string
-				for _, arg := range expr.Arguments {
-					args = append(args, g.generateArgumentExpr(arg))
-				}
-				return x + "." + mangledName + "(" + strings.Join(args, ", ") + ")"
-			}
+	// 泛型方法调用改写为包级函数调用
+	if call, ok := g.generateGenericMethodCall(expr); ok {
+		return call
+	}
+
+	// 扩展方法调用改写为包级函数调用
+	if call, ok := g.generateExtensionCall(expr); ok {
+		return call
+	}
+
+	// 方法调用（obj.method() / Class::method()）需要重载解析或参数绑定时按选中的重载生成
+	switch fn := expr.Function.(type) {
+	case *parser.SelectorExpr:
+		if call, ok := g.generateMethodCall(expr, fn); ok {
+			return call
+		}
+	case *parser.StaticAccessExpr:
+		if call, ok := g.generateStaticCall(expr, fn); ok {
+			return call
+		}
+	}
+
+	g.checkCallNullArgs(expr)
+
+	// 命名参数只能传给 tugo 的函数、方法和构造函数
+	for _, arg := range expr.Arguments {
+		if na, ok := arg.(*parser.NamedArg); ok {
+			g.transpiler.AddError(na.Token.Line, na.Token.Column, i18n.T(i18n.ErrNamedArgNotSupported, na.Name))
 		}
 	}
 
@@ -4230,7 +5329,7 @@ func (g *CodeGen) generateCallExpr(expr *parser.CallExpr) string {
 func (g *CodeGen) generateArgumentExpr(arg parser.Expression) string {
 	if g.selfReplaceMode {
 		if _, ok := arg.(*parser.ThisExpr); ok {
-			return "_self"
+			return g.names.name("_self")
 		}
 	}
 	return g.generateExpression(arg)
@@ -4317,7 +5416,8 @@ func (g *CodeGen) getReceiverType(expr parser.Expression) string {
 }
 
 // generateDefaultParamCall 生成带默认参数函数的调用
-func (g *CodeGen) generateDefaultParamCall(sym *symbol.Symbol, args []parser.Expression) string {
+func (g *CodeGen) generateDefaultParamCall(sym *symbol.Symbol, call *parser.CallExpr) string {
+	args := call.Arguments
 	funcName := sym.GoName
 	optsName := funcName + "__Opts"
 
@@ -4337,24 +5437,14 @@ func (g *CodeGen) generateDefaultParamCall(sym *symbol.Symbol, args []parser.Exp
 		return funcName + "(" + strings.Join(argStrs, ", ") + ")"
 	}
 
-	// 构建 Opts 结构体
-	var fields []string
-	for i, param := range funcDecl.Params {
-		fieldName := symbol.ToGoName(param.Name, true)
-		var value string
-		if i < len(args) {
-			// 使用传入的参数
-			value = g.generateExpression(args[i])
-		} else if param.DefaultValue != nil {
-			// 使用默认值
-			value = g.generateExpression(param.DefaultValue)
-		} else {
-			continue
-		}
-		fields = append(fields, fieldName+": "+value)
+	// 绑定位置参数和命名参数，未传的参数使用默认值
+	method := &parser.ClassMethod{Name: funcDecl.Name, Params: funcDecl.Params, Results: funcDecl.Results}
+	b, msg := bindArgs(funcDecl.Name, method, args)
+	if b == nil {
+		g.transpiler.AddError(call.Token.Line, call.Token.Column, msg)
+		return funcName + "(" + optsName + "{})"
 	}
-
-	return funcName + "(" + optsName + "{" + strings.Join(fields, ", ") + "})"
+	return funcName + "(" + g.optsLiteral(optsName, b) + ")"
 }
 
 // generatePrintCall 生成 print 调用
@@ -4368,6 +5458,9 @@ func (g *CodeGen) generatePrintCall(funcName string, args []parser.Expression) s
 
 // generateIndexExpr 生成索引表达式
 func (g *CodeGen) generateIndexExpr(expr *parser.IndexExpr) string {
+	if use := g.lookupIndexOperator(expr); use != nil {
+		return g.generateOperatorCall(expr, use)
+	}
 	return g.generateExpression(expr.X) + "[" + g.generateExpression(expr.Index) + "]"
 }
 
@@ -4391,6 +5484,9 @@ func (g *CodeGen) generateSliceExpr(expr *parser.SliceExpr) string {
 
 // generateSelectorExpr 生成选择器表达式
 func (g *CodeGen) generateSelectorExpr(expr *parser.SelectorExpr) string {
+	if access, ok := g.generateEnumFieldAccess(expr); ok {
+		return access
+	}
 	x := g.generateExpression(expr.X)
 
 	// 如果是 this.field/method（即接收者.成员），查找当前结构体/类的成员
@@ -4458,14 +5554,27 @@ func (g *CodeGen) generateSelectorExpr(expr *parser.SelectorExpr) string {
 
 // generateStaticAccessExpr 生成静态访问表达式 (ClassName::member 或 self::member)
 func (g *CodeGen) generateStaticAccessExpr(expr *parser.StaticAccessExpr) string {
+	return g.generateStaticMember(expr, nil)
+}
+
+// generateStaticMember 生成静态成员引用，target 是重载解析选中的方法（重载方法使用修饰名）
+func (g *CodeGen) generateStaticMember(expr *parser.StaticAccessExpr, target *parser.ClassMethod) string {
 	var className string
 	var classDecl *parser.ClassDecl
 	var pkgPrefix string // 包前缀，用于导入的类
+	var typeParams *parser.TypeParamList // 泛型类的类型参数
+	var typeArgs []parser.Expression     // 显式类型实参 ClassName[T]::
+	isSelf := false
+
+	// 枚举成员 Enum::Case、Enum::values、Enum::parse
+	if decl, pkgPrefix := g.enumStaticTarget(expr); decl != nil {
+		return g.generateEnumStaticMember(expr, decl, pkgPrefix)
+	}
 
 	// 获取类名和类声明
-	switch left := expr.Left.(type) {
-	case *parser.SelfExpr:
+	if _, ok := expr.Left.(*parser.SelfExpr); ok {
 		// self:: 使用当前静态类或当前普通类
+		isSelf = true
 		className = g.currentReceiver
 		if g.currentStaticClass != nil {
 			classDecl = g.currentStaticClass
@@ -4473,17 +5582,28 @@ func (g *CodeGen) generateStaticAccessExpr(expr *parser.StaticAccessExpr) string
 			// 普通类中的静态成员访问
 			classDecl = g.currentClassDecl
 		}
-	case *parser.Identifier:
-		// ClassName:: 直接使用类名
-		className = left.Value
+		if classDecl != nil {
+			typeParams = classDecl.TypeParams
+		}
+	} else {
+		// ClassName:: 或 ClassName[T]:: 直接使用类名
+		ident, args := staticAccessTarget(expr.Left)
+		if ident == nil {
+			return "/* invalid static access */"
+		}
+		className = ident.Value
+		typeArgs = args
 		// 检查是否是导入的类型（如标准库的 Str）
+		classPkg := g.transpiler.pkg
 		if pkg, ok := g.typeToPackage[className]; ok {
 			pkgPrefix = pkg + "."
+			classPkg = pkg
 		}
 		// 从 transpiler 中查找静态类声明
 		classDecl = g.transpiler.GetClassDecl(g.transpiler.pkg, className)
-	default:
-		return "/* invalid static access */"
+		if classInfo := g.transpiler.table.GetClass(classPkg, className); classInfo != nil {
+			typeParams = classInfo.TypeParams
+		}
 	}
 
 	memberName := expr.Member
@@ -4499,7 +5619,7 @@ func (g *CodeGen) generateStaticAccessExpr(expr *parser.StaticAccessExpr) string
 	if memberName == "class" {
 		// 需要导入 tugo/runtime 包
 		g.tugoImports["tugo/runtime"] = "runtime"
-		infoVarName := fmt.Sprintf("X%s_ClassInfo", goClassName)
+		infoVarName := classInfoName(goClassName)
 		return pkgPrefix + infoVarName
 	}
 
@@ -4508,31 +5628,128 @@ func (g *CodeGen) generateStaticAccessExpr(expr *parser.StaticAccessExpr) string
 		// 检查是否是字段
 		for _, field := range classDecl.Fields {
 			if field.Name == memberName {
-				isPublic := field.Visibility == "public"
-				if isPublic {
-					return pkgPrefix + goClassName + symbol.ToGoName(memberName, true)
-				} else {
-					// 私有静态字段使用 _ClassName_fieldName 格式
-					return pkgPrefix + fmt.Sprintf("_%s_%s", goClassName, memberName)
-				}
+				return pkgPrefix + staticFieldName(goClassName, field)
 			}
 		}
 
 		// 检查是否是方法
 		for _, method := range classDecl.Methods {
-			if method.Name == memberName {
-				isPublic := method.Visibility == "public"
+			if method.Name == memberName && (target == nil || method == target) {
+				instantiation := g.staticTypeArgs(expr, className, typeParams, typeArgs, method, isSelf)
+				isPublic := method.Visibility == "public" || method.Visibility == "protected"
+				methodName := symbol.ToGoName(memberName, true)
+				if target != nil && g.transpiler.table.IsMethodOverloaded(g.transpiler.pkg, classDecl.Name, memberName) {
+					methodName = symbol.GenerateMangledName(memberName, target.Params, isPublic)
+				}
 				if isPublic {
-					return pkgPrefix + goClassName + symbol.ToGoName(memberName, true)
+					return pkgPrefix + goClassName + methodName + instantiation
 				} else {
-					return pkgPrefix + strings.ToLower(string(classDecl.Name[0])) + classDecl.Name[1:] + symbol.ToGoName(memberName, true)
+					return pkgPrefix + strings.ToLower(string(classDecl.Name[0])) + classDecl.Name[1:] + methodName + instantiation
 				}
 			}
 		}
 	}
 
 	// 默认使用公开格式（带包前缀）
-	return pkgPrefix + goClassName + symbol.ToGoName(memberName, true)
+	method := target
+	if method == nil {
+		method = g.findClassMethod(className, memberName)
+	}
+	instantiation := g.staticTypeArgs(expr, className, typeParams, typeArgs, method, isSelf)
+	methodName := symbol.ToGoName(memberName, true)
+	if target != nil {
+		classPkg := g.transpiler.pkg
+		if pkgPrefix != "" {
+			classPkg = strings.TrimSuffix(pkgPrefix, ".")
+		}
+		if g.transpiler.table.IsMethodOverloaded(classPkg, className, memberName) {
+			methodName = symbol.GenerateMangledName(memberName, target.Params, true)
+		}
+	}
+	return pkgPrefix + goClassName + methodName + instantiation
+}
+
+// staticAccessTarget 解析静态访问左侧的类名和显式类型实参
+// 支持 ClassName::m、ClassName[T]::m 和 ClassName[K, V]::m
+func staticAccessTarget(left parser.Expression) (*parser.Identifier, []parser.Expression) {
+	switch l := left.(type) {
+	case *parser.Identifier:
+		return l, nil
+	case *parser.IndexExpr:
+		if ident, ok := l.X.(*parser.Identifier); ok {
+			return ident, []parser.Expression{l.Index}
+		}
+	case *parser.GenericType:
+		if ident, ok := l.Type.(*parser.Identifier); ok {
+			return ident, l.TypeArgs
+		}
+	}
+	return nil, nil
+}
+
+// findClassMethod 在符号表中查找类的方法声明（用于导入的类）
+func (g *CodeGen) findClassMethod(className, methodName string) *parser.ClassMethod {
+	classPkg := g.transpiler.pkg
+	if pkg, ok := g.typeToPackage[className]; ok {
+		classPkg = pkg
+	}
+	classInfo := g.transpiler.table.GetClass(classPkg, className)
+	if classInfo == nil {
+		return nil
+	}
+	for _, method := range classInfo.Methods {
+		if method.Name == methodName {
+			return method
+		}
+	}
+	return nil
+}
+
+// staticTypeArgs 生成泛型类静态方法的类型实参 [int, string]
+// 显式写出的类型实参直接使用；self:: 在类内部使用类自身的类型参数；
+// 否则依赖 Go 从方法参数推断，无法推断时报错
+func (g *CodeGen) staticTypeArgs(expr *parser.StaticAccessExpr, className string, typeParams *parser.TypeParamList, typeArgs []parser.Expression, method *parser.ClassMethod, isSelf bool) string {
+	if typeParams == nil || len(typeParams.Params) == 0 {
+		if len(typeArgs) > 0 {
+			g.transpiler.AddError(expr.Token.Line, expr.Token.Column, i18n.T(i18n.ErrNotGenericClass, className))
+		}
+		return ""
+	}
+
+	if len(typeArgs) > 0 {
+		if len(typeArgs) != len(typeParams.Params) {
+			g.transpiler.AddError(expr.Token.Line, expr.Token.Column, i18n.T(i18n.ErrTypeArgCount,
+				className, len(typeParams.Params), len(typeArgs)))
+			return ""
+		}
+		var args []string
+		for _, arg := range typeArgs {
+			args = append(args, g.generateType(arg))
+		}
+		return "[" + strings.Join(args, ", ") + "]"
+	}
+
+	if isSelf {
+		return g.getTypeParamsUse(typeParams)
+	}
+
+	// 类型实参只能从方法参数中推断
+	if method != nil {
+		used := make(map[string]bool)
+		for _, param := range method.Params {
+			for name := range collectIdentifiers(param.Type) {
+				used[name] = true
+			}
+		}
+		for _, param := range typeParams.Params {
+			if !used[param.Name] {
+				g.transpiler.AddError(expr.Token.Line, expr.Token.Column, i18n.T(i18n.ErrCannotInferTypeArg,
+					param.Name, className, expr.Member))
+				return ""
+			}
+		}
+	}
+	return ""
 }
 
 // generateTypeAssertExpr 生成类型断言表达式
@@ -4608,60 +5825,6 @@ func (g *CodeGen) generateStructLiteral(lit *parser.StructLiteral) string {
 	return typeExpr + "{" + strings.Join(fields, ", ") + "}"
 }
 
-// generateFuncLiteral 生成函数字面量
-func (g *CodeGen) generateFuncLiteral(lit *parser.FuncLiteral) string {
-	var result strings.Builder
-	result.WriteString("func(")
-
-	// 参数
-	for i, param := range lit.Params {
-		if i > 0 {
-			result.WriteString(", ")
-		}
-		if param.Name != "" {
-			result.WriteString(symbol.TransformDollarVar(param.Name))
-			result.WriteString(" ")
-		}
-		result.WriteString(g.generateType(param.Type))
-	}
-	result.WriteString(")")
-
-	// 返回值
-	if len(lit.Results) > 0 {
-		result.WriteString(" ")
-		if len(lit.Results) == 1 && lit.Results[0].Name == "" {
-			result.WriteString(g.generateType(lit.Results[0].Type))
-		} else {
-			result.WriteString("(")
-			for i, r := range lit.Results {
-				if i > 0 {
-					result.WriteString(", ")
-				}
-				if r.Name != "" {
-					result.WriteString(symbol.TransformDollarVar(r.Name))
-					result.WriteString(" ")
-				}
-				result.WriteString(g.generateType(r.Type))
-			}
-			result.WriteString(")")
-		}
-	}
-
-	// 函数体
-	result.WriteString(" {\n")
-	for _, stmt := range lit.Body.Statements {
-		// 简化处理，实际应该递归生成
-		if exprStmt, ok := stmt.(*parser.ExpressionStmt); ok {
-			result.WriteString("\t\t")
-			result.WriteString(g.generateExpression(exprStmt.Expression))
-			result.WriteString("\n")
-		}
-	}
-	result.WriteString("\t}")
-
-	return result.String()
-}
-
 // generateMakeExpr 生成 make 表达式
 func (g *CodeGen) generateMakeExpr(expr *parser.MakeExpr) string {
 	result := "make(" + g.generateType(expr.Type)
@@ -4832,10 +5995,10 @@ func (g *CodeGen) generateStructType(t *parser.StructType) string {
 // 4. 有带默认参数的 init（关键场景，容易出 bug！）:
 //    tugo: public func init(id: int = 0, name: string = "默认")
 //    无参调用: new Role()     -> New__Role(NewDefault__Role__InitOpts())
-//    命名参数: new Role(name: "x") -> New__Role(Role__InitOpts{Name: "x"})
+//    命名参数: new Role(name: "x") -> New__Role(Role__InitOpts{Id: 0, Name: "x"})
 //
 // 5. 多个 init 重载:
-//    根据参数数量和类型匹配对应的构造函数
+//    按实参类型做重载解析（规则见 overload.go），选中的构造函数使用修饰名
 //
 // 6. 跨包引用（通过 use 导入）:
 //    tugo: use "pkg.models.Role"; new Role()
@@ -4846,8 +6009,7 @@ func (g *CodeGen) generateStructType(t *parser.StructType) string {
 //
 // - classDecl == nil: 找不到类声明（可能是外部包未预加载），使用简单调用
 // - classDecl.InitMethod == nil: 类没有 init 方法，使用 New__X()
-// - len(classDecl.InitMethod.Params) == 0: init 无参数，使用 New__X()
-// - len(classDecl.InitMethod.Params) > 0: init 有参数，使用 opts 模式
+// - 其他情况: 在所有 init 中解析出一个，有默认参数时使用 opts 模式，否则按位置传参
 //
 // ================================================================
 func (g *CodeGen) generateNewExpr(expr *parser.NewExpr) string {
@@ -4910,165 +6072,65 @@ func (g *CodeGen) generateNewExpr(expr *parser.NewExpr) string {
 	// ========== 第三步：查找类声明 ==========
 	// 注意：跨包引用需要先调用 PreloadDeclarations 预加载所有文件的声明
 	// 否则这里会找不到类声明，导致生成错误的代码
-	classDecl := g.transpiler.GetClassDecl(classPkg, className)
-	
-	// 确定类是否公开（用于生成正确的 Go 名称）
-	isClassPublic := true
-	if classDecl != nil {
+	// 其他包（如标准库）的类声明可能没有预加载，此时使用符号表中的类信息
+	var inits []*parser.ClassMethod
+	var typeParams *parser.TypeParamList
+	isClassPublic := true // 确定类是否公开（用于生成正确的 Go 名称）
+	if classDecl := g.transpiler.GetClassDecl(classPkg, className); classDecl != nil {
 		isClassPublic = classDecl.Public
-	}
-	goClassName := symbol.ToGoName(className, isClassPublic)
-
-	// ========== 第四步：生成参数表达式 ==========
-	var argStrs []string
-	for _, arg := range expr.Arguments {
-		argStrs = append(argStrs, g.generateExpression(arg))
-	}
-	hasArgs := len(argStrs) > 0
-
-	// ========== 第五步：处理多个 init 重载的情况 ==========
-	if classDecl != nil && len(classDecl.InitMethods) > 1 {
-		matchedInit := g.findMatchingInit(classDecl.InitMethods, expr.Arguments)
-		if matchedInit != nil {
-			return g.generateInitCall(pkgPrefix, goClassName, typeArgs, matchedInit, argStrs)
+		typeParams = classDecl.TypeParams
+		inits = classDecl.InitMethods
+		if len(inits) == 0 && classDecl.InitMethod != nil {
+			inits = []*parser.ClassMethod{classDecl.InitMethod}
 		}
+	} else if classInfo := g.transpiler.table.GetClass(classPkg, className); classInfo != nil {
+		isClassPublic = classInfo.Public
+		typeParams = classInfo.TypeParams
+		inits = classInits(classInfo)
 	}
+	goClassName := symbol.ToGoName(className, isClassPublic)
 
-	// ========== 第六步：处理单个 init 或无 init 的情况 ==========
-	
-	// 情况 A：找不到类声明（外部包未预加载）
-	// 只能根据是否有参数来猜测调用方式
-	if classDecl == nil {
-		if hasArgs {
-			// 有参数，尝试推断修饰名（用于方法重载）
-			mangledSuffix := g.inferMangledSuffix(expr.Arguments)
-			if mangledSuffix != "" {
-				return fmt.Sprintf("%sNew__%s%s%s(%s)", pkgPrefix, goClassName, mangledSuffix, typeArgs, strings.Join(argStrs, ", "))
-			}
-			// 无法推断，直接传参
-			return fmt.Sprintf("%sNew__%s%s(%s)", pkgPrefix, goClassName, typeArgs, strings.Join(argStrs, ", "))
-		}
-		// 无参数，使用简单调用
-		return fmt.Sprintf("%sNew__%s%s()", pkgPrefix, goClassName, typeArgs)
-	}
-
-	// 情况 B：找到类声明，根据 init 方法生成调用
-	initMethod := classDecl.InitMethod // 单个 init（或 InitMethods[0]）
-	
-	// B1: 类没有 init 方法 -> New__X()
-	if initMethod == nil {
-		if hasArgs {
-			return fmt.Sprintf("%sNew__%s%s(%s)", pkgPrefix, goClassName, typeArgs, strings.Join(argStrs, ", "))
-		}
-		return fmt.Sprintf("%sNew__%s%s()", pkgPrefix, goClassName, typeArgs)
-	}
-
-	// B2: init 有参数 -> 需要使用 opts 模式
-	// 【重要】这是容易出 bug 的地方！
-	// 即使调用时无参数 new Role()，如果 init 有带默认值的参数，
-	// 也需要生成 New__Role(NewDefault__Role__InitOpts())
-	if len(initMethod.Params) > 0 {
-		if hasArgs {
-			// 有参数调用: 根据是否有默认值决定使用 opts 结构体还是直接传参
-			hasDefault := false
-			for _, param := range initMethod.Params {
-				if param.DefaultValue != nil {
-					hasDefault = true
-					break
-				}
-			}
-			if hasDefault {
-				// 有默认值，使用 opts 结构体（支持命名参数）
-				return g.generateConstructorOptsCall(pkgPrefix, goClassName, initMethod, expr.Arguments)
+	// ========== 第四步：选择构造函数 ==========
+	// 找不到类（外部包未加载）或类没有 init 方法：按位置直接传参
+	if len(inits) == 0 {
+		var argStrs []string
+		for _, arg := range expr.Arguments {
+			if na, ok := arg.(*parser.NamedArg); ok {
+				g.transpiler.AddError(na.Token.Line, na.Token.Column, i18n.T(i18n.ErrNamedArgNotSupported, na.Name))
 			}
-			// 无默认值，直接传参
-			return fmt.Sprintf("%sNew__%s%s(%s)", pkgPrefix, goClassName, typeArgs, strings.Join(argStrs, ", "))
+			argStrs = append(argStrs, g.generateExpression(arg))
 		}
-		// 【关键】无参数调用，但 init 有参数 -> 使用默认值
-		return fmt.Sprintf("%sNew__%s(%sNewDefault__%s__InitOpts())", pkgPrefix, goClassName, pkgPrefix, goClassName)
-	}
-
-	// B3: init 无参数 -> New__X()
-	if hasArgs {
 		return fmt.Sprintf("%sNew__%s%s(%s)", pkgPrefix, goClassName, typeArgs, strings.Join(argStrs, ", "))
 	}
-	return fmt.Sprintf("%sNew__%s%s()", pkgPrefix, goClassName, typeArgs)
-}
-
-// generateInitCall 为匹配的 init 方法生成调用代码
-func (g *CodeGen) generateInitCall(pkgPrefix, goClassName, typeArgs string, init *parser.ClassMethod, argStrs []string) string {
-	// 检查是否有默认参数
-	hasDefault := false
-	for _, param := range init.Params {
-		if param.DefaultValue != nil {
-			hasDefault = true
-			break
-		}
-	}
 
-	// 有默认参数且无参调用 -> 使用默认值
-	if hasDefault && len(argStrs) == 0 {
-		return fmt.Sprintf("%sNew__%s(%sNewDefault__%s__InitOpts())", pkgPrefix, goClassName, pkgPrefix, goClassName)
-	}
-
-	// 生成构造函数名（可能有重载修饰）
-	constructorName := "New__" + goClassName
-	if len(init.Params) > 0 {
-		constructorName = symbol.GenerateMangledName("New__"+goClassName, init.Params, true)
-	}
-
-	if len(argStrs) == 0 {
-		return fmt.Sprintf("%s%s%s()", pkgPrefix, constructorName, typeArgs)
+	// 按实参类型在所有 init 中做重载解析（单个 init 同样需要绑定命名参数和默认值）
+	g.expectLambdaArgs(inits, expr.Arguments, typeParamNames(typeParams), typeArgBindings(typeParams, expr.Type))
+	b := g.resolveCall(expr, "new "+className, inits, expr.Arguments, typeParamNames(typeParams))
+	if b == nil {
+		return "nil"
 	}
-	return fmt.Sprintf("%s%s%s(%s)", pkgPrefix, constructorName, typeArgs, strings.Join(argStrs, ", "))
+	return g.generateInitCall(pkgPrefix, goClassName, typeArgs, len(inits) > 1, b)
 }
 
-// generateConstructorOptsCall 生成构造函数的 opts 结构体调用
-// 用于 new ClassName(arg1, arg2) 这种有默认参数的构造函数调用
-func (g *CodeGen) generateConstructorOptsCall(pkgPrefix, goClassName string, init *parser.ClassMethod, args []parser.Expression) string {
-	optsName := fmt.Sprintf("%s%s__InitOpts", pkgPrefix, goClassName)
-	
-	// 生成 opts 结构体字面量（按位置参数顺序）
-	var fields []string
-	for i, arg := range args {
-		if i < len(init.Params) {
-			fieldName := symbol.ToGoName(init.Params[i].Name, true)
-			argVal := g.generateExpression(arg)
-			fields = append(fields, fmt.Sprintf("%s: %s", fieldName, argVal))
-		}
-	}
+// generateInitCall 为选中的 init 方法生成构造函数调用
+func (g *CodeGen) generateInitCall(pkgPrefix, goClassName, typeArgs string, overloaded bool, b *callBinding) string {
+	init := b.method
 
-	return fmt.Sprintf("%sNew__%s(%s{%s})", pkgPrefix, goClassName, optsName, strings.Join(fields, ", "))
-}
-
-// findMatchingInit 根据参数找到匹配的 init 方法
-func (g *CodeGen) findMatchingInit(initMethods []*parser.ClassMethod, args []parser.Expression) *parser.ClassMethod {
-	for _, init := range initMethods {
-		if len(init.Params) == len(args) {
-			return init
+	// 有默认参数的 init 使用 opts 结构体，未传的参数在调用处填入默认值
+	if hasDefaultParams(init) {
+		if len(b.costs) == 0 {
+			return fmt.Sprintf("%sNew__%s(%sNewDefault__%s__InitOpts())", pkgPrefix, goClassName, pkgPrefix, goClassName)
 		}
+		optsName := fmt.Sprintf("%s%s__InitOpts", pkgPrefix, goClassName)
+		return fmt.Sprintf("%sNew__%s(%s)", pkgPrefix, goClassName, g.optsLiteral(optsName, b))
 	}
-	// 没有精确匹配，返回第一个（或 nil）
-	if len(initMethods) > 0 {
-		return initMethods[0]
-	}
-	return nil
-}
 
-// inferMangledSuffix 根据参数推断修饰后缀
-func (g *CodeGen) inferMangledSuffix(args []parser.Expression) string {
-	if len(args) == 0 {
-		return ""
-	}
-	var types []string
-	for _, arg := range args {
-		t := g.inferExprType(arg)
-		if t == "any" {
-			return "" // 无法推断，不使用修饰名
-		}
-		types = append(types, t)
+	// 多个 init 时有参数的构造函数使用修饰名（与 generateClassConstructorSimpleForInit 保持一致）
+	constructorName := "New__" + goClassName
+	if overloaded && len(init.Params) > 0 {
+		constructorName = symbol.GenerateMangledName("New__"+goClassName, init.Params, true)
 	}
-	return "_" + strings.Join(types, "_")
+	return fmt.Sprintf("%s%s%s(%s)", pkgPrefix, constructorName, typeArgs, strings.Join(g.boundArgs(b), ", "))
 }
 
 // generateType 生成类型
@@ -5087,7 +6149,7 @@ func (g *CodeGen) inferExprType(expr parser.Expression) string {
 		return "int"
 	case *parser.FloatLiteral:
 		return "float64"
-	case *parser.StringLiteral:
+	case *parser.StringLiteral, *parser.InterpolatedString:
 		return "string"
 	case *parser.CharLiteral:
 		return "rune"
@@ -5149,6 +6211,12 @@ func (g *CodeGen) inferExprType(expr parser.Expression) string {
 	case *parser.SelectorExpr:
 		// 选择器表达式（简化）
 		return "any"
+	case *parser.StaticAccessExpr:
+		// 枚举成员 Enum::Case
+		if t := g.enumStaticType(e); t != "" {
+			return g.generateIdentifier(&parser.Identifier{Value: t})
+		}
+		return "any"
 	case *parser.ParenExpr:
 		return g.inferExprType(e.X)
 	default:
@@ -5170,97 +6238,12 @@ func (g *CodeGen) getClassPackage(className string) string {
 	return g.transpiler.pkg
 }
 
-// resolveOverloadedMethod 解析重载方法调用，返回修饰后的方法名
-func (g *CodeGen) resolveOverloadedMethod(receiver, methodName string, args []parser.Expression) string {
-	return g.resolveOverloadedMethodInPkg(g.transpiler.pkg, receiver, methodName, args)
-}
-
-// resolveOverloadedMethodInPkg 在指定包中解析重载方法调用
-func (g *CodeGen) resolveOverloadedMethodInPkg(pkg, receiver, methodName string, args []parser.Expression) string {
-	mangledName, _ := g.resolveOverloadedMethodWithInfo(pkg, receiver, methodName, args)
-	return mangledName
-}
-
-// resolveOverloadedMethodWithInfo 在指定包中解析重载方法调用，同时返回方法信息
-func (g *CodeGen) resolveOverloadedMethodWithInfo(pkg, receiver, methodName string, args []parser.Expression) (string, *symbol.OverloadedMethod) {
-	// 推断参数类型
-	var argTypes []string
-	for _, arg := range args {
-		argTypes = append(argTypes, g.inferExprType(arg))
-	}
-
-	// 查找匹配的重载
-	group := g.transpiler.table.GetOverloadGroup(pkg, receiver, methodName)
-	if group != nil {
-		// 首先尝试精确匹配
-		for _, method := range group.Methods {
-			if len(method.ParamTypes) == len(argTypes) {
-				match := true
-				for i, pt := range method.ParamTypes {
-					if pt != argTypes[i] && argTypes[i] != "any" {
-						match = false
-						break
-					}
-				}
-				if match {
-					return method.MangledName, method
-				}
-			}
-		}
-		
-		// 如果精确匹配失败，尝试按参数数量匹配
-		for _, method := range group.Methods {
-			if len(method.ParamTypes) == len(args) {
-				return method.MangledName, method
-			}
-		}
-		
-		// 如果还是没找到，返回第一个版本
-		if len(group.Methods) > 0 {
-			return group.Methods[0].MangledName, group.Methods[0]
-		}
+// classGoName 返回类的 Go 类型名，非公开类为小写（找不到类时按公开处理）
+func (g *CodeGen) classGoName(className string) string {
+	if info := g.transpiler.table.GetClass(g.getClassPackage(className), className); info != nil {
+		return symbol.ToGoName(className, info.Public)
 	}
-
-	// 回退到标准名称转换
-	return symbol.ToGoName(methodName, true), nil
-}
-
-// generateOptsCall 生成使用 Opts 结构体的方法调用
-func (g *CodeGen) generateOptsCall(receiver, classPkg, className, mangledName string, method *symbol.OverloadedMethod, args []parser.Expression) string {
-	// 获取 Go 类名（用于 Opts 结构体名）
-	classInfo := g.transpiler.table.GetClass(classPkg, className)
-	goClassName := className
-	if classInfo != nil {
-		goClassName = classInfo.GoName
-	}
-	
-	optsName := goClassName + "__" + mangledName + "__Opts"
-	
-	// 检查是否需要包前缀
-	pkgPrefix := ""
-	if classPkg != g.transpiler.pkg {
-		// 跨包调用，需要添加包前缀
-		// 从 typeToPackage 获取导入别名
-		for typeName, pkgName := range g.typeToPackage {
-			if typeName == className {
-				pkgPrefix = pkgName + "."
-				break
-			}
-		}
-	}
-	
-	// 生成 Opts 结构体字面量
-	var fields []string
-	for i, arg := range args {
-		if i < len(method.ParamNames) {
-			fieldName := symbol.ToGoName(method.ParamNames[i], true)
-			argVal := g.generateExpression(arg)
-			fields = append(fields, fmt.Sprintf("%s: %s", fieldName, argVal))
-		}
-	}
-	
-	optsLiteral := pkgPrefix + optsName + "{" + strings.Join(fields, ", ") + "}"
-	return receiver + "." + mangledName + "(" + optsLiteral + ")"
+	return symbol.ToGoName(className, true)
 }
 
 // write 写入内容
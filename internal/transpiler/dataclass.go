@@ -0,0 +1,255 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 数据类
+//
+//	data class Point(x: int, y: int = 0)
+//
+// 解析器把主构造参数展开为公开字段和构造方法，并声明 equals、hash、string、copy 方法
+// （ClassMethod.Derived，类中已声明同名方法时不生成），方法体在这里按字段生成：
+//
+//	func (t *Point) Equals(other *Point) bool   // 逐个比较字段
+//	func (t *Point) Hash() int                  // runtime.Hash(t.X, t.Y)
+//	func (t *Point) String() string             // Point(x=1, y=2)
+//	func (t *Point) Copy(opts Point__Copy__Opts) *Point
+//
+// equals 对基本类型、枚举和普通类的指针使用 ==，数据类字段调用其 Equals，其余类型使用 reflect.DeepEqual。
+// copy 与带默认参数的方法一样接收 Opts 结构体，形参的默认值是 this.field，
+// 调用处未传的参数取接收者的字段值：p.copy(y: 5) 生成 p.Copy(Point__Copy__Opts{X: p.X, Y: 5})。
+// x, y := p 按主构造参数的顺序解构数据类。
+
+// isDataClass 判断类是否是数据类
+func isDataClass(info *symbol.ClassInfo) bool {
+	return info != nil && len(info.DataParams) > 0
+}
+
+// dataFieldEquality 返回数据类字段的比较方式："==", "equals" 或 "deep"（reflect.DeepEqual）
+func (g *CodeGen) dataFieldEquality(typ parser.Expression) string {
+	t := canonicalType(typ)
+	if basicTypes[t] && t != "any" {
+		return "=="
+	}
+	if base, ok := strings.CutPrefix(t, "*"); ok {
+		if isDataClass(g.lookupClass(base)) {
+			return "equals"
+		}
+		return "=="
+	}
+	if g.transpiler.lookupEnum(t) != nil {
+		return "=="
+	}
+	return "deep"
+}
+
+// prescanDataClass 记录数据类生成的方法需要的导入
+func (g *CodeGen) prescanDataClass(decl *parser.ClassDecl) {
+	for _, method := range decl.Methods {
+		if !method.Derived {
+			continue
+		}
+		switch method.Name {
+		case "string":
+			g.transpiler.SetNeedFmt(true)
+		case "equals":
+			for _, param := range decl.DataParams {
+				if g.dataFieldEquality(param.Type) == "deep" {
+					g.goImports["reflect"] = true
+				}
+			}
+		}
+	}
+}
+
+// generateDataMethod 生成数据类自动声明的方法
+func (g *CodeGen) generateDataMethod(decl *parser.ClassDecl, className string, method *parser.ClassMethod) {
+	g.markSource(method.Token)
+	var fields []string
+	for _, param := range decl.DataParams {
+		fields = append(fields, symbol.ToGoName(param.Name, true))
+	}
+
+	switch method.Name {
+	case "equals":
+		g.writeLine(fmt.Sprintf("func (t *%s) Equals(other *%s) bool {", className, className))
+		g.indent++
+		g.writeLine("if t == nil || other == nil {")
+		g.indent++
+		g.writeLine("return t == other")
+		g.indent--
+		g.writeLine("}")
+		var conds []string
+		for i, param := range decl.DataParams {
+			f := fields[i]
+			switch g.dataFieldEquality(param.Type) {
+			case "==":
+				conds = append(conds, fmt.Sprintf("t.%s == other.%s", f, f))
+			case "equals":
+				conds = append(conds, fmt.Sprintf("t.%s.Equals(other.%s)", f, f))
+			default:
+				conds = append(conds, fmt.Sprintf("reflect.DeepEqual(t.%s, other.%s)", f, f))
+			}
+		}
+		g.writeLine("return " + strings.Join(conds, " && "))
+		g.indent--
+		g.writeLine("}")
+
+	case "hash":
+		var values []string
+		for _, f := range fields {
+			values = append(values, "t."+f)
+		}
+		g.writeLine(fmt.Sprintf("func (t *%s) Hash() int {", className))
+		g.indent++
+		g.writeLine(fmt.Sprintf("return runtime.Hash(%s)", strings.Join(values, ", ")))
+		g.indent--
+		g.writeLine("}")
+
+	case "string":
+		var verbs, values []string
+		for i, param := range decl.DataParams {
+			verbs = append(verbs, param.Name+"=%v")
+			values = append(values, "t."+fields[i])
+		}
+		format := decl.Name + "(" + strings.Join(verbs, ", ") + ")"
+		g.writeLine(fmt.Sprintf("func (t *%s) String() string {", className))
+		g.indent++
+		g.writeLine(fmt.Sprintf("return fmt.Sprintf(%q, %s)", format, strings.Join(values, ", ")))
+		g.indent--
+		g.writeLine("}")
+
+	case "copy":
+		g.generateDataCopy(decl, className, method, fields)
+	}
+}
+
+// generateDataCopy 生成 copy 的 Opts 结构体和 Copy 方法，用 Opts 中的字段值调用构造函数
+func (g *CodeGen) generateDataCopy(decl *parser.ClassDecl, className string, method *parser.ClassMethod, fields []string) {
+	optsName := className + "__Copy__Opts"
+	g.writeLine(fmt.Sprintf("type %s struct {", optsName))
+	g.indent++
+	for i, param := range method.Params {
+		g.writeLine(fmt.Sprintf("%s %s", fields[i], g.generateType(param.Type)))
+	}
+	g.indent--
+	g.writeLine("}")
+	g.writeLine("")
+
+	var values []string
+	for _, f := range fields {
+		values = append(values, "opts."+f)
+	}
+	args := strings.Join(values, ", ")
+	if hasDefaultParams(decl.InitMethod) {
+		var inits []string
+		for _, f := range fields {
+			inits = append(inits, f+": opts."+f)
+		}
+		args = fmt.Sprintf("%s__InitOpts{%s}", className, strings.Join(inits, ", "))
+	}
+	g.writeLine(fmt.Sprintf("func (t *%s) Copy(opts %s) *%s {", className, optsName, className))
+	g.indent++
+	g.writeLine(fmt.Sprintf("return New__%s(%s)", className, args))
+	g.indent--
+	g.writeLine("}")
+}
+
+// receiverField 默认值是 this.field 时返回字段名
+func receiverField(expr parser.Expression) string {
+	sel, ok := expr.(*parser.SelectorExpr)
+	if !ok || sel.Safe {
+		return ""
+	}
+	if _, ok := sel.X.(*parser.ThisExpr); !ok {
+		return ""
+	}
+	return sel.Sel
+}
+
+// usesReceiverDefaults 判断调用是否有未传的参数使用 this.field 默认值
+func usesReceiverDefaults(b *callBinding) bool {
+	for i, param := range b.method.Params {
+		if b.args[i] == nil && receiverField(param.DefaultValue) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// bindReceiverCall 把接收者绑定到函数参数后再调用 obj.m(...)，默认值 this.field 对接收者只求值一次
+// 接收者或返回值的类型未知时返回 false
+func (g *CodeGen) bindReceiverCall(expr *parser.CallExpr, sel *parser.SelectorExpr) (string, bool) {
+	recvType := g.exprType(sel.X)
+	goRecv := g.goTypeText(recvType)
+	goResult := g.goTypeText(g.exprType(expr))
+	if goRecv == "" || goResult == "" {
+		return "", false
+	}
+	tmp := g.names.fresh("__recv_%d")
+	g.localTypes[tmp] = recvType
+	g.varTypes[tmp] = stripTypeArgs(strings.TrimPrefix(recvType, "*"))
+	defer func() {
+		delete(g.localTypes, tmp)
+		delete(g.varTypes, tmp)
+	}()
+	call := &parser.CallExpr{
+		Token:     expr.Token,
+		Function:  &parser.SelectorExpr{Token: sel.Token, X: &parser.Identifier{Token: sel.Token, Value: tmp}, Sel: sel.Sel},
+		Arguments: expr.Arguments,
+	}
+	return fmt.Sprintf("func(%s %s) %s { return %s }(%s)", tmp, goRecv, goResult, g.generateExpression(call), g.generateExpression(sel.X)), true
+}
+
+// generateDestructure 生成数据类的解构 x, y := p，按主构造参数的顺序取字段
+// 值不是数据类时返回 false，由调用方按多返回值生成
+func (g *CodeGen) generateDestructure(decl *parser.ShortVarDecl) bool {
+	if _, ok := decl.Value.(*parser.ArrayLiteral); ok {
+		return false
+	}
+	t := g.exprType(decl.Value)
+	base, ok := strings.CutPrefix(t, "*")
+	if !ok {
+		return false
+	}
+	info := g.lookupClass(base)
+	if !isDataClass(info) {
+		return false
+	}
+	if len(decl.Names) != len(info.DataParams) {
+		g.transpiler.AddError(decl.Token.Line, decl.Token.Column, i18n.T(i18n.ErrDestructureCount, info.Name, len(info.DataParams), len(decl.Names)))
+		return true
+	}
+
+	recv := decl.Value
+	if stablePath(recv) == "" {
+		tmp := g.names.fresh("__data_%d")
+		value := g.generateExpression(decl.Value)
+		g.flushPendingStatements()
+		g.writeLine(fmt.Sprintf("%s := %s", tmp, value))
+		g.setLocalType(tmp, t)
+		g.varTypes[tmp] = info.Name
+		recv = &parser.Identifier{Token: decl.Token, Value: tmp}
+	}
+
+	names := make([]string, len(decl.Names))
+	values := make([]string, len(decl.Names))
+	for i, name := range decl.Names {
+		param := info.DataParams[i]
+		names[i] = symbol.TransformDollarVar(name)
+		values[i] = g.generateExpression(&parser.SelectorExpr{Token: decl.Token, X: recv, Sel: param.Name})
+	}
+	for i, name := range decl.Names {
+		g.setLocalType(name, canonicalType(info.DataParams[i].Type))
+		g.declareNullable(name, nil, nil)
+	}
+	g.flushPendingStatements()
+	g.writeLine(fmt.Sprintf("%s := %s", strings.Join(names, ", "), strings.Join(values, ", ")))
+	return true
+}
@@ -0,0 +1,515 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 枚举
+//
+// enum Color { Red, Green, Blue } 生成带类型的常量集合：
+//
+//	type Color int
+//	const (
+//		ColorRed Color = iota
+//		ColorGreen
+//		ColorBlue
+//	)
+//
+// 指定底层类型的枚举（enum Status: string { Active = "active" }）使用成员声明的值，
+// 整数枚举中没有值的成员取上一个成员的值加一。
+//
+// 每个枚举还会生成：
+//   - String() 返回成员名
+//   - MarshalText / UnmarshalText：文本形式是成员名，底层类型为 string 的枚举是成员的值，JSON 同样使用文本形式
+//   - Color::values() 返回所有成员（ColorValues），Color::parse(s) 按成员名查找成员（ColorParse，errable），与 String() 互逆
+//
+// 枚举的字段由每个成员提供值（Red("#ff0000")），生成为同名的方法，c.hex 翻译为 c.Hex()。
+
+// lookupEnum 按名称查找当前包或导入的枚举
+func (t *Transpiler) lookupEnum(name string) *parser.EnumDecl {
+	ref, ok := t.resolveTypeRef(name)
+	if !ok || ref.goPath != "" {
+		return nil
+	}
+	return t.GetEnumDecl(ref.pkg, ref.name)
+}
+
+// isEnumParse 判断调用目标是否是 Enum::parse
+func (t *Transpiler) isEnumParse(access *parser.StaticAccessExpr) bool {
+	ident, ok := access.Left.(*parser.Identifier)
+	return ok && access.Member == "parse" && t.lookupEnum(ident.Value) != nil
+}
+
+// enumCase 按名称查找枚举成员
+func enumCase(decl *parser.EnumDecl, name string) *parser.EnumCase {
+	for _, c := range decl.Cases {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// enumBaseType 返回枚举的底层类型
+func (g *CodeGen) enumBaseType(decl *parser.EnumDecl) string {
+	if decl.Type == nil {
+		return "int"
+	}
+	return g.generateType(decl.Type)
+}
+
+// enumCaseName 返回成员常量的 Go 名称 ColorRed
+func enumCaseName(goName, caseName string) string {
+	return goName + symbol.ToGoName(caseName, true)
+}
+
+// generateEnumDecl 生成枚举声明
+func (g *CodeGen) generateEnumDecl(decl *parser.EnumDecl) {
+	goName := symbol.ToGoName(decl.Name, decl.Public)
+	baseType := g.enumBaseType(decl)
+	g.checkEnumDecl(decl, goName, baseType)
+	g.transpiler.needFmt = true
+
+	g.writeLine(fmt.Sprintf("type %s %s", goName, baseType))
+	g.writeLine("")
+	g.generateEnumCases(decl, goName, baseType)
+	g.writeLine("")
+
+	g.generateEnumString(decl, goName, baseType)
+	g.generateEnumText(decl, goName, baseType)
+	g.generateEnumValues(decl, goName)
+	g.generateEnumParse(decl, goName, baseType)
+
+	for i, field := range decl.Fields {
+		g.generateEnumField(decl, goName, field, i)
+	}
+	for _, method := range decl.Methods {
+		g.generateEnumMethod(decl, goName, method)
+		g.writeLine("")
+	}
+}
+
+// checkEnumDecl 校验枚举成员和成员
+func (g *CodeGen) checkEnumDecl(decl *parser.EnumDecl, goName, baseType string) {
+	seen := make(map[string]bool)
+	values := make(map[string]string)
+	for _, c := range decl.Cases {
+		if seen[c.Name] {
+			g.transpiler.AddError(c.Token.Line, c.Token.Column, i18n.T(i18n.ErrEnumDuplicateCase, decl.Name, c.Name))
+		}
+		seen[c.Name] = true
+		for _, generated := range []string{"Values", "Parse"} {
+			if symbol.ToGoName(c.Name, true) == generated {
+				g.transpiler.AddError(c.Token.Line, c.Token.Column, i18n.T(i18n.ErrEnumReservedCase, decl.Name, c.Name, goName+generated))
+			}
+		}
+		if c.Value == nil {
+			if !isIntegerType(baseType) {
+				g.transpiler.AddError(c.Token.Line, c.Token.Column, i18n.T(i18n.ErrEnumCaseValue, decl.Name, c.Name, baseType))
+			}
+			continue
+		}
+		// 字面量值重复会让生成的 switch 出现重复的 case
+		if lit := enumLiteral(c.Value); lit != "" {
+			if other, ok := values[lit]; ok {
+				g.transpiler.AddError(c.Token.Line, c.Token.Column, i18n.T(i18n.ErrEnumDuplicateValue, decl.Name, other, c.Name))
+			}
+			values[lit] = c.Name
+		}
+	}
+	for _, field := range decl.Fields {
+		if field.Static {
+			g.transpiler.AddError(decl.Token.Line, decl.Token.Column, i18n.T(i18n.ErrEnumStaticMember, decl.Name, field.Name))
+		}
+	}
+	for _, method := range decl.Methods {
+		if method.Static {
+			g.transpiler.AddError(method.Token.Line, method.Token.Column, i18n.T(i18n.ErrEnumStaticMember, decl.Name, method.Name))
+		}
+	}
+}
+
+// enumLiteral 返回字面量成员值的文本，不是字面量时返回空串
+func enumLiteral(expr parser.Expression) string {
+	switch v := expr.(type) {
+	case *parser.IntegerLiteral:
+		return "int:" + v.Token.Literal
+	case *parser.StringLiteral:
+		return "string:" + v.Value
+	}
+	return ""
+}
+
+// generateEnumCases 生成成员常量
+// 没有成员指定值时使用 iota，否则没有值的成员取上一个成员的值加一（第一个成员为 0）
+func (g *CodeGen) generateEnumCases(decl *parser.EnumDecl, goName, baseType string) {
+	useIota := true
+	for _, c := range decl.Cases {
+		if c.Value != nil {
+			useIota = false
+		}
+	}
+
+	g.writeLine("const (")
+	g.indent++
+	for i, c := range decl.Cases {
+		name := enumCaseName(goName, c.Name)
+		switch {
+		case c.Value != nil:
+			g.writeLine(fmt.Sprintf("%s %s = %s", name, goName, g.generateExpression(c.Value)))
+		case useIota && i == 0:
+			g.writeLine(fmt.Sprintf("%s %s = iota", name, goName))
+		case useIota:
+			g.writeLine(name)
+		case i == 0:
+			g.writeLine(fmt.Sprintf("%s %s = 0", name, goName))
+		default:
+			g.writeLine(fmt.Sprintf("%s %s = %s + 1", name, goName, enumCaseName(goName, decl.Cases[i-1].Name)))
+		}
+	}
+	g.indent--
+	g.writeLine(")")
+}
+
+// enumCaseList 返回所有成员常量，用逗号分隔
+func enumCaseList(decl *parser.EnumDecl, goName string) string {
+	names := make([]string, len(decl.Cases))
+	for i, c := range decl.Cases {
+		names[i] = enumCaseName(goName, c.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// enumDeclaresMethod 判断枚举是否声明了指定 Go 名称的方法
+func enumDeclaresMethod(decl *parser.EnumDecl, goName string) bool {
+	for _, method := range decl.Methods {
+		if symbol.ToGoName(method.Name, method.Visibility == "public") == goName {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEnumString 生成 String 方法（枚举自己声明了 String 时跳过）
+func (g *CodeGen) generateEnumString(decl *parser.EnumDecl, goName, baseType string) {
+	if enumDeclaresMethod(decl, "String") {
+		return
+	}
+	g.writeLine(fmt.Sprintf("func (e %s) String() string {", goName))
+	g.indent++
+	g.writeLine("switch e {")
+	for _, c := range decl.Cases {
+		g.writeLine(fmt.Sprintf("case %s:", enumCaseName(goName, c.Name)))
+		g.indent++
+		g.writeLine(fmt.Sprintf("return %q", c.Name))
+		g.indent--
+	}
+	g.writeLine("}")
+	g.writeLine(fmt.Sprintf("return fmt.Sprintf(\"%s(%%v)\", %s(e))", decl.Name, baseType))
+	g.indent--
+	g.writeLine("}")
+	g.writeLine("")
+}
+
+// generateEnumText 生成 MarshalText / UnmarshalText
+func (g *CodeGen) generateEnumText(decl *parser.EnumDecl, goName, baseType string) {
+	g.writeLine(fmt.Sprintf("func (e %s) MarshalText() ([]byte, error) {", goName))
+	g.indent++
+	g.writeLine("switch e {")
+	if baseType == "string" {
+		g.writeLine(fmt.Sprintf("case %s:", enumCaseList(decl, goName)))
+		g.indent++
+		g.writeLine("return []byte(e), nil")
+		g.indent--
+	} else {
+		for _, c := range decl.Cases {
+			g.writeLine(fmt.Sprintf("case %s:", enumCaseName(goName, c.Name)))
+			g.indent++
+			g.writeLine(fmt.Sprintf("return []byte(%q), nil", c.Name))
+			g.indent--
+		}
+	}
+	g.writeLine("}")
+	g.writeLine(fmt.Sprintf("return nil, fmt.Errorf(\"invalid %s value %%v\", %s(e))", decl.Name, baseType))
+	g.indent--
+	g.writeLine("}")
+	g.writeLine("")
+
+	g.writeLine(fmt.Sprintf("func (e *%s) UnmarshalText(text []byte) error {", goName))
+	g.indent++
+	if baseType == "string" {
+		// 文本形式是成员的值，不经过按成员名查找的 Parse
+		g.writeLine(fmt.Sprintf("switch v := %s(text); v {", goName))
+		g.writeLine(fmt.Sprintf("case %s:", enumCaseList(decl, goName)))
+		g.indent++
+		g.writeLine("*e = v")
+		g.writeLine("return nil")
+		g.indent--
+		g.writeLine("}")
+		g.writeLine(fmt.Sprintf("return fmt.Errorf(\"invalid %s %%q\", text)", decl.Name))
+	} else {
+		g.writeLine(fmt.Sprintf("v, err := %sParse(string(text))", goName))
+		g.writeLine("if err != nil {")
+		g.indent++
+		g.writeLine("return err")
+		g.indent--
+		g.writeLine("}")
+		g.writeLine("*e = v")
+		g.writeLine("return nil")
+	}
+	g.indent--
+	g.writeLine("}")
+	g.writeLine("")
+}
+
+// generateEnumValues 生成 Enum::values()
+func (g *CodeGen) generateEnumValues(decl *parser.EnumDecl, goName string) {
+	g.writeLine(fmt.Sprintf("func %sValues() []%s {", goName, goName))
+	g.indent++
+	g.writeLine(fmt.Sprintf("return []%s{%s}", goName, enumCaseList(decl, goName)))
+	g.indent--
+	g.writeLine("}")
+	g.writeLine("")
+}
+
+// generateEnumParse 生成 Enum::parse(s)，按成员名查找成员
+func (g *CodeGen) generateEnumParse(decl *parser.EnumDecl, goName, baseType string) {
+	zero := zeroText(baseType)
+	if strings.HasPrefix(zero, "*new(") {
+		zero = "*new(" + goName + ")"
+	}
+
+	g.writeLine(fmt.Sprintf("func %sParse(s string) (%s, error) {", goName, goName))
+	g.indent++
+	g.writeLine("switch s {")
+	for _, c := range decl.Cases {
+		g.writeLine(fmt.Sprintf("case %q:", c.Name))
+		g.indent++
+		g.writeLine(fmt.Sprintf("return %s, nil", enumCaseName(goName, c.Name)))
+		g.indent--
+	}
+	g.writeLine("}")
+	g.writeLine(fmt.Sprintf("return %s, fmt.Errorf(\"invalid %s %%q\", s)", zero, decl.Name))
+	g.indent--
+	g.writeLine("}")
+	g.writeLine("")
+}
+
+// generateEnumField 把字段生成为按成员返回值的方法
+func (g *CodeGen) generateEnumField(decl *parser.EnumDecl, goName string, field *parser.ClassField, index int) {
+	typeName := g.generateType(field.Type)
+	methodName := symbol.ToGoName(field.Name, field.Visibility == "public")
+
+	g.writeLine(fmt.Sprintf("func (e %s) %s() %s {", goName, methodName, typeName))
+	g.indent++
+	g.writeLine("switch e {")
+	for _, c := range decl.Cases {
+		value := g.enumFieldValue(decl, c, field, index)
+		if value == nil {
+			continue
+		}
+		g.writeLine(fmt.Sprintf("case %s:", enumCaseName(goName, c.Name)))
+		g.indent++
+		g.writeLine("return " + g.generateExpression(value))
+		g.indent--
+	}
+	g.writeLine("}")
+	g.writeLine("return " + zeroText(typeName))
+	g.indent--
+	g.writeLine("}")
+	g.writeLine("")
+}
+
+// enumFieldValue 返回成员为字段提供的值：按位置或命名参数，没有提供时使用字段的默认值
+// 字段的值只在生成第一个字段时校验，避免同一个错误报告多次
+func (g *CodeGen) enumFieldValue(decl *parser.EnumDecl, c *parser.EnumCase, field *parser.ClassField, index int) parser.Expression {
+	report := index == 0
+	var value parser.Expression
+	positional := 0
+	for _, arg := range c.Args {
+		if na, ok := arg.(*parser.NamedArg); ok {
+			if na.Name == field.Name {
+				value = na.Value
+			} else if report && !enumHasField(decl, na.Name) {
+				g.transpiler.AddError(na.Token.Line, na.Token.Column, i18n.T(i18n.ErrEnumUnknownField, decl.Name, na.Name))
+			}
+			continue
+		}
+		if positional == index {
+			value = arg
+		}
+		positional++
+	}
+	if report && positional > len(decl.Fields) {
+		g.transpiler.AddError(c.Token.Line, c.Token.Column, i18n.T(i18n.ErrEnumFieldCount, decl.Name, c.Name, positional, len(decl.Fields)))
+	}
+	if value == nil {
+		value = field.Value
+	}
+	if value == nil {
+		g.transpiler.AddError(c.Token.Line, c.Token.Column, i18n.T(i18n.ErrEnumMissingField, decl.Name, c.Name, field.Name))
+	}
+	return value
+}
+
+// enumHasField 判断枚举是否声明了字段
+func enumHasField(decl *parser.EnumDecl, name string) bool {
+	for _, field := range decl.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEnumMethod 生成枚举方法（值接收者，this 翻译为接收者）
+func (g *CodeGen) generateEnumMethod(decl *parser.EnumDecl, goName string, method *parser.ClassMethod) {
+	g.markSource(method.Token)
+
+	isPublic := method.Visibility == "public"
+	receiverName := getReceiverName(method.Params, "e")
+	g.write(fmt.Sprintf("func (%s %s) %s(", receiverName, goName, symbol.ToGoName(method.Name, isPublic)))
+	g.generateParams(method.Params)
+	g.write(")")
+
+	// 返回值
+	if len(method.Results) > 0 {
+		g.write(" ")
+		if method.Errable {
+			g.write("(")
+			g.generateParams(method.Results)
+			g.write(", error)")
+		} else if len(method.Results) == 1 && method.Results[0].Name == "" {
+			g.write(g.generateType(method.Results[0].Type))
+		} else {
+			g.write("(")
+			g.generateParams(method.Results)
+			g.write(")")
+		}
+	} else if method.Errable {
+		g.write(" error")
+	}
+	g.writeLine(" {")
+	g.indent++
+
+	g.currentReceiver = receiverName
+	g.currentEnumDecl = decl
+	g.currentFuncErrable = method.Errable
+	g.currentFuncResults = method.Results
+	g.currentFuncParams = make(map[string]bool)
+	g.trackParamTypes(method.Params)
+	for _, param := range method.Params {
+		g.currentFuncParams[param.Name] = true
+	}
+	if method.Body != nil {
+		for _, stmt := range method.Body.Statements {
+			g.generateStatement(stmt)
+		}
+		if method.Errable && !terminates(method.Body.Statements) {
+			g.writeLine("return nil")
+		}
+	}
+	g.currentReceiver = ""
+	g.currentEnumDecl = nil
+	g.currentFuncErrable = false
+	g.currentFuncResults = nil
+	g.currentFuncParams = nil
+
+	g.indent--
+	g.writeLine("}")
+}
+
+// enumStaticTarget 返回 Enum::member 或枚举方法中 self::member 所指的枚举和 Go 包前缀
+func (g *CodeGen) enumStaticTarget(access *parser.StaticAccessExpr) (*parser.EnumDecl, string) {
+	if _, ok := access.Left.(*parser.SelfExpr); ok {
+		return g.currentEnumDecl, ""
+	}
+	ident, ok := access.Left.(*parser.Identifier)
+	if !ok {
+		return nil, ""
+	}
+	decl := g.transpiler.lookupEnum(ident.Value)
+	if decl == nil {
+		return nil, ""
+	}
+	if pkg, ok := g.typeToPackage[ident.Value]; ok {
+		return decl, pkg + "."
+	}
+	return decl, ""
+}
+
+// generateEnumStaticMember 生成 Enum::Case、Enum::values 和 Enum::parse
+func (g *CodeGen) generateEnumStaticMember(access *parser.StaticAccessExpr, decl *parser.EnumDecl, pkgPrefix string) string {
+	goName := symbol.ToGoName(decl.Name, decl.Public)
+	if enumCase(decl, access.Member) != nil {
+		return pkgPrefix + enumCaseName(goName, access.Member)
+	}
+	switch access.Member {
+	case "values":
+		return pkgPrefix + goName + "Values"
+	case "parse":
+		return pkgPrefix + goName + "Parse"
+	}
+	g.transpiler.AddError(access.Token.Line, access.Token.Column, i18n.T(i18n.ErrEnumUnknownMember, decl.Name, access.Member))
+	return pkgPrefix + goName
+}
+
+// enumStaticType 返回 Enum::Case 的类型，不是枚举成员时返回空串
+func (g *CodeGen) enumStaticType(access *parser.StaticAccessExpr) string {
+	decl, _ := g.enumStaticTarget(access)
+	if decl == nil || enumCase(decl, access.Member) == nil {
+		return ""
+	}
+	return decl.Name
+}
+
+// enumCallType 返回 Enum::values() 和 Enum::parse() 的结果类型，不是枚举调用时返回空串
+func (g *CodeGen) enumCallType(access *parser.StaticAccessExpr) string {
+	decl, _ := g.enumStaticTarget(access)
+	if decl == nil {
+		return ""
+	}
+	switch access.Member {
+	case "values":
+		return "[]" + decl.Name
+	case "parse":
+		return decl.Name
+	}
+	return ""
+}
+
+// generateEnumFieldAccess 把枚举字段访问 c.hex 翻译为方法调用 c.Hex()
+func (g *CodeGen) generateEnumFieldAccess(expr *parser.SelectorExpr) (string, bool) {
+	decl := g.transpiler.lookupEnum(typeBaseName(g.exprType(expr.X)))
+	if decl == nil {
+		return "", false
+	}
+	for _, field := range decl.Fields {
+		if field.Name == expr.Sel {
+			return g.generateExpression(expr.X) + "." + symbol.ToGoName(field.Name, field.Visibility == "public") + "()", true
+		}
+	}
+	return "", false
+}
+
+// enumMatchPattern 匹配枚举时分支可以直接写成员名：match(c) { Red => ... }
+func (g *CodeGen) enumMatchPattern(decl *parser.EnumDecl, pattern parser.Expression) (string, bool) {
+	ident, ok := pattern.(*parser.Identifier)
+	if !ok || decl == nil || enumCase(decl, ident.Value) == nil {
+		return "", false
+	}
+	if _, isLocal := g.localTypes[ident.Value]; isLocal {
+		return "", false
+	}
+	access := &parser.StaticAccessExpr{Token: ident.Token, Left: &parser.Identifier{Token: ident.Token, Value: decl.Name}, Member: ident.Value}
+	target, pkgPrefix := g.enumStaticTarget(access)
+	if target == nil {
+		target = decl
+	}
+	return g.generateEnumStaticMember(access, target, pkgPrefix), true
+}
@@ -0,0 +1,115 @@
+package transpiler
+
+import (
+	"fmt"
+
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 异常类
+//
+// 内置的 Exception 类是 tugo 异常的基类，实现在 tugo/runtime 包中（src/runtime/exception.go）：
+//   - message 是错误信息，cause 是导致该异常的错误，Error() 在 message 后附加 cause 的错误信息
+//   - Unwrap() 返回 cause，catch、errors.Is 和 errors.As 沿着 cause 链匹配
+//
+// Exception 没有被同名的本地类型或导入遮蔽时指向 runtime.Exception，不需要导入。
+//
+// 继承 Exception 或声明 implements error 的类，以及它们的子类，是错误类。
+// errors.As 只按具体类型匹配，错误类因此生成 As 方法：先匹配自身，再交给嵌入的父类，
+// 使 catch (e *AppError) 同样能捕获 AppError 的子类。
+
+// builtinPkg 内置类在符号表中的包名（即 tugo/runtime 的包名）
+const builtinPkg = "runtime"
+
+// builtinClasses 内置类名
+var builtinClasses = []string{"Exception"}
+
+// builtinSource 内置类的 tugo 声明，只用于符号收集、校验和构造函数调用，方法体不会生成
+const builtinSource = `package runtime
+
+public class Exception {
+    public message string
+    public cause error
+
+    public func init(message string = "", cause error = nil) {
+    }
+
+    public func Error() string {
+        return ""
+    }
+
+    public func Unwrap() error {
+        return nil
+    }
+}
+`
+
+// registerBuiltins 把内置类的声明收集到符号表
+func (t *Transpiler) registerBuiltins() {
+	file, errs := parser.Parse(builtinSource)
+	if len(errs) > 0 {
+		panic("builtin declarations: " + errs[0])
+	}
+	symbol.NewCollector(t.table).CollectFile(file)
+	t.PreloadDeclarations([]*parser.File{file})
+}
+
+// builtinClass 返回没有被当前包的类型遮蔽的内置类
+func (t *Transpiler) builtinClass(name string) *symbol.ClassInfo {
+	if t.table.Get(t.pkg, name) != nil {
+		return nil
+	}
+	return t.table.GetClass(builtinPkg, name)
+}
+
+// isErrorClass 判断类是否是错误类
+func (t *Transpiler) isErrorClass(info *symbol.ClassInfo) bool {
+	for depth := 0; info != nil && depth < maxInheritanceDepth; depth++ {
+		if info.Package == builtinPkg && t.table.GetClass(builtinPkg, info.Name) == info {
+			return true
+		}
+		for _, iface := range info.Implements {
+			if iface == "error" {
+				return true
+			}
+		}
+		parent := t.parentClassInfo(info)
+		if parent == nil && info.Extends != "" {
+			parent = t.table.GetClass(builtinPkg, info.Extends)
+		}
+		info = parent
+	}
+	return false
+}
+
+// useBuiltinClasses 让没有被本地类型或导入遮蔽的内置类指向 runtime 包
+func (g *CodeGen) useBuiltinClasses() {
+	for _, name := range builtinClasses {
+		if _, imported := g.typeToPackage[name]; imported || g.transpiler.builtinClass(name) == nil {
+			continue
+		}
+		g.typeToPackage[name] = builtinPkg
+		g.tugoImports["tugo/runtime"] = "runtime"
+	}
+}
+
+// generateErrorAs 为错误类生成 As 方法，parentField 是嵌入的错误父类（没有时为空）
+func (g *CodeGen) generateErrorAs(decl *parser.ClassDecl, className, parentField string) {
+	goType := className + g.getTypeParamsUse(decl.TypeParams)
+	g.writeLine(fmt.Sprintf("func (t *%s) As(target any) bool {", goType))
+	g.indent++
+	g.writeLine(fmt.Sprintf("if p, ok := target.(**%s); ok {", goType))
+	g.indent++
+	g.writeLine("*p = t")
+	g.writeLine("return true")
+	g.indent--
+	g.writeLine("}")
+	if parentField != "" {
+		g.writeLine(fmt.Sprintf("return t.%s.As(target)", parentField))
+	} else {
+		g.writeLine("return false")
+	}
+	g.indent--
+	g.writeLine("}")
+}
@@ -0,0 +1,190 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// match 的穷尽检查
+//
+// 主体是枚举、bool 或密封类（sealed abstract class）时可以列出全部取值，
+// 没有 default 分支的 match 必须覆盖每一种情况，否则报错并列出缺少的情况。
+// 密封类只能被同一个包中的类继承，全部非抽象子类都在符号表中。
+// 其他类型没有 default 分支时给出 lint 警告。
+//
+// 没有 default 分支的 match 表达式和可以穷尽检查的 match 语句会生成一个 panic 的 default 分支，
+// 运行时出现未覆盖的值（例如由整数转换得到的非法枚举值）时报告 match 的位置，而不是返回零值。
+
+// checkMatchExhaustive 检查没有 default 分支的 match 是否覆盖了所有情况，返回是否需要 panic 兜底分支
+// match 语句的主体取值无法一一列出时和 switch 一样，没有分支匹配就什么都不执行
+func (g *CodeGen) checkMatchExhaustive(expr *parser.MatchExpr, enum *parser.EnumDecl, isStmt bool) bool {
+	missing, checked := g.matchMissingCases(expr, enum)
+	if !checked {
+		if isStmt {
+			return false
+		}
+		g.transpiler.lint(ruleMatchWithoutDefault, expr.Token.Line, expr.Token.Column,
+			i18n.T(i18n.WarnMatchWithoutDefault))
+		return true
+	}
+	if len(missing) > 0 {
+		g.transpiler.AddError(expr.Token.Line, expr.Token.Column,
+			i18n.T(i18n.ErrMatchNotExhaustive, strings.Join(missing, ", ")))
+	}
+	return true
+}
+
+// matchMissingCases 返回 match 没有覆盖的情况，checked 为 false 表示主体的取值无法一一列出
+func (g *CodeGen) matchMissingCases(expr *parser.MatchExpr, enum *parser.EnumDecl) (missing []string, checked bool) {
+	if enum != nil {
+		return g.enumMissingCases(expr, enum), true
+	}
+	subjectType := g.exprType(expr.Subject)
+	if subjectType == "bool" {
+		return boolMissingCases(expr), true
+	}
+	if sealed := g.sealedClass(subjectType); sealed != nil && expr.IsType {
+		return g.sealedMissingCases(expr, sealed), true
+	}
+	return nil, false
+}
+
+// enumMissingCases 返回没有被任何分支覆盖的枚举成员
+func (g *CodeGen) enumMissingCases(expr *parser.MatchExpr, enum *parser.EnumDecl) []string {
+	covered := make(map[string]bool)
+	for _, arm := range expr.Arms {
+		if arm.Guard != nil {
+			continue
+		}
+		for _, pattern := range arm.Patterns {
+			if name := g.enumPatternCase(enum, pattern); name != "" {
+				covered[name] = true
+			}
+		}
+	}
+	var missing []string
+	for _, c := range enum.Cases {
+		if !covered[c.Name] {
+			missing = append(missing, enum.Name+"::"+c.Name)
+		}
+	}
+	return missing
+}
+
+// enumPatternCase 返回分支模式对应的枚举成员名（Red 或 Color::Red），不是该枚举的成员时返回空串
+func (g *CodeGen) enumPatternCase(enum *parser.EnumDecl, pattern parser.Expression) string {
+	switch p := pattern.(type) {
+	case *parser.Identifier:
+		if _, isLocal := g.localTypes[p.Value]; !isLocal && enumCase(enum, p.Value) != nil {
+			return p.Value
+		}
+	case *parser.StaticAccessExpr:
+		if target, _ := g.enumStaticTarget(p); target != nil && target.Name == enum.Name && enumCase(enum, p.Member) != nil {
+			return p.Member
+		}
+	}
+	return ""
+}
+
+// boolMissingCases 返回没有被覆盖的 true / false
+func boolMissingCases(expr *parser.MatchExpr) []string {
+	covered := make(map[bool]bool)
+	for _, arm := range expr.Arms {
+		if arm.Guard != nil {
+			continue
+		}
+		for _, pattern := range arm.Patterns {
+			if lit, ok := pattern.(*parser.BoolLiteral); ok {
+				covered[lit.Value] = true
+			}
+		}
+	}
+	var missing []string
+	for _, value := range []bool{true, false} {
+		if !covered[value] {
+			missing = append(missing, fmt.Sprint(value))
+		}
+	}
+	return missing
+}
+
+// sealedClass 返回类型对应的密封类，不是密封类时返回 nil
+func (g *CodeGen) sealedClass(typeName string) *symbol.ClassInfo {
+	classInfo := g.lookupClass(typeBaseName(strings.TrimPrefix(typeName, "*")))
+	if classInfo == nil || !classInfo.Sealed {
+		return nil
+	}
+	return classInfo
+}
+
+// sealedMissingCases 返回没有被类型分支覆盖的密封类的非抽象子类
+// 分支写父类（包括中间的抽象类）时覆盖它的全部子类
+func (g *CodeGen) sealedMissingCases(expr *parser.MatchExpr, sealed *symbol.ClassInfo) []string {
+	var covered []*symbol.ClassInfo
+	for _, arm := range expr.Arms {
+		if arm.Guard != nil {
+			continue
+		}
+		for _, pattern := range arm.Patterns {
+			if classInfo := g.lookupClass(g.coveredTypeName(pattern)); classInfo != nil {
+				covered = append(covered, classInfo)
+			}
+		}
+	}
+
+	var missing []string
+	for _, classInfo := range g.transpiler.table.GetAllClasses() {
+		if classInfo.Abstract || classInfo.Package != sealed.Package || !g.transpiler.inheritsFrom(classInfo, sealed) {
+			continue
+		}
+		matched := false
+		for _, arm := range covered {
+			if g.transpiler.inheritsFrom(classInfo, arm) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			missing = append(missing, classInfo.Name)
+		}
+	}
+	return missing
+}
+
+// coveredTypeName 返回类型分支完整覆盖的类型名
+// 解构时只有每个字段都是绑定或 _ 的模式才覆盖整个类型
+func (g *CodeGen) coveredTypeName(pattern parser.Expression) string {
+	switch p := pattern.(type) {
+	case *parser.Identifier:
+		return p.Value
+	case *parser.TypePattern:
+		for _, field := range p.Fields {
+			if field.Value == nil {
+				continue
+			}
+			if ident, ok := field.Value.(*parser.Identifier); !ok || ident.Value != "_" && !g.isMatchBinding(ident, nil) {
+				return ""
+			}
+		}
+		return typeBaseName(canonicalType(p.Type))
+	}
+	return ""
+}
+
+// matchPanicArm 生成没有 default 分支的 match 的兜底分支，未匹配时 panic 并报告 match 的位置
+func (g *CodeGen) matchPanicArm(expr *parser.MatchExpr) string {
+	return fmt.Sprintf("default:\n\tpanic(%q)\n", g.matchPanicMessage(expr))
+}
+
+// matchPanicMessage 返回未匹配时的 panic 信息
+func (g *CodeGen) matchPanicMessage(expr *parser.MatchExpr) string {
+	location := fmt.Sprintf("line %d:%d", expr.Token.Line, expr.Token.Column)
+	if g.transpiler.currentFile != "" {
+		location = fmt.Sprintf("%s.tugo:%d:%d", g.transpiler.currentFile, expr.Token.Line, expr.Token.Column)
+	}
+	return "match: no arm matched at " + location
+}
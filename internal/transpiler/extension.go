@@ -0,0 +1,417 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 扩展方法
+//
+//	extend string {
+//		public func isBlank() bool { return strings.TrimSpace(this) == "" }
+//	}
+//
+// 每个扩展方法生成为包级函数，接收者是第一个参数，this 翻译为该参数：
+//
+//	func StringIsBlank(x string) bool { ... }
+//
+// 调用 s.isBlank() 时按 s 的静态类型查找扩展方法，改写为 StringIsBlank(s)。
+// 接收者类型自身有同名成员（字段、属性、方法，Go 类型的字段和方法）时使用真实成员。
+// 当前包声明的扩展方法直接可见，其他包的公开扩展方法需要按方法名导入：
+//
+//	use "com.demo.util.isBlank"
+//
+// tugo 类和结构体以指针传递（extend User 的接收者是 *User），泛型扩展 extend[T any] []T
+// 的类型参数由 Go 从接收者推断。
+
+// extensionFuncName 返回扩展方法对应的包级函数名：被扩展类型的名称 + 方法名
+func extensionFuncName(ext *symbol.ExtensionInfo) string {
+	name := extensionTypeName(ext.Type, typeParamNames(ext.TypeParams)) + symbol.ToGoName(ext.Method.Name, true)
+	return symbol.ToGoName(name, ext.Method.Visibility == "public")
+}
+
+// extensionTypeName 返回类型在函数名中的写法：[]int -> IntSlice，[]T -> Slice，time.Time -> Time
+func extensionTypeName(typ parser.Expression, typeParams map[string]bool) string {
+	switch t := typ.(type) {
+	case *parser.Identifier:
+		if typeParams[t.Value] {
+			return ""
+		}
+		return symbol.ToGoName(t.Value, true)
+	case *parser.SelectorExpr:
+		return symbol.ToGoName(t.Sel, true)
+	case *parser.PointerType:
+		return extensionTypeName(t.Base, typeParams)
+	case *parser.NullableType:
+		return extensionTypeName(t.Base, typeParams)
+	case *parser.GenericType:
+		return extensionTypeName(t.Type, typeParams)
+	case *parser.SliceType:
+		return extensionTypeName(t.Elt, typeParams) + "Slice"
+	case *parser.ArrayType:
+		return extensionTypeName(t.Elt, typeParams) + "Array"
+	case *parser.MapType:
+		return extensionTypeName(t.Key, typeParams) + extensionTypeName(t.Value, typeParams) + "Map"
+	case *parser.ChanType:
+		return extensionTypeName(t.Value, typeParams) + "Chan"
+	case *parser.FuncType:
+		return "Func"
+	}
+	return "Value"
+}
+
+// extensionTypeParams 返回扩展方法的类型参数：方法自身的在前，extend 的在后
+// 这样调用处可以只写出方法的类型实参，extend 的类型实参由 Go 从接收者推断
+func extensionTypeParams(ext *symbol.ExtensionInfo) *parser.TypeParamList {
+	var params []*parser.TypeParam
+	if ext.Method.TypeParams != nil {
+		params = append(params, ext.Method.TypeParams.Params...)
+	}
+	if ext.TypeParams != nil {
+		params = append(params, ext.TypeParams.Params...)
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return &parser.TypeParamList{Params: params}
+}
+
+// extensionPointer 判断被扩展的类型是否以指针传递（tugo 的非抽象类和结构体）
+func (g *CodeGen) extensionPointer(ext *symbol.ExtensionInfo) bool {
+	if ext.TypePkg == "" {
+		return false
+	}
+	name := stripTypeArgs(canonicalType(ext.Type))
+	if info := g.transpiler.table.GetClass(ext.TypePkg, name); info != nil {
+		return !info.Abstract
+	}
+	return g.transpiler.GetStructDecl(ext.TypePkg, name) != nil
+}
+
+// extensionReceiverType 返回扩展方法接收者的规范类型
+func (g *CodeGen) extensionReceiverType(ext *symbol.ExtensionInfo) string {
+	if g.extensionPointer(ext) {
+		return "*" + canonicalType(ext.Type)
+	}
+	return canonicalType(ext.Type)
+}
+
+// generateExtendDecl 生成 extend 声明中的扩展方法
+func (g *CodeGen) generateExtendDecl(decl *parser.ExtendDecl) {
+	for _, method := range decl.Methods {
+		ext := g.declaredExtension(method)
+		if ext == nil {
+			continue
+		}
+		g.checkExtension(ext)
+		g.generateExtensionFunc(ext)
+		g.writeLine("")
+	}
+}
+
+// declaredExtension 返回符号表中方法对应的扩展信息
+func (g *CodeGen) declaredExtension(method *parser.ClassMethod) *symbol.ExtensionInfo {
+	for _, ext := range g.transpiler.table.GetExtensions(method.Name) {
+		if ext.Method == method {
+			return ext
+		}
+	}
+	return nil
+}
+
+// checkExtension 同一个包中同一类型的扩展方法不能重名
+func (g *CodeGen) checkExtension(ext *symbol.ExtensionInfo) {
+	for _, other := range g.transpiler.table.GetExtensions(ext.Method.Name) {
+		if other == ext {
+			return // 只在后声明的方法上报告
+		}
+		if other.Package == ext.Package && canonicalType(other.Type) == canonicalType(ext.Type) {
+			g.transpiler.AddError(ext.Method.Token.Line, ext.Method.Token.Column,
+				i18n.T(i18n.ErrDuplicateExtension, typeText(ext.Type), ext.Method.Name))
+			return
+		}
+	}
+}
+
+// generateExtensionFunc 生成扩展方法对应的包级函数
+func (g *CodeGen) generateExtensionFunc(ext *symbol.ExtensionInfo) {
+	method := ext.Method
+	g.markSource(method.Token)
+
+	receiverType := g.generateType(ext.Type)
+	if g.extensionPointer(ext) {
+		receiverType = "*" + receiverType
+	}
+	receiverName := getReceiverName(method.Params, "x")
+	g.write(fmt.Sprintf("func %s%s(%s %s", extensionFuncName(ext), g.getTypeParamsDef(extensionTypeParams(ext)), receiverName, receiverType))
+	if len(method.Params) > 0 {
+		g.write(", ")
+		g.generateParams(method.Params)
+	}
+	g.write(")")
+	g.generateMethodReturnSignature(method)
+	g.writeLine(" {")
+	g.indent++
+
+	g.currentReceiver = receiverName
+	g.currentExtendType = g.extensionReceiverType(ext)
+	g.currentFuncErrable = method.Errable
+	g.currentFuncResults = method.Results
+	g.currentFuncParams = make(map[string]bool)
+	g.trackParamTypes(method.Params)
+	for _, param := range method.Params {
+		g.currentFuncParams[param.Name] = true
+	}
+	if method.Body != nil {
+		for _, stmt := range method.Body.Statements {
+			g.generateStatement(stmt)
+		}
+		if method.Errable && !terminates(method.Body.Statements) {
+			g.writeLine("return nil")
+		}
+	}
+	g.currentReceiver = ""
+	g.currentExtendType = ""
+	g.currentFuncErrable = false
+	g.currentFuncResults = nil
+	g.currentFuncParams = nil
+
+	g.indent--
+	g.writeLine("}")
+}
+
+// extensionLookup x.name(...) 查找扩展方法的结果
+type extensionLookup struct {
+	ext         *symbol.ExtensionInfo   // 选中的扩展方法
+	recv        string                  // 接收者的类型
+	ambiguous   []*symbol.ExtensionInfo // 同样匹配的多个扩展方法
+	notImported *symbol.ExtensionInfo   // 匹配但没有导入的扩展方法
+	unresolved  bool                    // 有可见的同名扩展方法，但接收者的类型未知
+}
+
+// lookupExtension 按接收者的静态类型查找 x.name(...) 调用的扩展方法
+// 接收者自身有同名成员时返回 nil；非泛型的扩展比泛型扩展优先。
+// 接收者类型未知时无法判断调用的是哪个方法，有可见的同名扩展方法时标记为 unresolved，
+// 不生成可能是 Go 类型上并不存在的方法调用
+func (g *CodeGen) lookupExtension(sel *parser.SelectorExpr) *extensionLookup {
+	exts := g.transpiler.table.GetExtensions(sel.Sel)
+	if len(exts) == 0 {
+		return nil
+	}
+	recv := defaultType(g.exprType(sel.X))
+	if recv == "" {
+		// pkg.name 是包成员而不是方法调用
+		if ident, ok := sel.X.(*parser.Identifier); ok && !g.localNames[ident.Value] {
+			return nil
+		}
+		for _, ext := range exts {
+			if g.extensionVisible(ext) {
+				return &extensionLookup{unresolved: true}
+			}
+		}
+		return nil
+	}
+	if g.hasMember(recv, sel.Sel) {
+		return nil
+	}
+
+	result := &extensionLookup{recv: recv}
+	var exact, generic []*symbol.ExtensionInfo
+	for _, ext := range exts {
+		if !matchTypeText(g.extensionReceiverType(ext), recv, typeParamNames(ext.TypeParams), map[string]string{}) {
+			continue
+		}
+		if ext.GoPath != "" && g.transpiler.goTypeHasMember(ext.GoPath, typeBaseName(stripTypeArgs(recv)), symbol.ToGoName(sel.Sel, true)) {
+			return nil
+		}
+		switch {
+		case !g.extensionVisible(ext):
+			result.notImported = ext
+		case ext.TypeParams == nil || len(ext.TypeParams.Params) == 0:
+			exact = append(exact, ext)
+		default:
+			generic = append(generic, ext)
+		}
+	}
+
+	candidates := exact
+	if len(candidates) == 0 {
+		candidates = generic
+	}
+	switch len(candidates) {
+	case 0:
+		if result.notImported == nil {
+			return nil
+		}
+	case 1:
+		result.ext = candidates[0]
+	default:
+		result.ambiguous = candidates
+	}
+	return result
+}
+
+// extensionFor 返回 x.name(...) 调用的扩展方法，没有唯一可用的扩展方法时返回 nil
+func (g *CodeGen) extensionFor(sel *parser.SelectorExpr) *symbol.ExtensionInfo {
+	if found := g.lookupExtension(sel); found != nil {
+		return found.ext
+	}
+	return nil
+}
+
+// hasMember 检查类型自身是否有名为 name 的成员（类、结构体、枚举的字段和方法，接口的方法和属性）
+func (g *CodeGen) hasMember(recv, name string) bool {
+	typeName := stripTypeArgs(strings.TrimPrefix(recv, "*"))
+	if owner, _ := g.methodCandidates(g.getClassPackage(typeName), typeName, name, false); owner != nil {
+		return true
+	}
+	if typ, _ := g.memberFieldDecl(recv, name); typ != nil {
+		return true
+	}
+	if iface := g.lookupInterface(typeName); iface != nil {
+		for _, m := range iface.Methods {
+			if m.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extensionVisible 当前包的扩展方法直接可见，其他包的公开扩展方法需要 use 导入
+func (g *CodeGen) extensionVisible(ext *symbol.ExtensionInfo) bool {
+	if ext.Package == g.transpiler.pkg {
+		return true
+	}
+	if ext.Method.Visibility != "public" || g.transpiler.currentParsedFile == nil {
+		return false
+	}
+	for _, imp := range g.transpiler.currentParsedFile.Imports {
+		for _, spec := range imp.Specs {
+			if !spec.IsGoImport && spec.PkgName == ext.Package && spec.TypeName == ext.Method.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateExtensionCall 把扩展方法调用 x.name(args) 改写为包级函数调用 TypeName(x, args)
+// 返回 false 表示不是扩展方法调用
+func (g *CodeGen) generateExtensionCall(call *parser.CallExpr) (string, bool) {
+	sel, typeArgs := methodCallSelector(call.Function)
+	if sel == nil {
+		return "", false
+	}
+	found := g.lookupExtension(sel)
+	if found == nil {
+		return "", false
+	}
+	line, col := sel.Token.Line, sel.Token.Column
+	if found.unresolved {
+		g.transpiler.AddError(line, col, i18n.T(i18n.ErrExtensionReceiverUnknown, sel.Sel))
+		return "/* unresolved call */", true
+	}
+	if len(found.ambiguous) > 0 {
+		var candidates []string
+		for _, ext := range found.ambiguous {
+			candidates = append(candidates, ext.Package+".extend "+typeText(ext.Type))
+		}
+		g.transpiler.AddError(line, col, i18n.T(i18n.ErrAmbiguousExtension, sel.Sel, found.recv, strings.Join(candidates, ", ")))
+		return "/* unresolved call */", true
+	}
+	if found.ext == nil {
+		ext := found.notImported
+		g.transpiler.AddError(line, col, i18n.T(i18n.ErrExtensionNotImported, sel.Sel, found.recv, ext.Package))
+		return "/* unresolved call */", true
+	}
+
+	ext := found.ext
+	name := typeText(ext.Type) + "." + sel.Sel
+	b := g.resolveCall(call, name, []*parser.ClassMethod{ext.Method}, call.Arguments, withTypeParams(typeParamNames(ext.TypeParams), ext.Method.TypeParams))
+	if b == nil {
+		return "/* unresolved call */", true
+	}
+
+	// 显式类型实参只对应方法自身的类型参数
+	instantiation := ""
+	if len(typeArgs) > 0 {
+		var args []string
+		for _, arg := range typeArgs {
+			args = append(args, g.generateType(arg))
+		}
+		instantiation = "[" + strings.Join(args, ", ") + "]"
+	}
+
+	prefix := ""
+	if ext.Package != g.transpiler.pkg {
+		prefix = ext.Package + "."
+	}
+	args := append([]string{g.generateExpression(sel.X)}, g.boundArgs(b)...)
+	return prefix + extensionFuncName(ext) + instantiation + "(" + strings.Join(args, ", ") + ")", true
+}
+
+// matchTypeText 判断规范类型 actual 是否是 pattern 的实例，pattern 中的类型参数可以匹配任意类型
+// 同一个类型参数多次出现时必须匹配相同的类型，bound 记录已绑定的类型
+func matchTypeText(pattern, actual string, typeParams map[string]bool, bound map[string]string) bool {
+	for pattern != "" {
+		word := leadingIdent(pattern)
+		if word == "" {
+			if actual == "" || actual[0] != pattern[0] {
+				return false
+			}
+			pattern, actual = pattern[1:], actual[1:]
+			continue
+		}
+		if typeParams[word] {
+			n := typeTextLen(actual)
+			if n == 0 {
+				return false
+			}
+			if t, ok := bound[word]; ok && t != actual[:n] {
+				return false
+			}
+			bound[word] = actual[:n]
+			pattern, actual = pattern[len(word):], actual[n:]
+			continue
+		}
+		if leadingIdent(actual) != word {
+			return false
+		}
+		pattern, actual = pattern[len(word):], actual[len(word):]
+	}
+	return actual == ""
+}
+
+// leadingIdent 返回类型文本开头的标识符
+func leadingIdent(t string) string {
+	for i, r := range t {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127) {
+			return t[:i]
+		}
+	}
+	return t
+}
+
+// typeTextLen 返回类型文本开头一个完整类型的长度（到同层的 , ] ) 为止）
+func typeTextLen(t string) int {
+	depth := 0
+	for i, r := range t {
+		switch r {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}', ',':
+			if depth == 0 {
+				return i
+			}
+			if r != ',' {
+				depth--
+			}
+		}
+	}
+	return len(t)
+}
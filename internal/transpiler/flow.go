@@ -0,0 +1,701 @@
+package transpiler
+
+import (
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/lexer"
+	"github.com/tangzhangming/tugo/internal/parser"
+)
+
+// 控制流分析
+//
+// 终止语句（执行后不会继续执行下一条语句）的判定与 Go 规范一致：
+//   - return、throw、panic(...)
+//   - 最后一条语句为终止语句的代码块
+//   - if 和 else 分支都终止的 if 语句
+//   - 没有条件且内部没有 break 的 for 循环
+//   - 有 default、没有 break 且每个分支都终止（或 fallthrough）的 switch
+//   - 没有 break 且每个分支都终止的 select
+//   - try 块和 catch 块都终止的 try 语句（没有 catch 时错误被忽略，执行会继续）
+//   - 有 default（或 _）分支、每个分支都是终止的块且没有 break 的 match 语句
+//
+// 基于终止语句的检查：
+//   - 有返回值的函数（包括 int! 这样的 errable 函数）末尾必须是终止语句，否则报错
+//   - 终止语句之后的代码不可达（检查规则 unreachable-code）
+//   - 无返回值的 errable 函数末尾不是终止语句时，代码生成补上 return nil
+//
+// 变量赋值检查（检查规则 use-before-assign）：没有初始值的 var 声明，后面有赋值，
+// 但在某条路径上赋值之前就读取时给出警告。从未赋值的变量（如 var zero T）、切片和 map
+// 类型的变量（零值可以直接使用）以及依赖零值的写法不报告：x++、x += 1、append(x, ...)、
+// len(x)、x.field、x[i]、&x；闭包中的读取也不检查。
+
+// terminates 判断语句列表是否以终止语句结束
+func terminates(stmts []parser.Statement) bool {
+	if len(stmts) == 0 {
+		return false
+	}
+	return isTerminating(stmts[len(stmts)-1])
+}
+
+// isTerminating 判断语句是否是终止语句
+func isTerminating(stmt parser.Statement) bool {
+	switch s := stmt.(type) {
+	case *parser.ReturnStmt, *parser.ThrowStmt:
+		return true
+	case *parser.ExpressionStmt:
+		if m, ok := s.Expression.(*parser.MatchExpr); ok {
+			return matchTerminates(m)
+		}
+		return isPanicCall(s.Expression)
+	case *parser.BlockStmt:
+		return s != nil && terminates(s.Statements)
+	case *parser.IfStmt:
+		if s.Alternative == nil || s.Consequence == nil {
+			return false
+		}
+		return terminates(s.Consequence.Statements) && isTerminating(s.Alternative)
+	case *parser.ForStmt:
+		return s.Condition == nil && !hasBreak(s.Body.Statements)
+	case *parser.SwitchStmt:
+		hasDefault := false
+		for i, c := range s.Cases {
+			if c.Exprs == nil {
+				hasDefault = true
+			}
+			if hasBreak(c.Body) {
+				return false
+			}
+			last := len(c.Body) - 1
+			if last >= 0 {
+				if _, ok := c.Body[last].(*parser.FallthroughStmt); ok && i < len(s.Cases)-1 {
+					continue
+				}
+			}
+			if !terminates(c.Body) {
+				return false
+			}
+		}
+		return hasDefault
+	case *parser.SelectStmt:
+		for _, c := range s.Cases {
+			if hasBreak(c.Body) || !terminates(c.Body) {
+				return false
+			}
+		}
+		return true
+	case *parser.TryStmt:
+		return tryTerminates(s)
+	}
+	return false
+}
+
+// tryTerminates 判断 try 语句是否终止：finally 块终止，或者 try 块正常结束时已经返回、
+// 出错时每个 catch 块都返回（未被捕获的错误继续向外传播）
+func tryTerminates(s *parser.TryStmt) bool {
+	if s.Finally != nil && terminates(s.Finally.Statements) {
+		return true
+	}
+	// 没有 catch 和 finally 时错误被忽略，执行会继续
+	if s.Body == nil || (len(s.Catches) == 0 && s.Finally == nil) {
+		return false
+	}
+	if !terminates(s.Body.Statements) {
+		return false
+	}
+	for _, clause := range s.Catches {
+		if clause.Body == nil || !terminates(clause.Body.Statements) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchTerminates 判断 match 语句是否终止
+// 没有 default 分支时主体可能不匹配任何分支（穷尽检查需要类型信息，这里不做）
+func matchTerminates(m *parser.MatchExpr) bool {
+	hasDefault := false
+	for _, arm := range m.Arms {
+		if arm.IsDefault || arm.Guard == nil && len(arm.Patterns) == 1 && isWildcard(arm.Patterns[0]) {
+			hasDefault = true
+		}
+		if arm.Block == nil || hasBreak(arm.Block.Statements) || !terminates(arm.Block.Statements) {
+			return false
+		}
+	}
+	return hasDefault
+}
+
+// isWildcard 判断 match 模式是否是 _
+func isWildcard(pattern parser.Expression) bool {
+	ident, ok := pattern.(*parser.Identifier)
+	return ok && ident.Value == "_"
+}
+
+// isPanicCall 判断表达式是否是 panic(...) 调用
+func isPanicCall(expr parser.Expression) bool {
+	call, ok := expr.(*parser.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := call.Function.(*parser.Identifier)
+	return ok && ident.Value == "panic"
+}
+
+// hasBreak 判断语句列表中是否有跳出当前 for/switch/select 的 break
+// 嵌套的循环、switch、select 中不带标签的 break 不计入；带标签的 break 保守地计入
+func hasBreak(stmts []parser.Statement) bool {
+	found := false
+	for _, stmt := range stmts {
+		inspect(stmt, func(node parser.Node) bool {
+			switch n := node.(type) {
+			case *parser.BreakStmt:
+				found = true
+			case *parser.ForStmt, *parser.RangeStmt, *parser.SwitchStmt, *parser.SelectStmt:
+				if hasLabeledBreak(n) {
+					found = true
+				}
+				return false
+			case *parser.FuncLiteral:
+				return false
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// hasLabeledBreak 判断节点中是否有带标签的 break
+func hasLabeledBreak(node parser.Node) bool {
+	found := false
+	inspect(node, func(n parser.Node) bool {
+		if b, ok := n.(*parser.BreakStmt); ok && b.Label != "" {
+			found = true
+		}
+		_, isFunc := n.(*parser.FuncLiteral)
+		return !found && !isFunc
+	})
+	return found
+}
+
+// funcBody 参与控制流分析的函数体
+type funcBody struct {
+	name    string
+	token   lexer.Token
+	results []*parser.Field
+	body    *parser.BlockStmt
+}
+
+// fileFuncBodies 收集文件中所有带函数体的函数、方法和闭包
+func fileFuncBodies(file *parser.File) []funcBody {
+	var bodies []funcBody
+	addMethod := func(owner string, m *parser.ClassMethod) {
+		if m != nil && m.Body != nil {
+			bodies = append(bodies, funcBody{owner + "." + m.Name, m.Token, m.Results, m.Body})
+		}
+	}
+	for _, stmt := range file.Statements {
+		switch decl := stmt.(type) {
+		case *parser.ClassDecl:
+			for _, m := range classBodies(decl) {
+				addMethod(decl.Name, m)
+			}
+		case *parser.StructDecl:
+			addMethod(decl.Name, decl.InitMethod)
+			for _, m := range decl.Methods {
+				addMethod(decl.Name, m)
+			}
+		case *parser.EnumDecl:
+			for _, m := range decl.Methods {
+				addMethod(decl.Name, m)
+			}
+		case *parser.ExtendDecl:
+			for _, m := range decl.Methods {
+				addMethod("extend "+typeText(decl.Type), m)
+			}
+		case *parser.FuncDecl:
+			if decl.Body != nil {
+				bodies = append(bodies, funcBody{decl.Name, decl.Token, decl.Results, decl.Body})
+			}
+		}
+	}
+	inspect(file, func(node parser.Node) bool {
+		if lit, ok := node.(*parser.FuncLiteral); ok && lit.Body != nil {
+			bodies = append(bodies, funcBody{"func", lit.Token, lit.Results, lit.Body})
+		}
+		return true
+	})
+	return bodies
+}
+
+// validateReturns 校验有返回值的函数在所有路径上都有返回
+func (t *Transpiler) validateReturns(file *parser.File) {
+	for _, fn := range fileFuncBodies(file) {
+		if len(fn.results) > 0 && !terminates(fn.body.Statements) {
+			t.AddError(fn.token.Line, fn.token.Column, i18n.T(i18n.ErrMissingReturn, fn.name))
+		}
+	}
+}
+
+// lintControlFlow 检查不可达代码和赋值前读取的变量
+func (t *Transpiler) lintControlFlow(file *parser.File) {
+	for _, fn := range fileFuncBodies(file) {
+		t.lintUnreachable(fn.body)
+		a := &assignAnalyzer{t: t, assigned: assignedNames(fn.body), reported: make(map[string]bool)}
+		a.block(fn.body.Statements, make(map[string]bool))
+	}
+}
+
+// lintUnreachable 检查终止语句之后的代码，每个语句列表只报告第一处
+func (t *Transpiler) lintUnreachable(body *parser.BlockStmt) {
+	check := func(stmts []parser.Statement) {
+		for i, stmt := range stmts[:max(len(stmts)-1, 0)] {
+			if !isTerminating(stmt) && !isJump(stmt) {
+				continue
+			}
+			if tok, ok := statementToken(stmts[i+1]); ok {
+				t.lint(ruleUnreachableCode, tok.Line, tok.Column, i18n.T(i18n.WarnUnreachableCode))
+			}
+			return
+		}
+	}
+	inspect(body, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.BlockStmt:
+			check(n.Statements)
+		case *parser.SwitchStmt:
+			for _, c := range n.Cases {
+				check(c.Body)
+			}
+		case *parser.SelectStmt:
+			for _, c := range n.Cases {
+				check(c.Body)
+			}
+		case *parser.FuncLiteral:
+			// 闭包作为独立的函数体分析
+			return false
+		}
+		return true
+	})
+}
+
+// isJump 判断语句是否无条件跳转（break、continue）
+func isJump(stmt parser.Statement) bool {
+	switch stmt.(type) {
+	case *parser.BreakStmt, *parser.ContinueStmt:
+		return true
+	}
+	return false
+}
+
+// assignAnalyzer 变量赋值分析
+// 状态表中值为 true 的变量已声明但在当前路径上尚未赋值
+type assignAnalyzer struct {
+	t        *Transpiler
+	assigned map[string]bool // 函数体中用 = 赋值过的变量
+	reported map[string]bool // 每个变量只报告一次
+}
+
+// assignedNames 收集函数体中用 = 赋值过的变量名（不含闭包）
+// 从未赋值的变量是有意使用零值（如泛型中的 var zero T），不参与检查
+func assignedNames(body *parser.BlockStmt) map[string]bool {
+	names := make(map[string]bool)
+	inspect(body, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.AssignStmt:
+			for _, l := range n.Left {
+				if ident, ok := l.(*parser.Identifier); ok {
+					names[ident.Value] = true
+				}
+			}
+		case *parser.FuncLiteral:
+			return false
+		}
+		return true
+	})
+	return names
+}
+
+// block 分析代码块，返回代码块结束时的状态和是否终止
+// 代码块中声明的变量在代码块结束后恢复为外层的状态
+func (a *assignAnalyzer) block(stmts []parser.Statement, state map[string]bool) (map[string]bool, bool) {
+	outer := copyState(state)
+	declared := make(map[string]bool)
+	done := false
+	for _, stmt := range stmts {
+		if done {
+			break
+		}
+		state, done = a.stmt(stmt, state, declared)
+	}
+	for name := range declared {
+		if unassigned, ok := outer[name]; ok {
+			state[name] = unassigned
+		} else {
+			delete(state, name)
+		}
+	}
+	return state, done
+}
+
+// stmt 分析单条语句，declared 记录当前代码块中声明的变量
+func (a *assignAnalyzer) stmt(stmt parser.Statement, state, declared map[string]bool) (map[string]bool, bool) {
+	switch s := stmt.(type) {
+	case *parser.VarDecl:
+		if s.Value != nil {
+			a.read(s.Value, state)
+		}
+		for _, name := range s.Names {
+			declared[name] = true
+			state[name] = s.Value == nil && a.assigned[name] && !zeroValueUsable(s.Type)
+		}
+	case *parser.ShortVarDecl:
+		a.read(s.Value, state)
+		for _, name := range s.Names {
+			declared[name] = true
+			state[name] = false
+		}
+	case *parser.AssignStmt:
+		for _, r := range s.Right {
+			a.read(r, state)
+		}
+		for _, l := range s.Left {
+			// x += 1 依赖零值，同样视为赋值
+			if ident, ok := l.(*parser.Identifier); ok {
+				state[ident.Value] = false
+				continue
+			}
+			a.readTarget(l, state)
+		}
+	case *parser.IncDecStmt:
+		if ident, ok := s.X.(*parser.Identifier); ok {
+			state[ident.Value] = false
+		} else {
+			a.readTarget(s.X, state)
+		}
+	case *parser.ExpressionStmt:
+		if m, ok := s.Expression.(*parser.MatchExpr); ok {
+			return a.match(m, state)
+		}
+		a.read(s.Expression, state)
+		return state, isPanicCall(s.Expression)
+	case *parser.ReturnStmt:
+		for _, v := range s.Values {
+			a.read(v, state)
+		}
+		return state, true
+	case *parser.ThrowStmt:
+		a.read(s.Value, state)
+		return state, true
+	case *parser.BreakStmt, *parser.ContinueStmt:
+		return state, true
+	case *parser.SendStmt:
+		a.read(s.Channel, state)
+		a.read(s.Value, state)
+	case *parser.GoStmt:
+		a.read(s.Call, state)
+	case *parser.DeferStmt:
+		a.read(s.Call, state)
+	case *parser.BlockStmt:
+		return a.block(s.Statements, state)
+	case *parser.IfStmt:
+		return a.ifStmt(s, state)
+	case *parser.ForStmt:
+		inner, _ := a.block(nil, state)
+		if s.Init != nil {
+			inner, _ = a.block([]parser.Statement{s.Init}, inner)
+		}
+		a.read(s.Condition, inner)
+		body, _ := a.block(s.Body.Statements, copyState(inner))
+		if s.Post != nil {
+			a.block([]parser.Statement{s.Post}, body)
+		}
+		// 循环体可能一次都不执行，循环中的赋值不计入
+		return state, isTerminating(s)
+	case *parser.RangeStmt:
+		a.read(s.X, state)
+		inner := copyState(state)
+		for _, e := range []parser.Expression{s.Key, s.Value} {
+			if ident, ok := e.(*parser.Identifier); ok {
+				inner[ident.Value] = false
+			}
+		}
+		a.block(s.Body.Statements, inner)
+		return state, false
+	case *parser.SwitchStmt:
+		return a.switchStmt(s, state)
+	case *parser.SelectStmt:
+		var merged map[string]bool
+		for _, c := range s.Cases {
+			caseState := copyState(state)
+			if c.Comm != nil {
+				caseState, _ = a.block([]parser.Statement{c.Comm}, caseState)
+			}
+			end, done := a.block(c.Body, caseState)
+			if !done {
+				merged = mergeState(merged, end)
+			}
+		}
+		if merged == nil {
+			return state, len(s.Cases) > 0
+		}
+		return merged, false
+	case *parser.TryStmt:
+		return a.tryStmt(s, state)
+	}
+	return state, false
+}
+
+// ifStmt 分析 if 语句：变量只有在所有未终止的分支中都赋值后才算已赋值
+func (a *assignAnalyzer) ifStmt(s *parser.IfStmt, state map[string]bool) (map[string]bool, bool) {
+	inner := copyState(state)
+	declared := make(map[string]bool)
+	if s.Init != nil {
+		inner, _ = a.stmt(s.Init, inner, declared)
+	}
+	a.read(s.Condition, inner)
+
+	var merged map[string]bool
+	thenState, thenDone := a.block(s.Consequence.Statements, copyState(inner))
+	if !thenDone {
+		merged = mergeState(merged, thenState)
+	}
+	elseDone := false
+	if s.Alternative != nil {
+		var elseState map[string]bool
+		elseState, elseDone = a.stmt(s.Alternative, copyState(inner), make(map[string]bool))
+		if !elseDone {
+			merged = mergeState(merged, elseState)
+		}
+	} else {
+		merged = mergeState(merged, inner)
+	}
+	if merged == nil {
+		return state, thenDone && elseDone
+	}
+	return restoreDeclared(merged, state, declared), false
+}
+
+// switchStmt 分析 switch 语句：没有 default 时可能不执行任何分支
+func (a *assignAnalyzer) switchStmt(s *parser.SwitchStmt, state map[string]bool) (map[string]bool, bool) {
+	inner := copyState(state)
+	declared := make(map[string]bool)
+	if s.Init != nil {
+		inner, _ = a.stmt(s.Init, inner, declared)
+	}
+	a.read(s.Tag, inner)
+
+	var merged map[string]bool
+	hasDefault := false
+	for _, c := range s.Cases {
+		if c.Exprs == nil {
+			hasDefault = true
+		}
+		for _, e := range c.Exprs {
+			a.read(e, inner)
+		}
+		end, done := a.block(c.Body, copyState(inner))
+		if !done || hasBreak(c.Body) {
+			merged = mergeState(merged, end)
+		}
+	}
+	if !hasDefault {
+		merged = mergeState(merged, inner)
+	}
+	if merged == nil {
+		return state, true
+	}
+	return restoreDeclared(merged, state, declared), false
+}
+
+// match 分析 match：变量只有在所有未终止的分支中都赋值后才算已赋值
+// 没有 default 分支的 match 视为穷尽（未匹配时 panic），避免对穷尽的枚举 match 误报
+func (a *assignAnalyzer) match(m *parser.MatchExpr, state map[string]bool) (map[string]bool, bool) {
+	a.read(m.Subject, state)
+	var merged map[string]bool
+	for _, arm := range m.Arms {
+		inner := copyState(state)
+		for _, pattern := range arm.Patterns {
+			a.read(pattern, inner)
+		}
+		a.read(arm.Guard, inner)
+		if arm.Block == nil {
+			a.read(arm.Body, inner)
+			merged = mergeState(merged, inner)
+			continue
+		}
+		end, done := a.block(arm.Block.Statements, inner)
+		if !done || hasBreak(arm.Block.Statements) {
+			merged = mergeState(merged, end)
+		}
+	}
+	if merged == nil {
+		return state, len(m.Arms) > 0
+	}
+	return merged, false
+}
+
+// tryStmt 分析 try 语句：try 块可能在任意位置出错跳到 catch，catch 从 try 之前的状态开始；
+// finally 在所有路径上执行，同样从 try 之前的状态开始
+func (a *assignAnalyzer) tryStmt(s *parser.TryStmt, state map[string]bool) (map[string]bool, bool) {
+	var merged map[string]bool
+	tryState, tryDone := a.block(s.Body.Statements, copyState(state))
+	if !tryDone {
+		merged = mergeState(merged, tryState)
+	}
+	for _, clause := range s.Catches {
+		if clause.Body == nil {
+			continue
+		}
+		catchState := copyState(state)
+		if clause.Param != "" {
+			catchState[clause.Param] = false
+		}
+		end, done := a.block(clause.Body.Statements, catchState)
+		if !done {
+			if clause.Param != "" {
+				end = restoreDeclared(end, state, map[string]bool{clause.Param: true})
+			}
+			merged = mergeState(merged, end)
+		}
+	}
+	if len(s.Catches) == 0 && s.Finally == nil {
+		// 没有 catch 时错误被忽略，执行从出错位置继续
+		merged = mergeState(merged, state)
+	}
+	if s.Finally != nil {
+		finallyState, done := a.block(s.Finally.Statements, mergeState(copyState(state), merged))
+		if done || merged == nil {
+			return state, true
+		}
+		for name, unassigned := range finallyState {
+			if !unassigned {
+				merged[name] = false
+			}
+		}
+	}
+	if merged == nil {
+		return state, true
+	}
+	return merged, false
+}
+
+// read 检查表达式中读取的变量
+func (a *assignAnalyzer) read(expr parser.Expression, state map[string]bool) {
+	if expr == nil {
+		return
+	}
+	inspect(expr, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.Identifier:
+			a.use(n, state)
+		case *parser.FuncLiteral:
+			return false
+		case *parser.MatchExpr:
+			// 块分支中有赋值，按分支分析
+			end, _ := a.match(n, copyState(state))
+			for name := range state {
+				state[name] = end[name]
+			}
+			return false
+		case *parser.UnaryExpr:
+			// &x 通常用于让被调用方写入 x
+			if ident, ok := n.Operand.(*parser.Identifier); ok && n.Operator == "&" {
+				state[ident.Value] = false
+				return false
+			}
+		case *parser.SelectorExpr:
+			return !isIdent(n.X)
+		case *parser.IndexExpr:
+			if isIdent(n.X) {
+				a.read(n.Index, state)
+				return false
+			}
+		case *parser.AppendExpr:
+			if isIdent(n.Slice) {
+				for _, e := range n.Elems {
+					a.read(e, state)
+				}
+				return false
+			}
+		case *parser.LenExpr:
+			return !isIdent(n.X)
+		case *parser.CapExpr:
+			return !isIdent(n.X)
+		}
+		return true
+	})
+}
+
+// readTarget 检查赋值目标中读取的变量（x.field = v、x[i] = v 中的 x 不算读取）
+func (a *assignAnalyzer) readTarget(expr parser.Expression, state map[string]bool) {
+	switch e := expr.(type) {
+	case *parser.SelectorExpr:
+		if !isIdent(e.X) {
+			a.read(e.X, state)
+		}
+	case *parser.IndexExpr:
+		if !isIdent(e.X) {
+			a.read(e.X, state)
+		}
+		a.read(e.Index, state)
+	default:
+		a.read(expr, state)
+	}
+}
+
+// use 报告尚未赋值的变量
+func (a *assignAnalyzer) use(ident *parser.Identifier, state map[string]bool) {
+	if !state[ident.Value] || a.reported[ident.Value] {
+		return
+	}
+	a.reported[ident.Value] = true
+	a.t.lint(ruleUseBeforeAssign, ident.Token.Line, ident.Token.Column,
+		i18n.T(i18n.WarnUseBeforeAssign, ident.Value))
+}
+
+// zeroValueUsable 判断类型的零值是否可以直接使用
+// nil 切片可以 append、range，nil map 可以读取，var out []int 后按条件追加再返回是常见写法
+func zeroValueUsable(typ parser.Expression) bool {
+	switch typ.(type) {
+	case *parser.SliceType, *parser.MapType:
+		return true
+	}
+	return false
+}
+
+// isIdent 判断表达式是否是标识符
+func isIdent(expr parser.Expression) bool {
+	_, ok := expr.(*parser.Identifier)
+	return ok
+}
+
+// copyState 复制赋值状态
+func copyState(state map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(state))
+	for k, v := range state {
+		result[k] = v
+	}
+	return result
+}
+
+// mergeState 合并两条路径的状态：任一路径上未赋值的变量在合并后仍未赋值
+func mergeState(merged, state map[string]bool) map[string]bool {
+	if merged == nil {
+		return copyState(state)
+	}
+	for k, v := range state {
+		merged[k] = merged[k] || v
+	}
+	return merged
+}
+
+// restoreDeclared 把 if/switch 初始化语句中声明的变量恢复为外层的状态
+func restoreDeclared(state, outer, declared map[string]bool) map[string]bool {
+	for name := range declared {
+		if unassigned, ok := outer[name]; ok {
+			state[name] = unassigned
+		} else {
+			delete(state, name)
+		}
+	}
+	return state
+}
@@ -0,0 +1,214 @@
+package transpiler
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/lexer"
+	"github.com/tangzhangming/tugo/internal/parser"
+)
+
+// sourceMapping 记录生成的 Go 代码行与 tugo 源码位置的对应关系
+type sourceMapping struct {
+	goLine   int // 生成代码中的行号（从 1 开始）
+	tugoLine int // tugo 源码行号
+	tugoCol  int // tugo 源码列号
+}
+
+// goSourceError 生成的 Go 代码无法解析时的错误（内部编译器错误）
+type goSourceError struct {
+	line int    // 生成代码中的行号
+	msg  string // go/parser 给出的错误信息
+}
+
+func (e *goSourceError) Error() string {
+	return "line " + strconv.Itoa(e.line) + ": " + e.msg
+}
+
+// formatGoSource 对生成的 Go 代码进行 gofmt 格式化，并移除未使用的导入
+// 如果代码无法解析，返回 *goSourceError，调用方负责将其映射回 tugo 源码位置
+func formatGoSource(code string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "", code, goparser.ParseComments|goparser.SkipObjectResolution)
+	if err != nil {
+		if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+			return "", &goSourceError{line: list[0].Pos.Line, msg: list[0].Msg}
+		}
+		return "", &goSourceError{line: 1, msg: err.Error()}
+	}
+
+	pruneUnusedImports(file)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return "", &goSourceError{line: 1, msg: err.Error()}
+	}
+
+	// 再格式化一次，清理删除导入后留下的空行
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", &goSourceError{line: 1, msg: err.Error()}
+	}
+	return string(out), nil
+}
+
+// pruneUnusedImports 删除文件中没有被引用的导入
+// 只处理包名可以从路径确定的导入，无法确定包名的导入保持原样
+func pruneUnusedImports(file *ast.File) {
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var decls []ast.Decl
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+		var specs []ast.Spec
+		for _, spec := range gen.Specs {
+			imp := spec.(*ast.ImportSpec)
+			name, known := importName(imp)
+			if !known || used[name] {
+				specs = append(specs, spec)
+			}
+		}
+		if len(specs) == 0 {
+			continue
+		}
+		if len(specs) == 1 && len(gen.Specs) > 1 {
+			// 只剩一个导入时去掉括号，与直接生成的单个导入保持一致
+			gen.Lparen, gen.Rparen = token.NoPos, token.NoPos
+		}
+		gen.Specs = specs
+		decls = append(decls, gen)
+	}
+	file.Decls = decls
+
+	var imports []*ast.ImportSpec
+	for _, imp := range file.Imports {
+		name, known := importName(imp)
+		if !known || used[name] {
+			imports = append(imports, imp)
+		}
+	}
+	file.Imports = imports
+}
+
+// importName 返回导入在代码中使用的包名
+// 第二个返回值为 false 表示包名无法确定（或为 _ / . 导入），此时不能删除
+func importName(imp *ast.ImportSpec) (string, bool) {
+	if imp.Name != nil {
+		if imp.Name.Name == "_" || imp.Name.Name == "." {
+			return "", false
+		}
+		return imp.Name.Name, true
+	}
+	path, err := strconv.Unquote(imp.Path.Value)
+	if err != nil {
+		return "", false
+	}
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	// gopkg.in/yaml.v3、example.com/foo/v2 这类路径的包名无法可靠推断
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && isDigits(name[1:]) {
+		return "", false
+	}
+	if !token.IsIdentifier(name) {
+		return "", false
+	}
+	return name, true
+}
+
+// isDigits 检查字符串是否全部由数字组成
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// statementToken 获取语句的起始 token（用于源码位置映射）
+func statementToken(stmt parser.Statement) (lexer.Token, bool) {
+	switch s := stmt.(type) {
+	case *parser.ClassDecl:
+		return s.Token, true
+	case *parser.StructDecl:
+		return s.Token, true
+	case *parser.EnumDecl:
+		return s.Token, true
+	case *parser.ExtendDecl:
+		return s.Token, true
+	case *parser.InterfaceDecl:
+		return s.Token, true
+	case *parser.TypeDecl:
+		return s.Token, true
+	case *parser.FuncDecl:
+		return s.Token, true
+	case *parser.VarDecl:
+		return s.Token, true
+	case *parser.ConstDecl:
+		return s.Token, true
+	case *parser.ShortVarDecl:
+		return s.Token, true
+	case *parser.AssignStmt:
+		return s.Token, true
+	case *parser.ReturnStmt:
+		return s.Token, true
+	case *parser.ThrowStmt:
+		return s.Token, true
+	case *parser.TryStmt:
+		return s.Token, true
+	case *parser.IfStmt:
+		return s.Token, true
+	case *parser.ForStmt:
+		return s.Token, true
+	case *parser.RangeStmt:
+		return s.Token, true
+	case *parser.SwitchStmt:
+		return s.Token, true
+	case *parser.SelectStmt:
+		return s.Token, true
+	case *parser.GoStmt:
+		return s.Token, true
+	case *parser.DeferStmt:
+		return s.Token, true
+	case *parser.BreakStmt:
+		return s.Token, true
+	case *parser.ContinueStmt:
+		return s.Token, true
+	case *parser.BlockStmt:
+		return s.Token, true
+	case *parser.SendStmt:
+		return s.Token, true
+	case *parser.IncDecStmt:
+		return s.Token, true
+	case *parser.ExpressionStmt:
+		switch e := s.Expression.(type) {
+		case *parser.CallExpr:
+			return e.Token, true
+		case *parser.Identifier:
+			return e.Token, true
+		case *parser.SelectorExpr:
+			return e.Token, true
+		case *parser.StaticAccessExpr:
+			return e.Token, true
+		}
+	}
+	return lexer.Token{}, false
+}
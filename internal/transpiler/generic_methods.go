@@ -0,0 +1,325 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 泛型方法（方法自身带类型参数）
+//
+// Go 不允许方法声明类型参数，因此
+//
+//	public func map[U any](f func(T) U) *List[U]
+//
+// 被降级为包级泛型函数，接收者作为第一个参数：
+//
+//	func ListMap[U any, T any](t *List[T], f func(T) U) *List[U]
+//
+// 方法自身的类型参数排在类的类型参数之前，这样调用处 obj.map[int](f)
+// 可以只写出方法的类型实参（ListMap[int](obj, f)），类的类型实参由 Go 从接收者推断。
+
+// isGenericMethod 检查方法是否带有自身的类型参数
+func isGenericMethod(method *parser.ClassMethod) bool {
+	return method != nil && !method.Static && method.TypeParams != nil && len(method.TypeParams.Params) > 0
+}
+
+// methodCallSelector 获取方法调用的选择器，支持带类型实参的调用 obj.map[U](...)
+// 返回选择器和显式类型实参
+func methodCallSelector(fn parser.Expression) (*parser.SelectorExpr, []parser.Expression) {
+	switch f := fn.(type) {
+	case *parser.SelectorExpr:
+		return f, nil
+	case *parser.IndexExpr:
+		if sel, ok := f.X.(*parser.SelectorExpr); ok {
+			return sel, []parser.Expression{f.Index}
+		}
+	case *parser.GenericType:
+		if sel, ok := f.Type.(*parser.SelectorExpr); ok {
+			return sel, f.TypeArgs
+		}
+	}
+	return nil, nil
+}
+
+// genericMethodFuncName 生成泛型方法对应的包级函数名
+// 命名规则与静态方法一致：公开方法 -> ClassName + MethodName，私有方法 -> className + MethodName
+func genericMethodFuncName(className string, classPublic bool, method *parser.ClassMethod, overloaded bool) string {
+	isPublic := method.Visibility == "public" || method.Visibility == "protected"
+	suffix := symbol.ToGoName(method.Name, true)
+	if overloaded {
+		suffix = symbol.GenerateMangledName(method.Name, method.Params, true)
+	}
+	if isPublic {
+		return symbol.ToGoName(className, classPublic) + suffix
+	}
+	return strings.ToLower(className[:1]) + className[1:] + suffix
+}
+
+// generateGenericClassMethod 生成泛型方法对应的包级函数
+// structName 是接收者的 Go 结构体名（抽象类为 xxxBase）
+func (g *CodeGen) generateGenericClassMethod(decl *parser.ClassDecl, structName string, method *parser.ClassMethod) {
+	overloaded := g.transpiler.table.IsMethodOverloaded(g.transpiler.pkg, decl.Name, method.Name)
+	funcName := genericMethodFuncName(decl.Name, decl.Public, method, overloaded)
+
+	// 类型参数：方法的在前，类的在后
+	allParams := append([]*parser.TypeParam{}, method.TypeParams.Params...)
+	if decl.TypeParams != nil {
+		allParams = append(allParams, decl.TypeParams.Params...)
+	}
+	typeParams := g.getTypeParamsDef(&parser.TypeParamList{Params: allParams})
+
+	receiverName := getReceiverName(method.Params, "t")
+
+	g.writeIndent()
+	g.write(fmt.Sprintf("func %s%s(%s *%s%s", funcName, typeParams, receiverName, structName, g.getTypeParamsUse(decl.TypeParams)))
+	for _, param := range method.Params {
+		g.write(", ")
+		g.write(symbol.TransformDollarVar(param.Name))
+		g.write(" ")
+		g.write(g.generateType(param.Type))
+	}
+	g.write(")")
+	g.generateMethodReturnSignature(method)
+	g.writeLine(" {")
+	g.indent++
+
+	if method.Body != nil {
+		savedClassDecl := g.currentClassDecl
+		g.currentClassDecl = decl
+		g.currentReceiver = receiverName
+		g.currentFuncErrable = method.Errable
+		g.currentFuncResults = method.Results
+		g.currentFuncParams = make(map[string]bool)
+		g.trackParamTypes(method.Params)
+		for _, param := range method.Params {
+			g.currentFuncParams[param.Name] = true
+		}
+		for _, stmt := range method.Body.Statements {
+			g.generateStatement(stmt)
+		}
+
+		// 对于 errable 方法，如果函数体不是以终止语句结束，添加 return nil
+		if method.Errable && !terminates(method.Body.Statements) {
+			g.writeLine("return nil")
+		}
+
+		g.currentReceiver = ""
+		g.currentFuncErrable = false
+		g.currentFuncResults = nil
+		g.currentFuncParams = nil
+		g.currentClassDecl = savedClassDecl
+	}
+
+	g.indent--
+	g.writeLine("}")
+}
+
+// genericMethodTarget 泛型方法调用的解析结果
+type genericMethodTarget struct {
+	classPkg  string            // 声明方法的类所在包
+	pkgPrefix string            // 跨包调用时的包前缀（如 "models."）
+	class     *symbol.ClassInfo // 声明方法的类
+	method    *parser.ClassMethod
+	path      []string // 从接收者到声明类的嵌入字段路径（继承的方法）
+}
+
+// generateGenericMethodCall 将泛型方法调用改写为包级函数调用
+// obj.map[U](f) / obj.map(f) -> ListMap[U](obj, f) / ListMap(obj, f)
+// 返回 false 表示不是泛型方法调用
+func (g *CodeGen) generateGenericMethodCall(call *parser.CallExpr) (string, bool) {
+	sel, typeArgs := methodCallSelector(call.Function)
+	if sel == nil {
+		return "", false
+	}
+	target := g.resolveGenericMethod(sel, len(call.Arguments))
+	if target == nil {
+		return "", false
+	}
+	method := target.method
+
+	// 类型实参
+	instantiation := ""
+	if len(typeArgs) > 0 {
+		if len(typeArgs) != len(method.TypeParams.Params) {
+			g.transpiler.AddError(sel.Token.Line, sel.Token.Column, i18n.T(i18n.ErrMethodTypeArgCount,
+				target.class.Name, method.Name, len(method.TypeParams.Params), len(typeArgs)))
+			return "", true
+		}
+		var args []string
+		for _, arg := range typeArgs {
+			args = append(args, g.generateType(arg))
+		}
+		instantiation = "[" + strings.Join(args, ", ") + "]"
+	} else {
+		// 方法的类型参数只能从方法参数中推断
+		used := make(map[string]bool)
+		for _, param := range method.Params {
+			for name := range collectIdentifiers(param.Type) {
+				used[name] = true
+			}
+		}
+		for _, param := range method.TypeParams.Params {
+			if !used[param.Name] {
+				g.transpiler.AddError(sel.Token.Line, sel.Token.Column, i18n.T(i18n.ErrCannotInferMethodTypeArg,
+					param.Name, target.class.Name, method.Name))
+				return "", true
+			}
+		}
+	}
+
+	// 接收者：继承的方法需要沿嵌入字段找到声明类的实例
+	receiver := g.generateExpression(sel.X)
+	for i, field := range target.path {
+		receiver += "." + field
+		if i == len(target.path)-1 && target.class.Abstract {
+			// 抽象类以值的形式嵌入基础结构体
+			receiver = "&" + receiver
+		}
+	}
+
+	args := []string{receiver}
+	for _, arg := range call.Arguments {
+		args = append(args, g.generateArgumentExpr(arg))
+	}
+
+	overloaded := g.transpiler.table.IsMethodOverloaded(target.classPkg, target.class.Name, method.Name)
+	funcName := genericMethodFuncName(target.class.Name, target.class.Public, method, overloaded)
+	return target.pkgPrefix + funcName + instantiation + "(" + strings.Join(args, ", ") + ")", true
+}
+
+// resolveGenericMethod 解析选择器调用的泛型方法
+// 优先使用接收者的已知类型；无法确定时按方法名在所有类中查找，
+// 只有唯一一个类声明了该泛型方法时才能确定
+func (g *CodeGen) resolveGenericMethod(sel *parser.SelectorExpr, argCount int) *genericMethodTarget {
+	if receiverType := g.getReceiverType(sel.X); receiverType != "" {
+		classPkg := g.getClassPackage(receiverType)
+		if g.transpiler.table.GetClass(classPkg, receiverType) != nil {
+			return g.findGenericMethodInChain(classPkg, receiverType, sel.Sel, argCount, nil)
+		}
+	}
+
+	// 按方法名查找
+	var found *genericMethodTarget
+	var classes []string
+	for _, classInfo := range g.transpiler.table.GetAllClasses() {
+		if classInfo.Package != g.transpiler.pkg && !g.isClassImported(classInfo) {
+			continue
+		}
+		if method := findGenericMethod(classInfo, sel.Sel, argCount); method != nil {
+			found = g.newGenericMethodTarget(classInfo, method, nil)
+			classes = append(classes, classInfo.Name)
+		}
+	}
+	if len(classes) > 1 {
+		g.transpiler.AddError(sel.Token.Line, sel.Token.Column, i18n.T(i18n.ErrAmbiguousGenericMethod,
+			sel.Sel, strings.Join(classes, ", ")))
+		return nil
+	}
+	return found
+}
+
+// findGenericMethod 在类中查找泛型方法，重载时按参数个数选择
+func findGenericMethod(classInfo *symbol.ClassInfo, methodName string, argCount int) *parser.ClassMethod {
+	var found *parser.ClassMethod
+	for _, method := range classInfo.Methods {
+		if method.Name != methodName || !isGenericMethod(method) {
+			continue
+		}
+		if len(method.Params) == argCount {
+			return method
+		}
+		if found == nil {
+			found = method
+		}
+	}
+	return found
+}
+
+// hasMethodNamed 检查类中是否声明了指定名称的方法
+func hasMethodNamed(classInfo *symbol.ClassInfo, methodName string) bool {
+	for _, method := range classInfo.Methods {
+		if method.Name == methodName {
+			return true
+		}
+	}
+	return false
+}
+
+// findGenericMethodInChain 在类及其父类中查找泛型方法
+func (g *CodeGen) findGenericMethodInChain(classPkg, className, methodName string, argCount int, path []string) *genericMethodTarget {
+	classInfo := g.transpiler.table.GetClass(classPkg, className)
+	if classInfo == nil {
+		return nil
+	}
+	if hasMethodNamed(classInfo, methodName) {
+		// 子类声明的同名普通方法会遮蔽父类的泛型方法
+		if method := findGenericMethod(classInfo, methodName, argCount); method != nil {
+			return g.newGenericMethodTarget(classInfo, method, path)
+		}
+		return nil
+	}
+	if classInfo.Extends == "" {
+		return nil
+	}
+
+	// 父类的嵌入字段名：普通类为 *Parent，抽象类为 parentBase
+	parentPkg := classInfo.Package
+	if g.transpiler.table.GetClass(parentPkg, classInfo.Extends) == nil {
+		if pkg, ok := g.typeToPackage[classInfo.Extends]; ok {
+			parentPkg = pkg
+		}
+	}
+	parentInfo := g.transpiler.table.GetClass(parentPkg, classInfo.Extends)
+	if parentInfo == nil {
+		return nil
+	}
+	field := symbol.ToGoName(classInfo.Extends, parentInfo.Public)
+	if parentInfo.Abstract {
+		field = abstractBaseName(classInfo.Extends, parentInfo.Public)
+	}
+	return g.findGenericMethodInChain(parentPkg, classInfo.Extends, methodName, argCount, append(path, field))
+}
+
+// newGenericMethodTarget 创建泛型方法调用目标
+func (g *CodeGen) newGenericMethodTarget(classInfo *symbol.ClassInfo, method *parser.ClassMethod, path []string) *genericMethodTarget {
+	target := &genericMethodTarget{
+		classPkg: classInfo.Package,
+		class:    classInfo,
+		method:   method,
+		path:     path,
+	}
+	if classInfo.Package != g.transpiler.pkg {
+		target.pkgPrefix = classInfo.Package + "."
+	}
+	return target
+}
+
+// isClassImported 检查其他包的类是否通过 use 导入到当前文件
+func (g *CodeGen) isClassImported(classInfo *symbol.ClassInfo) bool {
+	pkg, ok := g.typeToPackage[classInfo.Name]
+	return ok && pkg == classInfo.Package
+}
+
+// validateGenericMethods 校验泛型方法的使用限制
+func (t *Transpiler) validateGenericMethods(classDecl *parser.ClassDecl) {
+	for _, method := range classDecl.AbstractMethods {
+		if method.TypeParams != nil && len(method.TypeParams.Params) > 0 {
+			t.errors = append(t.errors, i18n.T(i18n.ErrGenericMethodAbstract, classDecl.Name, method.Name))
+		}
+	}
+	for _, method := range classDecl.Methods {
+		if !isGenericMethod(method) {
+			continue
+		}
+		for _, param := range method.Params {
+			if param.DefaultValue != nil {
+				t.errors = append(t.errors, i18n.T(i18n.ErrGenericMethodDefaults, classDecl.Name, method.Name))
+				break
+			}
+		}
+	}
+}
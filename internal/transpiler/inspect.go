@@ -0,0 +1,256 @@
+package transpiler
+
+import "github.com/tangzhangming/tugo/internal/parser"
+
+// inspect 按深度优先顺序遍历 AST 节点，类似 go/ast.Inspect
+// fn 返回 false 时不再遍历该节点的子节点；类型表达式不会被遍历
+func inspect(node parser.Node, fn func(parser.Node) bool) {
+	if node == nil || isNilNode(node) || !fn(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *parser.File:
+		for _, stmt := range n.Statements {
+			inspect(stmt, fn)
+		}
+
+	// 声明
+	case *parser.ClassDecl:
+		for _, field := range n.Fields {
+			inspectExpr(field.Value, fn)
+		}
+		for _, init := range n.InitMethods {
+			inspectMethod(init, fn)
+		}
+		if len(n.InitMethods) == 0 {
+			inspectMethod(n.InitMethod, fn)
+		}
+		for _, method := range n.Methods {
+			inspectMethod(method, fn)
+		}
+	case *parser.StructDecl:
+		inspectMethod(n.InitMethod, fn)
+		for _, method := range n.Methods {
+			inspectMethod(method, fn)
+		}
+	case *parser.EnumDecl:
+		for _, c := range n.Cases {
+			inspectExpr(c.Value, fn)
+			for _, arg := range c.Args {
+				inspectExpr(arg, fn)
+			}
+		}
+		for _, method := range n.Methods {
+			inspectMethod(method, fn)
+		}
+	case *parser.ExtendDecl:
+		for _, method := range n.Methods {
+			inspectMethod(method, fn)
+		}
+	case *parser.FuncDecl:
+		inspectBlock(n.Body, fn)
+	case *parser.VarDecl:
+		inspectExpr(n.Value, fn)
+	case *parser.ConstDecl:
+		inspectExpr(n.Value, fn)
+
+	// 语句
+	case *parser.ShortVarDecl:
+		inspectExpr(n.Value, fn)
+	case *parser.AssignStmt:
+		inspectExprs(n.Left, fn)
+		inspectExprs(n.Right, fn)
+	case *parser.BlockStmt:
+		for _, stmt := range n.Statements {
+			inspect(stmt, fn)
+		}
+	case *parser.ReturnStmt:
+		inspectExprs(n.Values, fn)
+	case *parser.IfStmt:
+		inspect(n.Init, fn)
+		inspectExpr(n.Condition, fn)
+		inspectBlock(n.Consequence, fn)
+		inspect(n.Alternative, fn)
+	case *parser.ForStmt:
+		inspect(n.Init, fn)
+		inspectExpr(n.Condition, fn)
+		inspect(n.Post, fn)
+		inspectBlock(n.Body, fn)
+	case *parser.RangeStmt:
+		inspectExpr(n.Key, fn)
+		inspectExpr(n.Value, fn)
+		inspectExpr(n.X, fn)
+		inspectBlock(n.Body, fn)
+	case *parser.SwitchStmt:
+		inspect(n.Init, fn)
+		inspectExpr(n.Tag, fn)
+		for _, c := range n.Cases {
+			inspectExprs(c.Exprs, fn)
+			for _, stmt := range c.Body {
+				inspect(stmt, fn)
+			}
+		}
+	case *parser.SelectStmt:
+		for _, c := range n.Cases {
+			inspect(c.Comm, fn)
+			for _, stmt := range c.Body {
+				inspect(stmt, fn)
+			}
+		}
+	case *parser.GoStmt:
+		inspectExpr(n.Call, fn)
+	case *parser.DeferStmt:
+		inspectExpr(n.Call, fn)
+	case *parser.TryStmt:
+		inspectBlock(n.Body, fn)
+		for _, clause := range n.Catches {
+			inspect(clause, fn)
+		}
+		inspectBlock(n.Finally, fn)
+	case *parser.CatchClause:
+		inspectBlock(n.Body, fn)
+	case *parser.ThrowStmt:
+		inspectExpr(n.Value, fn)
+	case *parser.ExpressionStmt:
+		inspectExpr(n.Expression, fn)
+	case *parser.SendStmt:
+		inspectExpr(n.Channel, fn)
+		inspectExpr(n.Value, fn)
+	case *parser.IncDecStmt:
+		inspectExpr(n.X, fn)
+
+	// 表达式
+	case *parser.ArrayLiteral:
+		inspectExprs(n.Elements, fn)
+	case *parser.SliceLiteral:
+		inspectExprs(n.Elements, fn)
+	case *parser.MapLiteral:
+		for _, pair := range n.Pairs {
+			inspectExpr(pair.Key, fn)
+			inspectExpr(pair.Value, fn)
+		}
+	case *parser.StructLiteral:
+		for _, field := range n.Fields {
+			inspectExpr(field.Value, fn)
+		}
+	case *parser.InterpolatedString:
+		for _, part := range n.Parts {
+			inspectExpr(part.Expr, fn)
+		}
+	case *parser.BinaryExpr:
+		inspectExpr(n.Left, fn)
+		inspectExpr(n.Right, fn)
+	case *parser.TernaryExpr:
+		inspectExpr(n.Condition, fn)
+		inspectExpr(n.TrueExpr, fn)
+		inspectExpr(n.FalseExpr, fn)
+	case *parser.MatchExpr:
+		inspectExpr(n.Subject, fn)
+		for _, arm := range n.Arms {
+			for _, pattern := range arm.Patterns {
+				// 类型匹配中的类型名不是表达式
+				if _, isType := pattern.(*parser.Identifier); !n.IsType || !isType {
+					inspectExpr(pattern, fn)
+				}
+			}
+			inspectExpr(arm.Guard, fn)
+			inspectExpr(arm.Body, fn)
+			inspectBlock(arm.Block, fn)
+		}
+	case *parser.TypePattern:
+		for _, field := range n.Fields {
+			inspectExpr(field.Value, fn)
+		}
+	case *parser.RangePattern:
+		inspectExpr(n.Low, fn)
+		inspectExpr(n.High, fn)
+	case *parser.UnaryExpr:
+		inspectExpr(n.Operand, fn)
+	case *parser.CallExpr:
+		inspectExpr(n.Function, fn)
+		inspectExprs(n.Arguments, fn)
+	case *parser.IndexExpr:
+		inspectExpr(n.X, fn)
+		inspectExpr(n.Index, fn)
+	case *parser.SliceExpr:
+		inspectExpr(n.X, fn)
+		inspectExpr(n.Low, fn)
+		inspectExpr(n.High, fn)
+		inspectExpr(n.Max, fn)
+	case *parser.SelectorExpr:
+		inspectExpr(n.X, fn)
+	case *parser.StaticAccessExpr:
+		inspectExpr(n.Left, fn)
+	case *parser.TypeAssertExpr:
+		inspectExpr(n.X, fn)
+	case *parser.FuncLiteral:
+		inspectBlock(n.Body, fn)
+	case *parser.ReceiveExpr:
+		inspectExpr(n.X, fn)
+	case *parser.ParenExpr:
+		inspectExpr(n.X, fn)
+	case *parser.MakeExpr:
+		inspectExprs(n.Args, fn)
+	case *parser.NewExpr:
+		inspectExprs(n.Arguments, fn)
+	case *parser.NamedArg:
+		inspectExpr(n.Value, fn)
+	case *parser.LenExpr:
+		inspectExpr(n.X, fn)
+	case *parser.CapExpr:
+		inspectExpr(n.X, fn)
+	case *parser.AppendExpr:
+		inspectExpr(n.Slice, fn)
+		inspectExprs(n.Elems, fn)
+	case *parser.CopyExpr:
+		inspectExpr(n.Dst, fn)
+		inspectExpr(n.Src, fn)
+	case *parser.DeleteExpr:
+		inspectExpr(n.Map, fn)
+		inspectExpr(n.Key, fn)
+	}
+}
+
+// inspectMethod 遍历方法体
+func inspectMethod(method *parser.ClassMethod, fn func(parser.Node) bool) {
+	if method != nil {
+		inspectBlock(method.Body, fn)
+	}
+}
+
+// inspectBlock 遍历代码块（nil 时跳过）
+func inspectBlock(block *parser.BlockStmt, fn func(parser.Node) bool) {
+	if block != nil {
+		inspect(block, fn)
+	}
+}
+
+// inspectExpr 遍历表达式（nil 时跳过）
+func inspectExpr(expr parser.Expression, fn func(parser.Node) bool) {
+	if expr != nil {
+		inspect(expr, fn)
+	}
+}
+
+// inspectExprs 遍历表达式列表
+func inspectExprs(exprs []parser.Expression, fn func(parser.Node) bool) {
+	for _, expr := range exprs {
+		inspectExpr(expr, fn)
+	}
+}
+
+// isNilNode 判断接口中保存的是否为 nil 指针（如 Statement(nil *IfStmt)）
+func isNilNode(node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.BlockStmt:
+		return n == nil
+	case *parser.IfStmt:
+		return n == nil
+	case *parser.CallExpr:
+		return n == nil
+	case *parser.CatchClause:
+		return n == nil
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package transpiler
+
+import (
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/parser"
+)
+
+// 插值字符串
+//
+// "Hello ${user.name}, you are ${age} years old" 翻译为 fmt.Sprintf，
+// 每个表达式按推断出的类型选择格式动词：
+//   - string       -> %s
+//   - 整数         -> %d
+//   - 浮点数/复数  -> %g
+//   - bool         -> %t
+//   - 字符常量     -> %c
+//   - 其他或未知   -> %v
+//
+// ${price:.2f} 中冒号后的格式说明直接作为动词使用，省略动词时（如 ${price:.2}）补上类型对应的动词。
+// 不含表达式的插值字符串（只有 \$ 转义）翻译为普通字符串字面量。
+
+// generateInterpolatedString 生成插值字符串
+func (g *CodeGen) generateInterpolatedString(lit *parser.InterpolatedString) string {
+	var text, format strings.Builder
+	var args []string
+	for _, part := range lit.Parts {
+		if part.Expr == nil {
+			text.WriteString(part.Text)
+			format.WriteString(strings.ReplaceAll(part.Text, "%", "%%"))
+			continue
+		}
+		format.WriteString(g.interpolationVerb(part))
+		args = append(args, g.generateExpression(part.Expr))
+	}
+
+	if len(args) == 0 {
+		return "\"" + text.String() + "\""
+	}
+	g.transpiler.needFmt = true
+	return "fmt.Sprintf(\"" + format.String() + "\", " + strings.Join(args, ", ") + ")"
+}
+
+// interpolationVerb 返回插值片段对应的格式动词
+func (g *CodeGen) interpolationVerb(part *parser.InterpolationPart) string {
+	spec := part.Spec
+	if spec != "" {
+		last := spec[len(spec)-1]
+		if last >= 'a' && last <= 'z' || last >= 'A' && last <= 'Z' {
+			return "%" + spec
+		}
+	}
+
+	t := g.exprType(part.Expr)
+	switch {
+	case t == "untyped rune":
+		return "%" + spec + "c"
+	case isFloatType(defaultType(t)):
+		if spec != "" {
+			// 只指定了宽度或精度时，浮点数使用定点格式
+			return "%" + spec + "f"
+		}
+		return "%g"
+	}
+	switch t = defaultType(t); {
+	case t == "string":
+		return "%" + spec + "s"
+	case isIntegerType(t):
+		return "%" + spec + "d"
+	case t == "bool":
+		return "%" + spec + "t"
+	}
+	return "%" + spec + "v"
+}
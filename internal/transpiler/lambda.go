@@ -0,0 +1,660 @@
+package transpiler
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 箭头 lambda
+//
+//	xs.filter(x => x > 3)
+//	slices.SortFunc(users, (a, b) => a.age - b.age)
+//	var add func(int, int) int = (a, b) => { return a + b }
+//
+// 省略类型的参数按 lambda 所在位置期望的函数类型推断：调用的实参（tugo 函数、方法、构造函数、
+// Go 包函数和函数类型的变量）、带类型的变量声明、赋值和 return。期望类型中的类型参数由
+// 接收者的类型实参和其他实参的类型确定，Go 泛型函数还通过约束的核心类型（S ~[]E 中的 []E）推导。
+// 期望类型没有确定返回值类型时按函数体推断：表达式函数体取表达式的类型，块函数体取第一个
+// return 的值的类型；没有期望类型且表达式的类型未知时，表达式函数体视为没有返回值。
+// lambda 翻译为 Go 闭包，在类方法中直接捕获接收者；与接收者同名的参数会重命名。
+
+// lambdaSig 箭头 lambda 期望的函数类型
+type lambdaSig struct {
+	params  []lambdaSlot
+	results []lambdaSlot
+}
+
+// lambdaSlot 期望类型中的参数或返回值类型，goType 为空表示无法确定
+type lambdaSlot struct {
+	canonical string // 规范类型
+	goType    string // 生成代码中的 Go 类型
+}
+
+// resultsKnown 判断期望类型是否确定了全部返回值类型
+func (s *lambdaSig) resultsKnown() bool {
+	for _, r := range s.results {
+		if r.goType == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// resultsText 返回期望的返回值类型，用于错误信息；无法确定的类型写作 ?
+func (s *lambdaSig) resultsText() string {
+	var texts []string
+	for _, r := range s.results {
+		text := r.canonical
+		if text == "" {
+			text = "?"
+		}
+		texts = append(texts, text)
+	}
+	if len(texts) == 1 {
+		return texts[0]
+	}
+	return "(" + strings.Join(texts, ", ") + ")"
+}
+
+// lambdaReturns 推断返回值类型时记录的第一个 return 的值类型
+type lambdaReturns struct {
+	seen  bool
+	types []string
+}
+
+// funcState 进入函数字面量前的函数级状态，函数字面量生成结束后恢复
+type funcState struct {
+	errable        bool
+	results        []*parser.Field
+	inTryBlock     bool
+	tryCtx         *tryContext
+	caughtErr      string
+	pendingFinally []*finallyContext
+	branchTargets  []*branchTarget
+	localNames     map[string]bool
+	nullableVars   map[string]bool
+	lateVars       map[string]bool
+	nonNil         map[string]bool
+	returns        *lambdaReturns
+	scope          *matchScope
+}
+
+// enterFuncLiteral 进入函数字面量：外层的局部变量仍然可见，错误处理和返回值状态重新开始
+func (g *CodeGen) enterFuncLiteral() *funcState {
+	state := &funcState{
+		errable:        g.currentFuncErrable,
+		results:        g.currentFuncResults,
+		inTryBlock:     g.inTryBlock,
+		tryCtx:         g.tryCtx,
+		caughtErr:      g.caughtErr,
+		pendingFinally: g.pendingFinally,
+		branchTargets:  g.branchTargets,
+		localNames:     g.localNames,
+		nullableVars:   g.nullableVars,
+		lateVars:       g.lateVars,
+		nonNil:         g.nonNil,
+		returns:        g.lambdaReturns,
+		scope:          g.enterMatchScope(),
+	}
+	g.currentFuncErrable, g.currentFuncResults = false, nil
+	g.inTryBlock, g.tryCtx, g.caughtErr, g.pendingFinally = false, nil, "", nil
+	g.branchTargets = nil
+	g.localNames = copyState(g.localNames)
+	g.nullableVars = copyState(g.nullableVars)
+	g.lateVars = copyState(g.lateVars)
+	g.nonNil = copyState(g.nonNil)
+	g.lambdaReturns = nil
+	return state
+}
+
+// leaveFuncLiteral 恢复进入函数字面量前的状态
+func (g *CodeGen) leaveFuncLiteral(state *funcState) {
+	g.currentFuncErrable, g.currentFuncResults = state.errable, state.results
+	g.inTryBlock, g.tryCtx, g.caughtErr, g.pendingFinally = state.inTryBlock, state.tryCtx, state.caughtErr, state.pendingFinally
+	g.branchTargets = state.branchTargets
+	g.localNames, g.nullableVars, g.nonNil = state.localNames, state.nullableVars, state.nonNil
+	g.lateVars = state.lateVars
+	g.lambdaReturns = state.returns
+	g.leaveMatchScope(state.scope)
+}
+
+// generateFuncLiteral 生成函数字面量，箭头 lambda 省略的类型按期望类型和函数体推断
+func (g *CodeGen) generateFuncLiteral(lit *parser.FuncLiteral) string {
+	sig := g.lambdaTypes[lit]
+	state := g.enterFuncLiteral()
+	defer g.leaveFuncLiteral(state)
+
+	var params []string
+	for i, param := range lit.Params {
+		var canonical, goType string
+		switch {
+		case param.Type != nil:
+			canonical, goType = paramType(param.Type), g.generateType(param.Type)
+		case sig != nil && i < len(sig.params) && sig.params[i].goType != "":
+			canonical, goType = sig.params[i].canonical, sig.params[i].goType
+		default:
+			g.transpiler.AddError(lit.Token.Line, lit.Token.Column, i18n.T(i18n.ErrLambdaParamType, param.Name))
+			goType = "any"
+		}
+		if param.Name == "" {
+			params = append(params, goType)
+			continue
+		}
+		name := g.lambdaParamName(param.Name)
+		g.bindMatchVar(&matchBinding{name: param.Name, expr: name, typ: defaultType(g.goTypeKnown(canonical))})
+		g.localNames[param.Name] = true
+		delete(g.nonNil, param.Name)
+		if _, ok := param.Type.(*parser.NullableType); ok {
+			g.nullableVars[param.Name] = true
+		} else {
+			delete(g.nullableVars, param.Name)
+		}
+		params = append(params, name+" "+goType)
+	}
+
+	// 返回值：显式声明的类型、期望类型，或者由函数体推断
+	inferred := lit.Arrow && len(lit.Results) == 0 && (sig == nil || !sig.resultsKnown())
+	stmts := lit.Body.Statements
+	void := false
+	if lit.Arrow && lit.ExprBody {
+		// 没有返回值的表达式函数体作为表达式语句执行
+		value := stmts[0].(*parser.ReturnStmt).Values[0]
+		void = sig != nil && len(sig.results) == 0 || sig == nil && g.exprType(value) == ""
+		if !void && sig != nil && g.isVoidCall(value) {
+			g.transpiler.AddError(lit.Token.Line, lit.Token.Column, i18n.T(i18n.ErrLambdaNoValue, sig.resultsText()))
+			void = true
+		}
+		if void {
+			stmts = []parser.Statement{&parser.ExpressionStmt{Expression: value}}
+		}
+	}
+	if lit.StmtBody && len(lit.Results) == 0 && sig != nil && len(sig.results) > 0 {
+		g.transpiler.AddError(lit.Token.Line, lit.Token.Column, i18n.T(i18n.ErrLambdaNoValue, sig.resultsText()))
+	}
+	if !lit.Arrow {
+		g.currentFuncResults = lit.Results
+	}
+	var returns *lambdaReturns
+	if inferred && !void {
+		returns = &lambdaReturns{}
+		g.lambdaReturns = returns
+	}
+
+	indent := g.indent
+	body := g.captureOutput(func() {
+		g.indent = indent + 1
+		for _, stmt := range stmts {
+			g.generateStatement(stmt)
+		}
+	})
+
+	var results []string
+	switch {
+	case !lit.Arrow || len(lit.Results) > 0:
+		for _, r := range lit.Results {
+			text := g.generateType(r.Type)
+			if r.Name != "" {
+				text = symbol.TransformDollarVar(r.Name) + " " + text
+			}
+			results = append(results, text)
+		}
+	case returns == nil && sig != nil:
+		for _, r := range sig.results {
+			results = append(results, r.goType)
+		}
+	case returns != nil && returns.seen:
+		for i, t := range returns.types {
+			goType := g.goTypeText(t)
+			if sig != nil && i < len(sig.results) && sig.results[i].goType != "" {
+				goType = sig.results[i].goType
+			}
+			if goType == "" {
+				g.transpiler.AddError(lit.Token.Line, lit.Token.Column, i18n.T(i18n.ErrLambdaResultType))
+				goType = "any"
+			}
+			results = append(results, goType)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("func(" + strings.Join(params, ", ") + ")")
+	switch {
+	case len(results) == 1 && (len(lit.Results) == 0 || lit.Results[0].Name == ""):
+		sb.WriteString(" " + results[0])
+	case len(results) > 0:
+		sb.WriteString(" (" + strings.Join(results, ", ") + ")")
+	}
+	sb.WriteString(" {\n")
+	sb.WriteString(body)
+	for i := 0; i < indent; i++ {
+		sb.WriteString("\t")
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// isVoidCall 判断表达式是否是没有返回值的函数或方法调用
+func (g *CodeGen) isVoidCall(expr parser.Expression) bool {
+	call, ok := expr.(*parser.CallExpr)
+	if !ok {
+		return false
+	}
+	if typ, found := g.memberType(call); found {
+		return typ == nil
+	}
+	if sel, ok := call.Function.(*parser.SelectorExpr); ok {
+		if fn, ok := g.goSelectorObject(sel).(*types.Func); ok {
+			return fn.Type().(*types.Signature).Results().Len() == 0
+		}
+	}
+	return false
+}
+
+// goTypeKnown 返回可以用于重载解析的规范类型，包含无法确定的名字（如 Go 包中的类型）时返回空串
+func (g *CodeGen) goTypeKnown(t string) string {
+	if g.goTypeText(t) == "" {
+		return ""
+	}
+	return t
+}
+
+// lambdaParamName 返回参数在生成代码中的名字，与接收者同名时重命名，避免遮蔽闭包捕获的 this
+func (g *CodeGen) lambdaParamName(name string) string {
+	goName := symbol.TransformDollarVar(name)
+	if goName == g.currentReceiver {
+		return g.names.fresh("__" + goName + "_%d")
+	}
+	return goName
+}
+
+// noteLambdaReturn 记录需要推断返回值类型的 lambda 中第一个 return 的值类型
+func (g *CodeGen) noteLambdaReturn(stmt *parser.ReturnStmt) {
+	if g.lambdaReturns == nil || g.lambdaReturns.seen {
+		return
+	}
+	g.lambdaReturns.seen = true
+	for _, v := range stmt.Values {
+		g.lambdaReturns.types = append(g.lambdaReturns.types, g.exprType(v))
+	}
+}
+
+// funcLiteralType 推断函数字面量的类型，箭头 lambda 的类型未确定时返回空串
+func (g *CodeGen) funcLiteralType(lit *parser.FuncLiteral) string {
+	if !lit.Arrow {
+		return canonicalType(&parser.FuncType{Params: lit.Params, Results: lit.Results})
+	}
+	sig := g.lambdaTypes[lit]
+	var params, results []string
+	for i, p := range lit.Params {
+		switch {
+		case p.Type != nil:
+			params = append(params, paramType(p.Type))
+		case sig != nil && i < len(sig.params) && sig.params[i].goType != "":
+			params = append(params, sig.params[i].canonical)
+		default:
+			return ""
+		}
+	}
+	switch {
+	case sig != nil && sig.resultsKnown():
+		for _, r := range sig.results {
+			results = append(results, r.canonical)
+		}
+	case lit.ExprBody:
+		// 在参数的作用域中推断表达式函数体的类型
+		scope := g.enterMatchScope()
+		for i, p := range lit.Params {
+			g.bindMatchVar(&matchBinding{name: p.Name, expr: p.Name, typ: params[i]})
+		}
+		t := defaultType(g.exprType(lit.Body.Statements[0].(*parser.ReturnStmt).Values[0]))
+		g.leaveMatchScope(scope)
+		if t == "" {
+			return ""
+		}
+		results = append(results, t)
+	default:
+		return ""
+	}
+	switch len(results) {
+	case 0:
+		return "func(" + strings.Join(params, ", ") + ")"
+	case 1:
+		return "func(" + strings.Join(params, ", ") + ") " + results[0]
+	}
+	return "func(" + strings.Join(params, ", ") + ") (" + strings.Join(results, ", ") + ")"
+}
+
+// arrowLambda 返回实参（或命名实参的值）中的箭头 lambda
+func arrowLambda(arg parser.Expression) *parser.FuncLiteral {
+	if na, ok := arg.(*parser.NamedArg); ok {
+		arg = na.Value
+	}
+	if lit, ok := arg.(*parser.FuncLiteral); ok && lit.Arrow {
+		return lit
+	}
+	return nil
+}
+
+// expectCallLambdas 为调用中作为实参的箭头 lambda 记录期望的函数类型
+func (g *CodeGen) expectCallLambdas(call *parser.CallExpr) {
+	found := false
+	for _, arg := range call.Arguments {
+		if arrowLambda(arg) != nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+
+	if ident, ok := call.Function.(*parser.Identifier); ok && !g.localNames[ident.Value] {
+		if decl := g.transpiler.GetFuncDecl(g.transpiler.pkg, ident.Value); decl != nil {
+			fn := &parser.ClassMethod{Name: decl.Name, TypeParams: decl.TypeParams, Params: decl.Params}
+			g.expectLambdaArgs([]*parser.ClassMethod{fn}, call.Arguments, nil, nil)
+			return
+		}
+	}
+	if fn := g.goFuncMethod(call.Function); fn != nil {
+		g.expectLambdaArgs([]*parser.ClassMethod{fn}, call.Arguments, nil, nil)
+		return
+	}
+	if _, methods, typeParams := g.calleeMethods(call); len(methods) > 0 {
+		g.expectLambdaArgs(methods, call.Arguments, typeParams, g.receiverBindings(call, typeParams))
+		return
+	}
+
+	// 函数类型的变量或字段
+	if params, _, ok := splitFuncType(g.exprType(call.Function)); ok {
+		for i, arg := range call.Arguments {
+			if i < len(params) {
+				g.expectLambdaText(arg, params[i])
+			}
+		}
+	}
+}
+
+// expectLambdaArgs 按候选方法的形参类型为实参中的箭头 lambda 记录期望类型
+// 使用第一个能绑定全部实参、且 lambda 的参数个数与形参的函数类型一致的候选；bound 是已知的类型参数绑定
+func (g *CodeGen) expectLambdaArgs(methods []*parser.ClassMethod, args []parser.Expression, typeParams map[string]bool, bound map[string]string) {
+	for _, m := range methods {
+		b, _ := bindArgs(m.Name, m, args)
+		if b == nil || !lambdasFit(b) {
+			continue
+		}
+		tps := withTypeParams(typeParams, m.TypeParams)
+		binds := make(map[string]string, len(bound))
+		for name, t := range bound {
+			binds[name] = t
+		}
+		for i, arg := range b.args {
+			if arg != nil && arrowLambda(arg) == nil {
+				bindTypeText(canonicalType(m.Params[i].Type), g.exprType(arg), tps, binds)
+			}
+		}
+		if m.TypeParams != nil {
+			for _, tp := range m.TypeParams.Params {
+				if t, ok := binds[tp.Name]; ok && coreTypeConstraint(tp.Constraint) {
+					bindTypeText(canonicalType(tp.Constraint), t, tps, binds)
+				}
+			}
+		}
+		for i, arg := range b.args {
+			if lit := arrowLambda(arg); lit != nil {
+				g.lambdaTypes[lit] = g.lambdaSigOf(m.Params[i].Type.(*parser.FuncType), tps, binds)
+			}
+		}
+		return
+	}
+}
+
+// lambdasFit 判断绑定中每个箭头 lambda 对应的形参都是参数个数相同的函数类型
+func lambdasFit(b *callBinding) bool {
+	for _, arg := range b.variadic {
+		if arrowLambda(arg) != nil {
+			return false
+		}
+	}
+	for i, arg := range b.args {
+		lit := arrowLambda(arg)
+		if lit == nil {
+			continue
+		}
+		ft, ok := b.method.Params[i].Type.(*parser.FuncType)
+		if !ok || len(ft.Params) != len(lit.Params) {
+			return false
+		}
+	}
+	return true
+}
+
+// coreTypeConstraint 判断类型参数的约束是否是可以用来推导其他类型参数的复合类型
+func coreTypeConstraint(c parser.Expression) bool {
+	switch c.(type) {
+	case *parser.SliceType, *parser.ArrayType, *parser.MapType, *parser.ChanType, *parser.PointerType, *parser.FuncType:
+		return true
+	}
+	return false
+}
+
+// bindTypeText 用实参类型 actual 匹配形参类型 pattern 中的类型参数，匹配成功时才把绑定并入 bound
+func bindTypeText(pattern, actual string, typeParams map[string]bool, bound map[string]string) {
+	actual = defaultType(actual)
+	if actual == "" || strings.HasPrefix(actual, "untyped ") || !mentionsTypeParam(pattern, typeParams) {
+		return
+	}
+	tmp := make(map[string]string, len(bound))
+	for name, t := range bound {
+		tmp[name] = t
+	}
+	if matchTypeText(pattern, actual, typeParams, tmp) {
+		for name, t := range tmp {
+			bound[name] = t
+		}
+	}
+}
+
+// lambdaSigOf 把形参的函数类型转换为期望类型，类型参数替换为绑定的类型
+func (g *CodeGen) lambdaSigOf(ft *parser.FuncType, typeParams map[string]bool, bound map[string]string) *lambdaSig {
+	sig := &lambdaSig{}
+	for _, p := range ft.Params {
+		sig.params = append(sig.params, g.lambdaSlot(p.Type, typeParams, bound))
+	}
+	for _, r := range ft.Results {
+		sig.results = append(sig.results, g.lambdaSlot(r.Type, typeParams, bound))
+	}
+	return sig
+}
+
+// lambdaSlot 计算期望类型中的一个类型，仍然包含未绑定的类型参数时无法确定
+func (g *CodeGen) lambdaSlot(typ parser.Expression, typeParams map[string]bool, bound map[string]string) lambdaSlot {
+	t := canonicalType(typ)
+	if !mentionsTypeParam(t, typeParams) {
+		goType := g.goTypeText(t)
+		if goType == "" {
+			// Go 包中的类型，规范类型去掉了包名
+			goType = g.generateType(typ)
+		}
+		return lambdaSlot{canonical: t, goType: goType}
+	}
+	t = substituteTypeText(t, bound)
+	if mentionsTypeParam(t, typeParams) {
+		return lambdaSlot{}
+	}
+	return lambdaSlot{canonical: t, goType: g.goTypeText(t)}
+}
+
+// substituteTypeText 把类型文本中的类型参数替换为绑定的类型
+func substituteTypeText(t string, bound map[string]string) string {
+	var sb strings.Builder
+	for t != "" {
+		word := leadingIdent(t)
+		if word == "" {
+			sb.WriteByte(t[0])
+			t = t[1:]
+			continue
+		}
+		if b, ok := bound[word]; ok {
+			sb.WriteString(b)
+		} else {
+			sb.WriteString(word)
+		}
+		t = t[len(word):]
+	}
+	return sb.String()
+}
+
+// expectLambdaType 按声明的类型记录箭头 lambda 的期望类型，类型不是函数类型时忽略
+func (g *CodeGen) expectLambdaType(value, typ parser.Expression) {
+	lit := arrowLambda(value)
+	ft, ok := typ.(*parser.FuncType)
+	if lit == nil || !ok {
+		return
+	}
+	g.lambdaTypes[lit] = g.lambdaSigOf(ft, nil, nil)
+}
+
+// expectLambdaText 按规范类型文本记录箭头 lambda 的期望类型，类型不是函数类型时忽略
+func (g *CodeGen) expectLambdaText(value parser.Expression, t string) {
+	lit := arrowLambda(value)
+	params, results, ok := splitFuncType(t)
+	if lit == nil || !ok {
+		return
+	}
+	sig := &lambdaSig{}
+	for _, p := range params {
+		sig.params = append(sig.params, lambdaSlot{canonical: p, goType: g.goTypeText(p)})
+	}
+	for _, r := range results {
+		sig.results = append(sig.results, lambdaSlot{canonical: r, goType: g.goTypeText(r)})
+	}
+	g.lambdaTypes[lit] = sig
+}
+
+// splitFuncType 拆分规范的函数类型文本 func(A, B) R、func(A) (R1, R2)
+func splitFuncType(t string) (params, results []string, ok bool) {
+	if !strings.HasPrefix(t, "func(") {
+		return nil, nil, false
+	}
+	params, rest := splitTypeList(t[len("func("):])
+	if !strings.HasPrefix(rest, ")") {
+		return nil, nil, false
+	}
+	rest = strings.TrimPrefix(rest[1:], " ")
+	switch {
+	case rest == "":
+	case strings.HasPrefix(rest, "("):
+		results, rest = splitTypeList(rest[1:])
+		if rest != ")" {
+			return nil, nil, false
+		}
+	default:
+		results = []string{rest}
+	}
+	return params, results, true
+}
+
+// splitTypeList 拆分逗号分隔的类型列表，返回列表和列表之后的文本
+func splitTypeList(t string) ([]string, string) {
+	var list []string
+	for t != "" && t[0] != ')' {
+		n := typeTextLen(t)
+		if n == 0 {
+			break
+		}
+		list = append(list, t[:n])
+		t = strings.TrimPrefix(t[n:], ", ")
+	}
+	return list, t
+}
+
+// receiverBindings 按方法调用接收者的类型实参绑定类、结构体或扩展类型的类型参数
+func (g *CodeGen) receiverBindings(call *parser.CallExpr, typeParams map[string]bool) map[string]string {
+	bound := make(map[string]string)
+	sel, _ := methodCallSelector(call.Function)
+	if sel == nil || len(typeParams) == 0 {
+		return bound
+	}
+	recv := strings.TrimPrefix(g.exprType(sel.X), "*")
+	name := stripTypeArgs(recv)
+	var list *parser.TypeParamList
+	if info := g.lookupClass(name); info != nil {
+		list = info.TypeParams
+	} else if decl := g.lookupStruct(name); decl != nil {
+		list = decl.TypeParams
+	}
+
+	pattern := ""
+	if list != nil && len(list.Params) > 0 {
+		var names []string
+		for _, p := range list.Params {
+			names = append(names, p.Name)
+		}
+		pattern = name + "[" + strings.Join(names, ", ") + "]"
+	} else if ext := g.extensionFor(sel); ext != nil {
+		pattern = strings.TrimPrefix(canonicalType(ext.Type), "*")
+	}
+	bindTypeText(pattern, recv, typeParams, bound)
+	return bound
+}
+
+// typeArgBindings 按显式的类型实参（new Box[int](...)）绑定类型参数
+func typeArgBindings(list *parser.TypeParamList, typ parser.Expression) map[string]string {
+	bound := make(map[string]string)
+	generic, ok := typ.(*parser.GenericType)
+	if !ok || list == nil {
+		return bound
+	}
+	for i, arg := range generic.TypeArgs {
+		if i < len(list.Params) {
+			bound[list.Params[i].Name] = canonicalType(arg)
+		}
+	}
+	return bound
+}
+
+// goFuncMethod 把 Go 包函数 pkg.Func 的签名转换为方法声明（用于实参绑定），不是 Go 包函数时返回 nil
+// 类型参数的约束只保留核心类型（S ~[]E 中的 []E），用于从 S 的绑定推导 E
+func (g *CodeGen) goFuncMethod(fn parser.Expression) *parser.ClassMethod {
+	sel, ok := fn.(*parser.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	obj, ok := g.goPackageObject(sel).(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := obj.Type().(*types.Signature)
+	method := &parser.ClassMethod{Name: sel.X.(*parser.Identifier).Value + "." + obj.Name(), Params: goFields(sig.Params(), sig.Variadic())}
+	if sig.TypeParams().Len() > 0 {
+		method.TypeParams = &parser.TypeParamList{}
+	}
+	for i := 0; i < sig.TypeParams().Len(); i++ {
+		tp := sig.TypeParams().At(i)
+		param := &parser.TypeParam{Name: tp.Obj().Name()}
+		if core := goCoreType(tp); core != nil {
+			param.Constraint = goTypeExpr(core)
+		}
+		method.TypeParams.Params = append(method.TypeParams.Params, param)
+	}
+	return method
+}
+
+// goCoreType 返回只有一个类型项的约束（如 ~[]E）中的类型，其他约束返回 nil
+func goCoreType(tp *types.TypeParam) types.Type {
+	iface, ok := tp.Constraint().Underlying().(*types.Interface)
+	if !ok || iface.NumEmbeddeds() != 1 {
+		return nil
+	}
+	if union, ok := iface.EmbeddedType(0).(*types.Union); ok {
+		if union.Len() != 1 {
+			return nil
+		}
+		return union.Term(0).Type()
+	}
+	return iface.EmbeddedType(0)
+}
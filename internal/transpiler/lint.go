@@ -0,0 +1,469 @@
+package transpiler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/config"
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+)
+
+// 诊断级别与检查规则
+//
+// 转译器报告的问题分为两类：
+//   - 错误：写入 t.errors，阻止生成代码（语法、类型、签名等问题）
+//   - 检查规则（lint）：默认为警告，不阻止生成代码，可以在 tugo.toml 的 [lint.rules]
+//     中按规则名或编号调整为 off / warning / error
+//
+// 少数规则发现的问题同样会让 Go 编译失败（如未使用的局部变量），这些规则固定为错误，
+// 不能通过配置或抑制注释调整。
+//
+// 每条检查规则的消息末尾带有规则编号，如 "imported type 'User' is not used [TG0301]"。
+// 源码中的 // tugo:ignore 注释可以抑制检查规则：
+//   - 跟在代码后面的注释作用于同一行：use "com.demo.Tag" // tugo:ignore TG0301
+//   - 单独一行的注释作用于下一行
+//   - 不写编号时抑制该行的所有规则；多个编号用逗号或空格分隔，也可以写规则名
+//
+// --werror 或 [lint] werror = true 把所有警告当作错误，适合在 CI 中使用。
+
+// Severity 诊断级别
+type Severity int
+
+const (
+	SeverityOff Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// lintRule 检查规则
+type lintRule struct {
+	Code    string   // 规则编号，如 TG0301
+	Name    string   // 规则名，如 unused-import
+	Default Severity // 默认级别
+	Fixed   bool     // 级别固定为 Default，不能通过配置或 tugo:ignore 调整
+}
+
+var (
+	ruleDeadBranch          = &lintRule{Code: "TG0201", Name: "dead-branch", Default: SeverityWarning}
+	ruleUnusedImport        = &lintRule{Code: "TG0301", Name: "unused-import", Default: SeverityWarning}
+	ruleUnusedPrivateMethod = &lintRule{Code: "TG0302", Name: "unused-private-method", Default: SeverityWarning}
+	ruleUnusedLocal         = &lintRule{Code: "TG0303", Name: "unused-local", Default: SeverityError, Fixed: true}
+	ruleShadowedField       = &lintRule{Code: "TG0304", Name: "shadowed-field", Default: SeverityWarning}
+	ruleUnusedCatchError    = &lintRule{Code: "TG0305", Name: "unused-catch-error", Default: SeverityWarning}
+	ruleMatchWithoutDefault = &lintRule{Code: "TG0306", Name: "match-without-default", Default: SeverityWarning}
+	ruleUnreachableCode     = &lintRule{Code: "TG0307", Name: "unreachable-code", Default: SeverityWarning}
+	ruleUseBeforeAssign     = &lintRule{Code: "TG0308", Name: "use-before-assign", Default: SeverityWarning}
+	ruleMissingOverride     = &lintRule{Code: "TG0309", Name: "missing-override", Default: SeverityWarning}
+)
+
+// lintRules 所有检查规则
+var lintRules = []*lintRule{
+	ruleDeadBranch,
+	ruleUnusedImport,
+	ruleUnusedPrivateMethod,
+	ruleUnusedLocal,
+	ruleShadowedField,
+	ruleUnusedCatchError,
+	ruleMatchWithoutDefault,
+	ruleUnreachableCode,
+	ruleUseBeforeAssign,
+	ruleMissingOverride,
+}
+
+// ignoreDirective 抑制注释前缀
+const ignoreDirective = "tugo:ignore"
+
+// findLintRule 按规则编号或规则名查找规则
+func findLintRule(key string) *lintRule {
+	for _, rule := range lintRules {
+		if strings.EqualFold(rule.Code, key) || rule.Name == key {
+			return rule
+		}
+	}
+	return nil
+}
+
+// parseSeverity 解析配置中的级别
+func parseSeverity(level string) (Severity, bool) {
+	switch strings.ToLower(level) {
+	case "off":
+		return SeverityOff, true
+	case "warning", "warn":
+		return SeverityWarning, true
+	case "error":
+		return SeverityError, true
+	}
+	return SeverityOff, false
+}
+
+// CheckLintConfig 校验 [lint.rules] 中的规则名和级别
+func CheckLintConfig(cfg config.LintConfig) error {
+	for key, level := range cfg.Rules {
+		rule := findLintRule(key)
+		if rule == nil {
+			return fmt.Errorf("%s", i18n.T(i18n.ErrLintUnknownRule, key))
+		}
+		s, ok := parseSeverity(level)
+		if !ok {
+			return fmt.Errorf("%s", i18n.T(i18n.ErrLintInvalidLevel, level, key))
+		}
+		if rule.Fixed && s != rule.Default {
+			return fmt.Errorf("%s", i18n.T(i18n.ErrLintFixedRule, key, severityName(rule.Default)))
+		}
+	}
+	return nil
+}
+
+// severityName 返回级别在配置中的写法
+func severityName(s Severity) string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "error"
+}
+
+// severity 返回规则在当前配置下的级别
+// 同一条规则同时按编号和规则名配置时编号优先；配置是 map，按排序后的键查找以保证结果确定
+func (t *Transpiler) severity(rule *lintRule) Severity {
+	if t.config == nil || rule.Fixed {
+		return rule.Default
+	}
+	keys := make([]string, 0, len(t.config.Lint.Rules))
+	for key := range t.config.Lint.Rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, byCode := range []bool{true, false} {
+		for _, key := range keys {
+			if strings.EqualFold(key, rule.Code) != byCode || findLintRule(key) != rule {
+				continue
+			}
+			if s, ok := parseSeverity(t.config.Lint.Rules[key]); ok {
+				return s
+			}
+		}
+	}
+	return rule.Default
+}
+
+// lint 报告检查规则发现的问题
+// 规则关闭或被 tugo:ignore 抑制时忽略；级别为 error 或开启 werror 时作为错误报告
+func (t *Transpiler) lint(rule *lintRule, line, col int, msg string) {
+	level := t.severity(rule)
+	if level == SeverityOff || t.isSuppressed(rule, line) {
+		return
+	}
+	formatted := i18n.T(i18n.ErrGeneric, line, col, msg+" ["+rule.Code+"]")
+	if level == SeverityError || (t.config != nil && t.config.Lint.Werror) {
+		t.errors = append(t.errors, formatted)
+		return
+	}
+	t.warnings = append(t.warnings, formatted)
+}
+
+// isSuppressed 检查该行是否被 tugo:ignore 注释抑制，固定级别的规则不能被抑制
+func (t *Transpiler) isSuppressed(rule *lintRule, line int) bool {
+	if t.currentParsedFile == nil || rule.Fixed {
+		return false
+	}
+	for _, c := range t.currentParsedFile.Comments {
+		target := c.Line + 1
+		if c.Trailing {
+			target = c.Line
+		}
+		if target != line {
+			continue
+		}
+		keys, ok := parseIgnoreComment(c.Text)
+		if !ok {
+			continue
+		}
+		if len(keys) == 0 {
+			return true
+		}
+		for _, key := range keys {
+			if findLintRule(key) == rule {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseIgnoreComment 解析 // tugo:ignore [规则...] 注释，返回列出的规则（为空表示全部）
+func parseIgnoreComment(text string) ([]string, bool) {
+	text = strings.TrimPrefix(text, "//")
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, ignoreDirective) {
+		return nil, false
+	}
+	rest := strings.TrimPrefix(text, ignoreDirective)
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return nil, false
+	}
+	return strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}), true
+}
+
+// lintFile 执行不属于其他校验的检查规则
+func (t *Transpiler) lintFile(file *parser.File) {
+	for _, stmt := range file.Statements {
+		switch decl := stmt.(type) {
+		case *parser.ClassDecl:
+			t.lintUnusedPrivateMethods(file, decl)
+			for _, method := range classBodies(decl) {
+				t.lintFuncBody(method.Body)
+				if !method.Static {
+					t.lintShadowedFields(decl, method.Body)
+				}
+			}
+		case *parser.StructDecl:
+			if decl.InitMethod != nil {
+				t.lintFuncBody(decl.InitMethod.Body)
+			}
+			for _, method := range decl.Methods {
+				t.lintFuncBody(method.Body)
+			}
+		case *parser.EnumDecl:
+			for _, method := range decl.Methods {
+				t.lintFuncBody(method.Body)
+			}
+		case *parser.ExtendDecl:
+			for _, method := range decl.Methods {
+				t.lintFuncBody(method.Body)
+			}
+		case *parser.FuncDecl:
+			t.lintFuncBody(decl.Body)
+		}
+	}
+	t.lintControlFlow(file)
+
+	inspect(file, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.CatchClause:
+			// 不带值的 throw 重新抛出捕获的错误，同样算作使用了参数
+			if n.Param != "" && n.Param != "_" && !usesName(n.Body, n.Param) && !rethrows(n.Body) {
+				t.lint(ruleUnusedCatchError, n.Token.Line, n.Token.Column,
+					i18n.T(i18n.WarnUnusedCatchError, n.Param))
+			}
+		}
+		return true
+	})
+}
+
+// classBodies 返回类中带方法体的构造方法和方法
+func classBodies(decl *parser.ClassDecl) []*parser.ClassMethod {
+	var methods []*parser.ClassMethod
+	methods = append(methods, decl.InitMethods...)
+	if len(decl.InitMethods) == 0 && decl.InitMethod != nil {
+		methods = append(methods, decl.InitMethod)
+	}
+	for _, method := range decl.Methods {
+		if method.Body != nil {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// hasDefaultArm 检查 match 是否有 default 分支
+func hasDefaultArm(m *parser.MatchExpr) bool {
+	for _, arm := range m.Arms {
+		if arm.IsDefault {
+			return true
+		}
+	}
+	return false
+}
+
+// lintUnusedPrivateMethods 检查从未被调用的私有方法
+// 私有方法只能在本类中调用，因此只需要在当前文件中查找引用
+func (t *Transpiler) lintUnusedPrivateMethods(file *parser.File, decl *parser.ClassDecl) {
+	inherited, ok := t.parentMethodNames(decl)
+	if !ok {
+		return
+	}
+
+	used := make(map[string]bool)
+	var raw []string
+	inspect(file, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.SelectorExpr:
+			used[n.Sel] = true
+		case *parser.StaticAccessExpr:
+			used[n.Member] = true
+		case *parser.Identifier:
+			used[n.Value] = true
+		case *parser.RawCode:
+			raw = append(raw, n.Code)
+		}
+		return true
+	})
+
+	// 属性的 setter 通过赋值 x.name = v 使用
+	for _, prop := range decl.Properties {
+		if used[prop.Name] && prop.Setter != nil {
+			used[prop.Setter.Name] = true
+		}
+	}
+
+	reported := make(map[string]bool)
+	for _, method := range decl.Methods {
+		if method.Visibility != "private" || method.Abstract || method.Body == nil {
+			continue
+		}
+		if method.Name == "main" || used[method.Name] || inherited[method.Name] || reported[method.Name] {
+			continue
+		}
+		if rawCodeMentions(raw, method.Name) {
+			continue
+		}
+		reported[method.Name] = true
+		t.lint(ruleUnusedPrivateMethod, method.Token.Line, method.Token.Column,
+			i18n.T(i18n.WarnUnusedPrivateMethod, decl.Name, method.Name))
+	}
+}
+
+// parentMethodNames 收集父类链上声明的方法名（子类方法可能覆盖父类方法，由父类调用）
+// 父类无法加载时返回 false，调用方跳过检查
+func (t *Transpiler) parentMethodNames(decl *parser.ClassDecl) (map[string]bool, bool) {
+	names := make(map[string]bool)
+	classInfo := t.table.GetClass(t.pkg, decl.Name)
+	for depth := 0; classInfo != nil && classInfo.Extends != "" && depth < maxInheritanceDepth; depth++ {
+		classInfo = t.parentClassInfo(classInfo)
+		if classInfo == nil {
+			return nil, false
+		}
+		for _, m := range classInfo.Methods {
+			names[m.Name] = true
+		}
+		for _, m := range classInfo.AbstractMethods {
+			names[m.Name] = true
+		}
+	}
+	return names, true
+}
+
+// rawCodeMentions 检查内嵌 Go 代码中是否出现了该名称
+func rawCodeMentions(raw []string, name string) bool {
+	for _, code := range raw {
+		if strings.Contains(code, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintFuncBody 检查函数体中声明后从未使用的局部变量
+// 按名称判断：同名变量在函数体任意位置被引用都视为已使用
+func (t *Transpiler) lintFuncBody(body *parser.BlockStmt) {
+	if body == nil {
+		return
+	}
+
+	used := make(map[string]bool)
+	var raw []string
+	inspect(body, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.Identifier:
+			used[n.Value] = true
+		case *parser.RawCode:
+			raw = append(raw, n.Code)
+		}
+		return true
+	})
+
+	report := func(names []string, line, col int) {
+		for _, name := range names {
+			if name == "_" || used[name] || rawCodeMentions(raw, name) {
+				continue
+			}
+			t.lint(ruleUnusedLocal, line, col, i18n.T(i18n.WarnUnusedLocal, name))
+		}
+	}
+	inspect(body, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.ShortVarDecl:
+			report(n.Names, n.Token.Line, n.Token.Column)
+		case *parser.VarDecl:
+			report(n.Names, n.Token.Line, n.Token.Column)
+		}
+		return true
+	})
+}
+
+// lintShadowedFields 检查与当前类字段同名的局部变量（访问字段必须写 this.name，容易混淆）
+// 参数与字段同名是构造方法和 setter 的常见写法，不报告
+func (t *Transpiler) lintShadowedFields(decl *parser.ClassDecl, body *parser.BlockStmt) {
+	if body == nil {
+		return
+	}
+	fields := make(map[string]bool)
+	for _, f := range decl.Fields {
+		if !f.Static {
+			fields[f.Name] = true
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	check := func(names []string, line, col int) {
+		for _, name := range names {
+			if fields[name] {
+				t.lint(ruleShadowedField, line, col, i18n.T(i18n.WarnShadowedField, name, decl.Name))
+			}
+		}
+	}
+	inspect(body, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.ShortVarDecl:
+			check(n.Names, n.Token.Line, n.Token.Column)
+		case *parser.VarDecl:
+			check(n.Names, n.Token.Line, n.Token.Column)
+		}
+		return true
+	})
+}
+
+// rethrows 检查 catch 块中是否有不带值的 throw（嵌套的 catch 块和闭包中的不算）
+func rethrows(body *parser.BlockStmt) bool {
+	found := false
+	inspectBlock(body, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.ThrowStmt:
+			if n.Value == nil {
+				found = true
+			}
+		case *parser.CatchClause, *parser.FuncLiteral:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// usesName 检查代码块中是否引用了该名称
+func usesName(body *parser.BlockStmt, name string) bool {
+	found := false
+	inspectBlock(body, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.Identifier:
+			if n.Value == name {
+				found = true
+			}
+		case *parser.RawCode:
+			if strings.Contains(n.Code, name) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
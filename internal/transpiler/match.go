@@ -0,0 +1,573 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// match 的模式匹配
+//
+// 分支只写值和类型名时翻译为 switch（见 generateMatchExpr）。
+// 分支中有绑定、守卫条件、范围或解构时翻译为 if / else if 链：
+//
+//	match(shape) {
+//	    c Circle if c.radius > 10 => "big circle",
+//	    Point{x: 0, y} => "on y axis at ${y}",
+//	    1..9 => "digit",
+//	    n if n > 100 => "large",
+//	    nil => "none",
+//	    default => "other"
+//	}
+//
+// 类型模式的断言写在 if 的初始化语句中（c, ok := shape.(*circle); ok），
+// 绑定变量在守卫条件和分支结果中翻译为断言结果、主体或字段（y 翻译为 __v1.Y）。
+// 类按指针匹配；结构体在接口中可能是值也可能是指针，通过 runtime.As 统一为指针；
+// 抽象类翻译为接口，展开为它的全部非抽象子类，每个子类一个分支。
+// 首字母小写、不是当前函数的变量和参数、也不是包级符号的标识符是绑定模式，匹配任意值。
+//
+// 分支可以是代码块 pattern => { ... }。match 语句的分支作为语句执行；
+// match 表达式中块的最后一个表达式是分支的值，块也可以以 return、throw 等终止语句结束。
+// 块中跳出外层循环的 break 在 switch 中会变成跳出 switch，这样的 match 同样翻译为 if / else if 链。
+
+// matchBinding match 分支中的绑定变量
+type matchBinding struct {
+	name string // 变量名
+	expr string // 生成代码中的表达式
+	typ  string // 规范类型
+	used bool   // 守卫条件或分支结果中是否引用
+}
+
+// patternTest 一个模式生成的分支条件
+type patternTest struct {
+	assert   string          // 类型断言表达式，为空表示不需要断言
+	temp     string          // 保存断言结果的变量
+	tempUsed bool            // 条件中引用了断言结果
+	conds    []string        // 需要同时成立的条件，为空表示匹配任意值
+	bindings []*matchBinding // 模式绑定的变量
+}
+
+// patternTarget 类型模式对应的 Go 类型
+type patternTarget struct {
+	goType   string // 断言的 Go 类型
+	bindType string // 断言结果的规范类型
+	isStruct bool   // 结构体（值和指针都要匹配）
+}
+
+// matchBranch if / else if 链中的一个分支，cond 为空表示 else
+type matchBranch struct {
+	cond string
+	body string
+}
+
+// matchScope 进入分支前的绑定和局部变量类型，分支结束后恢复
+type matchScope struct {
+	bindings map[string]*matchBinding
+	types    map[string]string
+}
+
+// enterMatchScope 进入一个分支的作用域
+func (g *CodeGen) enterMatchScope() *matchScope {
+	scope := &matchScope{bindings: g.matchBindings, types: g.localTypes}
+	g.matchBindings = make(map[string]*matchBinding, len(scope.bindings))
+	for name, binding := range scope.bindings {
+		g.matchBindings[name] = binding
+	}
+	g.localTypes = make(map[string]string, len(scope.types))
+	for name, t := range scope.types {
+		g.localTypes[name] = t
+	}
+	return scope
+}
+
+// leaveMatchScope 恢复进入分支前的绑定和局部变量类型
+func (g *CodeGen) leaveMatchScope(scope *matchScope) {
+	g.matchBindings = scope.bindings
+	g.localTypes = scope.types
+}
+
+// bindMatchVar 在当前分支中绑定变量
+func (g *CodeGen) bindMatchVar(binding *matchBinding) {
+	g.matchBindings[binding.name] = binding
+	if binding.typ == "" {
+		delete(g.localTypes, binding.name)
+		return
+	}
+	g.localTypes[binding.name] = binding.typ
+}
+
+// needsMatchChain 检查 match 是否需要翻译为 if / else if 链
+func (g *CodeGen) needsMatchChain(expr *parser.MatchExpr, enum *parser.EnumDecl) bool {
+	typeSwitch := expr.IsType && enum == nil
+	for _, arm := range expr.Arms {
+		if arm.Guard != nil || arm.Block != nil && hasBreak(arm.Block.Statements) {
+			return true
+		}
+		for _, pattern := range arm.Patterns {
+			switch p := pattern.(type) {
+			case *parser.TypePattern, *parser.RangePattern:
+				return true
+			case *parser.Identifier:
+				if p.Value == "_" || g.isMatchBinding(p, enum) {
+					return true
+				}
+				// 类型 switch 中的值模式
+				if typeSwitch && !g.isPatternType(p.Value) {
+					return true
+				}
+			case *parser.NilLiteral:
+			default:
+				if typeSwitch {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isMatchBinding 检查标识符模式是否是绑定变量
+// 当前函数的变量和参数、包级符号、类型名和枚举成员按值或类型匹配
+func (g *CodeGen) isMatchBinding(ident *parser.Identifier, enum *parser.EnumDecl) bool {
+	name := ident.Value
+	if name == "" || name == "_" || strings.HasPrefix(name, "$") || name[0] >= 'A' && name[0] <= 'Z' {
+		return false
+	}
+	if enum != nil && enumCase(enum, name) != nil {
+		return false
+	}
+	if g.localNames[name] || g.currentFuncParams[name] {
+		return false
+	}
+	if _, ok := g.typeToPackage[name]; ok {
+		return false
+	}
+	return !g.isPatternType(name) && g.transpiler.LookupSymbol(name) == nil
+}
+
+// isPatternType 检查名字是否是可以用于类型模式的 tugo 类型
+func (g *CodeGen) isPatternType(name string) bool {
+	if g.localNames[name] || g.currentFuncParams[name] {
+		return false
+	}
+	return g.lookupClass(name) != nil || g.lookupStruct(name) != nil ||
+		g.lookupInterface(name) != nil || g.transpiler.lookupEnum(name) != nil
+}
+
+// isCatchAllPattern 检查模式是否匹配任意值（_ 或绑定变量）
+func (g *CodeGen) isCatchAllPattern(pattern parser.Expression, enum *parser.EnumDecl) bool {
+	ident, ok := pattern.(*parser.Identifier)
+	return ok && (ident.Value == "_" || g.isMatchBinding(ident, enum))
+}
+
+// hasCatchAllArm 检查 match 是否有匹配任意值的分支（default、_ 或没有守卫条件的绑定）
+func (g *CodeGen) hasCatchAllArm(expr *parser.MatchExpr, enum *parser.EnumDecl) bool {
+	for _, arm := range expr.Arms {
+		if arm.IsDefault {
+			return true
+		}
+		if arm.Guard != nil {
+			continue
+		}
+		for _, pattern := range arm.Patterns {
+			if g.isCatchAllPattern(pattern, enum) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateMatchChain 把 match 翻译为 if / else if 链，结果保存在 varName 中（为空时是 match 语句）
+// 分支结果可能引用绑定变量，结果类型优先按绑定后的分支结果推断，推断失败时使用 resultType
+func (g *CodeGen) generateMatchChain(expr *parser.MatchExpr, enum *parser.EnumDecl, varName, resultType string) string {
+	subject := g.generateExpression(expr.Subject)
+	subjectType := g.exprType(expr.Subject)
+	val := subject
+	switch expr.Subject.(type) {
+	case *parser.Identifier, *parser.ThisExpr:
+	default:
+		val = g.names.fresh("__subject_%d")
+	}
+
+	inferred := ""
+	var branches []matchBranch
+	var fallback *parser.MatchArm
+	catchAll := false
+	for _, arm := range expr.Arms {
+		if arm.IsDefault {
+			if fallback == nil {
+				fallback = arm
+			}
+			continue
+		}
+
+		scope := g.enterMatchScope()
+		var tests []*patternTest
+		for _, pattern := range arm.Patterns {
+			tests = append(tests, g.patternTests(pattern, val, subjectType, enum)...)
+		}
+		if len(arm.Patterns) > 1 {
+			for _, test := range tests {
+				if len(test.bindings) > 0 {
+					g.transpiler.AddError(arm.Token.Line, arm.Token.Column, i18n.T(i18n.ErrMatchAlternativeBinding))
+					break
+				}
+			}
+		}
+		for _, test := range mergeAlternatives(tests) {
+			for _, binding := range test.bindings {
+				g.bindMatchVar(binding)
+			}
+			branch := g.generateMatchBranch(arm, test, varName, &inferred)
+			branches = append(branches, branch)
+			if branch.cond == "" {
+				catchAll = true
+				break
+			}
+		}
+		g.leaveMatchScope(scope)
+		// 匹配任意值的分支之后的分支不会执行
+		if catchAll {
+			break
+		}
+	}
+
+	if !catchAll {
+		if fallback != nil {
+			branches = append(branches, matchBranch{body: g.matchArmCode(fallback, varName, &inferred)})
+		} else if g.checkMatchExhaustive(expr, enum, varName == "") {
+			branches = append(branches, matchBranch{body: fmt.Sprintf("panic(%q)\n", g.matchPanicMessage(expr))})
+		}
+	}
+
+	if inferred != "" {
+		resultType = inferred
+	}
+
+	var chain strings.Builder
+	for i, branch := range branches {
+		switch {
+		case i == 0 && branch.cond == "":
+			chain.WriteString(branch.body)
+			continue
+		case i == 0:
+			chain.WriteString("if " + branch.cond + " {\n")
+		case branch.cond == "":
+			chain.WriteString("} else {\n")
+		default:
+			chain.WriteString("} else if " + branch.cond + " {\n")
+		}
+		chain.WriteString(indentLines(branch.body))
+	}
+	if len(branches) > 0 && branches[0].cond != "" {
+		chain.WriteString("}")
+	}
+
+	var sb strings.Builder
+	if varName != "" {
+		sb.WriteString(fmt.Sprintf("var %s %s\n", varName, resultType))
+	}
+	if val != subject {
+		if strings.Contains(chain.String(), val) {
+			sb.WriteString(fmt.Sprintf("%s := %s\n", val, subject))
+		} else {
+			sb.WriteString(fmt.Sprintf("_ = %s\n", subject))
+		}
+	}
+	sb.WriteString(strings.TrimSuffix(chain.String(), "\n"))
+	return sb.String()
+}
+
+// indentLines 给每一行加一级缩进
+func indentLines(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "\t" + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// mergeAlternatives 把一个分支中不需要断言和绑定的多个模式合并为一个条件
+func mergeAlternatives(tests []*patternTest) []*patternTest {
+	if len(tests) < 2 {
+		return tests
+	}
+	var parts []string
+	for _, test := range tests {
+		if test.assert != "" || len(test.bindings) > 0 {
+			return tests
+		}
+		if len(test.conds) == 0 {
+			return []*patternTest{test}
+		}
+		cond := strings.Join(test.conds, " && ")
+		if len(test.conds) > 1 {
+			cond = "(" + cond + ")"
+		}
+		parts = append(parts, cond)
+	}
+	return []*patternTest{{conds: []string{strings.Join(parts, " || ")}}}
+}
+
+// generateMatchBranch 生成一个模式对应的分支，绑定变量已经在当前作用域中
+func (g *CodeGen) generateMatchBranch(arm *parser.MatchArm, test *patternTest, varName string, inferred *string) matchBranch {
+	conds := append([]string{}, test.conds...)
+	if arm.Guard != nil {
+		guard := g.generateExpression(arm.Guard)
+		if binary, ok := arm.Guard.(*parser.BinaryExpr); ok && binary.Operator == "||" {
+			guard = "(" + guard + ")"
+		}
+		conds = append(conds, guard)
+	}
+	body := g.matchArmCode(arm, varName, inferred)
+
+	cond := strings.Join(conds, " && ")
+	if test.assert != "" {
+		temp := test.temp
+		used := test.tempUsed
+		for _, binding := range test.bindings {
+			used = used || binding.used
+		}
+		if !used {
+			temp = "_"
+		}
+		ok := g.names.name("ok")
+		if cond == "" {
+			cond = ok
+		} else {
+			cond = ok + " && " + cond
+		}
+		cond = fmt.Sprintf("%s, %s := %s; %s", temp, ok, test.assert, cond)
+	}
+	return matchBranch{cond: cond, body: body}
+}
+
+// matchArmValue 返回分支的值：表达式分支的结果，或块分支最后的表达式（不是表达式时为 nil）
+func matchArmValue(arm *parser.MatchArm) parser.Expression {
+	if arm.Block == nil {
+		return arm.Body
+	}
+	stmts := arm.Block.Statements
+	if len(stmts) == 0 {
+		return nil
+	}
+	if last, ok := stmts[len(stmts)-1].(*parser.ExpressionStmt); ok && !isPanicCall(last.Expression) {
+		return last.Expression
+	}
+	return nil
+}
+
+// matchArmCode 生成分支的代码，varName 为空时分支作为语句执行，否则把分支的值赋给 varName
+// 分支需要的前置语句（如嵌套的 match）放在分支内部，只在分支命中时执行
+func (g *CodeGen) matchArmCode(arm *parser.MatchArm, varName string, inferred *string) string {
+	var stmts []parser.Statement
+	value := arm.Body
+	if arm.Block != nil {
+		stmts = arm.Block.Statements
+		if value = matchArmValue(arm); value != nil && varName != "" {
+			stmts = stmts[:len(stmts)-1]
+		} else {
+			value = nil
+		}
+		if varName != "" && value == nil && !terminates(arm.Block.Statements) {
+			g.transpiler.AddError(arm.Token.Line, arm.Token.Column, i18n.T(i18n.ErrMatchBlockValue))
+		}
+
+		// 块中赋值过的路径在 match 之后不再视为非 nil，块中的收窄不影响其他分支
+		g.forgetAssigned(arm.Block)
+		saved := g.copyNonNil()
+		defer func() { g.nonNil = saved }()
+	}
+
+	return g.captureOutput(func() {
+		for _, stmt := range stmts {
+			g.generateStatement(stmt)
+		}
+		switch {
+		case value == nil:
+		case varName == "":
+			g.generateStatement(&parser.ExpressionStmt{Expression: value})
+		default:
+			text := g.generateExpression(value)
+			g.flushPendingStatements()
+			g.writeLine(fmt.Sprintf("%s = %s", varName, text))
+			if *inferred == "" {
+				*inferred = g.goTypeText(g.exprType(value))
+			}
+		}
+	})
+}
+
+// patternTests 生成顶层模式的条件，抽象类的类型模式每个子类生成一个
+func (g *CodeGen) patternTests(pattern parser.Expression, val, valType string, enum *parser.EnumDecl) []*patternTest {
+	switch p := pattern.(type) {
+	case *parser.TypePattern:
+		return g.typePatternTests(p.Type, p.Binding, p.Fields, val, valType)
+	case *parser.Identifier:
+		if enum == nil && g.isPatternType(p.Value) {
+			return g.typePatternTests(p, "", nil, val, valType)
+		}
+	}
+	test := &patternTest{}
+	g.addPatternConds(test, pattern, val, valType)
+	return []*patternTest{test}
+}
+
+// typePatternTests 生成类型模式的条件，值的类型已经是模式类型时不需要断言
+func (g *CodeGen) typePatternTests(typ parser.Expression, binding string, fields []*parser.FieldPattern, val, valType string) []*patternTest {
+	targets := g.patternTargets(typ)
+	// 值的类型就是模式类型（包括抽象类）时不需要断言
+	if name := canonicalType(typ); sameMatchType(name, valType) {
+		targets = []patternTarget{{bindType: valType}}
+	}
+	var tests []*patternTest
+	for _, target := range targets {
+		test := &patternTest{}
+		temp, bindType := val, valType
+		if !sameMatchType(target.bindType, valType) {
+			temp = g.matchTemp(binding)
+			bindType = target.bindType
+			subject := val
+			if valType != "" && !g.isInterfaceType(stripTypeArgs(valType)) {
+				subject = "any(" + val + ")"
+			}
+			if target.isStruct {
+				g.tugoImports["tugo/runtime"] = "runtime"
+				test.assert = fmt.Sprintf("runtime.As[%s](%s)", target.goType, subject)
+			} else {
+				test.assert = fmt.Sprintf("%s.(%s)", subject, target.goType)
+			}
+			test.temp = temp
+		}
+		if binding != "" && binding != "_" {
+			test.bindings = append(test.bindings, &matchBinding{name: binding, expr: temp, typ: bindType})
+		}
+		for _, field := range fields {
+			g.addFieldPattern(test, field, temp, bindType)
+		}
+		test.tempUsed = len(test.conds) > 0
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// matchTemp 返回保存断言结果的变量名，优先使用绑定变量名
+func (g *CodeGen) matchTemp(binding string) string {
+	if binding != "" && binding != "_" {
+		if name := symbol.EscapeKeyword(binding); name != g.currentReceiver {
+			return name
+		}
+	}
+	return g.names.fresh("__v%d")
+}
+
+// sameMatchType 检查值的类型是否已经是模式类型（结构体的值和指针视为相同）
+func sameMatchType(target, valType string) bool {
+	return valType != "" && strings.TrimPrefix(target, "*") == strings.TrimPrefix(valType, "*")
+}
+
+// patternTargets 返回类型模式对应的 Go 类型
+func (g *CodeGen) patternTargets(typ parser.Expression) []patternTarget {
+	name := canonicalType(typ)
+	base := typeBaseName(stripTypeArgs(name))
+	if classInfo := g.lookupClass(base); classInfo != nil {
+		if !classInfo.Abstract {
+			return []patternTarget{{goType: "*" + g.generateType(typ), bindType: "*" + name}}
+		}
+		var targets []patternTarget
+		for _, sub := range g.transpiler.table.GetAllClasses() {
+			if sub.Abstract || !g.transpiler.inheritsFrom(sub, classInfo) {
+				continue
+			}
+			goType := sub.GoName
+			if sub.Package != g.transpiler.pkg {
+				goType = sub.Package + "." + sub.GoName
+			}
+			targets = append(targets, patternTarget{goType: "*" + goType, bindType: "*" + sub.Name})
+		}
+		return targets
+	}
+	if g.lookupStruct(base) != nil {
+		return []patternTarget{{goType: g.generateType(typ), bindType: "*" + name, isStruct: true}}
+	}
+	return []patternTarget{{goType: g.generateType(typ), bindType: name}}
+}
+
+// typePatternCases 返回 switch 类型分支对应的 Go case 类型
+func (g *CodeGen) typePatternCases(pattern parser.Expression) []string {
+	if _, ok := pattern.(*parser.NilLiteral); ok {
+		return []string{"nil"}
+	}
+	var types []string
+	for _, target := range g.patternTargets(pattern) {
+		if target.isStruct {
+			types = append(types, target.goType, "*"+target.goType)
+			continue
+		}
+		types = append(types, target.goType)
+	}
+	return types
+}
+
+// addFieldPattern 添加解构字段的条件，省略值的字段绑定为同名变量
+func (g *CodeGen) addFieldPattern(test *patternTest, field *parser.FieldPattern, base, baseType string) {
+	const key = "__match_field_base"
+	g.bindMatchVar(&matchBinding{name: key, expr: base, typ: baseType})
+	sel := &parser.SelectorExpr{Token: field.Token, X: &parser.Identifier{Token: field.Token, Value: key}, Sel: field.Name}
+	val, valType := g.generateExpression(sel), g.exprType(sel)
+	delete(g.matchBindings, key)
+
+	if field.Value == nil {
+		test.bindings = append(test.bindings, &matchBinding{name: field.Name, expr: val, typ: valType})
+		return
+	}
+	g.addPatternConds(test, field.Value, val, valType)
+}
+
+// addPatternConds 添加值模式的条件，嵌套的类型模式只能解构，不能再断言
+func (g *CodeGen) addPatternConds(test *patternTest, pattern parser.Expression, val, valType string) {
+	enum := g.transpiler.lookupEnum(typeBaseName(valType))
+	switch p := pattern.(type) {
+	case *parser.Identifier:
+		if p.Value == "_" {
+			return
+		}
+		if g.isMatchBinding(p, enum) {
+			test.bindings = append(test.bindings, &matchBinding{name: p.Value, expr: val, typ: valType})
+			return
+		}
+		if member, ok := g.enumMatchPattern(enum, p); ok {
+			test.conds = append(test.conds, val+" == "+member)
+			return
+		}
+	case *parser.NilLiteral:
+		test.conds = append(test.conds, val+" == nil")
+		return
+	case *parser.RangePattern:
+		test.conds = append(test.conds,
+			val+" >= "+g.generateExpression(p.Low),
+			val+" <= "+g.generateExpression(p.High))
+		return
+	case *parser.TypePattern:
+		targets := g.patternTargets(p.Type)
+		if len(targets) != 1 || !sameMatchType(targets[0].bindType, valType) {
+			g.transpiler.AddError(p.Token.Line, p.Token.Column,
+				i18n.T(i18n.ErrMatchNestedTypePattern, canonicalType(p.Type), valType))
+			return
+		}
+		if p.Binding != "" && p.Binding != "_" {
+			test.bindings = append(test.bindings, &matchBinding{name: p.Binding, expr: val, typ: valType})
+		}
+		for _, field := range p.Fields {
+			g.addFieldPattern(test, field, val, valType)
+		}
+		return
+	}
+	test.conds = append(test.conds, val+" == "+g.generateExpression(pattern))
+}
@@ -0,0 +1,254 @@
+package transpiler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// nameAllocator 编译器生成名称的分配器
+//
+// 代码生成器需要引入许多用户代码中不存在的名称（__match_N、_tryErr_N、_TryBlock_N、
+// _once、_err 等）。分配器在生成开始前收集当前文件中出现的所有用户标识符、当前包的
+// 全部符号、导入的包名以及 Go 的关键字和预声明标识符，保证分配出的名称不会与它们冲突。
+// 收集范围覆盖文件内的所有作用域，因此分配的名称在任何作用域内都是安全的。
+type nameAllocator struct {
+	reserved map[string]bool   // 不可使用的名称
+	fixed    map[string]string // 固定名称的分配结果 base -> name
+	counters map[string]int    // 编号名称的计数器 format -> n
+}
+
+// newNameAllocator 创建名称分配器
+func newNameAllocator() *nameAllocator {
+	return &nameAllocator{
+		reserved: make(map[string]bool),
+		fixed:    make(map[string]string),
+		counters: make(map[string]int),
+	}
+}
+
+// reserve 将名称标记为不可用
+func (a *nameAllocator) reserve(name string) {
+	if name != "" {
+		a.reserved[name] = true
+	}
+}
+
+// isFree 检查名称是否可用
+func (a *nameAllocator) isFree(name string) bool {
+	return !a.reserved[name] && !symbol.IsGoKeyword(name) && !symbol.IsGoPredeclared(name)
+}
+
+// name 分配一个固定名称（如 _once、_self），同一个 base 在文件内总是得到同一个名称
+// 如果 base 与用户标识符冲突，追加下划线直到不冲突
+func (a *nameAllocator) name(base string) string {
+	if n, ok := a.fixed[base]; ok {
+		return n
+	}
+	n := base
+	for !a.isFree(n) {
+		n += "_"
+	}
+	a.reserve(n)
+	a.fixed[base] = n
+	return n
+}
+
+// fresh 按格式分配一个新的编号名称（如 "__match_%d"），每次调用都返回不同的名称
+func (a *nameAllocator) fresh(format string) string {
+	for {
+		a.counters[format]++
+		n := fmt.Sprintf(format, a.counters[format])
+		if a.isFree(n) {
+			a.reserve(n)
+			return n
+		}
+	}
+}
+
+// collectFile 收集文件中的所有用户标识符和导入名
+func (a *nameAllocator) collectFile(file *parser.File) {
+	for _, imp := range file.Imports {
+		for _, spec := range imp.Specs {
+			a.reserve(spec.Alias)
+			a.reserve(spec.PkgName)
+			a.reserve(spec.TypeName)
+			if spec.IsGoImport {
+				parts := strings.Split(spec.Path, "/")
+				a.reserve(parts[len(parts)-1])
+			}
+		}
+	}
+	for name := range collectIdentifiers(file) {
+		a.reserve(name)
+		a.reserve(symbol.TransformDollarVar(name))
+	}
+}
+
+// collectTable 收集包内所有符号的名称（包括其他文件中的声明）
+func (a *nameAllocator) collectTable(table *symbol.Table, pkg string) {
+	for _, sym := range table.GetByPackage(pkg) {
+		a.reserve(sym.Name)
+		a.reserve(sym.GoName)
+	}
+}
+
+// identifierFields 在 AST 节点中保存标识符的字符串字段
+var identifierFields = map[string]bool{
+	"Name":   true,
+	"Names":  true,
+	"Sel":    true,
+	"Member": true,
+	"Param":  true,
+}
+
+// collectIdentifiers 收集 AST 中出现的所有标识符
+// AST 节点类型很多，这里通过反射遍历，避免为每种节点单独维护一份遍历代码
+func collectIdentifiers(node interface{}) map[string]bool {
+	names := make(map[string]bool)
+	collectIdentifiersValue(reflect.ValueOf(node), names)
+	return names
+}
+
+// collectIdentifiersValue 递归收集标识符
+func collectIdentifiersValue(v reflect.Value, names map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectIdentifiersValue(v.Elem(), names)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			collectIdentifiersValue(v.Index(i), names)
+		}
+	case reflect.Struct:
+		if ident, ok := v.Addr().Interface().(*parser.Identifier); ok {
+			names[ident.Value] = true
+			return
+		}
+		typ := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !typ.Field(i).IsExported() {
+				continue
+			}
+			switch field.Kind() {
+			case reflect.String:
+				if identifierFields[typ.Field(i).Name] && field.String() != "" {
+					names[field.String()] = true
+				}
+			case reflect.Slice:
+				if field.Type().Elem().Kind() == reflect.String {
+					if identifierFields[typ.Field(i).Name] {
+						for j := 0; j < field.Len(); j++ {
+							names[field.Index(j).String()] = true
+						}
+					}
+					continue
+				}
+				collectIdentifiersValue(field, names)
+			case reflect.Ptr, reflect.Interface, reflect.Struct:
+				if field.Type().PkgPath() == "github.com/tangzhangming/tugo/internal/lexer" {
+					continue
+				}
+				collectIdentifiersValue(field, names)
+			}
+		}
+	}
+}
+
+// classInfoName 返回类信息变量的名称 XClassName_ClassInfo
+func classInfoName(goClassName string) string {
+	return "X" + goClassName + "_ClassInfo"
+}
+
+// abstractBaseName 返回抽象类基础结构体的名称
+// 公开的抽象类导出基础结构体，其他包中的子类才能嵌入它
+func abstractBaseName(className string, public bool) string {
+	return symbol.ToGoName(className, public) + "Base"
+}
+
+// validateReservedNames 校验用户代码没有使用编译器保留的名称
+// 包级的生成名称（类信息变量、重载修饰名）需要跨文件、跨包保持稳定，不能由分配器改名，
+// 因此在这里检查冲突并报错；$ 变量的转换前缀同样保留给编译器
+func (t *Transpiler) validateReservedNames(file *parser.File) {
+	for name := range collectIdentifiers(file) {
+		if strings.HasPrefix(name, symbol.DollarVarPrefix) {
+			t.errors = append(t.errors, i18n.T(i18n.ErrReservedIdentifier, name, symbol.DollarVarPrefix))
+		}
+	}
+
+	// 包内用户声明的 Go 名称
+	declared := make(map[string]string) // goName -> 声明名
+	for _, sym := range t.table.GetByPackage(t.pkg) {
+		declared[sym.GoName] = sym.Name
+	}
+
+	for _, stmt := range file.Statements {
+		classDecl, ok := stmt.(*parser.ClassDecl)
+		if !ok {
+			continue
+		}
+
+		// 类信息变量
+		if !classDecl.Static {
+			infoName := classInfoName(symbol.ToGoName(classDecl.Name, classDecl.Public))
+			if userName, ok := declared[infoName]; ok {
+				t.errors = append(t.errors, i18n.T(i18n.ErrGeneratedNameConflict, userName, infoName, classDecl.Name))
+			}
+		}
+
+		t.validateMemberNames(classDecl)
+
+		// 重载方法的修饰名不能与同类中其他方法的 Go 名称相同
+		methodNames := make(map[string]string)
+		for _, method := range classDecl.Methods {
+			isPublic := method.Visibility == "public" || method.Visibility == "protected"
+			if !t.table.IsMethodOverloaded(t.pkg, classDecl.Name, method.Name) {
+				methodNames[symbol.ToGoName(method.Name, isPublic)] = method.Name
+			}
+		}
+		for _, method := range classDecl.Methods {
+			if !t.table.IsMethodOverloaded(t.pkg, classDecl.Name, method.Name) {
+				continue
+			}
+			isPublic := method.Visibility == "public" || method.Visibility == "protected"
+			mangled := symbol.GenerateMangledName(method.Name, method.Params, isPublic)
+			if userName, ok := methodNames[mangled]; ok {
+				t.errors = append(t.errors, i18n.T(i18n.ErrGeneratedNameConflict, userName, mangled, classDecl.Name))
+			}
+		}
+	}
+}
+
+// validateMemberNames 校验类的字段和方法生成的 Go 名称互不相同
+// protected 成员和 public 成员一样导出，protected name 与 public Name 会生成同一个 Go 名称
+func (t *Transpiler) validateMemberNames(classDecl *parser.ClassDecl) {
+	members := make(map[string]string) // goName -> 成员名
+	check := func(name, visibility string) {
+		goName := symbol.ToGoName(name, visibility == "public" || visibility == "protected")
+		if other, ok := members[goName]; ok && other != name {
+			t.errors = append(t.errors, i18n.T(i18n.ErrMemberNameConflict, other, name, classDecl.Name, goName))
+			return
+		}
+		members[goName] = name
+	}
+
+	for _, field := range classDecl.Fields {
+		if !field.Static {
+			check(field.Name, field.Visibility)
+		}
+	}
+	methods := append(append([]*parser.ClassMethod{}, classDecl.AbstractMethods...), classDecl.Methods...)
+	for _, method := range methods {
+		// 静态方法和泛型方法生成为包级函数，重载方法使用修饰名
+		if method.Static || isGenericMethod(method) || t.table.IsMethodOverloaded(t.pkg, classDecl.Name, method.Name) {
+			continue
+		}
+		check(method.Name, method.Visibility)
+	}
+}
@@ -0,0 +1,664 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+)
+
+// 空安全
+//
+// 类的指针类型 *User 默认非空，User? 或 *User? 表示可能为 nil，两者在 Go 中都是 *User：
+//   - 可能为 nil 的值不能赋给非空的变量、字段、参数和返回值
+//   - 访问可能为 nil 的值的成员是编译错误，需要使用 a?.b / a?.m() 或先判断 a != nil
+//   - a ?? b 在 a 为 nil 时取 b
+//
+// 判断 nil 后的收窄是流敏感的：if x != nil { ... } 中 x 非空，if x == nil { return } 之后 x 非空；
+// && / || 的右侧、三元表达式的分支同样收窄。收窄只跟踪变量和字段路径（x、this.f、self::f），
+// 循环和 switch 中赋值过的路径进入语句时不再视为非空。
+// 只检查 tugo 类的指针类型，Go 包中的指针、接口、map 等保持 Go 的语义。
+
+// generateNullableType 生成可空类型，User? 与 *User? 都生成 *User
+func (g *CodeGen) generateNullableType(t *parser.NullableType) string {
+	if _, ok := t.Base.(*parser.PointerType); ok {
+		return g.generateType(t.Base)
+	}
+	if !g.isClassRef("*" + canonicalType(t.Base)) {
+		g.transpiler.AddError(t.Token.Line, t.Token.Column, i18n.T(i18n.ErrNullableValueType, typeText(t.Base)))
+	}
+	return "*" + g.generateType(t.Base)
+}
+
+// isNullableType 判断声明的类型是否是可空类型 T?
+func isNullableType(typ parser.Expression) bool {
+	_, ok := typ.(*parser.NullableType)
+	return ok
+}
+
+// isClassRef 判断规范类型是否是 tugo 类的指针 *C
+func (g *CodeGen) isClassRef(t string) bool {
+	return strings.HasPrefix(t, "*") && g.lookupClass(stripTypeArgs(t[1:])) != nil
+}
+
+// isNonNullRef 判断声明的类型是否是非空的类指针
+func (g *CodeGen) isNonNullRef(typ parser.Expression) bool {
+	return typ != nil && !isNullableType(typ) && !isGoPackageType(typ) && g.isClassRef(canonicalType(typ))
+}
+
+// isGoPackageType 判断类型是否是 Go 包中的类型（如 *gorm.DB）
+// 规范类型会去掉包限定，不能用它区分同名的 tugo 类
+func isGoPackageType(typ parser.Expression) bool {
+	if ptr, ok := typ.(*parser.PointerType); ok {
+		typ = ptr.Base
+	}
+	if generic, ok := typ.(*parser.GenericType); ok {
+		typ = generic.Type
+	}
+	_, ok := typ.(*parser.SelectorExpr)
+	return ok
+}
+
+// stablePath 返回可以收窄的路径（x、this.f、x.f.g、self::f、Class::f），其他表达式返回空串
+func stablePath(expr parser.Expression) string {
+	switch e := expr.(type) {
+	case *parser.Identifier:
+		return e.Value
+	case *parser.ThisExpr:
+		return "this"
+	case *parser.ParenExpr:
+		return stablePath(e.X)
+	case *parser.SelectorExpr:
+		if e.Safe {
+			return ""
+		}
+		if x := stablePath(e.X); x != "" {
+			return x + "." + e.Sel
+		}
+	case *parser.StaticAccessExpr:
+		switch left := e.Left.(type) {
+		case *parser.SelfExpr:
+			return "self::" + e.Member
+		case *parser.Identifier:
+			return left.Value + "::" + e.Member
+		}
+	}
+	return ""
+}
+
+// nullText 渲染错误信息中的表达式
+func nullText(expr parser.Expression) string {
+	if path := stablePath(expr); path != "" {
+		return path
+	}
+	switch e := expr.(type) {
+	case *parser.NilLiteral:
+		return "nil"
+	case *parser.SelectorExpr:
+		return nullText(e.X) + "?." + e.Sel
+	case *parser.CallExpr:
+		return nullText(e.Function) + "()"
+	case *parser.NamedArg:
+		return nullText(e.Value)
+	case *parser.StaticAccessExpr:
+		return nullText(e.Left) + "::" + e.Member
+	}
+	return expr.TokenLiteral()
+}
+
+// safeSelector 返回安全导航 a?.b / a?.m(...) 的选择器，其他表达式返回 nil
+func safeSelector(expr parser.Expression) *parser.SelectorExpr {
+	switch e := expr.(type) {
+	case *parser.SelectorExpr:
+		if e.Safe {
+			return e
+		}
+	case *parser.CallExpr:
+		if sel, _ := methodCallSelector(e.Function); sel != nil && sel.Safe {
+			return sel
+		}
+	}
+	return nil
+}
+
+// isNullable 判断表达式的值在当前位置是否可能为 nil
+func (g *CodeGen) isNullable(expr parser.Expression) bool {
+	if path := stablePath(expr); path != "" && g.nonNil[path] {
+		return false
+	}
+	switch e := expr.(type) {
+	case *parser.NilLiteral:
+		return true
+	case *parser.ParenExpr:
+		return g.isNullable(e.X)
+	case *parser.NamedArg:
+		return g.isNullable(e.Value)
+	case *parser.Identifier:
+		return g.nullableVars[e.Value]
+	case *parser.TernaryExpr:
+		return g.isNullable(e.TrueExpr) || g.isNullable(e.FalseExpr)
+	case *parser.BinaryExpr:
+		return e.Operator == "??" && g.isNullable(e.Right)
+	case *parser.SelectorExpr, *parser.CallExpr, *parser.StaticAccessExpr:
+		typ, _ := g.memberType(expr)
+		if isNullableType(typ) {
+			return true
+		}
+		// a?.b 在 a 为 nil 时得到 nil
+		return safeSelector(expr) != nil && !isGoPackageType(typ) && g.isClassRef(canonicalType(typ))
+	}
+	return false
+}
+
+// memberType 返回字段访问或调用结果的声明类型；found 表示找到了声明，void 方法返回 (nil, true)
+func (g *CodeGen) memberType(expr parser.Expression) (typ parser.Expression, found bool) {
+	switch e := expr.(type) {
+	case *parser.SelectorExpr:
+		typ, _ = g.fieldDecl(e)
+		return typ, typ != nil
+	case *parser.StaticAccessExpr:
+		typ, _ = g.staticFieldDecl(e)
+		return typ, typ != nil
+	case *parser.CallExpr:
+		var results []*parser.Field
+		if ident, ok := e.Function.(*parser.Identifier); ok {
+			decl := g.transpiler.GetFuncDecl(g.transpiler.pkg, ident.Value)
+			if decl == nil {
+				return nil, false
+			}
+			results = decl.Results
+		} else {
+			name, methods, typeParams := g.calleeMethods(e)
+			method := g.selectedMethod(name, methods, e.Arguments, typeParams)
+			if method == nil {
+				return nil, false
+			}
+			results = method.Results
+		}
+		switch len(results) {
+		case 0:
+			return nil, true
+		case 1:
+			return results[0].Type, true
+		}
+	}
+	return nil, false
+}
+
+// checkDereference 检查成员访问 x.m 的 x 是否可能为 nil
+func (g *CodeGen) checkDereference(sel *parser.SelectorExpr) {
+	if g.nullReported[sel] || !g.isNullable(sel.X) {
+		return
+	}
+	g.nullReported[sel] = true
+	g.transpiler.AddError(sel.Token.Line, sel.Token.Column, i18n.T(i18n.ErrNullableDereference, nullText(sel.X)))
+}
+
+// checkNonNull 检查赋给非空类型 target（规范类型文本）的值是否可能为 nil
+func (g *CodeGen) checkNonNull(value parser.Expression, target string) {
+	if !g.isClassRef(target) || !g.isNullable(value) {
+		return
+	}
+	line, col := nodePos(value)
+	g.transpiler.AddError(line, col, i18n.T(i18n.ErrNilToNonNullable, nullText(value), target))
+}
+
+// checkNonNullDecl 检查赋给声明类型 typ 的值是否可能为 nil
+func (g *CodeGen) checkNonNullDecl(value, typ parser.Expression) {
+	if g.isNonNullRef(typ) {
+		g.checkNonNull(value, canonicalType(typ))
+	}
+}
+
+// checkNullArgs 检查传给非空参数的实参
+func (g *CodeGen) checkNullArgs(b *callBinding) {
+	for i, param := range b.method.Params {
+		if b.args[i] != nil && !isVariadicParam(param) {
+			g.checkNonNullDecl(b.args[i], param.Type)
+		}
+	}
+}
+
+// checkCallNullArgs 检查普通方法调用（不经过重载解析）的实参
+func (g *CodeGen) checkCallNullArgs(expr *parser.CallExpr) {
+	name, methods, typeParams := g.calleeMethods(expr)
+	if len(methods) == 0 {
+		return
+	}
+	if b, _, _ := g.selectOverload(name, methods, expr.Arguments, typeParams); b != nil {
+		g.checkNullArgs(b)
+	}
+}
+
+// declareNullable 记录新声明的变量是否可空：有声明类型时按类型，否则按初始值推断
+func (g *CodeGen) declareNullable(name string, typ, value parser.Expression) {
+	valueNullable := value == nil || g.isNullable(value)
+	g.forgetPath(name)
+	delete(g.lateVars, name)
+	switch {
+	case typ != nil:
+		g.nullableVars[name] = isNullableType(typ)
+	case value != nil:
+		g.nullableVars[name] = valueNullable
+	default:
+		delete(g.nullableVars, name)
+	}
+	if g.nullableVars[name] && !valueNullable {
+		g.nonNil[name] = true
+	}
+}
+
+// assignNullable 检查赋值 target = value 并更新收窄状态
+func (g *CodeGen) assignNullable(target, value parser.Expression) {
+	nullable := g.isNullable(value)
+	if ident, ok := target.(*parser.Identifier); ok {
+		if !g.nullableVars[ident.Value] || g.lateVars[ident.Value] {
+			g.checkNonNull(value, g.localTypes[ident.Value])
+		}
+	} else if typ, _ := g.memberType(target); typ != nil && safeSelector(target) == nil {
+		g.checkNonNullDecl(value, typ)
+	}
+
+	if path := stablePath(target); path != "" {
+		g.forgetPath(path)
+		if !nullable {
+			g.nonNil[path] = true
+		}
+	}
+}
+
+// checkFieldInit 检查非空类指针字段：需要有默认值，或在每个构造方法中都赋值
+func (g *CodeGen) checkFieldInit(decl *parser.ClassDecl) {
+	for _, field := range decl.Fields {
+		if field.Static || field.Value != nil || !g.isNonNullRef(field.Type) {
+			continue
+		}
+		if len(decl.InitMethods) == 0 {
+			g.transpiler.AddError(decl.Token.Line, decl.Token.Column, i18n.T(i18n.ErrNonNullFieldUninit, decl.Name, field.Name, typeText(field.Type)))
+			continue
+		}
+		for _, init := range decl.InitMethods {
+			if init.Body != nil && !assignsField(init.Body.Statements, field.Name) {
+				g.transpiler.AddError(init.Token.Line, init.Token.Column, i18n.T(i18n.ErrNonNullFieldUninit, decl.Name, field.Name, typeText(field.Type)))
+			}
+		}
+	}
+}
+
+// assignsField 判断语句列表是否在每条路径上都给 this.name 赋值：
+// 按顺序查找顶层的赋值（或 if 和 else 分支都赋值），遇到含有 return 的语句之前没有赋值则不算
+func assignsField(stmts []parser.Statement, name string) bool {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *parser.AssignStmt:
+			for _, l := range s.Left {
+				if sel, ok := l.(*parser.SelectorExpr); ok && sel.Sel == name {
+					if _, ok := sel.X.(*parser.ThisExpr); ok {
+						return true
+					}
+				}
+			}
+		case *parser.BlockStmt:
+			if assignsField(s.Statements, name) {
+				return true
+			}
+		case *parser.IfStmt:
+			if s.Consequence != nil && s.Alternative != nil &&
+				assignsField(s.Consequence.Statements, name) && assignsField([]parser.Statement{s.Alternative}, name) {
+				return true
+			}
+		}
+		if hasReturn(stmt) {
+			return false
+		}
+	}
+	return false
+}
+
+// hasReturn 判断语句中（不含闭包）是否有 return
+func hasReturn(stmt parser.Statement) bool {
+	found := false
+	inspect(stmt, func(node parser.Node) bool {
+		switch node.(type) {
+		case *parser.ReturnStmt:
+			found = true
+		case *parser.FuncLiteral:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// forgetPath 清除路径及以它为前缀的路径的收窄
+func (g *CodeGen) forgetPath(path string) {
+	for p := range g.nonNil {
+		if p == path || strings.HasPrefix(p, path+".") {
+			delete(g.nonNil, p)
+		}
+	}
+}
+
+// forgetFields 清除接收者 x 的字段路径（x.f、x.f.g）的收窄，x 本身的收窄保留
+// 用于方法调用之后：方法可能修改接收者的字段；super.m() 的接收者是 this
+func (g *CodeGen) forgetFields(recv parser.Expression) {
+	path := stablePath(recv)
+	if _, ok := recv.(*parser.SuperExpr); ok {
+		path = "this"
+	}
+	if path == "" {
+		return
+	}
+	for p := range g.nonNil {
+		if strings.HasPrefix(p, path+".") {
+			delete(g.nonNil, p)
+		}
+	}
+}
+
+// forgetAssigned 清除语句中（不含闭包）赋值或重新声明过的路径的收窄
+// 用于循环、switch 等：语句体可能执行多次或从任意分支离开
+func (g *CodeGen) forgetAssigned(stmt parser.Statement) {
+	inspect(stmt, func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.AssignStmt:
+			for _, l := range n.Left {
+				if path := stablePath(l); path != "" {
+					g.forgetPath(path)
+				}
+			}
+		case *parser.ShortVarDecl:
+			for _, name := range n.Names {
+				g.forgetPath(name)
+			}
+		case *parser.VarDecl:
+			for _, name := range n.Names {
+				g.forgetPath(name)
+			}
+		case *parser.FuncLiteral:
+			return false
+		}
+		return true
+	})
+}
+
+// narrow 按条件成立（positive）或不成立时可以确定非 nil 的路径收窄
+func (g *CodeGen) narrow(cond parser.Expression, positive bool) {
+	for _, path := range nilChecks(cond, positive) {
+		g.nonNil[path] = true
+	}
+}
+
+// nilChecks 返回条件为 positive 时确定非 nil 的路径
+func nilChecks(cond parser.Expression, positive bool) []string {
+	switch e := cond.(type) {
+	case *parser.ParenExpr:
+		return nilChecks(e.X, positive)
+	case *parser.UnaryExpr:
+		if e.Operator == "!" {
+			return nilChecks(e.Operand, !positive)
+		}
+	case *parser.BinaryExpr:
+		switch e.Operator {
+		case "&&":
+			if positive {
+				return append(nilChecks(e.Left, true), nilChecks(e.Right, true)...)
+			}
+		case "||":
+			if !positive {
+				return append(nilChecks(e.Left, false), nilChecks(e.Right, false)...)
+			}
+		case "!=", "==":
+			if (e.Operator == "!=") != positive {
+				return nil
+			}
+			var operand parser.Expression
+			if _, ok := e.Right.(*parser.NilLiteral); ok {
+				operand = e.Left
+			} else if _, ok := e.Left.(*parser.NilLiteral); ok {
+				operand = e.Right
+			}
+			if path := stablePath(operand); path != "" {
+				return []string{path}
+			}
+		}
+	}
+	return nil
+}
+
+// copyNonNil 复制当前的收窄状态
+func (g *CodeGen) copyNonNil() map[string]bool {
+	state := make(map[string]bool, len(g.nonNil))
+	for path := range g.nonNil {
+		state[path] = true
+	}
+	return state
+}
+
+// joinNonNil 合并 if 各分支结束时的状态：只保留所有能走到 if 之后的分支都确定非 nil 的路径
+func joinNonNil(states []map[string]bool) map[string]bool {
+	joined := make(map[string]bool)
+	if len(states) == 0 {
+		return joined
+	}
+	for path := range states[0] {
+		inAll := true
+		for _, state := range states[1:] {
+			if !state[path] {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			joined[path] = true
+		}
+	}
+	return joined
+}
+
+// fallsThrough 判断语句列表执行完后是否会继续执行后面的语句
+func fallsThrough(stmts []parser.Statement) bool {
+	if terminates(stmts) {
+		return false
+	}
+	return len(stmts) == 0 || !isJump(stmts[len(stmts)-1])
+}
+
+// generateIfBranches 生成 if 的条件和分支，按条件收窄各分支，结束后合并各分支的状态
+func (g *CodeGen) generateIfBranches(stmt *parser.IfStmt) {
+	g.write(g.generateExpression(stmt.Condition))
+	g.write(" ")
+
+	before := g.copyNonNil()
+	var states []map[string]bool
+	g.narrow(stmt.Condition, true)
+	g.generateBlockStmtInline(stmt.Consequence)
+	if fallsThrough(stmt.Consequence.Statements) {
+		states = append(states, g.nonNil)
+	}
+
+	g.nonNil = before
+	g.narrow(stmt.Condition, false)
+	switch alt := stmt.Alternative.(type) {
+	case *parser.BlockStmt:
+		g.write(" else ")
+		g.generateBlockStmtInline(alt)
+		if fallsThrough(alt.Statements) {
+			states = append(states, g.nonNil)
+		}
+	case *parser.IfStmt:
+		g.write(" else ")
+		g.generateIfStmtInline(alt)
+		if !isTerminating(alt) {
+			states = append(states, g.nonNil)
+		}
+	default:
+		states = append(states, g.nonNil)
+	}
+	g.nonNil = joinNonNil(states)
+}
+
+// generateSafeAccess 生成安全导航 a?.b / a?.m(...)：a 为 nil 时不访问成员，结果为成员类型的零值
+func (g *CodeGen) generateSafeAccess(access parser.Expression, sel *parser.SelectorExpr) string {
+	recv, cond, restore := g.bindSafeReceiver(sel.X)
+	inner := replaceSafe(access, sel, recv)
+	body := g.generateExpression(inner)
+	typ, found := g.memberType(inner)
+	goType := g.goTypeText(g.exprType(inner))
+	restore()
+
+	if found && typ == nil {
+		return fmt.Sprintf("func() { if %s { %s } }()", cond, body)
+	}
+	if goType == "" {
+		goType = "any"
+	}
+	return fmt.Sprintf("func() %s { if %s { return %s }; return %s }()", goType, cond, body, zeroText(goType))
+}
+
+// generateSafeCallStmt 生成作为语句的安全调用 a?.m(...)
+func (g *CodeGen) generateSafeCallStmt(call *parser.CallExpr, sel *parser.SelectorExpr) {
+	recv, cond, restore := g.bindSafeReceiver(sel.X)
+	body := g.generateExpression(replaceSafe(call, sel, recv))
+	restore()
+	g.flushPendingStatements()
+	g.writeLine("if " + cond + " {")
+	g.indent++
+	g.writeLine(body)
+	g.indent--
+	g.writeLine("}")
+}
+
+// generateCoalesce 生成 a ?? b：a 不为 nil 时取 a，否则计算 b
+func (g *CodeGen) generateCoalesce(expr *parser.BinaryExpr) string {
+	leftType := g.exprType(expr.Left)
+	goType := g.goTypeText(g.exprType(expr))
+	if goType == "" {
+		goType = "any"
+	}
+
+	// a?.name ?? "guest"：成员不是可为 nil 的类型时，按接收者是否为 nil 选择
+	if sel := safeSelector(expr.Left); sel != nil && leftType != "" && !isNilable(leftType) {
+		recv, cond, restore := g.bindSafeReceiver(sel.X)
+		body := g.generateExpression(replaceSafe(expr.Left, sel, recv))
+		restore()
+		right := g.generateExpression(expr.Right)
+		return fmt.Sprintf("func() %s { if %s { return %s }; return %s }()", goType, cond, body, right)
+	}
+
+	left := g.generateExpression(expr.Left)
+	right := g.generateExpression(expr.Right)
+	if leftType != "" && !isNilable(leftType) && !strings.HasPrefix(leftType, "untyped ") {
+		// 左侧不会为 nil
+		return left
+	}
+	if stablePath(expr.Left) != "" {
+		return fmt.Sprintf("func() %s { if %s != nil { return %s }; return %s }()", goType, left, left, right)
+	}
+	tmp := g.names.fresh("__coalesce_%d")
+	return fmt.Sprintf("func() %s { if %s := %s; %s != nil { return %s }; return %s }()", goType, tmp, left, tmp, tmp, right)
+}
+
+// bindSafeReceiver 准备安全导航的接收者，返回在成员访问中使用的接收者、判断非 nil 的条件和恢复状态的函数
+// 接收者是变量或字段路径时直接使用，否则先求值到临时变量，避免重复求值
+func (g *CodeGen) bindSafeReceiver(x parser.Expression) (parser.Expression, string, func()) {
+	if path := stablePath(x); path != "" {
+		cond := g.generateExpression(x) + " != nil"
+		was := g.nonNil[path]
+		g.nonNil[path] = true
+		return x, cond, func() {
+			if !was {
+				delete(g.nonNil, path)
+			}
+		}
+	}
+
+	tmp := g.names.fresh("__safe_%d")
+	cond := fmt.Sprintf("%s := %s; %s != nil", tmp, g.generateExpression(x), tmp)
+	g.localTypes[tmp] = g.exprType(x)
+	if recv := g.getReceiverType(x); recv != "" {
+		g.varTypes[tmp] = recv
+	}
+	g.nonNil[tmp] = true
+	return &parser.Identifier{Value: tmp}, cond, func() {
+		delete(g.localTypes, tmp)
+		delete(g.varTypes, tmp)
+		delete(g.nonNil, tmp)
+	}
+}
+
+// replaceSafe 复制安全导航表达式，把其中的 ?. 换成对 recv 的普通成员访问
+func replaceSafe(access parser.Expression, sel *parser.SelectorExpr, recv parser.Expression) parser.Expression {
+	plain := &parser.SelectorExpr{Token: sel.Token, X: recv, Sel: sel.Sel}
+	call, ok := access.(*parser.CallExpr)
+	if !ok {
+		return plain
+	}
+	copied := *call
+	switch fn := call.Function.(type) {
+	case *parser.IndexExpr:
+		index := *fn
+		index.X = plain
+		copied.Function = &index
+	case *parser.GenericType:
+		generic := *fn
+		generic.Type = plain
+		copied.Function = &generic
+	default:
+		copied.Function = plain
+	}
+	return &copied
+}
+
+// goTypeText 将规范类型文本转换为生成代码中的 Go 类型，包含无法确定的名字时返回空串
+func (g *CodeGen) goTypeText(t string) string {
+	t = defaultType(t)
+	if t == "" {
+		return ""
+	}
+	var sb strings.Builder
+	word := func(r rune) bool {
+		return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127
+	}
+	runes := []rune(t)
+	for i := 0; i < len(runes); {
+		if !word(runes[i]) {
+			sb.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && word(runes[j]) {
+			j++
+		}
+		name := string(runes[i:j])
+		i = j
+		switch {
+		case name[0] >= '0' && name[0] <= '9', basicTypes[name], g.isTypeParam(name):
+			sb.WriteString(name)
+		case name == "map" || name == "chan" || name == "func" || name == "struct" || name == "interface":
+			sb.WriteString(name)
+		case g.lookupClass(name) != nil || g.lookupStruct(name) != nil || g.lookupInterface(name) != nil:
+			sb.WriteString(g.generateIdentifier(&parser.Identifier{Value: name}))
+		default:
+			return ""
+		}
+	}
+	return sb.String()
+}
+
+// zeroText 返回 Go 类型的零值表达式
+func zeroText(goType string) string {
+	switch {
+	case goType == "string":
+		return `""`
+	case goType == "bool":
+		return "false"
+	case isIntegerType(goType) || isFloatType(goType) || goType == "byte" || goType == "rune":
+		return "0"
+	case isNilable(goType):
+		return "nil"
+	}
+	return "*new(" + goType + ")"
+}
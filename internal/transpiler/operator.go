@@ -0,0 +1,255 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/lexer"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 运算符重载
+//
+//	class Money {
+//		public operator +(other: Money) Money { ... }
+//		public operator <(other: Money) bool { ... }
+//	}
+//
+// 解析器把 operator 声明解析为 opAdd、opLess 等公开方法（见 parser.OperatorMethods）。
+// 操作数的类型声明了对应的运算符时，a + b 改写为 a.opAdd(b)，m[i] 改写为 m.opIndex(i)，
+// 之后按普通方法调用做重载解析。!= 取 opEquals 的反，a > b 改写为 b.opLess(a)，
+// a <= b 为 !b.opLess(a)，a >= b 为 !a.opLess(b)；与 nil 比较仍是指针比较。
+// 交换操作数会改变求值顺序，两侧都不是变量或常量时先把操作数按原顺序绑定到函数参数；
+// 结构体方法的接收者是指针，不可寻址的结构体值同样先绑定再调用。
+
+// operatorUse 运算符改写成的方法调用 recv.method(arg)
+type operatorUse struct {
+	op      string
+	recv    parser.Expression
+	arg     parser.Expression
+	swapped bool // 接收者是右操作数
+	negate  bool // 对调用结果取反
+	owner   *methodOwner
+	methods []*parser.ClassMethod
+}
+
+// lookupOperator 查找二元运算 left op right 使用的运算符方法，不是重载的运算符时返回 nil
+func (g *CodeGen) lookupOperator(op string, left, right parser.Expression) *operatorUse {
+	use := &operatorUse{op: op, recv: left, arg: right}
+	name := parser.OperatorMethods[op]
+	switch op {
+	case "[]":
+		return nil
+	case "!=":
+		name, use.negate = "opEquals", true
+	case ">":
+		name, use.swapped = "opLess", true
+	case "<=":
+		name, use.swapped, use.negate = "opLess", true, true
+	case ">=":
+		name, use.negate = "opLess", true
+	}
+	if name == "" {
+		return nil
+	}
+	if name == "opEquals" && (isNilLiteral(left) || isNilLiteral(right)) {
+		return nil
+	}
+	if use.swapped {
+		use.recv, use.arg = right, left
+	}
+	use.owner, use.methods = g.operatorMethods(use.recv, name)
+	if use.owner == nil {
+		return nil
+	}
+	return use
+}
+
+// lookupIndexOperator 查找 x[i] 使用的 operator []，不是重载的运算符时返回 nil
+func (g *CodeGen) lookupIndexOperator(expr *parser.IndexExpr) *operatorUse {
+	owner, methods := g.operatorMethods(expr.X, parser.OperatorMethods["[]"])
+	if owner == nil {
+		return nil
+	}
+	return &operatorUse{op: "[]", recv: expr.X, arg: expr.Index, owner: owner, methods: methods}
+}
+
+// operatorMethods 返回接收者类型中名为 name 的方法，其中没有 operator 声明时返回 nil
+func (g *CodeGen) operatorMethods(recv parser.Expression, name string) (*methodOwner, []*parser.ClassMethod) {
+	t := stripTypeArgs(strings.TrimPrefix(g.exprType(recv), "*"))
+	if t == "" || strings.HasPrefix(t, "untyped ") {
+		return nil, nil
+	}
+	owner, methods := g.methodCandidates(g.getClassPackage(t), t, name, false)
+	for _, m := range methods {
+		if m.Operator != "" {
+			return owner, methods
+		}
+	}
+	return nil, nil
+}
+
+// callExpr 运算符对应的方法调用（不含取反），用于推断结果类型
+func (use *operatorUse) callExpr(tok lexer.Token) *parser.CallExpr {
+	fn := &parser.SelectorExpr{Token: tok, X: use.recv, Sel: use.methods[0].Name}
+	return &parser.CallExpr{Token: tok, Function: fn, Arguments: []parser.Expression{use.arg}}
+}
+
+// operatorType 推断重载运算符的结果类型
+func (g *CodeGen) operatorType(tok lexer.Token, use *operatorUse) string {
+	if use.negate || use.swapped || use.op == "==" || use.op == "<" {
+		return "bool"
+	}
+	return g.exprType(use.callExpr(tok))
+}
+
+// generateOperatorCall 生成运算符方法调用
+func (g *CodeGen) generateOperatorCall(tok parser.Node, use *operatorUse) string {
+	recv, arg := use.recv, use.arg
+	var params, values, bound []string
+	bind := func(x parser.Expression) parser.Expression {
+		t := g.exprType(x)
+		goType := g.goTypeText(t)
+		if goType == "" {
+			return x
+		}
+		tmp := g.names.fresh("__operand_%d")
+		params = append(params, tmp+" "+goType)
+		values = append(values, g.generateExpression(x))
+		bound = append(bound, tmp)
+		g.localTypes[tmp] = t
+		return &parser.Identifier{Value: tmp}
+	}
+	reorder := use.swapped && !simpleOperand(use.arg) && !simpleOperand(use.recv)
+	if reorder {
+		arg = bind(arg)
+	}
+	if reorder || g.needsAddress(use) {
+		recv = bind(recv)
+	}
+	defer func() {
+		for _, tmp := range bound {
+			delete(g.localTypes, tmp)
+		}
+	}()
+
+	bindUse := *use
+	bindUse.recv, bindUse.arg = recv, arg
+	name := use.owner.name + ".operator " + use.op
+	args := []parser.Expression{arg}
+	// 只有一个候选时 resolveCall 把类型不匹配交给 Go 编译器，运算符在这里报告
+	if len(use.methods) == 1 {
+		param := canonicalType(use.methods[0].Params[0].Type)
+		if _, _, ok := g.conversionCost(g.exprType(arg), param, use.owner.typeParams); !ok {
+			line, col := nodePos(tok)
+			g.transpiler.AddError(line, col, i18n.T(i18n.ErrNoMatchingOverload, name, g.argTypesText(args), candidatesText(use.methods)))
+			return "/* unresolved call */"
+		}
+	}
+	b := g.resolveCall(tok, name, use.methods, args, use.owner.typeParams)
+	if b == nil {
+		return "/* unresolved call */"
+	}
+	methodName := symbol.ToGoName(b.method.Name, true)
+	if len(use.methods) > 1 {
+		methodName = symbol.GenerateMangledName(b.method.Name, b.method.Params, true)
+	}
+	call := g.generateExpression(recv) + "." + methodName + "(" + strings.Join(g.boundArgs(b), ", ") + ")"
+	if use.negate {
+		call = "!" + call
+	}
+	if len(params) == 0 {
+		return call
+	}
+
+	line, col := nodePos(tok)
+	resultType := g.goTypeText(g.operatorType(lexer.Token{Line: line, Column: col}, &bindUse))
+	if resultType == "" {
+		resultType = "any"
+	}
+	return fmt.Sprintf("func(%s) %s { return %s }(%s)", strings.Join(params, ", "), resultType, call, strings.Join(values, ", "))
+}
+
+// needsAddress 判断接收者是否是需要取地址才能调用指针方法的结构体值
+func (g *CodeGen) needsAddress(use *operatorUse) bool {
+	if !use.owner.isStruct || g.transpiler.GetStructDecl(use.owner.pkg, use.owner.name) == nil {
+		return false
+	}
+	return !strings.HasPrefix(g.exprType(use.recv), "*") && stablePath(use.recv) == ""
+}
+
+// simpleOperand 判断操作数是否是求值顺序无关的变量、字段或常量
+func simpleOperand(x parser.Expression) bool {
+	switch x.(type) {
+	case *parser.IntegerLiteral, *parser.FloatLiteral, *parser.StringLiteral, *parser.CharLiteral, *parser.BoolLiteral, *parser.NilLiteral:
+		return true
+	}
+	return stablePath(x) != ""
+}
+
+// isNilLiteral 判断表达式是否是 nil
+func isNilLiteral(x parser.Expression) bool {
+	_, ok := x.(*parser.NilLiteral)
+	return ok
+}
+
+// checkOperatorDefined 报告作用于 tugo 类或结构体、但类型没有重载的算术和比较运算
+func (g *CodeGen) checkOperatorDefined(expr *parser.BinaryExpr) {
+	switch expr.Operator {
+	case "+", "-", "*", "/", "%", "<", "<=", ">", ">=":
+	default:
+		return
+	}
+	t := g.exprType(expr.Left)
+	name := stripTypeArgs(strings.TrimPrefix(t, "*"))
+	if name == "" || g.lookupClass(name) == nil && g.lookupStruct(name) == nil {
+		return
+	}
+	g.transpiler.AddError(expr.Token.Line, expr.Token.Column, i18n.T(i18n.ErrOperatorNotDefined, expr.Operator, t))
+}
+
+// lowerOperatorAssign 把使用重载运算符的复合赋值 x += v 改写为 x = x + v，
+// 并报告给 operator [] 的结果赋值；不需要改写时 ok 为 false，出错时返回 nil 语句
+func (g *CodeGen) lowerOperatorAssign(stmt parser.Statement) (lowered parser.Statement, ok bool) {
+	var targets []parser.Expression
+	switch s := stmt.(type) {
+	case *parser.AssignStmt:
+		targets = s.Left
+	case *parser.IncDecStmt:
+		targets = []parser.Expression{s.X}
+	default:
+		return nil, false
+	}
+	for _, target := range targets {
+		if index, isIndex := target.(*parser.IndexExpr); isIndex && g.lookupIndexOperator(index) != nil {
+			g.transpiler.AddError(index.Token.Line, index.Token.Column, i18n.T(i18n.ErrOperatorIndexReadOnly, g.exprType(index.X)))
+			return nil, true
+		}
+	}
+
+	s, isAssign := stmt.(*parser.AssignStmt)
+	if !isAssign || len(s.Left) != 1 || len(s.Right) != 1 {
+		return nil, false
+	}
+	op := strings.TrimSuffix(s.Token.Literal, "=")
+	if op == "" || op == ":" || g.lookupOperator(op, s.Left[0], s.Right[0]) == nil {
+		return nil, false
+	}
+	if !simpleTarget(s.Left[0]) {
+		g.transpiler.AddError(s.Token.Line, s.Token.Column, i18n.T(i18n.ErrOperatorAssignTarget, op))
+		return nil, true
+	}
+	value := &parser.BinaryExpr{Token: s.Token, Left: s.Left[0], Operator: op, Right: s.Right[0]}
+	assign := lexer.Token{Type: lexer.TOKEN_ASSIGN, Literal: "=", Line: s.Token.Line, Column: s.Token.Column}
+	return &parser.AssignStmt{Token: assign, Left: s.Left, Right: []parser.Expression{value}}, true
+}
+
+// simpleTarget 判断赋值目标可以重复求值：变量、字段或操作数都是变量和常量的下标
+func simpleTarget(x parser.Expression) bool {
+	if index, ok := x.(*parser.IndexExpr); ok {
+		return stablePath(index.X) != "" && simpleOperand(index.Index)
+	}
+	return stablePath(x) != ""
+}
@@ -0,0 +1,828 @@
+package transpiler
+
+import (
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/lexer"
+	"github.com/tangzhangming/tugo/internal/parser"
+)
+
+// 常量折叠与死分支消除
+//
+// 优化在校验之后、代码生成之前直接改写 AST，由 --opt 或 tugo.toml 中的
+// [build] optimize = true 开启：
+//
+//   - 折叠整数算术、字符串拼接、比较和布尔运算：1 + 2 * 3 -> 7，"a" + "b" -> "ab"
+//   - 常量条件的三元表达式只保留被选中的分支
+//   - if true / if false 只保留会执行的分支，并对被删除的分支给出警告
+//   - 字面量主体的 match 删除不可能匹配的分支，并给出警告
+//
+// 浮点数不参与折叠：Go 的无类型浮点常量是精确值，按 float64 折叠会改变结果。
+// 被删除的代码中引用的局部变量会生成 _ = x，避免 Go 报告变量未使用。
+
+// optimizer AST 优化器
+type optimizer struct {
+	t         *Transpiler
+	scopes    []map[string]bool // 当前函数内可见的局部变量
+	stmtLevel int               // 当前语句所在的作用域层数
+	pending   []string          // 需要在当前语句之前保持使用的局部变量
+}
+
+// optimizeFile 对文件中的所有函数体执行常量折叠和死分支消除
+func (t *Transpiler) optimizeFile(file *parser.File) {
+	o := &optimizer{t: t}
+	for _, stmt := range file.Statements {
+		switch s := stmt.(type) {
+		case *parser.ClassDecl:
+			for _, field := range s.Fields {
+				field.Value = o.expr(field.Value)
+			}
+			o.methods(s.Methods)
+			o.methods(s.InitMethods)
+			if len(s.InitMethods) == 0 {
+				o.methods([]*parser.ClassMethod{s.InitMethod})
+			}
+		case *parser.StructDecl:
+			o.methods(s.Methods)
+			o.methods([]*parser.ClassMethod{s.InitMethod})
+		case *parser.EnumDecl:
+			o.methods(s.Methods)
+		case *parser.ExtendDecl:
+			o.methods(s.Methods)
+		case *parser.FuncDecl:
+			o.body(s.Body)
+		}
+	}
+}
+
+// methods 优化方法体
+func (o *optimizer) methods(methods []*parser.ClassMethod) {
+	for _, method := range methods {
+		if method != nil {
+			o.body(method.Body)
+		}
+	}
+}
+
+// body 优化函数体
+func (o *optimizer) body(body *parser.BlockStmt) {
+	if body == nil {
+		return
+	}
+	o.scopes = nil
+	o.pending = nil
+	body.Statements = o.block(body.Statements)
+}
+
+// block 优化语句列表，返回新的语句列表
+func (o *optimizer) block(stmts []parser.Statement) []parser.Statement {
+	o.scopes = append(o.scopes, make(map[string]bool))
+	savedLevel, savedPending := o.stmtLevel, o.pending
+	o.stmtLevel = len(o.scopes)
+
+	var result []parser.Statement
+	for _, stmt := range stmts {
+		o.pending = nil
+		replaced := o.stmt(stmt)
+		result = append(result, o.keepAliveStmts()...)
+		result = append(result, replaced...)
+		for _, s := range replaced {
+			o.declare(s)
+		}
+	}
+
+	o.stmtLevel, o.pending = savedLevel, savedPending
+	o.scopes = o.scopes[:len(o.scopes)-1]
+	return result
+}
+
+// stmt 优化单条语句，返回替换后的语句（可能为空或多条）
+func (o *optimizer) stmt(stmt parser.Statement) []parser.Statement {
+	switch s := stmt.(type) {
+	case *parser.ExpressionStmt:
+		folded := o.expr(s.Expression)
+		// 语句位置的 match/三元表达式被折叠成非调用表达式时，Go 会报告值未使用，保留原表达式
+		if _, ok := folded.(*parser.CallExpr); ok || !isBranchExpr(s.Expression) {
+			s.Expression = folded
+		}
+	case *parser.ShortVarDecl:
+		s.Value = o.expr(s.Value)
+	case *parser.VarDecl:
+		s.Value = o.expr(s.Value)
+	case *parser.ConstDecl:
+		s.Value = o.expr(s.Value)
+	case *parser.AssignStmt:
+		o.exprs(s.Left)
+		o.exprs(s.Right)
+	case *parser.ReturnStmt:
+		o.exprs(s.Values)
+	case *parser.ThrowStmt:
+		s.Value = o.expr(s.Value)
+	case *parser.IncDecStmt:
+		s.X = o.expr(s.X)
+	case *parser.SendStmt:
+		s.Channel = o.expr(s.Channel)
+		s.Value = o.expr(s.Value)
+	case *parser.GoStmt:
+		o.call(s.Call)
+	case *parser.DeferStmt:
+		o.call(s.Call)
+	case *parser.BlockStmt:
+		s.Statements = o.block(s.Statements)
+	case *parser.IfStmt:
+		return o.ifStmt(s)
+	case *parser.ForStmt:
+		o.scopes = append(o.scopes, make(map[string]bool))
+		if s.Init != nil {
+			s.Init = o.single(s.Init)
+			o.declare(s.Init)
+		}
+		s.Condition = o.expr(s.Condition)
+		if s.Post != nil {
+			s.Post = o.single(s.Post)
+		}
+		s.Body.Statements = o.block(s.Body.Statements)
+		o.scopes = o.scopes[:len(o.scopes)-1]
+	case *parser.RangeStmt:
+		s.X = o.expr(s.X)
+		o.scopes = append(o.scopes, make(map[string]bool))
+		o.declare(s)
+		s.Body.Statements = o.block(s.Body.Statements)
+		o.scopes = o.scopes[:len(o.scopes)-1]
+	case *parser.SwitchStmt:
+		o.scopes = append(o.scopes, make(map[string]bool))
+		if s.Init != nil {
+			s.Init = o.single(s.Init)
+			o.declare(s.Init)
+		}
+		s.Tag = o.expr(s.Tag)
+		for _, clause := range s.Cases {
+			o.exprs(clause.Exprs)
+			clause.Body = o.block(clause.Body)
+		}
+		o.scopes = o.scopes[:len(o.scopes)-1]
+	case *parser.SelectStmt:
+		for _, clause := range s.Cases {
+			clause.Body = o.block(clause.Body)
+		}
+	case *parser.TryStmt:
+		s.Body.Statements = o.block(s.Body.Statements)
+		for _, clause := range s.Catches {
+			if clause.Body != nil {
+				clause.Body.Statements = o.block(clause.Body.Statements)
+			}
+		}
+		if s.Finally != nil {
+			s.Finally.Statements = o.block(s.Finally.Statements)
+		}
+	}
+	return []parser.Statement{stmt}
+}
+
+// single 优化 for/switch 的初始化语句等只能是一条语句的位置
+func (o *optimizer) single(stmt parser.Statement) parser.Statement {
+	result := o.stmt(stmt)
+	if len(result) == 1 {
+		return result[0]
+	}
+	return stmt
+}
+
+// ifStmt 优化 if 语句，条件为常量时只保留会执行的分支
+func (o *optimizer) ifStmt(s *parser.IfStmt) []parser.Statement {
+	o.scopes = append(o.scopes, make(map[string]bool))
+	defer func() { o.scopes = o.scopes[:len(o.scopes)-1] }()
+
+	if s.Init != nil {
+		s.Init = o.single(s.Init)
+		o.declare(s.Init)
+	}
+	s.Condition = o.expr(s.Condition)
+
+	cond, ok := s.Condition.(*parser.BoolLiteral)
+	if !ok || s.Init != nil {
+		s.Consequence.Statements = o.block(s.Consequence.Statements)
+		s.Alternative = o.alternative(s.Alternative)
+		return []parser.Statement{s}
+	}
+
+	if cond.Value {
+		if s.Alternative != nil {
+			tok := statementTokenOf(s.Alternative, s.Token)
+			o.t.lint(ruleDeadBranch, tok.Line, tok.Column, i18n.T(i18n.WarnDeadElseBranch))
+			o.keepAlive(s.Alternative)
+		}
+		return o.inline(s.Consequence)
+	}
+
+	o.t.lint(ruleDeadBranch, s.Token.Line, s.Token.Column, i18n.T(i18n.WarnDeadIfBranch))
+	o.keepAlive(s.Consequence)
+	switch alt := s.Alternative.(type) {
+	case *parser.IfStmt:
+		return o.ifStmt(alt)
+	case *parser.BlockStmt:
+		return o.inline(alt)
+	}
+	return nil
+}
+
+// alternative 优化 else 分支，else if 被替换成多条语句时包装为代码块
+func (o *optimizer) alternative(alt parser.Statement) parser.Statement {
+	switch a := alt.(type) {
+	case *parser.BlockStmt:
+		a.Statements = o.block(a.Statements)
+		return a
+	case *parser.IfStmt:
+		savedLevel, savedPending := o.stmtLevel, o.pending
+		o.stmtLevel, o.pending = len(o.scopes), nil
+		result := o.ifStmt(a)
+		result = append(o.keepAliveStmts(), result...)
+		o.stmtLevel, o.pending = savedLevel, savedPending
+		switch {
+		case len(result) == 0:
+			return nil
+		case len(result) == 1:
+			if _, ok := result[0].(*parser.IfStmt); ok {
+				return result[0]
+			}
+		}
+		return &parser.BlockStmt{Token: a.Token, Statements: result}
+	}
+	return alt
+}
+
+// inline 展开保留下来的分支；分支中有声明时保留代码块以维持作用域
+func (o *optimizer) inline(block *parser.BlockStmt) []parser.Statement {
+	stmts := o.block(block.Statements)
+	for _, stmt := range stmts {
+		switch stmt.(type) {
+		case *parser.ShortVarDecl, *parser.VarDecl, *parser.ConstDecl:
+			return []parser.Statement{&parser.BlockStmt{Token: block.Token, Statements: stmts}}
+		}
+	}
+	return stmts
+}
+
+// exprs 就地优化表达式列表
+func (o *optimizer) exprs(exprs []parser.Expression) {
+	for i, e := range exprs {
+		exprs[i] = o.expr(e)
+	}
+}
+
+// call 优化函数调用的参数
+func (o *optimizer) call(call *parser.CallExpr) {
+	if call != nil {
+		call.Function = o.expr(call.Function)
+		o.exprs(call.Arguments)
+	}
+}
+
+// expr 优化表达式，返回折叠后的表达式
+func (o *optimizer) expr(expr parser.Expression) parser.Expression {
+	switch e := expr.(type) {
+	case *parser.ParenExpr:
+		e.X = o.expr(e.X)
+		if isConstant(e.X) {
+			return e.X
+		}
+	case *parser.UnaryExpr:
+		e.Operand = o.expr(e.Operand)
+		return foldUnary(e)
+	case *parser.BinaryExpr:
+		e.Left = o.expr(e.Left)
+		e.Right = o.expr(e.Right)
+		return o.foldBinary(e)
+	case *parser.TernaryExpr:
+		e.Condition = o.expr(e.Condition)
+		if cond, ok := e.Condition.(*parser.BoolLiteral); ok {
+			if cond.Value {
+				o.keepAlive(e.FalseExpr)
+				return o.expr(e.TrueExpr)
+			}
+			o.keepAlive(e.TrueExpr)
+			return o.expr(e.FalseExpr)
+		}
+		e.TrueExpr = o.expr(e.TrueExpr)
+		e.FalseExpr = o.expr(e.FalseExpr)
+	case *parser.MatchExpr:
+		return o.match(e)
+	case *parser.CallExpr:
+		o.call(e)
+	case *parser.IndexExpr:
+		e.X = o.expr(e.X)
+		e.Index = o.expr(e.Index)
+	case *parser.SliceExpr:
+		e.X = o.expr(e.X)
+		e.Low = o.expr(e.Low)
+		e.High = o.expr(e.High)
+		e.Max = o.expr(e.Max)
+	case *parser.SelectorExpr:
+		e.X = o.expr(e.X)
+	case *parser.TypeAssertExpr:
+		e.X = o.expr(e.X)
+	case *parser.ReceiveExpr:
+		e.X = o.expr(e.X)
+	case *parser.SliceLiteral:
+		o.exprs(e.Elements)
+	case *parser.ArrayLiteral:
+		o.exprs(e.Elements)
+	case *parser.MapLiteral:
+		for _, pair := range e.Pairs {
+			pair.Key = o.expr(pair.Key)
+			pair.Value = o.expr(pair.Value)
+		}
+	case *parser.StructLiteral:
+		for _, field := range e.Fields {
+			field.Value = o.expr(field.Value)
+		}
+	case *parser.NewExpr:
+		o.exprs(e.Arguments)
+	case *parser.NamedArg:
+		e.Value = o.expr(e.Value)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			part.Expr = o.expr(part.Expr)
+		}
+	case *parser.MakeExpr:
+		o.exprs(e.Args)
+	case *parser.LenExpr:
+		e.X = o.expr(e.X)
+	case *parser.CapExpr:
+		e.X = o.expr(e.X)
+	case *parser.AppendExpr:
+		e.Slice = o.expr(e.Slice)
+		o.exprs(e.Elems)
+	case *parser.FuncLiteral:
+		if e.Body != nil {
+			e.Body.Statements = o.block(e.Body.Statements)
+		}
+	}
+	return expr
+}
+
+// match 优化 match 表达式，主体为字面量时删除不可能匹配的分支
+func (o *optimizer) match(e *parser.MatchExpr) parser.Expression {
+	e.Subject = o.expr(e.Subject)
+	for _, arm := range e.Arms {
+		o.exprs(arm.Patterns)
+		arm.Guard = o.expr(arm.Guard)
+	}
+	// 块分支不能替换整个 match，不做常量折叠
+	if e.IsType || !isConstant(e.Subject) || hasBlockArm(e) {
+		o.armBodies(e)
+		return e
+	}
+
+	subject := constantText(e.Subject)
+	var kept []*parser.MatchArm
+	var selected *parser.MatchArm
+	for _, arm := range e.Arms {
+		if selected != nil {
+			o.t.lint(ruleDeadBranch, arm.Token.Line, arm.Token.Column, i18n.T(i18n.WarnDeadMatchArm, subject))
+			o.keepAlive(arm.Body)
+			continue
+		}
+		if arm.IsDefault {
+			kept = append(kept, arm)
+			continue
+		}
+		matches, known := o.armMatches(e.Subject, arm)
+		switch {
+		case !known:
+			kept = append(kept, arm)
+		case matches:
+			kept = append(kept, arm)
+			// 前面的分支都是不匹配的常量时，结果就是这个分支
+			if len(kept) == 1 {
+				selected = arm
+			}
+		default:
+			o.t.lint(ruleDeadBranch, arm.Token.Line, arm.Token.Column, i18n.T(i18n.WarnDeadMatchArm, subject))
+			o.keepAlive(arm.Body)
+		}
+	}
+
+	// 没有可匹配的常量分支时只剩 default
+	if selected == nil && len(kept) == 1 && kept[0].IsDefault {
+		selected = kept[0]
+	}
+	if selected != nil {
+		return o.expr(selected.Body)
+	}
+
+	e.Arms = kept
+	o.armBodies(e)
+	return e
+}
+
+// armBodies 优化 match 各分支的结果表达式和块
+func (o *optimizer) armBodies(e *parser.MatchExpr) {
+	for _, arm := range e.Arms {
+		if arm.Block != nil {
+			arm.Block.Statements = o.block(arm.Block.Statements)
+			continue
+		}
+		arm.Body = o.expr(arm.Body)
+	}
+}
+
+// hasBlockArm 检查 match 是否有块分支
+func hasBlockArm(e *parser.MatchExpr) bool {
+	for _, arm := range e.Arms {
+		if arm.Block != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// armMatches 判断常量主体是否匹配分支，known 为 false 表示分支中有非常量模式或守卫条件
+func (o *optimizer) armMatches(subject parser.Expression, arm *parser.MatchArm) (matches bool, known bool) {
+	if arm.Guard != nil {
+		return false, false
+	}
+	for _, pattern := range arm.Patterns {
+		if !isConstant(pattern) {
+			return false, false
+		}
+		eq, ok := compareConstants(subject, pattern, "==")
+		if !ok {
+			return false, false
+		}
+		if eq {
+			matches = true
+		}
+	}
+	return matches, true
+}
+
+// foldUnary 折叠一元运算
+func foldUnary(e *parser.UnaryExpr) parser.Expression {
+	switch operand := e.Operand.(type) {
+	case *parser.IntegerLiteral:
+		if e.Operator == "-" {
+			if v, ok := parseInt(operand.Value); ok {
+				return intLiteral(e.Token, new(big.Int).Neg(v))
+			}
+		}
+	case *parser.BoolLiteral:
+		if e.Operator == "!" {
+			return boolLiteral(e.Token, !operand.Value)
+		}
+	}
+	return e
+}
+
+// foldBinary 折叠二元运算
+func (o *optimizer) foldBinary(e *parser.BinaryExpr) parser.Expression {
+	// 短路运算：false && x -> false，true || x -> true，true && x -> x，false || x -> x
+	if left, ok := e.Left.(*parser.BoolLiteral); ok && (e.Operator == "&&" || e.Operator == "||") {
+		if left.Value == (e.Operator == "||") {
+			o.keepAlive(e.Right)
+			return left
+		}
+		return e.Right
+	}
+
+	switch left := e.Left.(type) {
+	case *parser.IntegerLiteral:
+		right, ok := e.Right.(*parser.IntegerLiteral)
+		if !ok {
+			break
+		}
+		x, ok1 := parseInt(left.Value)
+		y, ok2 := parseInt(right.Value)
+		if !ok1 || !ok2 {
+			break
+		}
+		if result, ok := compareInts(x, y, e.Operator); ok {
+			return boolLiteral(e.Token, result)
+		}
+		if result, ok := foldInts(x, y, e.Operator); ok {
+			return intLiteral(e.Token, result)
+		}
+	case *parser.StringLiteral:
+		right, ok := e.Right.(*parser.StringLiteral)
+		if !ok {
+			break
+		}
+		x, err1 := strconv.Unquote(left.Value)
+		y, err2 := strconv.Unquote(right.Value)
+		if err1 != nil || err2 != nil {
+			break
+		}
+		if e.Operator == "+" {
+			return &parser.StringLiteral{Token: literalToken(e.Token, lexer.TOKEN_STRING), Value: strconv.Quote(x + y)}
+		}
+		if result, ok := compareStrings(x, y, e.Operator); ok {
+			return boolLiteral(e.Token, result)
+		}
+	case *parser.BoolLiteral:
+		right, ok := e.Right.(*parser.BoolLiteral)
+		if !ok {
+			break
+		}
+		switch e.Operator {
+		case "==":
+			return boolLiteral(e.Token, left.Value == right.Value)
+		case "!=":
+			return boolLiteral(e.Token, left.Value != right.Value)
+		}
+	}
+	return e
+}
+
+// foldInts 计算整数运算，结果超出 int64 或除数为 0 时不折叠
+func foldInts(x, y *big.Int, op string) (*big.Int, bool) {
+	r := new(big.Int)
+	switch op {
+	case "+":
+		r.Add(x, y)
+	case "-":
+		r.Sub(x, y)
+	case "*":
+		r.Mul(x, y)
+	case "/":
+		if y.Sign() == 0 {
+			return nil, false
+		}
+		r.Quo(x, y)
+	case "%":
+		if y.Sign() == 0 {
+			return nil, false
+		}
+		r.Rem(x, y)
+	case "&":
+		r.And(x, y)
+	case "|":
+		r.Or(x, y)
+	case "^":
+		r.Xor(x, y)
+	case "&^":
+		r.AndNot(x, y)
+	case "<<", ">>":
+		if y.Sign() < 0 || !y.IsInt64() || y.Int64() > 63 {
+			return nil, false
+		}
+		if op == "<<" {
+			r.Lsh(x, uint(y.Int64()))
+		} else {
+			r.Rsh(x, uint(y.Int64()))
+		}
+	default:
+		return nil, false
+	}
+	if !r.IsInt64() {
+		return nil, false
+	}
+	return r, true
+}
+
+// compareInts 计算整数比较
+func compareInts(x, y *big.Int, op string) (bool, bool) {
+	return compareResult(x.Cmp(y), op)
+}
+
+// compareStrings 计算字符串比较
+func compareStrings(x, y string, op string) (bool, bool) {
+	switch {
+	case x < y:
+		return compareResult(-1, op)
+	case x > y:
+		return compareResult(1, op)
+	}
+	return compareResult(0, op)
+}
+
+// compareResult 将比较结果（-1、0、1）转换为比较运算的值
+func compareResult(cmp int, op string) (bool, bool) {
+	switch op {
+	case "==":
+		return cmp == 0, true
+	case "!=":
+		return cmp != 0, true
+	case "<":
+		return cmp < 0, true
+	case "<=":
+		return cmp <= 0, true
+	case ">":
+		return cmp > 0, true
+	case ">=":
+		return cmp >= 0, true
+	}
+	return false, false
+}
+
+// compareConstants 比较两个同类型的常量
+func compareConstants(x, y parser.Expression, op string) (bool, bool) {
+	switch a := x.(type) {
+	case *parser.IntegerLiteral:
+		if b, ok := y.(*parser.IntegerLiteral); ok {
+			v1, ok1 := parseInt(a.Value)
+			v2, ok2 := parseInt(b.Value)
+			if ok1 && ok2 {
+				return compareInts(v1, v2, op)
+			}
+		}
+	case *parser.StringLiteral:
+		if b, ok := y.(*parser.StringLiteral); ok {
+			v1, err1 := strconv.Unquote(a.Value)
+			v2, err2 := strconv.Unquote(b.Value)
+			if err1 == nil && err2 == nil {
+				return compareStrings(v1, v2, op)
+			}
+		}
+	case *parser.BoolLiteral:
+		if b, ok := y.(*parser.BoolLiteral); ok && (op == "==" || op == "!=") {
+			return (a.Value == b.Value) == (op == "=="), true
+		}
+	}
+	return false, false
+}
+
+// isConstant 检查表达式是否是可折叠的常量
+func isConstant(expr parser.Expression) bool {
+	switch expr.(type) {
+	case *parser.IntegerLiteral, *parser.StringLiteral, *parser.BoolLiteral:
+		return true
+	}
+	return false
+}
+
+// isBranchExpr 检查表达式是否是 match 或三元表达式
+func isBranchExpr(expr parser.Expression) bool {
+	switch expr.(type) {
+	case *parser.MatchExpr, *parser.TernaryExpr:
+		return true
+	}
+	return false
+}
+
+// constantText 返回常量的源码文本（用于警告信息）
+func constantText(expr parser.Expression) string {
+	switch e := expr.(type) {
+	case *parser.IntegerLiteral:
+		return e.Value
+	case *parser.StringLiteral:
+		return e.Value
+	case *parser.BoolLiteral:
+		return strconv.FormatBool(e.Value)
+	}
+	return ""
+}
+
+// parseInt 解析整数字面量（支持 0x、0b、0o 前缀）
+func parseInt(s string) (*big.Int, bool) {
+	return new(big.Int).SetString(s, 0)
+}
+
+// intLiteral 创建整数字面量
+func intLiteral(tok lexer.Token, v *big.Int) *parser.IntegerLiteral {
+	return &parser.IntegerLiteral{Token: literalToken(tok, lexer.TOKEN_INT), Value: v.String()}
+}
+
+// boolLiteral 创建布尔字面量
+func boolLiteral(tok lexer.Token, v bool) *parser.BoolLiteral {
+	typ := lexer.TOKEN_FALSE
+	if v {
+		typ = lexer.TOKEN_TRUE
+	}
+	return &parser.BoolLiteral{Token: literalToken(tok, typ), Value: v}
+}
+
+// literalToken 基于原表达式的位置创建字面量 token
+func literalToken(tok lexer.Token, typ lexer.TokenType) lexer.Token {
+	return lexer.Token{Type: typ, Line: tok.Line, Column: tok.Column}
+}
+
+// statementTokenOf 返回语句的 token，无法获取时使用 fallback
+func statementTokenOf(stmt parser.Statement, fallback lexer.Token) lexer.Token {
+	if tok, ok := statementToken(stmt); ok {
+		return tok
+	}
+	return fallback
+}
+
+// declare 记录语句声明的局部变量
+func (o *optimizer) declare(stmt parser.Statement) {
+	scope := o.scopes[len(o.scopes)-1]
+	switch s := stmt.(type) {
+	case *parser.ShortVarDecl:
+		for _, name := range s.Names {
+			scope[name] = true
+		}
+	case *parser.VarDecl:
+		for _, name := range s.Names {
+			scope[name] = true
+		}
+	case *parser.RangeStmt:
+		for _, e := range []parser.Expression{s.Key, s.Value} {
+			if ident, ok := e.(*parser.Identifier); ok && ident.Value != "_" {
+				scope[ident.Value] = true
+			}
+		}
+	}
+}
+
+// keepAlive 记录被删除代码中引用的局部变量，在当前语句之前生成 _ = x
+// 只处理当前语句之前已经声明的变量；被删除代码自己声明的变量不需要处理
+func (o *optimizer) keepAlive(node interface{}) {
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		return
+	}
+	declared := collectDeclaredNames(node)
+	for name := range collectIdentifiers(node) {
+		if declared[name] {
+			continue
+		}
+		for _, scope := range o.scopes[:o.stmtLevel] {
+			if scope[name] {
+				o.pending = append(o.pending, name)
+				break
+			}
+		}
+	}
+}
+
+// keepAliveStmts 生成并清空待处理的 _ = x 语句
+func (o *optimizer) keepAliveStmts() []parser.Statement {
+	if len(o.pending) == 0 {
+		return nil
+	}
+	names := make(map[string]bool)
+	for _, name := range o.pending {
+		names[name] = true
+	}
+	o.pending = nil
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var stmts []parser.Statement
+	for _, name := range sorted {
+		stmts = append(stmts, &parser.AssignStmt{
+			Token: lexer.Token{Type: lexer.TOKEN_ASSIGN, Literal: "="},
+			Left:  []parser.Expression{&parser.Identifier{Value: "_"}},
+			Right: []parser.Expression{&parser.Identifier{Value: name}},
+		})
+	}
+	return stmts
+}
+
+// collectDeclaredNames 收集 AST 中声明的所有变量名
+func collectDeclaredNames(node interface{}) map[string]bool {
+	names := make(map[string]bool)
+	collectDeclaredValue(reflect.ValueOf(node), names)
+	return names
+}
+
+// collectDeclaredValue 递归收集声明的变量名
+func collectDeclaredValue(v reflect.Value, names map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		switch n := v.Interface().(type) {
+		case *parser.ShortVarDecl:
+			for _, name := range n.Names {
+				names[name] = true
+			}
+		case *parser.VarDecl:
+			for _, name := range n.Names {
+				names[name] = true
+			}
+		case *parser.RangeStmt:
+			for _, e := range []parser.Expression{n.Key, n.Value} {
+				if ident, ok := e.(*parser.Identifier); ok {
+					names[ident.Value] = true
+				}
+			}
+		case *parser.Field:
+			names[n.Name] = true
+		}
+		collectDeclaredValue(v.Elem(), names)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			collectDeclaredValue(v.Index(i), names)
+		}
+	case reflect.Struct:
+		typ := v.Type()
+		if typ.PkgPath() == "github.com/tangzhangming/tugo/internal/lexer" {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if typ.Field(i).IsExported() {
+				collectDeclaredValue(v.Field(i), names)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package transpiler
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tangzhangming/tugo/internal/config"
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+var update = flag.Bool("update", false, "重新生成 testdata 中的 golden 文件")
+
+// TestOptimizeGolden 对比 --opt 前后生成的 Go 代码
+// testdata/optimize 下每个 .tugo 文件对应一种改写，.golden 文件记录优化产生的警告和代码差异；
+// 非 -short 模式下还会分别运行两份代码，确认优化没有改变程序输出
+func TestOptimizeGolden(t *testing.T) {
+	i18n.Init()
+	i18n.SetLanguage(i18n.LangEnglish)
+
+	files, err := filepath.Glob(filepath.Join("testdata", "optimize", "*.tugo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test cases in testdata/optimize")
+	}
+
+	for _, path := range files {
+		name := strings.TrimSuffix(filepath.Base(path), ".tugo")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain, _ := transpileForTest(t, string(source), false)
+			optimized, warnings := transpileForTest(t, string(source), true)
+
+			got := formatOptimizeGolden(warnings, lineDiff(plain, optimized))
+			golden := strings.TrimSuffix(path, ".tugo") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("missing golden file (run go test -update): %v", err)
+				}
+				if got != string(want) {
+					t.Errorf("optimized output differs from %s\n--- got ---\n%s\n--- want ---\n%s", golden, got, want)
+				}
+			}
+
+			if testing.Short() {
+				return
+			}
+			if want, got := runGoForTest(t, plain), runGoForTest(t, optimized); got != want {
+				t.Errorf("optimization changed program output\n--- unoptimized ---\n%s\n--- optimized ---\n%s", want, got)
+			}
+		})
+	}
+}
+
+// transpileForTest 转译单个入口文件，返回生成的 Go 代码和警告
+func transpileForTest(t *testing.T, source string, optimize bool) (string, []string) {
+	t.Helper()
+	file, errs := parser.Parse(source)
+	if len(errs) > 0 {
+		t.Fatalf("parse error: %s", errs[0])
+	}
+	cfg := config.DefaultConfig()
+	cfg.Build.Optimize = optimize
+
+	tr := New(symbol.Collect([]*parser.File{file}))
+	tr.SetConfig(cfg)
+	code, err := tr.TranspileFileWithName(file, "app")
+	if err != nil {
+		t.Fatalf("transpile error (optimize=%v): %v", optimize, err)
+	}
+	return code, tr.Warnings()
+}
+
+// formatOptimizeGolden 生成 golden 文件内容
+func formatOptimizeGolden(warnings, diff []string) string {
+	var sb strings.Builder
+	sb.WriteString("-- warnings --\n")
+	for _, w := range warnings {
+		sb.WriteString(w + "\n")
+	}
+	sb.WriteString("-- diff --\n")
+	for _, line := range diff {
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// lineDiff 按行比较两段文本，返回删除（-）和新增（+）的行，不包含上下文
+func lineDiff(a, b string) []string {
+	x := strings.Split(a, "\n")
+	y := strings.Split(b, "\n")
+
+	// lcs[i][j] 为 x[i:] 与 y[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "-"+x[i])
+			i++
+		default:
+			diff = append(diff, "+"+y[j])
+			j++
+		}
+	}
+	return diff
+}
+
+// runGoForTest 在临时模块中运行生成的 Go 代码，返回程序输出
+func runGoForTest(t *testing.T, code string) string {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+
+	dir := t.TempDir()
+	runtimeDir := filepath.Join(dir, "tugo", "runtime")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := filepath.Glob(filepath.Join("..", "..", "src", "runtime", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range sources {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(runtimeDir, filepath.Base(src)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(runtimeDir, "go.mod"): "module tugo/runtime\n\ngo 1.21\n",
+		filepath.Join(dir, "go.mod"):        "module App\n\ngo 1.21\n\nreplace tugo/runtime => ./tugo/runtime\n\nrequire tugo/runtime v0.0.0-00010101000000-000000000000\n",
+		filepath.Join(dir, "app.go"):        code,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run failed: %v\n%s\n--- code ---\n%s", err, out, code)
+	}
+	return string(out)
+}
@@ -0,0 +1,1327 @@
+package transpiler
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 重载解析
+//
+// obj.m(...)、Class::m(...) 和 new Class(...) 按实参的静态类型选择重载，规则参考 Java / C#：
+//   - 实参先按位置、再按名称 (name: value) 绑定到形参，未传的形参必须有默认值
+//   - 每个实参有一个转换代价：类型一致 < 无类型常量 < 数值拓宽 < 接口或抽象父类 < any < 类型参数
+//   - 候选 A 的每个实参代价都不高于 B 且至少一个更低时，A 比 B 更具体；
+//     代价完全相同时，使用默认值更少的候选更具体
+//   - 没有唯一最具体的候选时报告调用有歧义，没有候选可用时报告不匹配，两者都列出全部候选
+//   - 无法推断类型的实参（如 Go 包函数的返回值）与任意形参兼容，此时不报告歧义，按声明顺序选择
+//
+// Go 不做隐式数值转换，选中需要拓宽的重载时生成显式转换 int64(x)。
+
+// 转换代价
+const (
+	costExact   = 0  // 类型一致，或无类型常量的默认类型
+	costUntyped = 1  // 无类型常量转换为其他数值类型
+	costWiden   = 10 // 数值拓宽，按拓宽距离递增
+	costRef     = 30 // 实现的接口或抽象父类，按继承深度递增
+	costAny     = 60 // any / interface{}
+	costGeneric = 70 // 类型参数
+	costUnknown = 80 // 实参或形参的类型无法确定
+)
+
+// numericWidening 数值类型可以拓宽到的类型，按距离从近到远排列
+var numericWidening = map[string][]string{
+	"int8":    {"int16", "int32", "int", "int64", "float32", "float64"},
+	"int16":   {"int32", "int", "int64", "float32", "float64"},
+	"int32":   {"int", "int64", "float64"},
+	"int":     {"int64", "float64"},
+	"int64":   {"float64"},
+	"uint8":   {"uint16", "int16", "uint32", "int32", "uint", "int", "uint64", "int64", "float32", "float64"},
+	"uint16":  {"uint32", "int32", "uint", "int", "uint64", "int64", "float32", "float64"},
+	"uint32":  {"uint", "int", "uint64", "int64", "float64"},
+	"uint":    {"uint64", "float64"},
+	"uint64":  {"float64"},
+	"float32": {"float64"},
+}
+
+// basicTypes Go 预声明类型（规范形式，byte/rune 已统一为 uint8/int32）
+var basicTypes = map[string]bool{
+	"bool": true, "string": true, "error": true, "any": true, "uintptr": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// callBinding 实参到某个候选方法形参的绑定
+type callBinding struct {
+	method   *parser.ClassMethod
+	args     []parser.Expression // 按形参顺序排列的实参，nil 表示使用默认值
+	argIndex []int               // 每个形参对应的实参在调用中的位置，-1 表示使用默认值
+	variadic []parser.Expression // 传给可变参数的实参
+	widen    []bool              // 实参需要显式转换为形参类型
+	costs    []int               // 按调用中实参的顺序记录的转换代价
+	defaults int                 // 使用默认值的形参个数
+	unknown  bool                // 存在无法确定类型的实参或形参
+	receiver parser.Expression   // 实例方法调用的接收者，默认值 this.field 取它的字段（数据类的 copy）
+}
+
+// bindArgs 把实参按位置和名称绑定到形参，失败时返回错误描述
+func bindArgs(name string, method *parser.ClassMethod, args []parser.Expression) (*callBinding, string) {
+	params := method.Params
+	b := &callBinding{
+		method:   method,
+		args:     make([]parser.Expression, len(params)),
+		argIndex: make([]int, len(params)),
+		widen:    make([]bool, len(params)),
+		costs:    make([]int, len(args)),
+	}
+	for i := range b.argIndex {
+		b.argIndex[i] = -1
+	}
+
+	variadic := len(params) > 0 && isVariadicParam(params[len(params)-1])
+	fixed := len(params)
+	if variadic {
+		fixed--
+	}
+
+	named := false
+	pos := 0
+	for i, arg := range args {
+		if na, ok := arg.(*parser.NamedArg); ok {
+			named = true
+			slot := -1
+			for j, p := range params[:fixed] {
+				if p.Name == na.Name {
+					slot = j
+					break
+				}
+			}
+			if slot < 0 {
+				return nil, i18n.T(i18n.ErrUnknownNamedArg, name, na.Name)
+			}
+			if b.argIndex[slot] >= 0 {
+				return nil, i18n.T(i18n.ErrDuplicateNamedArg, na.Name, name)
+			}
+			b.args[slot] = na.Value
+			b.argIndex[slot] = i
+			continue
+		}
+		if named {
+			return nil, i18n.T(i18n.ErrPositionalAfterNamed, name)
+		}
+		if pos < fixed {
+			b.args[pos] = arg
+			b.argIndex[pos] = i
+			pos++
+			continue
+		}
+		if !variadic {
+			return nil, i18n.T(i18n.ErrTooManyArguments, name, len(params), len(args))
+		}
+		b.variadic = append(b.variadic, arg)
+	}
+
+	for i, p := range params[:fixed] {
+		if b.argIndex[i] >= 0 {
+			continue
+		}
+		if p.DefaultValue == nil {
+			return nil, i18n.T(i18n.ErrMissingArgument, p.Name, name)
+		}
+		b.defaults++
+	}
+	return b, ""
+}
+
+// isVariadicParam 检查形参是否是可变参数 ...T
+func isVariadicParam(p *parser.Field) bool {
+	_, ok := p.Type.(*parser.Ellipsis)
+	return ok
+}
+
+// hasNamedArgs 检查调用中是否有命名参数
+func hasNamedArgs(args []parser.Expression) bool {
+	for _, arg := range args {
+		if _, ok := arg.(*parser.NamedArg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDefaultParams 检查方法是否有带默认值的参数
+func hasDefaultParams(method *parser.ClassMethod) bool {
+	for _, p := range method.Params {
+		if p.DefaultValue != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// score 按实参类型计算绑定中每个实参的转换代价，有实参无法转换时返回 false
+func (g *CodeGen) score(b *callBinding, typeParams map[string]bool) bool {
+	for i, arg := range b.args {
+		if arg == nil {
+			continue
+		}
+		cost, widen, ok := g.conversionCost(g.exprType(arg), canonicalType(b.method.Params[i].Type), typeParams)
+		if !ok {
+			return false
+		}
+		b.costs[b.argIndex[i]] = cost
+		b.widen[i] = widen
+		b.unknown = b.unknown || cost == costUnknown
+	}
+	if len(b.variadic) == 0 {
+		return true
+	}
+
+	last := b.method.Params[len(b.method.Params)-1].Type.(*parser.Ellipsis)
+	elem := canonicalType(last.Elt)
+	first := len(b.costs) - len(b.variadic)
+	for i, arg := range b.variadic {
+		var cost int
+		var widen, ok bool
+		if spread, isSpread := arg.(*parser.Ellipsis); isSpread {
+			// xs... 必须是最后一个实参，且整体作为切片传入
+			if i != len(b.variadic)-1 {
+				return false
+			}
+			cost, widen, ok = g.conversionCost(g.exprType(spread.Elt), "[]"+elem, typeParams)
+		} else {
+			cost, widen, ok = g.conversionCost(g.exprType(arg), elem, typeParams)
+		}
+		// Go 不能对可变参数逐个插入转换，需要拓宽时视为不匹配
+		if !ok || widen {
+			return false
+		}
+		b.costs[first+i] = cost
+		b.unknown = b.unknown || cost == costUnknown
+	}
+	return true
+}
+
+// better 检查候选 a 是否比 b 更具体
+func (a *callBinding) better(b *callBinding) bool {
+	strict := false
+	for i := range a.costs {
+		if a.costs[i] > b.costs[i] {
+			return false
+		}
+		if a.costs[i] < b.costs[i] {
+			strict = true
+		}
+	}
+	if strict {
+		return true
+	}
+	if a.defaults != b.defaults {
+		return a.defaults < b.defaults
+	}
+	// 不展开可变参数的候选更具体
+	return len(a.variadic) == 0 && len(b.variadic) > 0
+}
+
+// resolveCall 从候选方法中选出最匹配的重载，无法选出时报告错误并返回 nil
+// name 是用于错误信息的方法名（如 Point.move、new Point）
+func (g *CodeGen) resolveCall(tok parser.Node, name string, methods []*parser.ClassMethod, args []parser.Expression, typeParams map[string]bool) *callBinding {
+	best, applicable, reason := g.selectOverload(name, methods, args, typeParams)
+	if best != nil {
+		g.checkNullArgs(best)
+		return best
+	}
+
+	line, col := nodePos(tok)
+	switch {
+	case len(methods) == 1 && reason != "":
+		g.transpiler.AddError(line, col, reason)
+	case len(applicable) > 1:
+		g.transpiler.AddError(line, col, i18n.T(i18n.ErrAmbiguousCall, name, g.argTypesText(args), candidatesText(applicable)))
+	default:
+		g.transpiler.AddError(line, col, i18n.T(i18n.ErrNoMatchingOverload, name, g.argTypesText(args), candidatesText(methods)))
+	}
+	return nil
+}
+
+// selectOverload 选出最具体的候选；失败时返回所有同样具体的候选（歧义）或单个候选的绑定错误
+func (g *CodeGen) selectOverload(name string, methods []*parser.ClassMethod, args []parser.Expression, typeParams map[string]bool) (*callBinding, []*parser.ClassMethod, string) {
+	var candidates []*callBinding
+	reason := ""
+	for _, method := range methods {
+		b, msg := bindArgs(name, method, args)
+		if b == nil {
+			reason = msg
+			continue
+		}
+		if g.score(b, withTypeParams(typeParams, method.TypeParams)) {
+			candidates = append(candidates, b)
+		} else if len(methods) == 1 {
+			// 没有重载时类型不匹配交给 Go 编译器报告
+			b.widen = make([]bool, len(b.widen))
+			return b, nil, ""
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil, reason
+	}
+
+	// 保留没有被其他候选严格胜过的候选
+	var maximal []*callBinding
+	for _, c := range candidates {
+		dominated := false
+		for _, other := range candidates {
+			if other != c && other.better(c) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			maximal = append(maximal, c)
+		}
+	}
+	if len(maximal) == 1 {
+		return maximal[0], nil, ""
+	}
+
+	// 类型不完全确定时无法判断歧义，按声明顺序选择
+	for _, c := range maximal {
+		if c.unknown {
+			return maximal[0], nil, ""
+		}
+	}
+	var tied []*parser.ClassMethod
+	for _, c := range maximal {
+		tied = append(tied, c.method)
+	}
+	return nil, tied, ""
+}
+
+// withTypeParams 合并类和方法自身的类型参数名
+func withTypeParams(classParams map[string]bool, methodParams *parser.TypeParamList) map[string]bool {
+	if methodParams == nil || len(methodParams.Params) == 0 {
+		return classParams
+	}
+	merged := make(map[string]bool, len(classParams)+len(methodParams.Params))
+	for name := range classParams {
+		merged[name] = true
+	}
+	for _, p := range methodParams.Params {
+		merged[p.Name] = true
+	}
+	return merged
+}
+
+// typeParamNames 返回类型参数名集合
+func typeParamNames(list *parser.TypeParamList) map[string]bool {
+	names := make(map[string]bool)
+	if list != nil {
+		for _, p := range list.Params {
+			names[p.Name] = true
+		}
+	}
+	return names
+}
+
+// conversionCost 计算实参类型转换到形参类型的代价，widen 表示需要生成显式转换
+func (g *CodeGen) conversionCost(arg, param string, typeParams map[string]bool) (cost int, widen bool, ok bool) {
+	switch {
+	case mentionsTypeParam(param, typeParams):
+		return costGeneric, false, true
+	case arg == "":
+		return costUnknown, false, true
+	case arg == param:
+		return costExact, false, true
+	case param == "any":
+		return costAny, false, true
+	case strings.HasPrefix(arg, "untyped "):
+		return g.untypedCost(arg, param)
+	}
+
+	for i, target := range numericWidening[arg] {
+		if target == param {
+			return costWiden + i, true, true
+		}
+	}
+	if depth, found := g.implementsDepth(arg, param); found {
+		return costRef + depth, false, true
+	}
+	if !g.isKnownType(arg) || !g.isKnownType(param) || g.isForeignInterface(param) {
+		return costUnknown, false, true
+	}
+	return 0, false, false
+}
+
+// untypedCost 计算无类型常量转换到形参类型的代价
+func (g *CodeGen) untypedCost(arg, param string) (int, bool, bool) {
+	kind := strings.TrimPrefix(arg, "untyped ")
+	if defaultType(arg) == param {
+		return costExact, false, true
+	}
+	switch kind {
+	case "int", "rune":
+		if isIntegerType(param) {
+			return costUntyped, false, true
+		}
+		if isFloatType(param) {
+			return costUntyped + 1, false, true
+		}
+	case "float":
+		if isFloatType(param) {
+			return costUntyped, false, true
+		}
+	case "nil":
+		if isNilable(param) || g.isInterfaceType(param) {
+			return costUntyped, false, true
+		}
+	}
+	if !g.isKnownType(param) {
+		// 可能是以基础类型定义的命名类型（如 time.Duration）
+		return costUnknown, false, true
+	}
+	return 0, false, false
+}
+
+// defaultType 返回无类型常量的默认类型
+func defaultType(t string) string {
+	switch t {
+	case "untyped int":
+		return "int"
+	case "untyped rune":
+		return "int32"
+	case "untyped float":
+		return "float64"
+	case "untyped string":
+		return "string"
+	case "untyped bool":
+		return "bool"
+	case "untyped nil":
+		return ""
+	}
+	return t
+}
+
+func isIntegerType(t string) bool {
+	switch t {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+		return true
+	}
+	return false
+}
+
+func isFloatType(t string) bool {
+	return t == "float32" || t == "float64" || t == "complex64" || t == "complex128"
+}
+
+func isNilable(t string) bool {
+	for _, prefix := range []string{"*", "[]", "map[", "chan ", "chan<- ", "<-chan ", "func("} {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return t == "error" || t == "any" || strings.HasPrefix(t, "interface{")
+}
+
+// mentionsTypeParam 检查类型文本中是否出现类型参数
+func mentionsTypeParam(t string, typeParams map[string]bool) bool {
+	if len(typeParams) == 0 {
+		return false
+	}
+	for _, name := range typeIdentifiers(t) {
+		if typeParams[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// typeIdentifiers 提取类型文本中的标识符（不含 map/chan/func 等关键字）
+func typeIdentifiers(t string) []string {
+	words := strings.FieldsFunc(t, func(r rune) bool {
+		return !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127)
+	})
+	var idents []string
+	for _, w := range words {
+		switch {
+		case w[0] >= '0' && w[0] <= '9':
+		case w == "map" || w == "chan" || w == "func" || w == "struct" || w == "interface" || w == "untyped":
+		default:
+			idents = append(idents, w)
+		}
+	}
+	return idents
+}
+
+// isKnownType 检查类型文本中的每个名字都是基础类型或已知的 tugo 类型
+func (g *CodeGen) isKnownType(t string) bool {
+	for _, name := range typeIdentifiers(t) {
+		if basicTypes[name] {
+			continue
+		}
+		if g.lookupClass(name) == nil && g.lookupInterface(name) == nil && g.lookupStruct(name) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isInterfaceType 检查类型是否是接口（tugo 接口、抽象类、error 或 any）
+func (g *CodeGen) isInterfaceType(t string) bool {
+	if t == "error" || t == "any" || strings.HasPrefix(t, "interface{") {
+		return true
+	}
+	if g.lookupInterface(t) != nil {
+		return true
+	}
+	classInfo := g.lookupClass(t)
+	return classInfo != nil && classInfo.Abstract
+}
+
+// isForeignInterface 检查形参是否是实参可能按方法集满足的接口
+// tugo 接口不要求显式 implements 即可被 Go 的结构化类型满足，因此不能确定不匹配
+func (g *CodeGen) isForeignInterface(param string) bool {
+	return g.lookupInterface(param) != nil
+}
+
+// implementsDepth 检查类实例 *C 能否赋值给接口或抽象父类 param，返回所在的继承深度
+func (g *CodeGen) implementsDepth(arg, param string) (int, bool) {
+	if !strings.HasPrefix(arg, "*") || strings.HasPrefix(param, "*") {
+		return 0, false
+	}
+	target := stripTypeArgs(param)
+	classInfo := g.lookupClass(stripTypeArgs(arg[1:]))
+	for depth := 0; classInfo != nil && depth < maxInheritanceDepth; depth++ {
+		if depth > 0 && classInfo.Name == target && classInfo.Abstract {
+			return depth, true
+		}
+		for _, iface := range classInfo.Implements {
+			if typeBaseName(iface) == target {
+				return depth + 1, true
+			}
+		}
+		classInfo = g.transpiler.parentClassInfo(classInfo)
+	}
+
+	if structDecl := g.lookupStruct(stripTypeArgs(arg[1:])); structDecl != nil {
+		for _, iface := range structDecl.Implements {
+			if typeBaseName(iface) == target {
+				return 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// stripTypeArgs 去掉类型实参 Box[int] -> Box
+func stripTypeArgs(t string) string {
+	if i := strings.Index(t, "["); i > 0 {
+		return t[:i]
+	}
+	return t
+}
+
+// typeBaseName 去掉包限定 models.Shape -> Shape
+func typeBaseName(name string) string {
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		return name[dot+1:]
+	}
+	return name
+}
+
+// lookupClass 按名称查找当前包或导入的类
+func (g *CodeGen) lookupClass(name string) *symbol.ClassInfo {
+	if classInfo := g.transpiler.table.GetClass(g.transpiler.pkg, name); classInfo != nil {
+		return classInfo
+	}
+	if pkg, ok := g.typeToPackage[name]; ok {
+		return g.transpiler.table.GetClass(pkg, name)
+	}
+	return nil
+}
+
+// classInits 返回类信息中的全部 init 方法
+func classInits(info *symbol.ClassInfo) []*parser.ClassMethod {
+	if len(info.InitMethods) > 0 {
+		return info.InitMethods
+	}
+	if info.InitMethod != nil {
+		return []*parser.ClassMethod{info.InitMethod}
+	}
+	return nil
+}
+
+// lookupInterface 按名称查找当前包或导入的 tugo 接口
+func (g *CodeGen) lookupInterface(name string) *symbol.InterfaceInfo {
+	if iface := g.transpiler.table.GetInterface(g.transpiler.pkg, name); iface != nil {
+		return iface
+	}
+	if pkg, ok := g.typeToPackage[name]; ok {
+		return g.transpiler.table.GetInterface(pkg, name)
+	}
+	return nil
+}
+
+// lookupStruct 按名称查找当前包或导入的结构体
+func (g *CodeGen) lookupStruct(name string) *parser.StructDecl {
+	if decl := g.transpiler.GetStructDecl(g.transpiler.pkg, name); decl != nil {
+		return decl
+	}
+	if pkg, ok := g.typeToPackage[name]; ok {
+		return g.transpiler.GetStructDecl(pkg, name)
+	}
+	return nil
+}
+
+// argTypesText 渲染实参类型列表，如 (int, string, ?)
+func (g *CodeGen) argTypesText(args []parser.Expression) string {
+	var types []string
+	for _, arg := range args {
+		prefix := ""
+		if na, ok := arg.(*parser.NamedArg); ok {
+			prefix = na.Name + ": "
+			arg = na.Value
+		}
+		t := g.exprType(arg)
+		switch {
+		case t == "untyped nil":
+			t = "nil"
+		case strings.HasPrefix(t, "untyped "):
+			t = defaultType(t)
+		case t == "":
+			t = "?"
+		}
+		types = append(types, prefix+t)
+	}
+	return "(" + strings.Join(types, ", ") + ")"
+}
+
+// candidatesText 渲染候选方法列表，每行一个签名
+func candidatesText(methods []*parser.ClassMethod) string {
+	var lines []string
+	for _, m := range methods {
+		lines = append(lines, "    "+classMethodSig(m).String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nodePos 返回节点在源码中的位置
+func nodePos(node parser.Node) (int, int) {
+	switch n := node.(type) {
+	case *parser.CallExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.NewExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.Identifier:
+		return n.Token.Line, n.Token.Column
+	case *parser.NilLiteral:
+		return n.Token.Line, n.Token.Column
+	case *parser.SelectorExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.StaticAccessExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.BinaryExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.IndexExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.TernaryExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.ParenExpr:
+		return n.Token.Line, n.Token.Column
+	case *parser.NamedArg:
+		return n.Token.Line, n.Token.Column
+	}
+	return 0, 0
+}
+
+// boundArgs 按形参顺序生成实参，未传的参数使用默认值，可变参数的实参追加在最后
+func (g *CodeGen) boundArgs(b *callBinding) []string {
+	var out []string
+	for i, param := range b.method.Params {
+		if isVariadicParam(param) {
+			for _, arg := range b.variadic {
+				out = append(out, g.generateArgumentExpr(arg))
+			}
+			continue
+		}
+		out = append(out, g.boundArg(b, i))
+	}
+	return out
+}
+
+// boundArg 生成第 i 个形参的实参
+func (g *CodeGen) boundArg(b *callBinding, i int) string {
+	param := b.method.Params[i]
+	if b.args[i] == nil {
+		if field := receiverField(param.DefaultValue); field != "" && b.receiver != nil {
+			return g.generateExpression(&parser.SelectorExpr{X: b.receiver, Sel: field})
+		}
+		return g.generateExpression(param.DefaultValue)
+	}
+	arg := g.generateArgumentExpr(b.args[i])
+	if b.widen[i] {
+		arg = g.generateType(param.Type) + "(" + arg + ")"
+	}
+	return arg
+}
+
+// optsLiteral 生成带默认参数方法的 Opts 结构体字面量，未传的参数使用默认值
+func (g *CodeGen) optsLiteral(optsName string, b *callBinding) string {
+	var fields []string
+	for i, param := range b.method.Params {
+		fields = append(fields, symbol.ToGoName(param.Name, true)+": "+g.boundArg(b, i))
+	}
+	return optsName + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// methodOwner 声明方法的类或结构体
+type methodOwner struct {
+	pkg        string
+	name       string
+	goName     string
+	isStruct   bool
+	typeParams map[string]bool
+}
+
+// isPublic 返回方法在 Go 中是否导出（类的 protected 方法同样导出）
+func (o *methodOwner) isPublic(method *parser.ClassMethod) bool {
+	if o.isStruct {
+		return method.Visibility == "public"
+	}
+	return method.Visibility == "public" || method.Visibility == "protected"
+}
+
+// prefix 返回跨包引用时的包前缀
+func (o *methodOwner) prefix(g *CodeGen) string {
+	if o.pkg == g.transpiler.pkg {
+		return ""
+	}
+	return o.pkg + "."
+}
+
+// methodCandidates 沿继承链查找最近声明了 name 的类，返回其中所有同名方法
+func (g *CodeGen) methodCandidates(pkg, typeName, name string, static bool) (*methodOwner, []*parser.ClassMethod) {
+	classInfo := g.transpiler.table.GetClass(pkg, typeName)
+	for depth := 0; classInfo != nil && depth < maxInheritanceDepth; depth++ {
+		var methods []*parser.ClassMethod
+		for _, m := range classInfo.Methods {
+			if m.Name == name && m.Static == static {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) > 0 {
+			return &methodOwner{
+				pkg:        classInfo.Package,
+				name:       classInfo.Name,
+				goName:     classInfo.GoName,
+				typeParams: typeParamNames(classInfo.TypeParams),
+			}, methods
+		}
+		classInfo = g.transpiler.parentClassInfo(classInfo)
+	}
+
+	if decl := g.transpiler.GetStructDecl(pkg, typeName); decl != nil && !static {
+		var methods []*parser.ClassMethod
+		for _, m := range decl.Methods {
+			if m.Name == name {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) > 0 {
+			return &methodOwner{
+				pkg:        pkg,
+				name:       decl.Name,
+				goName:     symbol.ToGoName(decl.Name, decl.Public),
+				isStruct:   true,
+				typeParams: typeParamNames(decl.TypeParams),
+			}, methods
+		}
+	}
+
+	if decl := g.transpiler.GetEnumDecl(pkg, typeName); decl != nil && !static {
+		var methods []*parser.ClassMethod
+		for _, m := range decl.Methods {
+			if m.Name == name {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) > 0 {
+			return &methodOwner{
+				pkg:      pkg,
+				name:     decl.Name,
+				goName:   symbol.ToGoName(decl.Name, decl.Public),
+				isStruct: true,
+			}, methods
+		}
+	}
+	return nil, nil
+}
+
+// generateMethodCall 生成需要重载解析或参数绑定的实例方法调用 obj.m(...)
+// 普通调用（无重载、无默认参数、无命名参数）返回 false，由调用方按原样生成
+func (g *CodeGen) generateMethodCall(expr *parser.CallExpr, sel *parser.SelectorExpr) (string, bool) {
+	receiverType := g.getReceiverType(sel.X)
+	if receiverType == "" {
+		// 字段、方法调用等接收者按推断的类型查找
+		receiverType = stripTypeArgs(strings.TrimPrefix(g.exprType(sel.X), "*"))
+	}
+	if receiverType == "" || strings.HasPrefix(receiverType, "untyped ") {
+		return "", false
+	}
+	owner, methods := g.methodCandidates(g.getClassPackage(receiverType), receiverType, sel.Sel, false)
+	if owner == nil {
+		return "", false
+	}
+	overloaded := len(methods) > 1
+	if !overloaded && !hasDefaultParams(methods[0]) && !hasNamedArgs(expr.Arguments) {
+		return "", false
+	}
+
+	b := g.resolveCall(expr, owner.name+"."+sel.Sel, methods, expr.Arguments, owner.typeParams)
+	if b == nil {
+		return "/* unresolved call */", true
+	}
+	if usesReceiverDefaults(b) {
+		if stablePath(sel.X) == "" {
+			if call, ok := g.bindReceiverCall(expr, sel); ok {
+				return call, true
+			}
+		}
+		b.receiver = sel.X
+	}
+
+	var funcExpr, methodName string
+	if overloaded {
+		methodName = symbol.GenerateMangledName(sel.Sel, b.method.Params, owner.isPublic(b.method))
+		funcExpr = g.generateExpression(sel.X) + "." + methodName
+	} else {
+		methodName = symbol.ToGoName(sel.Sel, owner.isPublic(b.method))
+		funcExpr = g.generateExpression(expr.Function)
+	}
+
+	if hasDefaultParams(b.method) && !owner.isStruct {
+		optsName := owner.prefix(g) + owner.goName + "__" + methodName + "__Opts"
+		return funcExpr + "(" + g.optsLiteral(optsName, b) + ")", true
+	}
+	return funcExpr + "(" + strings.Join(g.boundArgs(b), ", ") + ")", true
+}
+
+// generateStaticCall 生成需要重载解析或参数绑定的静态方法调用 Class::m(...)
+// 静态方法按位置接收参数，未传的默认参数在调用处补齐
+func (g *CodeGen) generateStaticCall(expr *parser.CallExpr, access *parser.StaticAccessExpr) (string, bool) {
+	classDecl := g.staticTargetClass(access)
+	if classDecl == nil {
+		return "", false
+	}
+	methods := staticMethods(classDecl, access.Member)
+	if len(methods) == 0 {
+		return "", false
+	}
+	if len(methods) == 1 && !hasDefaultParams(methods[0]) && !hasNamedArgs(expr.Arguments) {
+		return "", false
+	}
+
+	b := g.resolveCall(expr, classDecl.Name+"::"+access.Member, methods, expr.Arguments, typeParamNames(classDecl.TypeParams))
+	if b == nil {
+		return "/* unresolved call */", true
+	}
+	return g.generateStaticMember(access, b.method) + "(" + strings.Join(g.boundArgs(b), ", ") + ")", true
+}
+
+// staticTargetClass 返回 Class::member 或 self::member 所指的类声明
+func (g *CodeGen) staticTargetClass(access *parser.StaticAccessExpr) *parser.ClassDecl {
+	if _, ok := access.Left.(*parser.SelfExpr); ok {
+		if g.currentStaticClass != nil {
+			return g.currentStaticClass
+		}
+		return g.currentClassDecl
+	}
+	ident, _ := staticAccessTarget(access.Left)
+	if ident == nil {
+		return nil
+	}
+	pkg := g.transpiler.pkg
+	if p, ok := g.typeToPackage[ident.Value]; ok {
+		pkg = p
+	}
+	return g.transpiler.GetClassDecl(pkg, ident.Value)
+}
+
+// staticMethods 返回类中可以用 Class::name 调用的同名方法
+func staticMethods(classDecl *parser.ClassDecl, name string) []*parser.ClassMethod {
+	var methods []*parser.ClassMethod
+	for _, m := range classDecl.Methods {
+		if m.Name == name && (m.Static || classDecl.Static) {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+// calleeMethods 返回方法调用 obj.m(...) 或 Class::m(...) 的候选方法，name 用于错误信息
+func (g *CodeGen) calleeMethods(e *parser.CallExpr) (name string, methods []*parser.ClassMethod, typeParams map[string]bool) {
+	if access, ok := e.Function.(*parser.StaticAccessExpr); ok {
+		classDecl := g.staticTargetClass(access)
+		if classDecl == nil {
+			return "", nil, nil
+		}
+		return classDecl.Name + "::" + access.Member, staticMethods(classDecl, access.Member), typeParamNames(classDecl.TypeParams)
+	}
+
+	sel, _ := methodCallSelector(e.Function)
+	if sel == nil {
+		return "", nil, nil
+	}
+	recv := stripTypeArgs(strings.TrimPrefix(g.exprType(sel.X), "*"))
+	if recv == "" {
+		recv = g.getReceiverType(sel.X)
+	}
+	if recv == "" {
+		return "", nil, nil
+	}
+	owner, methods := g.methodCandidates(g.getClassPackage(recv), recv, sel.Sel, false)
+	if owner == nil {
+		if ext := g.extensionFor(sel); ext != nil {
+			return typeText(ext.Type) + "." + sel.Sel, []*parser.ClassMethod{ext.Method}, withTypeParams(typeParamNames(ext.TypeParams), nil)
+		}
+		return "", nil, nil
+	}
+	return owner.name + "." + sel.Sel, methods, owner.typeParams
+}
+
+// exprType 推断表达式的静态类型，返回规范化的类型文本（见 canonicalType）
+// 常量返回 "untyped int"、"untyped string" 等，无法推断时返回空串
+func (g *CodeGen) exprType(expr parser.Expression) string {
+	switch e := expr.(type) {
+	case *parser.IntegerLiteral:
+		return "untyped int"
+	case *parser.FloatLiteral:
+		return "untyped float"
+	case *parser.CharLiteral:
+		return "untyped rune"
+	case *parser.StringLiteral:
+		return "untyped string"
+	case *parser.InterpolatedString:
+		return "string"
+	case *parser.BoolLiteral:
+		return "untyped bool"
+	case *parser.NilLiteral:
+		return "untyped nil"
+	case *parser.Identifier:
+		return g.localTypes[e.Value]
+	case *parser.ThisExpr:
+		if g.currentClassDecl != nil {
+			return "*" + g.currentClassDecl.Name
+		}
+		if g.currentStructDecl != nil {
+			return "*" + g.currentStructDecl.Name
+		}
+		if g.currentEnumDecl != nil {
+			return g.currentEnumDecl.Name
+		}
+		return g.currentExtendType
+	case *parser.ParenExpr:
+		return g.exprType(e.X)
+	case *parser.NamedArg:
+		return g.exprType(e.Value)
+	case *parser.UnaryExpr:
+		return g.unaryType(e)
+	case *parser.BinaryExpr:
+		return g.binaryType(e)
+	case *parser.TernaryExpr:
+		if t := g.exprType(e.TrueExpr); t != "" && !strings.HasPrefix(t, "untyped ") {
+			return t
+		}
+		if t := g.exprType(e.FalseExpr); t != "" && !strings.HasPrefix(t, "untyped ") {
+			return t
+		}
+		return g.exprType(e.TrueExpr)
+	case *parser.NewExpr:
+		return "*" + canonicalType(e.Type)
+	case *parser.StructLiteral:
+		return canonicalType(e.Type)
+	case *parser.SliceLiteral:
+		return "[]" + canonicalType(e.Type)
+	case *parser.ArrayLiteral:
+		return "[" + strconv.Itoa(len(e.Elements)) + "]" + canonicalType(e.Type)
+	case *parser.MapLiteral:
+		return "map[" + canonicalType(e.KeyType) + "]" + canonicalType(e.ValType)
+	case *parser.MakeExpr:
+		return canonicalType(e.Type)
+	case *parser.TypeAssertExpr:
+		return canonicalType(e.Type)
+	case *parser.FuncLiteral:
+		return g.funcLiteralType(e)
+	case *parser.LenExpr, *parser.CapExpr, *parser.CopyExpr:
+		return "int"
+	case *parser.AppendExpr:
+		return g.exprType(e.Slice)
+	case *parser.IndexExpr:
+		if use := g.lookupIndexOperator(e); use != nil {
+			return g.operatorType(e.Token, use)
+		}
+		return elemType(g.exprType(e.X))
+	case *parser.SliceExpr:
+		t := g.exprType(e.X)
+		if strings.HasPrefix(t, "[") && !strings.HasPrefix(t, "[]") {
+			return "[]" + elemType(t)
+		}
+		return t
+	case *parser.ReceiveExpr:
+		return elemType(g.exprType(e.X))
+	case *parser.SelectorExpr:
+		if t := g.fieldType(e); t != "" {
+			return t
+		}
+		return g.goExprTypeText(e)
+	case *parser.StaticAccessExpr:
+		if t := g.enumStaticType(e); t != "" {
+			return t
+		}
+		return g.staticFieldType(e)
+	case *parser.CallExpr:
+		if t := g.callType(e); t != "" {
+			return t
+		}
+		return g.goExprTypeText(e)
+	}
+	return ""
+}
+
+// goExprTypeText 返回由 Go 包类型信息推断出的规范类型（如 time.Now() 为 Time），无法推断时返回空串
+func (g *CodeGen) goExprTypeText(expr parser.Expression) string {
+	if t := g.goExprType(expr); t != nil {
+		return canonicalType(goTypeExpr(t))
+	}
+	return ""
+}
+
+// unaryType 推断一元表达式的类型
+func (g *CodeGen) unaryType(e *parser.UnaryExpr) string {
+	t := g.exprType(e.Operand)
+	switch e.Operator {
+	case "!":
+		return "bool"
+	case "&":
+		if t == "" || strings.HasPrefix(t, "untyped ") {
+			return ""
+		}
+		return "*" + t
+	case "*":
+		if strings.HasPrefix(t, "*") {
+			return t[1:]
+		}
+		return ""
+	case "<-":
+		return elemType(t)
+	}
+	return t
+}
+
+// binaryType 推断二元表达式的类型
+func (g *CodeGen) binaryType(e *parser.BinaryExpr) string {
+	switch e.Operator {
+	case "==", "!=", "<", "<=", ">", ">=", "&&", "||":
+		return "bool"
+	case "<<", ">>":
+		return g.exprType(e.Left)
+	}
+	if use := g.lookupOperator(e.Operator, e.Left, e.Right); use != nil {
+		return g.operatorType(e.Token, use)
+	}
+	left, right := g.exprType(e.Left), g.exprType(e.Right)
+	leftUntyped, rightUntyped := strings.HasPrefix(left, "untyped "), strings.HasPrefix(right, "untyped ")
+	switch {
+	case left != "" && !leftUntyped:
+		return left
+	case right != "" && !rightUntyped:
+		return right
+	case left == "" || right == "":
+		return ""
+	}
+	// 两个常量运算，结果取更"宽"的常量类型
+	rank := map[string]int{"untyped int": 1, "untyped rune": 2, "untyped float": 3}
+	if rank[right] > rank[left] {
+		return right
+	}
+	return left
+}
+
+// elemType 返回切片、数组、map、通道的元素类型，string 的元素类型是 uint8
+func elemType(t string) string {
+	switch {
+	case t == "string":
+		return "uint8"
+	case strings.HasPrefix(t, "[]"):
+		return t[2:]
+	case strings.HasPrefix(t, "["):
+		if end := strings.Index(t, "]"); end > 0 {
+			return t[end+1:]
+		}
+	case strings.HasPrefix(t, "map["):
+		depth := 0
+		for i := 3; i < len(t); i++ {
+			switch t[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					return t[i+1:]
+				}
+			}
+		}
+	case strings.HasPrefix(t, "chan "):
+		return t[len("chan "):]
+	case strings.HasPrefix(t, "<-chan "):
+		return t[len("<-chan "):]
+	}
+	return ""
+}
+
+// fieldType 推断字段访问 obj.field 的类型
+func (g *CodeGen) fieldType(e *parser.SelectorExpr) string {
+	typ, typeParams := g.fieldDecl(e)
+	if typ == nil {
+		return ""
+	}
+	return concreteType(typ, typeParams)
+}
+
+// fieldDecl 返回字段 obj.field 声明的类型和所在类型的类型参数，找不到时返回 nil
+func (g *CodeGen) fieldDecl(e *parser.SelectorExpr) (parser.Expression, map[string]bool) {
+	return g.memberFieldDecl(g.exprType(e.X), e.Sel)
+}
+
+// memberFieldDecl 返回类型 recv 的字段（或属性）field 声明的类型和所在类型的类型参数
+func (g *CodeGen) memberFieldDecl(recv, field string) (parser.Expression, map[string]bool) {
+	name := stripTypeArgs(strings.TrimPrefix(recv, "*"))
+	if name == "" {
+		return nil, nil
+	}
+	classInfo := g.lookupClass(name)
+	for depth := 0; classInfo != nil && depth < maxInheritanceDepth; depth++ {
+		for _, f := range classInfo.Fields {
+			if f.Name == field && !f.Static {
+				return f.Type, typeParamNames(classInfo.TypeParams)
+			}
+		}
+		for _, prop := range classInfo.Properties {
+			if prop.Name == field {
+				return prop.Type, typeParamNames(classInfo.TypeParams)
+			}
+		}
+		classInfo = g.transpiler.parentClassInfo(classInfo)
+	}
+	if iface := g.lookupInterface(name); iface != nil {
+		for _, prop := range iface.Properties {
+			if prop.Name == field {
+				return prop.Type, nil
+			}
+		}
+	}
+	if decl := g.lookupStruct(name); decl != nil {
+		for _, f := range decl.Fields {
+			if f.Name == field {
+				return f.Type, typeParamNames(decl.TypeParams)
+			}
+		}
+	}
+	if decl := g.transpiler.lookupEnum(name); decl != nil {
+		for _, f := range decl.Fields {
+			if f.Name == field {
+				return f.Type, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// staticFieldType 推断静态字段 Class::field 的类型
+func (g *CodeGen) staticFieldType(e *parser.StaticAccessExpr) string {
+	typ, typeParams := g.staticFieldDecl(e)
+	if typ == nil {
+		return ""
+	}
+	return concreteType(typ, typeParams)
+}
+
+// staticFieldDecl 返回静态字段 Class::field 声明的类型和类的类型参数，找不到时返回 nil
+func (g *CodeGen) staticFieldDecl(e *parser.StaticAccessExpr) (parser.Expression, map[string]bool) {
+	classDecl := g.staticTargetClass(e)
+	if classDecl == nil {
+		return nil, nil
+	}
+	for _, f := range classDecl.Fields {
+		if f.Name == e.Member {
+			return f.Type, typeParamNames(classDecl.TypeParams)
+		}
+	}
+	return nil, nil
+}
+
+// callType 推断调用表达式的结果类型（多返回值和 errable 的 error 不计入）
+func (g *CodeGen) callType(e *parser.CallExpr) string {
+	switch fn := e.Function.(type) {
+	case *parser.Identifier:
+		if basicTypes[canonicalType(fn)] && fn.Value != "error" && fn.Value != "any" {
+			return canonicalType(fn) // 类型转换 int64(x)
+		}
+		if decl := g.transpiler.GetFuncDecl(g.transpiler.pkg, fn.Value); decl != nil {
+			return resultType(decl.Results, typeParamNames(decl.TypeParams))
+		}
+		return ""
+	case *parser.SliceType, *parser.MapType, *parser.ArrayType:
+		return canonicalType(fn)
+	case *parser.ParenExpr:
+		return canonicalType(fn.X) // (*T)(x)
+	case *parser.StaticAccessExpr:
+		if t := g.enumCallType(fn); t != "" {
+			return t
+		}
+	}
+
+	name, methods, typeParams := g.calleeMethods(e)
+	return g.candidatesResultType(name, methods, e.Arguments, typeParams)
+}
+
+// candidatesResultType 返回调用选中的重载的结果类型，无法选出时返回空串
+func (g *CodeGen) candidatesResultType(name string, methods []*parser.ClassMethod, args []parser.Expression, typeParams map[string]bool) string {
+	method := g.selectedMethod(name, methods, args, typeParams)
+	if method == nil {
+		return ""
+	}
+	return resultType(method.Results, withTypeParams(typeParams, method.TypeParams))
+}
+
+// selectedMethod 返回调用会选中的候选方法，无法选出时返回 nil（不报告错误）
+func (g *CodeGen) selectedMethod(name string, methods []*parser.ClassMethod, args []parser.Expression, typeParams map[string]bool) *parser.ClassMethod {
+	switch len(methods) {
+	case 0:
+		return nil
+	case 1:
+		return methods[0]
+	}
+	b, _, _ := g.selectOverload(name, methods, args, typeParams)
+	if b == nil {
+		return nil
+	}
+	return b.method
+}
+
+// resultType 返回单返回值的类型，多返回值或依赖类型参数时返回空串
+func resultType(results []*parser.Field, typeParams map[string]bool) string {
+	if len(results) != 1 {
+		return ""
+	}
+	return concreteType(results[0].Type, typeParams)
+}
+
+// concreteType 返回声明类型的规范文本，依赖类型参数时无法确定实际类型，返回空串
+func concreteType(t parser.Expression, typeParams map[string]bool) string {
+	text := canonicalType(t)
+	if mentionsTypeParam(text, typeParams) {
+		return ""
+	}
+	return text
+}
+
+// paramType 返回形参在函数体内的类型，...T 在函数体内是 []T
+func paramType(t parser.Expression) string {
+	if ellipsis, ok := t.(*parser.Ellipsis); ok {
+		return "[]" + canonicalType(ellipsis.Elt)
+	}
+	return canonicalType(t)
+}
+
+// setLocalType 记录局部变量的类型，类型未知时清除旧记录（同名变量可能来自其他作用域）
+func (g *CodeGen) setLocalType(name, t string) {
+	t = defaultType(t)
+	if name == "_" {
+		return
+	}
+	g.localNames[name] = true
+	if t == "" {
+		delete(g.localTypes, name)
+		return
+	}
+	g.localTypes[name] = t
+}
+
+// trackRangeTypes 记录 for range 中键和值变量的类型
+func (g *CodeGen) trackRangeTypes(stmt *parser.RangeStmt) {
+	t := g.exprType(stmt.X)
+	var key, value string
+	switch {
+	case t == "string":
+		key, value = "int", "int32"
+	case strings.HasPrefix(t, "map["):
+		value = elemType(t)
+		key = strings.TrimSuffix(strings.TrimPrefix(t, "map["), "]"+value)
+	case strings.HasPrefix(t, "["):
+		key, value = "int", elemType(t)
+	case strings.HasPrefix(t, "chan ") || strings.HasPrefix(t, "<-chan "):
+		key = elemType(t)
+	case isIntegerType(t) || t == "untyped int":
+		key = defaultType(t)
+	}
+	if ident, ok := stmt.Key.(*parser.Identifier); ok {
+		g.setLocalType(ident.Value, key)
+	}
+	if ident, ok := stmt.Value.(*parser.Identifier); ok {
+		g.setLocalType(ident.Value, value)
+	}
+}
+
+// overloadParamKey 用于重载声明校验的参数类型键
+func overloadParamKey(method *parser.ClassMethod) string {
+	var types []string
+	for _, p := range method.Params {
+		types = append(types, canonicalType(p.Type))
+	}
+	return strings.Join(types, ", ")
+}
+
+// validateOverloadSet 校验同名方法（或多个 init）能否构成合法的重载：
+// 参数类型相同的重载无法区分，仅返回类型不同同样报错；参数类型不同但修饰名相同时生成的 Go 方法会重名
+func (t *Transpiler) validateOverloadSet(typeName string, methods []*parser.ClassMethod) {
+	byName := make(map[string][]*parser.ClassMethod)
+	var names []string
+	for _, m := range methods {
+		if _, ok := byName[m.Name]; !ok {
+			names = append(names, m.Name)
+		}
+		byName[m.Name] = append(byName[m.Name], m)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		group := byName[name]
+		if len(group) <= 1 {
+			continue
+		}
+		for i, m := range group {
+			for _, prev := range group[:i] {
+				if overloadParamKey(m) == overloadParamKey(prev) {
+					if strings.Join(classMethodSig(m).goResultTypes(), ",") != strings.Join(classMethodSig(prev).goResultTypes(), ",") {
+						t.errors = append(t.errors, i18n.T(i18n.ErrOverloadOnlyReturnDiffers, typeName, name))
+					} else {
+						t.errors = append(t.errors, i18n.T(i18n.ErrDuplicateOverloadSignature,
+							typeName, name, "("+overloadParamKey(m)+")"))
+					}
+					break
+				}
+				if symbol.GenerateParamSignature(m.Params) == symbol.GenerateParamSignature(prev.Params) {
+					t.errors = append(t.errors, i18n.T(i18n.ErrOverloadNameCollision, typeName, name,
+						classMethodSig(prev).String(), classMethodSig(m).String(),
+						symbol.GenerateMangledName(name, m.Params, true)))
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package transpiler
+
+import (
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/lexer"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 属性（prop）的读写
+// 解析器把属性展开为后备字段和 getter/setter 方法，这里把读取 x.name 改写为 x.name()，
+// 把赋值 x.name = v 改写为 x.setName(v)，之后按普通方法调用生成：
+// 重载、可见性、继承的虚方法包装和接口实现都沿用方法的处理
+
+// propertyRef x.name 引用的属性，owner 是声明属性的类（接口属性为 nil）
+type propertyRef struct {
+	prop  *parser.ClassProperty
+	owner *symbol.ClassInfo
+}
+
+// lookupProperty 按接收者的类型查找 x.name 引用的属性，同名字段优先
+func (g *CodeGen) lookupProperty(sel *parser.SelectorExpr) *propertyRef {
+	name := stripTypeArgs(strings.TrimPrefix(g.exprType(sel.X), "*"))
+	if name == "" {
+		return nil
+	}
+	classInfo := g.lookupClass(name)
+	for depth := 0; classInfo != nil && depth < maxInheritanceDepth; depth++ {
+		for _, f := range classInfo.Fields {
+			if f.Name == sel.Sel {
+				return nil
+			}
+		}
+		for _, prop := range classInfo.Properties {
+			if prop.Name == sel.Sel {
+				return &propertyRef{prop: prop, owner: classInfo}
+			}
+		}
+		classInfo = g.transpiler.parentClassInfo(classInfo)
+	}
+	if iface := g.lookupInterface(name); iface != nil {
+		for _, prop := range iface.Properties {
+			if prop.Name == sel.Sel {
+				return &propertyRef{prop: prop}
+			}
+		}
+	}
+	return nil
+}
+
+// propertyGet 把属性读取 x.name 改写为 getter 调用，不是属性时返回 nil
+// 被调用的 x.name() 是直接调用 getter，不再改写
+func (g *CodeGen) propertyGet(sel *parser.SelectorExpr) *parser.CallExpr {
+	if parser.Expression(sel) == g.callee {
+		return nil
+	}
+	ref := g.lookupProperty(sel)
+	if ref == nil {
+		return nil
+	}
+	return propertyCall(sel, ref.prop.Getter.Name)
+}
+
+// propertyCall 构造访问器调用 x.method(args...)
+func propertyCall(sel *parser.SelectorExpr, method string, args ...parser.Expression) *parser.CallExpr {
+	fn := &parser.SelectorExpr{Token: sel.Token, X: sel.X, Sel: method, Safe: sel.Safe}
+	return &parser.CallExpr{Token: sel.Token, Function: fn, Arguments: args}
+}
+
+// lowerPropertyAssign 把给属性赋值的语句改写为 setter 调用：
+// x.name = v 调用 x.setName(v)，x.name += v 和 x.name++ 调用 x.setName(x.name() + v)
+// 只读的自动属性只能在声明它的类中赋值（直接写入后备字段）
+// 不是属性赋值时 ok 为 false；出错时返回 nil 语句
+func (g *CodeGen) lowerPropertyAssign(stmt parser.Statement) (lowered parser.Statement, ok bool) {
+	var tok lexer.Token
+	var sel *parser.SelectorExpr
+	var ref *propertyRef
+	var value parser.Expression
+
+	switch s := stmt.(type) {
+	case *parser.AssignStmt:
+		for _, left := range s.Left {
+			if target, isSel := left.(*parser.SelectorExpr); isSel {
+				if r := g.lookupProperty(target); r != nil {
+					sel, ref = target, r
+					break
+				}
+			}
+		}
+		if ref == nil {
+			return nil, false
+		}
+		if len(s.Left) != 1 || len(s.Right) != 1 {
+			g.transpiler.AddError(s.Token.Line, s.Token.Column, i18n.T(i18n.ErrPropertyMultiAssign, sel.Sel))
+			return nil, true
+		}
+		tok, value = s.Token, s.Right[0]
+		if op := s.Token.Literal; op != "=" {
+			sel = g.stablePropertyTarget(sel)
+			value = &parser.BinaryExpr{Token: s.Token, Left: sel, Operator: strings.TrimSuffix(op, "="), Right: value}
+		}
+	case *parser.IncDecStmt:
+		target, isSel := s.X.(*parser.SelectorExpr)
+		if !isSel {
+			return nil, false
+		}
+		if ref = g.lookupProperty(target); ref == nil {
+			return nil, false
+		}
+		tok, sel = s.Token, g.stablePropertyTarget(target)
+		op := "-"
+		if s.Inc {
+			op = "+"
+		}
+		one := &parser.IntegerLiteral{Token: s.Token, Value: "1"}
+		value = &parser.BinaryExpr{Token: s.Token, Left: sel, Operator: op, Right: one}
+	default:
+		return nil, false
+	}
+
+	if ref.prop.Setter != nil {
+		return &parser.ExpressionStmt{Expression: propertyCall(sel, ref.prop.Setter.Name, value)}, true
+	}
+	if ref.prop.Field != nil && g.declaresProperty(ref) {
+		field := &parser.SelectorExpr{Token: sel.Token, X: sel.X, Sel: ref.prop.Field.Name}
+		assign := lexer.Token{Type: lexer.TOKEN_ASSIGN, Literal: "=", Line: tok.Line, Column: tok.Column}
+		return &parser.AssignStmt{Token: assign, Left: []parser.Expression{field}, Right: []parser.Expression{value}}, true
+	}
+	g.transpiler.AddError(tok.Line, tok.Column, i18n.T(i18n.ErrPropertyReadOnly, sel.Sel))
+	return nil, true
+}
+
+// stablePropertyTarget 复合赋值既读又写属性，接收者有副作用时先保存到临时变量，只求值一次
+func (g *CodeGen) stablePropertyTarget(sel *parser.SelectorExpr) *parser.SelectorExpr {
+	if stablePath(sel.X) != "" {
+		return sel
+	}
+	recv := g.names.fresh("_recv%d")
+	g.localTypes[recv] = g.exprType(sel.X)
+	x := g.generateExpression(sel.X)
+	g.flushPendingStatements()
+	g.writeLine(recv + " := " + x)
+	return &parser.SelectorExpr{Token: sel.Token, X: &parser.Identifier{Token: sel.Token, Value: recv}, Sel: sel.Sel}
+}
+
+// declaresProperty 判断当前类是否是声明属性的类
+func (g *CodeGen) declaresProperty(ref *propertyRef) bool {
+	return ref.owner != nil && g.currentClassDecl != nil &&
+		ref.owner.Package == g.transpiler.pkg && ref.owner.Name == g.currentClassDecl.Name
+}
@@ -0,0 +1,258 @@
+package transpiler
+
+import (
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+)
+
+// 方法签名比较（implements / extends 校验）
+//
+// Go 只有在方法签名完全一致时才认为类型实现了接口，因此这里按 Go 的规则逐项比较：
+//   - 参数名不参与比较，参数类型必须一致（指针与值、...T 与 []T 都视为不同类型）
+//   - errable 标记 `int!` 等价于显式的 `(int, error)`，生成的 Go 代码相同
+//   - 返回值不支持协变：实现方法返回子类而接口要求父类或接口时同样报错
+//   - byte/uint8、rune/int32、any/interface{} 是 Go 中的同一类型，视为相同
+//   - 包限定名只比较类型名：接口在 models 包中写作 Point，实现方写作 models.Point 时视为相同
+//     （无法得知其他包文件的导入，同名类型的冲突交给 Go 编译器检查）
+
+// methodSig 参与比较的方法签名
+type methodSig struct {
+	name    string
+	params  []*parser.Field
+	results []*parser.Field
+	errable bool
+}
+
+func classMethodSig(m *parser.ClassMethod) methodSig {
+	return methodSig{name: m.Name, params: m.Params, results: m.Results, errable: m.Errable}
+}
+
+func funcSignatureSig(s *parser.FuncSignature) methodSig {
+	return methodSig{name: s.Name, params: s.Params, results: s.Results, errable: s.Errable}
+}
+
+// goResultTypes 返回 Go 层面的返回值类型列表（errable 追加 error）
+func (s methodSig) goResultTypes() []string {
+	var types []string
+	for _, r := range s.results {
+		types = append(types, canonicalType(r.Type))
+	}
+	if s.errable {
+		types = append(types, "error")
+	}
+	return types
+}
+
+// String 以 tugo 语法渲染签名，如 read(p []byte) (int, error) 或 read(p []byte) int!
+func (s methodSig) String() string {
+	var sb strings.Builder
+	sb.WriteString(s.name)
+	sb.WriteString("(")
+	for i, p := range s.params {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if p.Name != "" {
+			sb.WriteString(p.Name)
+			sb.WriteString(" ")
+		}
+		sb.WriteString(typeText(p.Type))
+	}
+	sb.WriteString(")")
+
+	switch {
+	case len(s.results) == 0:
+		if s.errable {
+			sb.WriteString(" void!")
+		}
+	case len(s.results) == 1 && s.results[0].Name == "":
+		sb.WriteString(" ")
+		sb.WriteString(typeText(s.results[0].Type))
+	default:
+		sb.WriteString(" (")
+		for i, r := range s.results {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			if r.Name != "" {
+				sb.WriteString(r.Name)
+				sb.WriteString(" ")
+			}
+			sb.WriteString(typeText(r.Type))
+		}
+		sb.WriteString(")")
+	}
+	if s.errable && len(s.results) > 0 {
+		sb.WriteString("!")
+	}
+	return sb.String()
+}
+
+// compareSignatures 比较实现方法与期望签名的参数和返回值，返回第一处差异的描述，一致时返回空串
+func compareSignatures(got, want methodSig) string {
+	if len(got.params) != len(want.params) {
+		return i18n.T(i18n.ErrSigParamCount, len(got.params), len(want.params))
+	}
+	for i := range want.params {
+		if i >= len(got.params) {
+			break
+		}
+		g, w := canonicalType(got.params[i].Type), canonicalType(want.params[i].Type)
+		if g != w {
+			name := got.params[i].Name
+			if name == "" {
+				name = "_"
+			}
+			return i18n.T(i18n.ErrSigParamType, i+1, name,
+				typeText(got.params[i].Type), typeText(want.params[i].Type))
+		}
+	}
+
+	gotResults, wantResults := got.goResultTypes(), want.goResultTypes()
+	if len(gotResults) != len(wantResults) {
+		// 只差一个 error 时给出更明确的提示
+		if got.errable && len(gotResults) == len(wantResults)+1 {
+			return i18n.T(i18n.ErrSigUnexpectedErrable)
+		}
+		if len(wantResults) == len(gotResults)+1 && wantResults[len(wantResults)-1] == "error" {
+			return i18n.T(i18n.ErrSigMissingErrable)
+		}
+		return i18n.T(i18n.ErrSigResultCount, len(gotResults), len(wantResults))
+	}
+	for i := range wantResults {
+		if gotResults[i] != wantResults[i] {
+			return i18n.T(i18n.ErrSigResultType, i+1,
+				resultText(got, i), resultText(want, i))
+		}
+	}
+	return ""
+}
+
+// resultText 渲染第 i 个返回值的类型（errable 的隐式 error 也计入）
+func resultText(s methodSig, i int) string {
+	if i < len(s.results) {
+		return typeText(s.results[i].Type)
+	}
+	return "error"
+}
+
+// typeText 按源码写法渲染类型
+func typeText(expr parser.Expression) string {
+	return renderType(expr, false)
+}
+
+// canonicalType 渲染类型的规范形式，Go 中的同一类型得到相同的字符串
+func canonicalType(expr parser.Expression) string {
+	return renderType(expr, true)
+}
+
+// renderType 渲染类型表达式，canonical 为 true 时统一 Go 的内置别名并去掉包限定
+func renderType(expr parser.Expression, canonical bool) string {
+	switch t := expr.(type) {
+	case nil:
+		return ""
+	case *parser.Identifier:
+		if canonical {
+			switch t.Value {
+			case "byte":
+				return "uint8"
+			case "rune":
+				return "int32"
+			}
+		}
+		return t.Value
+	case *parser.SelectorExpr:
+		if canonical {
+			return t.Sel
+		}
+		return renderType(t.X, canonical) + "." + t.Sel
+	case *parser.PointerType:
+		return "*" + renderType(t.Base, canonical)
+	case *parser.NullableType:
+		// 可空与非空在 Go 中是同一个指针类型
+		if !canonical {
+			return renderType(t.Base, canonical) + "?"
+		}
+		if _, ok := t.Base.(*parser.PointerType); ok {
+			return renderType(t.Base, canonical)
+		}
+		return "*" + renderType(t.Base, canonical)
+	case *parser.SliceType:
+		return "[]" + renderType(t.Elt, canonical)
+	case *parser.ArrayType:
+		length := "..."
+		if lit, ok := t.Len.(*parser.IntegerLiteral); ok {
+			length = lit.Value
+		} else if t.Len != nil {
+			length = renderType(t.Len, canonical)
+		}
+		return "[" + length + "]" + renderType(t.Elt, canonical)
+	case *parser.MapType:
+		return "map[" + renderType(t.Key, canonical) + "]" + renderType(t.Value, canonical)
+	case *parser.ChanType:
+		switch t.Dir {
+		case 1:
+			return "chan<- " + renderType(t.Value, canonical)
+		case 2:
+			return "<-chan " + renderType(t.Value, canonical)
+		}
+		return "chan " + renderType(t.Value, canonical)
+	case *parser.Ellipsis:
+		return "..." + renderType(t.Elt, canonical)
+	case *parser.GenericType:
+		var args []string
+		for _, arg := range t.TypeArgs {
+			args = append(args, renderType(arg, canonical))
+		}
+		return renderType(t.Type, canonical) + "[" + strings.Join(args, ", ") + "]"
+	case *parser.FuncType:
+		return "func(" + renderTypeList(t.Params, canonical) + ")" + renderFuncResults(t.Results, canonical)
+	case *parser.InterfaceType:
+		if len(t.Methods) == 0 {
+			if canonical {
+				return "any"
+			}
+			return "interface{}"
+		}
+		var methods []string
+		for _, m := range t.Methods {
+			sig := m.Name + "(" + renderTypeList(m.Params, canonical) + ")" + renderFuncResults(m.Results, canonical)
+			if m.Errable {
+				sig += "!"
+			}
+			methods = append(methods, sig)
+		}
+		return "interface{ " + strings.Join(methods, "; ") + " }"
+	case *parser.StructType:
+		var fields []string
+		for _, f := range t.Fields {
+			fields = append(fields, f.Name+" "+renderType(f.Type, canonical))
+		}
+		return "struct{ " + strings.Join(fields, "; ") + " }"
+	case *parser.ParenExpr:
+		return renderType(t.X, canonical)
+	}
+	return expr.TokenLiteral()
+}
+
+// renderTypeList 渲染参数类型列表（忽略参数名）
+func renderTypeList(fields []*parser.Field, canonical bool) string {
+	var types []string
+	for _, f := range fields {
+		types = append(types, renderType(f.Type, canonical))
+	}
+	return strings.Join(types, ", ")
+}
+
+// renderFuncResults 渲染函数类型的返回值部分
+func renderFuncResults(results []*parser.Field, canonical bool) string {
+	switch len(results) {
+	case 0:
+		return ""
+	case 1:
+		return " " + renderType(results[0].Type, canonical)
+	}
+	return " (" + renderTypeList(results, canonical) + ")"
+}
@@ -0,0 +1,555 @@
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/i18n"
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// 父类调用和方法重写
+//
+//	class Dog extends Animal {
+//	    public func init(name string) { super.init(name) }
+//	    public override func sound() string { return "woof, " + super.sound() }
+//	}
+//
+// super.init(...) 调用父类的构造函数为嵌入的父类指针赋值：t.Animal = New__Animal(name)，
+// 只能作为 init 的第一条语句；父类的构造函数需要实参时子类必须调用它。
+// super.m(...) 调用父类的实现：t.Animal.Sound()；父类的方法按 self 传递生成时调用
+// t.Animal.Sound__Impl(_self)，父类方法中的 this 仍然是子类实例。
+//
+// 重写父类的方法必须声明 override，override 方法的签名必须与父类的方法一致；
+// 实现父类的抽象方法时 override 可以省略。
+//
+// Go 的嵌入没有虚方法，父类方法中的 this.m() 总是调用父类的 m。m 是抽象方法、属于可以被其他包继承的
+// 公开类或被同一个包中的子类重写时，调用它的方法按 self 传递生成 M__Impl(_self, ...)，this.m() 生成为 _self.(interface{ M() string }).M()，
+// 按实际类型分派；子类继承的包装方法把自身作为 _self 传入。
+
+// superSelector 表达式是 super.name 时返回该选择器
+func superSelector(expr parser.Expression) *parser.SelectorExpr {
+	sel, ok := expr.(*parser.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	if _, ok := sel.X.(*parser.SuperExpr); !ok {
+		return nil
+	}
+	return sel
+}
+
+// superInitCall 语句是 super.init(...) 时返回该调用
+func superInitCall(stmt parser.Statement) *parser.CallExpr {
+	es, ok := stmt.(*parser.ExpressionStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := es.Expression.(*parser.CallExpr)
+	if !ok {
+		return nil
+	}
+	if sel := superSelector(call.Function); sel == nil || sel.Sel != "init" {
+		return nil
+	}
+	return call
+}
+
+// callsSuperInit 判断构造方法是否以 super.init(...) 开始
+func callsSuperInit(init *parser.ClassMethod) bool {
+	return init.Body != nil && len(init.Body.Statements) > 0 && superInitCall(init.Body.Statements[0]) != nil
+}
+
+// initNeedsArgs 判断类的构造函数是否必须传入实参（没有无参的 init，且不是只有一个参数都有默认值的 init）
+func initNeedsArgs(decl *parser.ClassDecl) bool {
+	inits := decl.InitMethods
+	if len(inits) == 0 && decl.InitMethod != nil {
+		inits = []*parser.ClassMethod{decl.InitMethod}
+	}
+	if len(inits) == 0 {
+		return false
+	}
+	for _, init := range inits {
+		if len(init.Params) == 0 {
+			return false
+		}
+	}
+	return len(inits) > 1 || !allParamsDefaulted(inits[0])
+}
+
+// ========== 校验 ==========
+
+// inheritedMethods 沿继承链查找最近声明了 name 的祖先类中可以重写的方法（非私有、非静态）
+// abstract 表示找到的是抽象方法
+func (t *Transpiler) inheritedMethods(parent *symbol.ClassInfo, name string) (methods []*parser.ClassMethod, abstract bool) {
+	for depth := 0; parent != nil && depth < maxInheritanceDepth; depth++ {
+		for _, m := range parent.Methods {
+			if m.Name == name && !m.Static && m.Visibility != "private" {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) > 0 {
+			return methods, false
+		}
+		for _, m := range parent.AbstractMethods {
+			if m.Name == name {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) > 0 {
+			return methods, true
+		}
+		parent = t.parentClassInfo(parent)
+	}
+	return nil, false
+}
+
+// validateOverrides 校验 override 修饰符：重写父类的方法必须声明 override，
+// 声明 override 的方法必须重写父类中签名相同的方法
+func (t *Transpiler) validateOverrides(classDecl *parser.ClassDecl) {
+	var parent *symbol.ClassInfo
+	if classDecl.Extends != "" {
+		parent, _ = t.lookupClass(classDecl.Extends)
+	}
+	// 属性的 getter/setter 不能声明 override，不参与校验
+	accessors := make(map[*parser.ClassMethod]bool)
+	for _, prop := range classDecl.Properties {
+		accessors[prop.Getter] = true
+		accessors[prop.Setter] = true
+	}
+
+	for _, method := range classDecl.Methods {
+		if method.Derived || accessors[method] {
+			continue
+		}
+		line, col := method.Token.Line, method.Token.Column
+		if method.Static {
+			if method.Override {
+				t.AddError(line, col, i18n.T(i18n.ErrOverrideStatic, classDecl.Name, method.Name))
+			}
+			continue
+		}
+		if classDecl.Extends == "" {
+			if method.Override {
+				t.AddError(line, col, i18n.T(i18n.ErrOverrideNoParent, classDecl.Name, method.Name))
+			}
+			continue
+		}
+		// 父类所在的包没有加载到符号表，无法校验
+		if parent == nil {
+			continue
+		}
+
+		inherited, abstract := t.inheritedMethods(parent, method.Name)
+		switch {
+		case len(inherited) == 0:
+			if method.Override {
+				t.AddError(line, col, i18n.T(i18n.ErrOverrideNotFound, classDecl.Name, method.Name, classDecl.Extends))
+			}
+		case abstract:
+			// 抽象方法的签名由 validateExtends 校验
+		default:
+			// 没有声明 override 的同名方法同样会被分派，签名也必须一致
+			if !method.Override {
+				t.lint(ruleMissingOverride, line, col, i18n.T(i18n.WarnOverrideMissing, classDecl.Name, method.Name, classDecl.Extends))
+			}
+			if detail, want := overrideMismatch(method, inherited); detail != "" {
+				t.AddError(line, col, i18n.T(i18n.ErrOverrideSignatureMismatch, classDecl.Name, method.Name,
+					classDecl.Extends, classMethodSig(method).String(), classMethodSig(want).String(), detail))
+			}
+		}
+	}
+}
+
+// overrideMismatch 在父类的同名方法中查找签名与 method 一致的方法，找不到时返回差异和用于比较的父类方法
+// 优先与参数数量相同的方法比较
+func overrideMismatch(method *parser.ClassMethod, inherited []*parser.ClassMethod) (string, *parser.ClassMethod) {
+	want := inherited[0]
+	for _, m := range inherited {
+		if len(m.Params) != len(method.Params) {
+			continue
+		}
+		if compareSignatures(classMethodSig(method), classMethodSig(m)) == "" {
+			return "", nil
+		}
+		if len(want.Params) != len(method.Params) {
+			want = m
+		}
+	}
+	if len(want.Params) != len(method.Params) {
+		return i18n.T(i18n.ErrSigParamCount, len(method.Params), len(want.Params)), want
+	}
+	return compareSignatures(classMethodSig(method), classMethodSig(want)), want
+}
+
+// validateSuper 校验 super 的用法，以及父类的构造函数需要实参时子类是否调用了 super.init(...)
+func (t *Transpiler) validateSuper(classDecl *parser.ClassDecl) {
+	var parent *symbol.ClassInfo
+	if classDecl.Extends != "" {
+		parent, _ = t.lookupClass(classDecl.Extends)
+	}
+	inits := classDecl.InitMethods
+	if len(inits) == 0 && classDecl.InitMethod != nil {
+		inits = []*parser.ClassMethod{classDecl.InitMethod}
+	}
+	for _, init := range inits {
+		t.validateSuperUses(classDecl, parent, init, true)
+	}
+	for _, method := range classDecl.Methods {
+		t.validateSuperUses(classDecl, parent, method, false)
+	}
+
+	if parent == nil || parent.Abstract {
+		return
+	}
+	parentDecl := t.GetClassDecl(parent.Package, parent.Name)
+	if parentDecl == nil || parentDecl.TypeParams != nil || !initNeedsArgs(parentDecl) {
+		return
+	}
+	if len(inits) == 0 {
+		t.AddError(classDecl.Token.Line, classDecl.Token.Column, i18n.T(i18n.ErrSuperInitRequired, classDecl.Name, classDecl.Extends))
+	}
+	for _, init := range inits {
+		if !callsSuperInit(init) {
+			t.AddError(init.Token.Line, init.Token.Column, i18n.T(i18n.ErrSuperInitRequired, classDecl.Name, classDecl.Extends))
+		}
+	}
+}
+
+// validateSuperUses 校验方法体中的 super：只能以 super.m(...) 或 super.init(...) 的形式出现
+func (t *Transpiler) validateSuperUses(classDecl *parser.ClassDecl, parent *symbol.ClassInfo, method *parser.ClassMethod, isInit bool) {
+	var visit func(node parser.Node) bool
+	visit = func(node parser.Node) bool {
+		switch n := node.(type) {
+		case *parser.CallExpr:
+			sel := superSelector(n.Function)
+			if sel == nil {
+				return true
+			}
+			t.validateSuperCall(classDecl, parent, method, isInit, n, sel)
+			inspectExprs(n.Arguments, visit)
+			return false
+		case *parser.SuperExpr:
+			t.AddError(n.Token.Line, n.Token.Column, i18n.T(i18n.ErrSuperUsage))
+		}
+		return true
+	}
+	inspectMethod(method, visit)
+}
+
+// validateSuperCall 校验 super.m(...) 或 super.init(...)
+func (t *Transpiler) validateSuperCall(classDecl *parser.ClassDecl, parent *symbol.ClassInfo, method *parser.ClassMethod, isInit bool, call *parser.CallExpr, sel *parser.SelectorExpr) {
+	tok := sel.X.(*parser.SuperExpr).Token
+	if classDecl.Extends == "" || method.Static {
+		t.AddError(tok.Line, tok.Column, i18n.T(i18n.ErrSuperOutsideSubclass, classDecl.Name))
+		return
+	}
+
+	if sel.Sel == "init" {
+		switch {
+		case !isInit || superInitCall(method.Body.Statements[0]) != call:
+			t.AddError(tok.Line, tok.Column, i18n.T(i18n.ErrSuperInitPosition, classDecl.Name))
+		case parent != nil && parent.Abstract:
+			t.AddError(tok.Line, tok.Column, i18n.T(i18n.ErrSuperInitAbstract, classDecl.Name, classDecl.Extends))
+		}
+		return
+	}
+
+	// 父类所在的包没有加载到符号表，无法校验
+	if parent == nil {
+		return
+	}
+	methods, abstract := t.inheritedMethods(parent, sel.Sel)
+	switch {
+	case len(methods) == 0:
+		t.AddError(tok.Line, tok.Column, i18n.T(i18n.ErrSuperMethodNotFound, sel.Sel, classDecl.Extends))
+	case abstract:
+		t.AddError(tok.Line, tok.Column, i18n.T(i18n.ErrSuperAbstractMethod, sel.Sel, classDecl.Extends))
+	}
+}
+
+// ========== 代码生成 ==========
+
+// superField 返回子类中嵌入的父类字段名（普通类嵌入父类指针，抽象类嵌入基础结构体）
+func (g *CodeGen) superField(decl *parser.ClassDecl) string {
+	if parent := g.lookupClass(decl.Extends); parent != nil && parent.Abstract {
+		return abstractBaseName(decl.Extends, parent.Public)
+	}
+	return g.classGoName(decl.Extends)
+}
+
+// superReceiver 返回 super 对应的 Go 表达式，即当前接收者中嵌入的父类
+func (g *CodeGen) superReceiver() string {
+	decl := g.currentClassDecl
+	if decl == nil || decl.Extends == "" || g.currentReceiver == "" {
+		return "nil"
+	}
+	return g.currentReceiver + "." + g.superField(decl)
+}
+
+// selfArg 返回传给 M__Impl 的 _self：self 传递的方法体中继续传递 _self，否则传递接收者
+func (g *CodeGen) selfArg() string {
+	if g.selfReplaceMode {
+		return g.names.name("_self")
+	}
+	return g.currentReceiver
+}
+
+// generateSuperCall 生成 super.m(...) 或 super.init(...)
+func (g *CodeGen) generateSuperCall(expr *parser.CallExpr, sel *parser.SelectorExpr) string {
+	decl := g.currentClassDecl
+	if decl == nil || decl.Extends == "" || g.currentReceiver == "" {
+		return "/* super */"
+	}
+	if sel.Sel == "init" {
+		return g.generateSuperInit(expr, decl)
+	}
+
+	recv := g.superReceiver()
+	parent := g.lookupClass(decl.Extends)
+	if parent == nil {
+		// 父类没有加载到符号表，按名称生成调用
+		var args []string
+		for _, arg := range expr.Arguments {
+			args = append(args, g.generateArgumentExpr(arg))
+		}
+		return recv + "." + symbol.ToGoName(sel.Sel, true) + "(" + strings.Join(args, ", ") + ")"
+	}
+	owner, methods := g.methodCandidates(parent.Package, parent.Name, sel.Sel, false)
+	if owner == nil {
+		return "/* unresolved call */"
+	}
+	b := g.resolveCall(expr, owner.name+"."+sel.Sel, methods, expr.Arguments, owner.typeParams)
+	if b == nil {
+		return "/* unresolved call */"
+	}
+	if usesReceiverDefaults(b) {
+		b.receiver = &parser.ThisExpr{Token: sel.Token}
+	}
+
+	methodName := ownerMethodName(owner, methods, b.method)
+	args := g.ownerCallArgs(owner, methodName, b)
+	if g.methodNeedsSelf(g.transpiler.table.GetClass(owner.pkg, owner.name), b.method) {
+		return fmt.Sprintf("%s.%s__Impl(%s)", recv, methodName, joinArgs(g.selfArg(), args))
+	}
+	return recv + "." + methodName + "(" + args + ")"
+}
+
+// generateSuperInit 生成 super.init(...)：用父类的构造函数初始化嵌入的父类指针
+func (g *CodeGen) generateSuperInit(expr *parser.CallExpr, decl *parser.ClassDecl) string {
+	var typ parser.Expression = &parser.Identifier{Token: expr.Token, Value: decl.Extends}
+	if pkg, name, ok := strings.Cut(decl.Extends, "."); ok {
+		typ = &parser.SelectorExpr{Token: expr.Token, X: &parser.Identifier{Token: expr.Token, Value: pkg}, Sel: name}
+	}
+	newExpr := g.generateNewExpr(&parser.NewExpr{Token: expr.Token, Type: typ, Arguments: expr.Arguments})
+	return fmt.Sprintf("%s.%s = %s", g.currentReceiver, g.superField(decl), newExpr)
+}
+
+// generateDispatchCall 在 self 传递的方法体中生成 this.m(...)：
+// m 是抽象方法或被子类重写时通过 _self 按实际类型分派，m 按 self 传递生成时调用 M__Impl 继续传递 _self
+// 其余调用返回 false，由调用方按普通方法调用生成
+func (g *CodeGen) generateDispatchCall(expr *parser.CallExpr) (string, bool) {
+	sel, ok := expr.Function.(*parser.SelectorExpr)
+	if !ok || sel.Safe || g.currentClassDecl == nil {
+		return "", false
+	}
+	if _, ok := sel.X.(*parser.ThisExpr); !ok {
+		return "", false
+	}
+	info := g.transpiler.table.GetClass(g.transpiler.pkg, g.currentClassDecl.Name)
+	if info == nil {
+		return "", false
+	}
+	owner, methods := g.dispatchCandidates(info, sel.Sel)
+	if owner == nil {
+		return "", false
+	}
+	b := g.resolveCall(expr, owner.name+"."+sel.Sel, methods, expr.Arguments, owner.typeParams)
+	if b == nil {
+		return "/* unresolved call */", true
+	}
+
+	virtual := g.isVirtualMethod(info, b.method)
+	if !virtual && !g.methodNeedsSelf(g.transpiler.table.GetClass(owner.pkg, owner.name), b.method) {
+		return "", false
+	}
+	if usesReceiverDefaults(b) {
+		b.receiver = sel.X
+	}
+	methodName := ownerMethodName(owner, methods, b.method)
+	args := g.ownerCallArgs(owner, methodName, b)
+	self := g.names.name("_self")
+	if virtual {
+		return fmt.Sprintf("%s.(interface{ %s }).%s(%s)", self, g.methodSignatureText(owner, methodName, b.method), methodName, args), true
+	}
+	return fmt.Sprintf("%s.%s__Impl(%s)", g.currentReceiver, methodName, joinArgs(self, args)), true
+}
+
+// dispatchCandidates 返回 this.name(...) 的候选方法，继承链上没有实现时查找抽象方法
+func (g *CodeGen) dispatchCandidates(info *symbol.ClassInfo, name string) (*methodOwner, []*parser.ClassMethod) {
+	if owner, methods := g.methodCandidates(info.Package, info.Name, name, false); owner != nil {
+		return owner, methods
+	}
+	for c, depth := info, 0; c != nil && depth < maxInheritanceDepth; c, depth = g.transpiler.parentClassInfo(c), depth+1 {
+		var methods []*parser.ClassMethod
+		for _, m := range c.AbstractMethods {
+			if m.Name == name {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) > 0 {
+			return &methodOwner{
+				pkg:        c.Package,
+				name:       c.Name,
+				goName:     c.GoName,
+				typeParams: typeParamNames(c.TypeParams),
+			}, methods
+		}
+	}
+	return nil, nil
+}
+
+// isVirtualMethod 判断 info 的方法中 this.m(...) 调用 method 时是否需要按实际类型分派：
+// method 是抽象方法，info 是可以被其他包继承的公开类（生成 info 时看不到其他包的子类），
+// 或 method 被同一个包中 info 的子类重写
+func (g *CodeGen) isVirtualMethod(info *symbol.ClassInfo, method *parser.ClassMethod) bool {
+	if method.Static || method.Visibility == "private" || isGenericMethod(method) {
+		return false
+	}
+	if method.Abstract || info.Public && !info.Sealed {
+		return true
+	}
+	for _, c := range g.transpiler.table.GetAllClasses() {
+		if c == info || c.Package != info.Package || !g.transpiler.inheritsFrom(c, info) {
+			continue
+		}
+		for _, m := range c.Methods {
+			if m.Name == method.Name && !m.Static {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// methodNeedsSelf 判断类的方法是否按 self 传递生成（M__Impl + 包装方法）
+func (g *CodeGen) methodNeedsSelf(info *symbol.ClassInfo, method *parser.ClassMethod) bool {
+	if info == nil {
+		return g.needsSelfPass(method)
+	}
+	return g.selfPassMethods(info)[method]
+}
+
+// selfPassMethods 返回类中需要 self 传递的方法：this 作为参数传递，
+// 或者调用了需要分派的 this.m(...)、需要 self 传递的 this.m(...) 或 super.m(...)
+// 方法之间相互调用，按不动点计算
+func (g *CodeGen) selfPassMethods(info *symbol.ClassInfo) map[*parser.ClassMethod]bool {
+	if result, ok := g.selfPass[info]; ok {
+		return result
+	}
+	result := make(map[*parser.ClassMethod]bool)
+	g.selfPass[info] = result
+	for _, m := range info.Methods {
+		if g.needsSelfPass(m) {
+			result[m] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, m := range info.Methods {
+			if result[m] || m.Static || isGenericMethod(m) {
+				continue
+			}
+			if g.callsDispatched(info, m, result) {
+				result[m] = true
+				changed = true
+			}
+		}
+	}
+	return result
+}
+
+// callsDispatched 判断方法体中是否有需要分派或继续传递 _self 的 this.m(...) 或 super.m(...)
+// 重载方法不做解析，任一同名候选需要时即成立
+func (g *CodeGen) callsDispatched(info *symbol.ClassInfo, method *parser.ClassMethod, result map[*parser.ClassMethod]bool) bool {
+	found := false
+	inspectMethod(method, func(node parser.Node) bool {
+		if found {
+			return false
+		}
+		call, ok := node.(*parser.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Function.(*parser.SelectorExpr)
+		if !ok || sel.Safe {
+			return true
+		}
+		switch sel.X.(type) {
+		case *parser.ThisExpr:
+			owner, methods := g.dispatchCandidates(info, sel.Sel)
+			for _, m := range methods {
+				if g.isVirtualMethod(info, m) || g.ownerNeedsSelf(info, owner, m, result) {
+					found = true
+				}
+			}
+		case *parser.SuperExpr:
+			if parent := g.transpiler.parentClassInfo(info); parent != nil {
+				owner, methods := g.methodCandidates(parent.Package, parent.Name, sel.Sel, false)
+				for _, m := range methods {
+					if g.ownerNeedsSelf(info, owner, m, result) {
+						found = true
+					}
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// ownerNeedsSelf 判断 owner 的方法是否需要 self 传递，owner 是正在计算的类时查询中间结果
+func (g *CodeGen) ownerNeedsSelf(info *symbol.ClassInfo, owner *methodOwner, method *parser.ClassMethod, result map[*parser.ClassMethod]bool) bool {
+	if owner.pkg == info.Package && owner.name == info.Name {
+		return result[method]
+	}
+	return g.methodNeedsSelf(g.transpiler.table.GetClass(owner.pkg, owner.name), method)
+}
+
+// ownerMethodName 返回方法的 Go 名称，重载方法使用修饰名
+func ownerMethodName(owner *methodOwner, methods []*parser.ClassMethod, method *parser.ClassMethod) string {
+	if len(methods) > 1 {
+		return symbol.GenerateMangledName(method.Name, method.Params, owner.isPublic(method))
+	}
+	return symbol.ToGoName(method.Name, owner.isPublic(method))
+}
+
+// ownerCallArgs 生成方法调用的实参，带默认参数的方法传递 Opts 结构体
+func (g *CodeGen) ownerCallArgs(owner *methodOwner, methodName string, b *callBinding) string {
+	if hasDefaultParams(b.method) && !owner.isStruct {
+		return g.optsLiteral(owner.prefix(g)+owner.goName+"__"+methodName+"__Opts", b)
+	}
+	return strings.Join(g.boundArgs(b), ", ")
+}
+
+// methodSignatureText 生成方法在 Go 接口类型中的签名，如 Sound(int) string
+func (g *CodeGen) methodSignatureText(owner *methodOwner, methodName string, method *parser.ClassMethod) string {
+	var params []string
+	if hasDefaultParams(method) && !owner.isStruct {
+		params = append(params, owner.prefix(g)+owner.goName+"__"+methodName+"__Opts")
+	} else {
+		for _, p := range method.Params {
+			params = append(params, g.generateType(p.Type))
+		}
+	}
+	return methodName + "(" + strings.Join(params, ", ") + ")" + g.methodResultSignature(method)
+}
+
+// joinArgs 把 _self 放在实参之前
+func joinArgs(self, args string) string {
+	if args == "" {
+		return self
+	}
+	return self + ", " + args
+}
@@ -1,6 +1,9 @@
 package transpiler
 
 import (
+	"go/types"
+	"strings"
+
 	"github.com/tangzhangming/tugo/internal/config"
 	"github.com/tangzhangming/tugo/internal/i18n"
 	"github.com/tangzhangming/tugo/internal/parser"
@@ -17,12 +20,17 @@ type Transpiler struct {
 	classDecls        map[string]*parser.ClassDecl       // 类声明缓存 key: pkg.name
 	interfaceDecls    map[string]*parser.InterfaceDecl   // 接口声明缓存 key: pkg.name
 	structDecls       map[string]*parser.StructDecl      // 结构体声明缓存 key: pkg.name
+	enumDecls         map[string]*parser.EnumDecl        // 枚举声明缓存 key: pkg.name
 	errors            []string                           // 转译错误
+	warnings          []string                           // 转译警告（不阻止生成代码）
 	config            *config.Config                     // 项目配置
 	typeImports       map[string]string                  // 类型名到包名的映射 (User -> models)
 	currentFile       string                             // 当前文件名（不含路径和后缀）
 	skipValidation    bool                               // 跳过顶层语句验证（用于标准库）
 	currentParsedFile *parser.File                       // 当前正在处理的文件
+	goImporter        types.Importer                     // 加载 Go 包类型信息（implements io.Reader 等）
+	goPackages        map[string]*types.Package          // 已加载的 Go 包，加载失败时为 nil
+	extendClass       string                             // 正在校验可见性的扩展方法所扩展的类（this 的类型）
 }
 
 // AddError 添加转译错误
@@ -31,18 +39,31 @@ func (t *Transpiler) AddError(line, col int, msg string) {
 	t.errors = append(t.errors, formatted)
 }
 
+// AddWarning 添加转译警告
+func (t *Transpiler) AddWarning(line, col int, msg string) {
+	t.warnings = append(t.warnings, i18n.T(i18n.ErrGeneric, line, col, msg))
+}
+
+// Warnings 获取最近一次转译产生的警告
+func (t *Transpiler) Warnings() []string {
+	return t.warnings
+}
+
 // New 创建一个新的转译器
 func New(table *symbol.Table) *Transpiler {
-	return &Transpiler{
+	t := &Transpiler{
 		table:          table,
 		imports:        make(map[string]bool),
 		funcDecls:      make(map[string]*parser.FuncDecl),
 		classDecls:     make(map[string]*parser.ClassDecl),
 		interfaceDecls: make(map[string]*parser.InterfaceDecl),
 		structDecls:    make(map[string]*parser.StructDecl),
+		enumDecls:      make(map[string]*parser.EnumDecl),
 		errors:         []string{},
 		typeImports:    make(map[string]string),
 	}
+	t.registerBuiltins()
+	return t
 }
 
 // SetConfig 设置项目配置
@@ -82,6 +103,9 @@ func (t *Transpiler) PreloadDeclarations(files []*parser.File) {
 			case *parser.StructDecl:
 				key := pkg + "." + decl.Name
 				t.structDecls[key] = decl
+			case *parser.EnumDecl:
+				key := pkg + "." + decl.Name
+				t.enumDecls[key] = decl
 			}
 		}
 	}
@@ -98,6 +122,7 @@ func (t *Transpiler) TranspileFileWithName(file *parser.File, fileName string) (
 	t.imports = make(map[string]bool)
 	t.needFmt = false
 	t.errors = []string{}
+	t.warnings = nil
 	t.currentFile = fileName
 	t.currentParsedFile = file
 
@@ -116,6 +141,9 @@ func (t *Transpiler) TranspileFileWithName(file *parser.File, fileName string) (
 		case *parser.StructDecl:
 			key := t.pkg + "." + decl.Name
 			t.structDecls[key] = decl
+		case *parser.EnumDecl:
+			key := t.pkg + "." + decl.Name
+			t.enumDecls[key] = decl
 		}
 	}
 
@@ -138,9 +166,13 @@ func (t *Transpiler) TranspileFileWithName(file *parser.File, fileName string) (
 			if len(classDecl.Implements) > 0 {
 				t.validateImplements(classDecl)
 			}
+			t.validateGenericMethods(classDecl)
 			if classDecl.Extends != "" {
 				t.validateExtends(classDecl, file)
 			}
+			// 校验 override 修饰符和 super 的用法
+			t.validateOverrides(classDecl)
+			t.validateSuper(classDecl)
 			// 验证入口类的 main 方法
 			if t.IsEntryClass(classDecl) {
 				t.validateMainMethod(classDecl)
@@ -160,6 +192,9 @@ func (t *Transpiler) TranspileFileWithName(file *parser.File, fileName string) (
 
 	// 校验 errable 函数调用
 	t.validateErrableCalls(file)
+
+	// 校验有返回值的函数在所有路径上都有返回
+	t.validateReturns(file)
 	
 	// 校验方法/字段访问的可见性
 	t.validateVisibility(file)
@@ -172,11 +207,24 @@ func (t *Transpiler) TranspileFileWithName(file *parser.File, fileName string) (
 	// 校验未使用的导入
 	t.validateUnusedImports(file)
 
+	// 校验用户代码没有使用编译器保留的名称
+	t.validateReservedNames(file)
+
+	// 检查规则（未使用的私有方法和局部变量等）- 标准库跳过
+	if !t.skipValidation {
+		t.lintFile(file)
+	}
+
 	// 如果有错误，返回错误
 	if len(t.errors) > 0 {
 		return "", &ImplementsError{Errors: t.errors}
 	}
 
+	// 常量折叠和死分支消除
+	if t.config != nil && t.config.Build.Optimize {
+		t.optimizeFile(file)
+	}
+
 	gen := NewCodeGen(t)
 	code := gen.Generate(file)
 	
@@ -184,8 +232,18 @@ func (t *Transpiler) TranspileFileWithName(file *parser.File, fileName string) (
 	if len(t.errors) > 0 {
 		return "", &ImplementsError{Errors: t.errors}
 	}
+
+	// 格式化生成的代码并移除未使用的导入
+	// 生成的代码无法解析说明代码生成器有 bug，报告为内部错误，不写入磁盘
+	formatted, err := formatGoSource(code)
+	if err != nil {
+		goErr := err.(*goSourceError)
+		line, col := gen.SourcePos(goErr.line)
+		t.AddError(line, col, i18n.T(i18n.ErrInternalCodegen, goErr.line, goErr.msg))
+		return "", &ImplementsError{Errors: t.errors}
+	}
 	
-	return code, nil
+	return formatted, nil
 }
 
 // GetCurrentFile 获取当前文件名
@@ -261,6 +319,12 @@ func (t *Transpiler) GetStructDecl(pkg, name string) *parser.StructDecl {
 	return t.structDecls[key]
 }
 
+// GetEnumDecl 获取枚举声明
+func (t *Transpiler) GetEnumDecl(pkg, name string) *parser.EnumDecl {
+	key := pkg + "." + name
+	return t.enumDecls[key]
+}
+
 // AddImport 添加需要导入的包
 func (t *Transpiler) AddImport(pkg string) {
 	t.imports[pkg] = true
@@ -343,21 +407,27 @@ func (e *ImplementsError) Error() string {
 
 // validateImplements 校验类是否正确实现了所有声明的接口
 func (t *Transpiler) validateImplements(classDecl *parser.ClassDecl) {
-	// 收集类的所有方法签名
-	classMethods := make(map[string]*parser.ClassMethod)
+	// 泛型方法生成为包级函数，不能用来实现接口
+	var methods []*parser.ClassMethod
 	for _, method := range classDecl.Methods {
-		classMethods[method.Name] = method
+		if !isGenericMethod(method) {
+			methods = append(methods, method)
+		}
 	}
 
 	// 遍历每个需要实现的接口
 	for _, ifaceName := range classDecl.Implements {
-		// 查找接口定义
-		ifaceInfo := t.table.GetInterface(t.pkg, ifaceName)
+		// 查找接口定义（当前包、导入的 tugo 包或 Go 包）
+		ifaceInfo, isGo, skip := t.lookupInterface(ifaceName)
+		if skip {
+			continue
+		}
 		if ifaceInfo == nil {
 			t.errors = append(t.errors, i18n.T(i18n.ErrInterfaceNotFound,
 				classDecl.Name, ifaceName))
 			continue
 		}
+		classMethods := implementingMethods(methods, isGo)
 
 		// 检查接口的每个方法
 		for _, ifaceMethod := range ifaceInfo.Methods {
@@ -378,41 +448,46 @@ func (t *Transpiler) validateImplements(classDecl *parser.ClassDecl) {
 
 // validateMethodSignature 校验方法签名是否匹配
 func (t *Transpiler) validateMethodSignature(className, ifaceName string, method *parser.ClassMethod, sig *parser.FuncSignature) string {
-	// 检查参数数量
-	if len(method.Params) != len(sig.Params) {
-		return i18n.T(i18n.ErrParamCountMismatch,
-			className, method.Name, len(method.Params), ifaceName, len(sig.Params))
+	// 检查参数和返回值（数量不一致时同样给出两边的签名）
+	got, want := classMethodSig(method), funcSignatureSig(sig)
+	if detail := compareSignatures(got, want); detail != "" {
+		return i18n.T(i18n.ErrSignatureMismatch,
+			className, method.Name, ifaceName, got.String(), want.String(), detail)
 	}
 
-	// 检查返回值数量
-	if len(method.Results) != len(sig.Results) {
-		return i18n.T(i18n.ErrReturnCountMismatch,
-			className, method.Name, len(method.Results), ifaceName, len(sig.Results))
-	}
-
-	// 注意：类型匹配校验需要更复杂的类型比较，这里做简化处理
-	// 实际上应该比较每个参数和返回值的类型是否一致
-
 	return ""
 }
 
-// validateStructImplements 校验结构体是否正确实现了接口
-func (t *Transpiler) validateStructImplements(structDecl *parser.StructDecl) {
-	// 收集结构体的所有方法签名
-	structMethods := make(map[string]*parser.ClassMethod)
-	for _, method := range structDecl.Methods {
-		structMethods[method.Name] = method
+// implementingMethods 按接口方法名索引实现方法
+// Go 接口的方法名是导出名（如 Read），此时按方法翻译后的 Go 名称索引
+func implementingMethods(methods []*parser.ClassMethod, goNames bool) map[string]*parser.ClassMethod {
+	result := make(map[string]*parser.ClassMethod)
+	for _, method := range methods {
+		name := method.Name
+		if goNames {
+			isPublic := method.Visibility == "public" || method.Visibility == "protected"
+			name = symbol.ToGoName(method.Name, isPublic)
+		}
+		result[name] = method
 	}
+	return result
+}
 
+// validateStructImplements 校验结构体是否正确实现了接口
+func (t *Transpiler) validateStructImplements(structDecl *parser.StructDecl) {
 	// 遍历每个需要实现的接口
 	for _, ifaceName := range structDecl.Implements {
-		// 查找接口定义
-		ifaceInfo := t.table.GetInterface(t.pkg, ifaceName)
+		// 查找接口定义（当前包、导入的 tugo 包或 Go 包）
+		ifaceInfo, isGo, skip := t.lookupInterface(ifaceName)
+		if skip {
+			continue
+		}
 		if ifaceInfo == nil {
 			t.errors = append(t.errors, i18n.T(i18n.ErrStructInterfaceNotFound,
 				structDecl.Name, ifaceName))
 			continue
 		}
+		structMethods := implementingMethods(structDecl.Methods, isGo)
 
 		// 检查接口的每个方法
 		for _, ifaceMethod := range ifaceInfo.Methods {
@@ -433,16 +508,11 @@ func (t *Transpiler) validateStructImplements(structDecl *parser.StructDecl) {
 
 // validateStructMethodSignature 校验结构体方法签名是否匹配
 func (t *Transpiler) validateStructMethodSignature(structName, ifaceName string, method *parser.ClassMethod, sig *parser.FuncSignature) string {
-	// 检查参数数量
-	if len(method.Params) != len(sig.Params) {
-		return i18n.T(i18n.ErrStructParamMismatch,
-			structName, method.Name, len(method.Params), ifaceName, len(sig.Params))
-	}
-
-	// 检查返回值数量
-	if len(method.Results) != len(sig.Results) {
-		return i18n.T(i18n.ErrStructReturnMismatch,
-			structName, method.Name, len(method.Results), ifaceName, len(sig.Results))
+	// 检查参数和返回值（数量不一致时同样给出两边的签名）
+	got, want := classMethodSig(method), funcSignatureSig(sig)
+	if detail := compareSignatures(got, want); detail != "" {
+		return i18n.T(i18n.ErrStructSignatureMismatch,
+			structName, method.Name, ifaceName, got.String(), want.String(), detail)
 	}
 
 	return ""
@@ -450,12 +520,11 @@ func (t *Transpiler) validateStructMethodSignature(structName, ifaceName string,
 
 // validateExtends 校验子类是否正确实现了父类的所有抽象方法
 func (t *Transpiler) validateExtends(classDecl *parser.ClassDecl, file *parser.File) {
-	// 查找父类信息
-	parentInfo := t.table.GetClass(t.pkg, classDecl.Extends)
+	// 查找父类信息（当前包或导入的包，如 db.Model）
+	parentInfo, imported := t.lookupClass(classDecl.Extends)
 	if parentInfo == nil {
-		// 检查是否是通过 use 导入的外部类（如 tugo.db.model）
-		// 外部类无法验证，跳过检查
-		if t.isImportedType(classDecl.Extends, file) {
+		// 导入的外部类所在的包没有加载到符号表，无法验证，跳过检查
+		if imported || t.isImportedType(classDecl.Extends, file) {
 			return
 		}
 		t.errors = append(t.errors, i18n.T(i18n.ErrParentClassNotFound,
@@ -463,6 +532,12 @@ func (t *Transpiler) validateExtends(classDecl *parser.ClassDecl, file *parser.F
 		return
 	}
 
+	// 密封类的子类必须在同一个包中，match 才能列出全部子类
+	if parentInfo.Sealed && parentInfo.Package != t.pkg {
+		t.errors = append(t.errors, i18n.T(i18n.ErrSealedExtends,
+			classDecl.Name, classDecl.Extends, parentInfo.Package))
+	}
+
 	// 只有抽象类需要检查方法实现
 	if !parentInfo.Abstract {
 		// 普通类继承：允许，无需额外检查
@@ -522,16 +597,11 @@ func (t *Transpiler) isImportedType(typeName string, file *parser.File) bool {
 
 // validateAbstractMethodSignature 校验抽象方法签名是否匹配
 func (t *Transpiler) validateAbstractMethodSignature(className, parentName string, method *parser.ClassMethod, abstractMethod *parser.ClassMethod) string {
-	// 检查参数数量
-	if len(method.Params) != len(abstractMethod.Params) {
-		return i18n.T(i18n.ErrAbstractParamMismatch,
-			className, method.Name, len(method.Params), parentName, len(abstractMethod.Params))
-	}
-
-	// 检查返回值数量
-	if len(method.Results) != len(abstractMethod.Results) {
-		return i18n.T(i18n.ErrAbstractReturnMismatch,
-			className, method.Name, len(method.Results), parentName, len(abstractMethod.Results))
+	// 检查参数和返回值（数量不一致时同样给出两边的签名）
+	got, want := classMethodSig(method), classMethodSig(abstractMethod)
+	if detail := compareSignatures(got, want); detail != "" {
+		return i18n.T(i18n.ErrAbstractSignatureMismatch,
+			className, method.Name, parentName, got.String(), want.String(), detail)
 	}
 
 	return ""
@@ -621,6 +691,12 @@ func (t *Transpiler) exprContainsThis(expr parser.Expression) bool {
 		return t.exprContainsThis(e.Left) || t.exprContainsThis(e.Right)
 	case *parser.UnaryExpr:
 		return t.exprContainsThis(e.Operand)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			if t.exprContainsThis(part.Expr) {
+				return true
+			}
+		}
 	case *parser.CallExpr:
 		if t.exprContainsThis(e.Function) {
 			return true
@@ -634,6 +710,18 @@ func (t *Transpiler) exprContainsThis(expr parser.Expression) bool {
 		return t.exprContainsThis(e.X)
 	case *parser.IndexExpr:
 		return t.exprContainsThis(e.X) || t.exprContainsThis(e.Index)
+	case *parser.MatchExpr:
+		if t.exprContainsThis(e.Subject) {
+			return true
+		}
+		for _, arm := range e.Arms {
+			if t.exprContainsThis(arm.Guard) || t.exprContainsThis(arm.Body) {
+				return true
+			}
+			if arm.Block != nil && t.containsThis(arm.Block) {
+				return true
+			}
+		}
 	}
 	return false
 }
@@ -648,6 +736,10 @@ func (t *Transpiler) validateTopLevelStatements(file *parser.File) {
 			// 允许
 		case *parser.StructDecl:
 			// 允许
+		case *parser.EnumDecl:
+			// 允许
+		case *parser.ExtendDecl:
+			// 允许
 		case *parser.InterfaceDecl:
 			// 允许
 		case *parser.TypeDecl:
@@ -767,6 +859,18 @@ func (t *Transpiler) validateErrableCalls(file *parser.File) {
 			if s.InitMethod != nil && s.InitMethod.Body != nil {
 				t.validateErrableCallsInFunc(s.Name+".init", s.InitMethod.Errable, s.InitMethod.Body)
 			}
+		case *parser.EnumDecl:
+			for _, method := range s.Methods {
+				if method.Body != nil {
+					t.validateErrableCallsInFunc(s.Name+"."+method.Name, method.Errable, method.Body)
+				}
+			}
+		case *parser.ExtendDecl:
+			for _, method := range s.Methods {
+				if method.Body != nil {
+					t.validateErrableCallsInFunc(typeText(s.Type)+"."+method.Name, method.Errable, method.Body)
+				}
+			}
 		}
 	}
 }
@@ -793,9 +897,12 @@ func (t *Transpiler) validateErrableCallsInStmt(funcName string, funcIsErrable b
 		if s.Body != nil {
 			t.validateErrableCallsInBlock(funcName, funcIsErrable, true, s.Body)
 		}
-		// catch 块内的调用也需要检查（catch 块不是 try 块）
-		if s.Catch != nil && s.Catch.Body != nil {
-			t.validateErrableCallsInBlock(funcName, funcIsErrable, inTryBlock, s.Catch.Body)
+		// catch 和 finally 块内的调用也需要检查（它们不是 try 块）
+		for _, c := range s.Catches {
+			t.validateErrableCallsInBlock(funcName, funcIsErrable, inTryBlock, c.Body)
+		}
+		if s.Finally != nil {
+			t.validateErrableCallsInBlock(funcName, funcIsErrable, inTryBlock, s.Finally)
 		}
 	case *parser.ReturnStmt:
 		for _, v := range s.Values {
@@ -842,6 +949,31 @@ func (t *Transpiler) validateErrableCallsInStmt(funcName string, funcIsErrable b
 	}
 }
 
+// isErrableStaticCall 判断 ClassName::method 的目标是否是 errable 静态方法（包括 Enum::parse）
+// self:: 的目标类由调用方给出，不知道时为 nil
+func (t *Transpiler) isErrableStaticCall(access *parser.StaticAccessExpr, self *parser.ClassDecl) bool {
+	if t.isEnumParse(access) {
+		return true
+	}
+	var methods []*parser.ClassMethod
+	if _, ok := access.Left.(*parser.SelfExpr); ok {
+		if self == nil {
+			return false
+		}
+		methods = self.Methods
+	} else if ident, _ := staticAccessTarget(access.Left); ident != nil {
+		if info, _ := t.lookupClass(ident.Value); info != nil {
+			methods = info.Methods
+		}
+	}
+	for _, method := range methods {
+		if method.Static && method.Name == access.Member && method.Errable {
+			return true
+		}
+	}
+	return false
+}
+
 // validateErrableCallsInExpr 在表达式中验证 errable 调用
 func (t *Transpiler) validateErrableCallsInExpr(funcName string, funcIsErrable bool, inTryBlock bool, expr parser.Expression) {
 	if expr == nil {
@@ -849,6 +981,8 @@ func (t *Transpiler) validateErrableCallsInExpr(funcName string, funcIsErrable b
 	}
 
 	switch e := expr.(type) {
+	case *parser.NamedArg:
+		t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, e.Value)
 	case *parser.CallExpr:
 		// 检查被调用的函数是否是 errable
 		if ident, ok := e.Function.(*parser.Identifier); ok {
@@ -861,22 +995,33 @@ func (t *Transpiler) validateErrableCallsInExpr(funcName string, funcIsErrable b
 						funcName, ident.Value))
 				}
 			}
-		} else if sel, ok := e.Function.(*parser.SelectorExpr); ok {
-			// 方法调用 obj.method()
+		} else if sel, _ := methodCallSelector(e.Function); sel != nil {
+			// 方法调用 obj.method() 或 obj.method[T]()
 			methodName := sel.Sel
 			// 尝试查找方法符号（需要知道接收者类型，这里简化处理）
-			// 遍历所有符号查找匹配的方法
+			// 遍历所有符号和扩展方法查找匹配的方法
+			errable := false
 			for _, sym := range t.table.GetAll() {
 				if sym.Kind == symbol.SymbolClassMethod || sym.Kind == symbol.SymbolMethod {
 					if sym.Name == methodName && sym.Errable {
-						if !inTryBlock && !funcIsErrable {
-							t.errors = append(t.errors, i18n.T(i18n.ErrErrableMethodNotHandled,
-								funcName, methodName))
-						}
+						errable = true
 						break
 					}
 				}
 			}
+			for _, ext := range t.table.GetExtensions(methodName) {
+				errable = errable || ext.Method.Errable
+			}
+			if errable && !inTryBlock && !funcIsErrable {
+				t.errors = append(t.errors, i18n.T(i18n.ErrErrableMethodNotHandled,
+					funcName, methodName))
+			}
+		} else if access, ok := e.Function.(*parser.StaticAccessExpr); ok && t.isErrableStaticCall(access, nil) {
+			if !inTryBlock && !funcIsErrable {
+				ident, _ := staticAccessTarget(access.Left)
+				t.errors = append(t.errors, i18n.T(i18n.ErrErrableNotHandled,
+					funcName, ident.Value+"::"+access.Member))
+			}
 		}
 
 		// 递归检查参数
@@ -888,11 +1033,24 @@ func (t *Transpiler) validateErrableCallsInExpr(funcName string, funcIsErrable b
 		t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, e.Right)
 	case *parser.UnaryExpr:
 		t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, e.Operand)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, part.Expr)
+		}
 	case *parser.IndexExpr:
 		t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, e.X)
 		t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, e.Index)
 	case *parser.SelectorExpr:
 		t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, e.X)
+	case *parser.MatchExpr:
+		t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, e.Subject)
+		for _, arm := range e.Arms {
+			t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, arm.Guard)
+			t.validateErrableCallsInExpr(funcName, funcIsErrable, inTryBlock, arm.Body)
+			if arm.Block != nil {
+				t.validateErrableCallsInBlock(funcName, funcIsErrable, inTryBlock, arm.Block)
+			}
+		}
 	}
 }
 
@@ -930,8 +1088,13 @@ func (t *Transpiler) validateUndefinedSymbols(file *parser.File) {
 			definedTypes[decl.Name] = true
 		case *parser.StructDecl:
 			definedTypes[decl.Name] = true
+		case *parser.EnumDecl:
+			definedTypes[decl.Name] = true
 		}
 	}
+	for _, name := range builtinClasses {
+		definedTypes[name] = true
+	}
 	
 	// 遍历所有语句，检查使用的类型
 	for _, stmt := range file.Statements {
@@ -963,6 +1126,18 @@ func (t *Transpiler) validateSymbolsInStmt(stmt parser.Statement, importedTypes,
 				t.validateSymbolsInBlock(method.Body, importedTypes, definedTypes)
 			}
 		}
+	case *parser.EnumDecl:
+		for _, method := range s.Methods {
+			if method.Body != nil {
+				t.validateSymbolsInBlock(method.Body, importedTypes, definedTypes)
+			}
+		}
+	case *parser.ExtendDecl:
+		for _, method := range s.Methods {
+			if method.Body != nil {
+				t.validateSymbolsInBlock(method.Body, importedTypes, definedTypes)
+			}
+		}
 	}
 }
 
@@ -1021,9 +1196,19 @@ func (t *Transpiler) validateSymbolsInBlockStmt(stmt parser.Statement, importedT
 		}
 	case *parser.TryStmt:
 		t.validateSymbolsInBlock(s.Body, importedTypes, definedTypes)
-		if s.Catch != nil && s.Catch.Body != nil {
-			t.validateSymbolsInBlock(s.Catch.Body, importedTypes, definedTypes)
+		for _, c := range s.Catches {
+			typ := c.Type
+			if ptr, ok := typ.(*parser.PointerType); ok {
+				typ = ptr.Base
+			}
+			if ident, ok := typ.(*parser.Identifier); ok {
+				if !importedTypes[ident.Value] && !definedTypes[ident.Value] && !t.isBuiltinType(ident.Value) {
+					t.errors = append(t.errors, i18n.T(i18n.ErrUndefinedType, ident.Value))
+				}
+			}
+			t.validateSymbolsInBlock(c.Body, importedTypes, definedTypes)
 		}
+		t.validateSymbolsInBlock(s.Finally, importedTypes, definedTypes)
 	case *parser.ThrowStmt:
 		t.validateSymbolsInExpr(s.Value, importedTypes, definedTypes)
 	}
@@ -1036,6 +1221,8 @@ func (t *Transpiler) validateSymbolsInExpr(expr parser.Expression, importedTypes
 	}
 	
 	switch e := expr.(type) {
+	case *parser.NamedArg:
+		t.validateSymbolsInExpr(e.Value, importedTypes, definedTypes)
 	case *parser.CallExpr:
 		// 检查构造函数调用 new Type()
 		if newExpr, ok := e.Function.(*parser.NewExpr); ok {
@@ -1067,18 +1254,34 @@ func (t *Transpiler) validateSymbolsInExpr(expr parser.Expression, importedTypes
 		t.validateSymbolsInExpr(e.Right, importedTypes, definedTypes)
 	case *parser.UnaryExpr:
 		t.validateSymbolsInExpr(e.Operand, importedTypes, definedTypes)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			t.validateSymbolsInExpr(part.Expr, importedTypes, definedTypes)
+		}
 	case *parser.IndexExpr:
 		t.validateSymbolsInExpr(e.X, importedTypes, definedTypes)
 		t.validateSymbolsInExpr(e.Index, importedTypes, definedTypes)
 	case *parser.SelectorExpr:
 		t.validateSymbolsInExpr(e.X, importedTypes, definedTypes)
 	case *parser.StaticAccessExpr:
-		// 静态访问 Str::ToUpper, 检查类名是否已导入或定义
-		if ident, ok := e.Left.(*parser.Identifier); ok {
+		// 静态访问 Str::ToUpper 或 Builder[User]::query, 检查类名是否已导入或定义
+		if ident, typeArgs := staticAccessTarget(e.Left); ident != nil {
 			typeName := ident.Value
 			if !importedTypes[typeName] && !definedTypes[typeName] && !t.isBuiltinType(typeName) {
 				t.errors = append(t.errors, i18n.T(i18n.ErrUndefinedType, typeName))
 			}
+			for _, arg := range typeArgs {
+				t.validateSymbolsInExpr(arg, importedTypes, definedTypes)
+			}
+		}
+	case *parser.MatchExpr:
+		t.validateSymbolsInExpr(e.Subject, importedTypes, definedTypes)
+		for _, arm := range e.Arms {
+			t.validateSymbolsInExpr(arm.Guard, importedTypes, definedTypes)
+			t.validateSymbolsInExpr(arm.Body, importedTypes, definedTypes)
+			if arm.Block != nil {
+				t.validateSymbolsInBlock(arm.Block, importedTypes, definedTypes)
+			}
 		}
 	}
 }
@@ -1126,15 +1329,44 @@ func (t *Transpiler) validateUnusedImports(file *parser.File) {
 	// 检查代码中是否使用了这些类型
 	usedTypes := make(map[string]bool)
 	t.collectUsedTypes(file, usedTypes)
+
+	// 导入的扩展方法按方法名使用
+	inspect(file, func(node parser.Node) bool {
+		if call, ok := node.(*parser.CallExpr); ok {
+			if sel, _ := methodCallSelector(call.Function); sel != nil && len(t.table.GetExtensions(sel.Sel)) > 0 {
+				usedTypes[sel.Sel] = true
+			}
+		}
+		return true
+	})
 	
-	// 检查未使用的导入
-	for typeName, spec := range importedTypes {
-		if !usedTypes[typeName] {
-			t.errors = append(t.errors, i18n.T(i18n.ErrUnusedImport, typeName, spec.Path))
+	// 检查未使用的导入（按源码顺序报告）
+	for _, imp := range file.Imports {
+		for _, spec := range imp.Specs {
+			if spec.IsGoImport {
+				continue
+			}
+			typeName := spec.TypeName
+			if spec.Alias != "" {
+				typeName = spec.Alias
+			}
+			if importedTypes[typeName] == spec && !usedTypes[typeName] {
+				t.lint(ruleUnusedImport, imp.Token.Line, imp.Token.Column,
+					i18n.T(i18n.ErrUnusedImport, typeName, spec.Path))
+			}
 		}
 	}
 }
 
+// markUsedTypeName 标记 extends/implements 中使用的类型
+// 包限定名 models.Shape 同时标记类型名 Shape（导入以类型名为键）
+func markUsedTypeName(name string, usedTypes map[string]bool) {
+	usedTypes[name] = true
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		usedTypes[name[dot+1:]] = true
+	}
+}
+
 // collectUsedTypes 收集代码中使用的类型
 func (t *Transpiler) collectUsedTypes(file *parser.File, usedTypes map[string]bool) {
 	for _, stmt := range file.Statements {
@@ -1152,11 +1384,11 @@ func (t *Transpiler) collectUsedTypesInStmt(stmt parser.Statement, usedTypes map
 	case *parser.ClassDecl:
 		// 检查父类（extends）
 		if s.Extends != "" {
-			usedTypes[s.Extends] = true
+			markUsedTypeName(s.Extends, usedTypes)
 		}
 		// 检查实现的接口
 		for _, impl := range s.Implements {
-			usedTypes[impl] = true
+			markUsedTypeName(impl, usedTypes)
 		}
 		// 检查类中的方法
 		for _, method := range s.Methods {
@@ -1169,6 +1401,22 @@ func (t *Transpiler) collectUsedTypesInStmt(stmt parser.Statement, usedTypes map
 			t.collectUsedTypesInBlock(s.Body, usedTypes)
 		}
 	case *parser.StructDecl:
+		for _, impl := range s.Implements {
+			markUsedTypeName(impl, usedTypes)
+		}
+		for _, method := range s.Methods {
+			if method.Body != nil {
+				t.collectUsedTypesInBlock(method.Body, usedTypes)
+			}
+		}
+	case *parser.EnumDecl:
+		for _, method := range s.Methods {
+			if method.Body != nil {
+				t.collectUsedTypesInBlock(method.Body, usedTypes)
+			}
+		}
+	case *parser.ExtendDecl:
+		t.collectTypeNameFromExpr(s.Type, usedTypes)
 		for _, method := range s.Methods {
 			if method.Body != nil {
 				t.collectUsedTypesInBlock(method.Body, usedTypes)
@@ -1236,9 +1484,13 @@ func (t *Transpiler) collectUsedTypesInBlockStmt(stmt parser.Statement, usedType
 		}
 	case *parser.TryStmt:
 		t.collectUsedTypesInBlock(s.Body, usedTypes)
-		if s.Catch != nil && s.Catch.Body != nil {
-			t.collectUsedTypesInBlock(s.Catch.Body, usedTypes)
+		for _, c := range s.Catches {
+			if c.Type != nil {
+				t.collectTypeNameFromExpr(c.Type, usedTypes)
+			}
+			t.collectUsedTypesInBlock(c.Body, usedTypes)
 		}
+		t.collectUsedTypesInBlock(s.Finally, usedTypes)
 	case *parser.ThrowStmt:
 		t.collectUsedTypesInExpr(s.Value, usedTypes)
 	}
@@ -1251,6 +1503,8 @@ func (t *Transpiler) collectUsedTypesInExpr(expr parser.Expression, usedTypes ma
 	}
 	
 	switch e := expr.(type) {
+	case *parser.NamedArg:
+		t.collectUsedTypesInExpr(e.Value, usedTypes)
 	case *parser.CallExpr:
 		// 检查构造函数调用 new Type()
 		if newExpr, ok := e.Function.(*parser.NewExpr); ok {
@@ -1269,15 +1523,22 @@ func (t *Transpiler) collectUsedTypesInExpr(expr parser.Expression, usedTypes ma
 		// new 表达式
 		t.collectTypeNameFromExpr(e.Type, usedTypes)
 	case *parser.StaticAccessExpr:
-		// 静态方法调用 Type::method
-		if ident, ok := e.Left.(*parser.Identifier); ok {
+		// 静态方法调用 Type::method 或 Type[T]::method
+		if ident, typeArgs := staticAccessTarget(e.Left); ident != nil {
 			usedTypes[ident.Value] = true
+			for _, arg := range typeArgs {
+				t.collectTypeNameFromExpr(arg, usedTypes)
+			}
 		}
 	case *parser.BinaryExpr:
 		t.collectUsedTypesInExpr(e.Left, usedTypes)
 		t.collectUsedTypesInExpr(e.Right, usedTypes)
 	case *parser.UnaryExpr:
 		t.collectUsedTypesInExpr(e.Operand, usedTypes)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			t.collectUsedTypesInExpr(part.Expr, usedTypes)
+		}
 	case *parser.IndexExpr:
 		t.collectUsedTypesInExpr(e.X, usedTypes)
 		t.collectUsedTypesInExpr(e.Index, usedTypes)
@@ -1289,11 +1550,41 @@ func (t *Transpiler) collectUsedTypesInExpr(expr parser.Expression, usedTypes ma
 		for _, field := range e.Fields {
 			t.collectUsedTypesInExpr(field.Value, usedTypes)
 		}
+	case *parser.MatchExpr:
+		t.collectUsedTypesInExpr(e.Subject, usedTypes)
+		for _, arm := range e.Arms {
+			for _, pattern := range arm.Patterns {
+				t.collectUsedTypesInPattern(pattern, usedTypes)
+			}
+			t.collectUsedTypesInExpr(arm.Guard, usedTypes)
+			t.collectUsedTypesInExpr(arm.Body, usedTypes)
+			if arm.Block != nil {
+				t.collectUsedTypesInBlock(arm.Block, usedTypes)
+			}
+		}
 	case *parser.Identifier:
 		// 标识符可能是类型或变量，这里不处理（由其他地方处理）
 	}
 }
 
+// collectUsedTypesInPattern 收集 match 模式中使用的类型（类型模式 Circle、c Circle、Point{x: 0}）
+func (t *Transpiler) collectUsedTypesInPattern(pattern parser.Expression, usedTypes map[string]bool) {
+	switch p := pattern.(type) {
+	case *parser.Identifier:
+		usedTypes[p.Value] = true
+	case *parser.TypePattern:
+		t.collectTypeNameFromExpr(p.Type, usedTypes)
+		for _, field := range p.Fields {
+			t.collectUsedTypesInPattern(field.Value, usedTypes)
+		}
+	case *parser.RangePattern:
+		t.collectUsedTypesInExpr(p.Low, usedTypes)
+		t.collectUsedTypesInExpr(p.High, usedTypes)
+	default:
+		t.collectUsedTypesInExpr(pattern, usedTypes)
+	}
+}
+
 // collectTypeNameFromExpr 从类型表达式中提取类型名（支持泛型类型）
 func (t *Transpiler) collectTypeNameFromExpr(typeExpr parser.Expression, usedTypes map[string]bool) {
 	if typeExpr == nil {
@@ -1312,58 +1603,15 @@ func (t *Transpiler) collectTypeNameFromExpr(typeExpr parser.Expression, usedTyp
 }
 
 // validateOverloads 验证类方法重载的合法性
-// 检查是否有重复的参数签名
+// 参数类型相同的重载（包括仅返回类型不同的）无法区分
 func (t *Transpiler) validateOverloads(classDecl *parser.ClassDecl) {
-	// 按方法名分组
-	methodsByName := make(map[string][]*parser.ClassMethod)
-	for _, method := range classDecl.Methods {
-		methodsByName[method.Name] = append(methodsByName[method.Name], method)
-	}
-
-	// 检查每个方法名组
-	for methodName, methods := range methodsByName {
-		if len(methods) <= 1 {
-			continue // 没有重载
-		}
-
-		// 检查签名唯一性
-		signatures := make(map[string]bool)
-		for _, method := range methods {
-			sig := symbol.GenerateParamSignature(method.Params)
-			if signatures[sig] {
-				t.errors = append(t.errors, i18n.T(i18n.ErrDuplicateOverloadSignature,
-					classDecl.Name, methodName, sig))
-			}
-			signatures[sig] = true
-		}
-	}
+	t.validateOverloadSet(classDecl.Name, classDecl.Methods)
+	t.validateOverloadSet(classDecl.Name, classDecl.InitMethods)
 }
 
 // validateStructOverloads 验证结构体方法重载的合法性
 func (t *Transpiler) validateStructOverloads(structDecl *parser.StructDecl) {
-	// 按方法名分组
-	methodsByName := make(map[string][]*parser.ClassMethod)
-	for _, method := range structDecl.Methods {
-		methodsByName[method.Name] = append(methodsByName[method.Name], method)
-	}
-
-	// 检查每个方法名组
-	for methodName, methods := range methodsByName {
-		if len(methods) <= 1 {
-			continue // 没有重载
-		}
-
-		// 检查签名唯一性
-		signatures := make(map[string]bool)
-		for _, method := range methods {
-			sig := symbol.GenerateParamSignature(method.Params)
-			if signatures[sig] {
-				t.errors = append(t.errors, i18n.T(i18n.ErrDuplicateOverloadSignature,
-					structDecl.Name, methodName, sig))
-			}
-			signatures[sig] = true
-		}
-	}
+	t.validateOverloadSet(structDecl.Name, structDecl.Methods)
 }
 
 // validateVisibility 校验方法/字段访问的可见性
@@ -1390,33 +1638,64 @@ func (t *Transpiler) validateVisibility(file *parser.File) {
 			// 检查类中所有方法的方法调用
 			for _, method := range s.Methods {
 				if method.Body != nil {
-					localVarTypes := make(map[string]string)
+					localVarTypes := paramVarTypes(method.Params)
 					t.validateVisibilityInBlock(s.Name, method.Body, localVarTypes, typeToPackage)
 				}
 			}
 			if s.InitMethod != nil && s.InitMethod.Body != nil {
-				localVarTypes := make(map[string]string)
+				localVarTypes := paramVarTypes(s.InitMethod.Params)
 				t.validateVisibilityInBlock(s.Name, s.InitMethod.Body, localVarTypes, typeToPackage)
 			}
 		case *parser.StructDecl:
 			for _, method := range s.Methods {
 				if method.Body != nil {
-					localVarTypes := make(map[string]string)
+					localVarTypes := paramVarTypes(method.Params)
 					t.validateVisibilityInBlock(s.Name, method.Body, localVarTypes, typeToPackage)
 				}
 			}
 			if s.InitMethod != nil && s.InitMethod.Body != nil {
-				localVarTypes := make(map[string]string)
+				localVarTypes := paramVarTypes(s.InitMethod.Params)
 				t.validateVisibilityInBlock(s.Name, s.InitMethod.Body, localVarTypes, typeToPackage)
 			}
 		case *parser.FuncDecl:
 			// 顶层函数（如 main）
 			if s.Body != nil {
-				localVarTypes := make(map[string]string)
+				localVarTypes := paramVarTypes(s.Params)
 				t.validateVisibilityInBlock("", s.Body, localVarTypes, typeToPackage)
 			}
+		case *parser.ExtendDecl:
+			// 扩展方法不属于被扩展的类，通过 this 只能访问公开成员
+			t.extendClass = paramVarTypes([]*parser.Field{{Name: "this", Type: s.Type}})["this"]
+			for _, method := range s.Methods {
+				if method.Body != nil {
+					localVarTypes := paramVarTypes(method.Params)
+					t.validateVisibilityInBlock("", method.Body, localVarTypes, typeToPackage)
+				}
+			}
+			t.extendClass = ""
+		}
+	}
+}
+
+// paramVarTypes 用参数的类型初始化变量类型表（用于可见性检查）
+func paramVarTypes(params []*parser.Field) map[string]string {
+	varTypes := make(map[string]string)
+	for _, param := range params {
+		typ := param.Type
+		if nullable, ok := typ.(*parser.NullableType); ok {
+			typ = nullable.Base
+		}
+		if ptr, ok := typ.(*parser.PointerType); ok {
+			typ = ptr.Base
+		}
+		switch t := typ.(type) {
+		case *parser.Identifier:
+			varTypes[param.Name] = t.Value
+		case *parser.SelectorExpr:
+			varTypes[param.Name] = t.Sel
 		}
 	}
+	return varTypes
 }
 
 // validateVisibilityInBlock 在代码块中验证可见性
@@ -1436,9 +1715,22 @@ func (t *Transpiler) validateVisibilityInStmt(callerClass string, stmt parser.St
 			t.validateVisibilityInExpr(callerClass, v, varTypes, typeToPackage)
 		}
 	case *parser.AssignStmt:
+		for _, l := range s.Left {
+			// 给属性赋值检查 setter，普通赋值不读取属性，只检查接收者
+			if sel, ok := l.(*parser.SelectorExpr); ok && t.checkPropertySetVisibility(callerClass, sel, varTypes, typeToPackage) && s.Token.Literal == "=" {
+				t.validateVisibilityInExpr(callerClass, sel.X, varTypes, typeToPackage)
+				continue
+			}
+			t.validateVisibilityInExpr(callerClass, l, varTypes, typeToPackage)
+		}
 		for _, r := range s.Right {
 			t.validateVisibilityInExpr(callerClass, r, varTypes, typeToPackage)
 		}
+	case *parser.IncDecStmt:
+		if sel, ok := s.X.(*parser.SelectorExpr); ok {
+			t.checkPropertySetVisibility(callerClass, sel, varTypes, typeToPackage)
+		}
+		t.validateVisibilityInExpr(callerClass, s.X, varTypes, typeToPackage)
 	case *parser.ShortVarDecl:
 		// 追踪变量类型
 		if len(s.Names) == 1 {
@@ -1481,8 +1773,11 @@ func (t *Transpiler) validateVisibilityInStmt(callerClass string, stmt parser.St
 		if s.Body != nil {
 			t.validateVisibilityInBlock(callerClass, s.Body, varTypes, typeToPackage)
 		}
-		if s.Catch != nil && s.Catch.Body != nil {
-			t.validateVisibilityInBlock(callerClass, s.Catch.Body, varTypes, typeToPackage)
+		for _, c := range s.Catches {
+			t.validateVisibilityInBlock(callerClass, c.Body, varTypes, typeToPackage)
+		}
+		if s.Finally != nil {
+			t.validateVisibilityInBlock(callerClass, s.Finally, varTypes, typeToPackage)
 		}
 	case *parser.BlockStmt:
 		t.validateVisibilityInBlock(callerClass, s, varTypes, typeToPackage)
@@ -1496,10 +1791,15 @@ func (t *Transpiler) validateVisibilityInExpr(callerClass string, expr parser.Ex
 	}
 
 	switch e := expr.(type) {
+	case *parser.NamedArg:
+		t.validateVisibilityInExpr(callerClass, e.Value, varTypes, typeToPackage)
 	case *parser.CallExpr:
 		// 检查方法调用
 		if sel, ok := e.Function.(*parser.SelectorExpr); ok {
 			t.checkMethodCallVisibility(callerClass, sel, e, varTypes, typeToPackage)
+			t.validateVisibilityInExpr(callerClass, sel.X, varTypes, typeToPackage)
+		} else {
+			t.validateVisibilityInExpr(callerClass, e.Function, varTypes, typeToPackage)
 		}
 		// 检查参数
 		for _, arg := range e.Arguments {
@@ -1507,12 +1807,19 @@ func (t *Transpiler) validateVisibilityInExpr(callerClass string, expr parser.Ex
 		}
 	case *parser.SelectorExpr:
 		// 检查字段访问（不是方法调用的情况）
+		t.checkFieldAccessVisibility(callerClass, e, varTypes, typeToPackage)
 		t.validateVisibilityInExpr(callerClass, e.X, varTypes, typeToPackage)
+	case *parser.StaticAccessExpr:
+		t.checkStaticAccessVisibility(callerClass, e, typeToPackage)
 	case *parser.BinaryExpr:
 		t.validateVisibilityInExpr(callerClass, e.Left, varTypes, typeToPackage)
 		t.validateVisibilityInExpr(callerClass, e.Right, varTypes, typeToPackage)
 	case *parser.UnaryExpr:
 		t.validateVisibilityInExpr(callerClass, e.Operand, varTypes, typeToPackage)
+	case *parser.InterpolatedString:
+		for _, part := range e.Parts {
+			t.validateVisibilityInExpr(callerClass, part.Expr, varTypes, typeToPackage)
+		}
 	case *parser.IndexExpr:
 		t.validateVisibilityInExpr(callerClass, e.X, varTypes, typeToPackage)
 		t.validateVisibilityInExpr(callerClass, e.Index, varTypes, typeToPackage)
@@ -1520,51 +1827,207 @@ func (t *Transpiler) validateVisibilityInExpr(callerClass string, expr parser.Ex
 		for _, arg := range e.Arguments {
 			t.validateVisibilityInExpr(callerClass, arg, varTypes, typeToPackage)
 		}
+	case *parser.MatchExpr:
+		t.validateVisibilityInExpr(callerClass, e.Subject, varTypes, typeToPackage)
+		for _, arm := range e.Arms {
+			t.validateVisibilityInExpr(callerClass, arm.Guard, varTypes, typeToPackage)
+			t.validateVisibilityInExpr(callerClass, arm.Body, varTypes, typeToPackage)
+			if arm.Block != nil {
+				t.validateVisibilityInBlock(callerClass, arm.Block, varTypes, typeToPackage)
+			}
+		}
 	}
 }
 
 // checkMethodCallVisibility 检查方法调用的可见性
+// private 方法只能在声明它的类中调用，protected 方法只能在声明它的类及其子类（可以在其他包中）中调用
 func (t *Transpiler) checkMethodCallVisibility(callerClass string, sel *parser.SelectorExpr, call *parser.CallExpr, varTypes map[string]string, typeToPackage map[string]string) {
-	methodName := sel.Sel
-
-	// 获取接收者的类型
-	receiverType := t.inferReceiverTypeWithVars(sel.X, varTypes, typeToPackage)
-	if receiverType == "" {
+	classInfo := t.selectorClassInfo(callerClass, sel.X, varTypes, typeToPackage)
+	if classInfo == nil {
 		return // 无法确定类型，跳过检查
 	}
+	owner, visibility := t.findClassMember(classInfo, sel.Sel, true)
+	if owner != nil {
+		t.checkMemberAccess(callerClass, owner, visibility, sel.Sel, i18n.ErrPrivateMethodAccess, i18n.ErrProtectedMethodAccess)
+	}
+}
 
-	// 如果调用者和被调用者是同一个类，允许访问私有方法
-	if receiverType == callerClass {
+// checkFieldAccessVisibility 检查字段访问的可见性，规则与方法相同
+func (t *Transpiler) checkFieldAccessVisibility(callerClass string, sel *parser.SelectorExpr, varTypes map[string]string, typeToPackage map[string]string) {
+	classInfo := t.selectorClassInfo(callerClass, sel.X, varTypes, typeToPackage)
+	if classInfo == nil {
 		return
 	}
+	owner, visibility := t.findClassMember(classInfo, sel.Sel, false)
+	if owner != nil {
+		t.checkMemberAccess(callerClass, owner, visibility, sel.Sel, i18n.ErrPrivateFieldAccess, i18n.ErrProtectedFieldAccess)
+		return
+	}
+	// 读取属性使用 getter 的可见性
+	if owner, prop := t.findClassProperty(classInfo, sel.Sel); prop != nil {
+		t.checkMemberAccess(callerClass, owner, prop.Getter.Visibility, sel.Sel, i18n.ErrPrivatePropertyAccess, i18n.ErrProtectedPropertyAccess)
+	}
+}
 
-	// 查找目标类的方法，检查可见性
-	// 首先在当前包中查找
-	classInfo := t.table.GetClass(t.pkg, receiverType)
+// checkPropertySetVisibility 检查给属性赋值时 setter 的可见性（只读属性由代码生成报告），返回目标是否是属性
+func (t *Transpiler) checkPropertySetVisibility(callerClass string, sel *parser.SelectorExpr, varTypes map[string]string, typeToPackage map[string]string) bool {
+	classInfo := t.selectorClassInfo(callerClass, sel.X, varTypes, typeToPackage)
 	if classInfo == nil {
-		// 尝试从类型导入映射中获取包名
-		if pkgName, ok := typeToPackage[receiverType]; ok {
-			classInfo = t.table.GetClass(pkgName, receiverType)
+		return false
+	}
+	owner, prop := t.findClassProperty(classInfo, sel.Sel)
+	if prop == nil {
+		return false
+	}
+	if prop.Setter != nil {
+		t.checkMemberAccess(callerClass, owner, prop.Setter.Visibility, sel.Sel, i18n.ErrPrivatePropertySet, i18n.ErrProtectedPropertySet)
+	}
+	return true
+}
+
+// checkStaticAccessVisibility 检查 ClassName::member 的可见性（self:: 总是访问当前类）
+func (t *Transpiler) checkStaticAccessVisibility(callerClass string, expr *parser.StaticAccessExpr, typeToPackage map[string]string) {
+	ident, _ := staticAccessTarget(expr.Left)
+	if ident == nil {
+		return
+	}
+	classInfo := t.classInfoByName(ident.Value, typeToPackage)
+	if classInfo == nil {
+		return
+	}
+	if owner, visibility := t.findClassMember(classInfo, expr.Member, true); owner != nil {
+		t.checkMemberAccess(callerClass, owner, visibility, expr.Member, i18n.ErrPrivateMethodAccess, i18n.ErrProtectedMethodAccess)
+	} else if owner, visibility := t.findClassMember(classInfo, expr.Member, false); owner != nil {
+		t.checkMemberAccess(callerClass, owner, visibility, expr.Member, i18n.ErrPrivateFieldAccess, i18n.ErrProtectedFieldAccess)
+	}
+}
+
+// checkMemberAccess 按成员的可见性检查调用者能否访问，owner 是声明该成员的类
+func (t *Transpiler) checkMemberAccess(callerClass string, owner *symbol.ClassInfo, visibility, member, privateKey, protectedKey string) {
+	// 声明成员的类可以访问所有成员
+	if owner.Package == t.pkg && owner.Name == callerClass {
+		return
+	}
+	callerName := callerClass
+	if callerName == "" && t.extendClass != "" {
+		callerName = "extend " + t.extendClass
+	} else if callerName == "" {
+		callerName = "main"
+	}
+	switch visibility {
+	case "private":
+		t.errors = append(t.errors, i18n.T(privateKey, callerName, owner.Name, member))
+	case "protected":
+		if !t.isSubclassOf(callerClass, owner) {
+			t.errors = append(t.errors, i18n.T(protectedKey, callerName, owner.Name, member))
 		}
 	}
+}
 
-	if classInfo != nil {
-		// 检查方法
-		for _, method := range classInfo.Methods {
-			if method.Name == methodName {
-				isPublic := method.Visibility == "public" || method.Visibility == "protected"
-				if !isPublic {
-					callerName := callerClass
-					if callerName == "" {
-						callerName = "main"
-					}
-					t.errors = append(t.errors, i18n.T(i18n.ErrPrivateMethodAccess,
-						callerName, receiverType, methodName))
+// selectorClassInfo 返回 x.member 中 x 的类信息，this 为调用者所在的类
+func (t *Transpiler) selectorClassInfo(callerClass string, x parser.Expression, varTypes map[string]string, typeToPackage map[string]string) *symbol.ClassInfo {
+	if _, ok := x.(*parser.ThisExpr); ok {
+		if callerClass == "" && t.extendClass != "" {
+			return t.classInfoByName(t.extendClass, typeToPackage)
+		}
+		if callerClass == "" {
+			return nil
+		}
+		return t.table.GetClass(t.pkg, callerClass)
+	}
+	receiverType := t.inferReceiverTypeWithVars(x, varTypes, typeToPackage)
+	if receiverType == "" {
+		return nil
+	}
+	return t.classInfoByName(receiverType, typeToPackage)
+}
+
+// classInfoByName 按当前包和导入查找类信息
+func (t *Transpiler) classInfoByName(name string, typeToPackage map[string]string) *symbol.ClassInfo {
+	if classInfo := t.table.GetClass(t.pkg, name); classInfo != nil {
+		return classInfo
+	}
+	if pkgName, ok := typeToPackage[name]; ok {
+		return t.table.GetClass(pkgName, name)
+	}
+	return nil
+}
+
+// findClassMember 在类及其父类链中查找方法或字段，返回声明该成员的类和成员的可见性
+func (t *Transpiler) findClassMember(classInfo *symbol.ClassInfo, name string, method bool) (*symbol.ClassInfo, string) {
+	for depth := 0; classInfo != nil && depth < maxInheritanceDepth; depth++ {
+		if method {
+			for _, m := range classInfo.Methods {
+				if m.Name == name {
+					return classInfo, m.Visibility
 				}
-				return
 			}
+		} else {
+			for _, f := range classInfo.Fields {
+				if f.Name == name {
+					return classInfo, f.Visibility
+				}
+			}
+		}
+		classInfo = t.parentClassInfo(classInfo)
+	}
+	return nil, ""
+}
+
+// findClassProperty 在类及其父类链中查找属性，返回声明该属性的类（同名字段优先，找到字段时返回 nil）
+func (t *Transpiler) findClassProperty(classInfo *symbol.ClassInfo, name string) (*symbol.ClassInfo, *parser.ClassProperty) {
+	for depth := 0; classInfo != nil && depth < maxInheritanceDepth; depth++ {
+		for _, f := range classInfo.Fields {
+			if f.Name == name {
+				return nil, nil
+			}
+		}
+		for _, prop := range classInfo.Properties {
+			if prop.Name == name {
+				return classInfo, prop
+			}
+		}
+		classInfo = t.parentClassInfo(classInfo)
+	}
+	return nil, nil
+}
+
+// isSubclassOf 检查当前包中的类是否是 ancestor 或其子类
+func (t *Transpiler) isSubclassOf(className string, ancestor *symbol.ClassInfo) bool {
+	if className == "" {
+		return false
+	}
+	return t.inheritsFrom(t.table.GetClass(t.pkg, className), ancestor)
+}
+
+// inheritsFrom 检查类是否是 ancestor 或其子类
+func (t *Transpiler) inheritsFrom(classInfo, ancestor *symbol.ClassInfo) bool {
+	for depth := 0; classInfo != nil && depth < maxInheritanceDepth; depth++ {
+		if classInfo.Package == ancestor.Package && classInfo.Name == ancestor.Name {
+			return true
 		}
+		classInfo = t.parentClassInfo(classInfo)
+	}
+	return false
+}
+
+// maxInheritanceDepth 遍历继承链的最大深度（防止循环继承导致死循环）
+const maxInheritanceDepth = 32
+
+// parentClassInfo 返回类的父类信息（父类所在的包在收集符号时确定）
+func (t *Transpiler) parentClassInfo(classInfo *symbol.ClassInfo) *symbol.ClassInfo {
+	if classInfo.Extends == "" {
+		return nil
+	}
+	name := classInfo.Extends
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	pkg := classInfo.ExtendsPkg
+	if pkg == "" {
+		pkg = classInfo.Package
 	}
+	return t.table.GetClass(pkg, name)
 }
 
 // inferExprType 推断表达式的类型（用于变量追踪）
@@ -1655,6 +2118,7 @@ func (t *Transpiler) GetExternalClass(pkgName, className string) *symbol.ClassIn
 			Fields:          classDecl.Fields,
 			Methods:         classDecl.Methods,
 			AbstractMethods: classDecl.AbstractMethods,
+			Properties:      classDecl.Properties,
 			InitMethod:      classDecl.InitMethod,
 		}
 	}
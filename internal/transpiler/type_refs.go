@@ -0,0 +1,328 @@
+package transpiler
+
+import (
+	"go/importer"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/tangzhangming/tugo/internal/parser"
+	"github.com/tangzhangming/tugo/internal/symbol"
+)
+
+// implements / extends 中引用的类型解析
+//
+// 支持的写法：
+//   - 当前包中的类型：implements Shape
+//   - import 导入的类型（含别名）：import "com.demo.models.Shape" 后 implements Shape
+//   - 包限定名：implements models.Shape、extends db.Model
+//   - Go 包中的接口：import "io" from golang 后 implements io.Reader
+//
+// Go 接口没有对应的 tugo 声明，通过 go/importer 从 Go 源码加载包的类型信息，
+// 再把接口方法转换为 tugo 的签名表示，与 tugo 接口使用同一套签名比较。
+// 无法加载类型信息（例如没有 Go 源码）时跳过校验，交给 Go 编译器检查。
+
+// typeRef 解析后的类型引用
+type typeRef struct {
+	pkg    string // 符号表中的包名（tugo 类型）
+	name   string // 类型名
+	goPath string // Go 包导入路径，非空表示 Go 包中的类型
+}
+
+// resolveTypeRef 解析 implements / extends 中的类型名，找不到来源时返回 false
+func (t *Transpiler) resolveTypeRef(name string) (typeRef, bool) {
+	var specs []*parser.ImportSpec
+	if t.currentParsedFile != nil {
+		for _, imp := range t.currentParsedFile.Imports {
+			specs = append(specs, imp.Specs...)
+		}
+	}
+
+	// 包限定名 pkg.Type
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		qualifier, typeName := name[:dot], name[dot+1:]
+		if qualifier == t.pkg {
+			return typeRef{pkg: t.pkg, name: typeName}, true
+		}
+		for _, spec := range specs {
+			if spec.IsGoImport {
+				if goImportName(spec) == qualifier {
+					return typeRef{name: typeName, goPath: spec.Path}, true
+				}
+			} else if spec.PkgName == qualifier {
+				return typeRef{pkg: spec.PkgName, name: typeName}, true
+			}
+		}
+		return typeRef{}, false
+	}
+
+	if t.table.Get(t.pkg, name) != nil {
+		return typeRef{pkg: t.pkg, name: name}, true
+	}
+	for _, spec := range specs {
+		if spec.IsGoImport {
+			continue
+		}
+		if spec.Alias == name || (spec.Alias == "" && spec.TypeName == name) {
+			return typeRef{pkg: spec.PkgName, name: spec.TypeName}, true
+		}
+	}
+	if t.builtinClass(name) != nil {
+		return typeRef{pkg: builtinPkg, name: name}, true
+	}
+	return typeRef{}, false
+}
+
+// goImportName 返回 Go 导入在代码中使用的包名（别名或路径最后一段）
+func goImportName(spec *parser.ImportSpec) string {
+	if spec.Alias != "" {
+		return spec.Alias
+	}
+	path := strings.Trim(spec.Path, "\"")
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// lookupInterface 查找 implements 中引用的接口
+// isGo 表示接口来自 Go 包（方法名按导出名匹配）；skip 表示无法加载 Go 类型信息，应跳过校验
+func (t *Transpiler) lookupInterface(name string) (info *symbol.InterfaceInfo, isGo, skip bool) {
+	ref, ok := t.resolveTypeRef(name)
+	if !ok {
+		if name == "error" {
+			// 预声明的 error 接口（implements error）
+			return goInterfaceInfo(name, "", types.Universe.Lookup(name).Type().Underlying().(*types.Interface)), true, false
+		}
+		return nil, false, false
+	}
+	if ref.goPath != "" {
+		info, loaded := t.goInterface(ref.goPath, ref.name)
+		return info, true, !loaded
+	}
+	return t.table.GetInterface(ref.pkg, ref.name), false, false
+}
+
+// lookupClass 查找 extends 中引用的父类
+// imported 表示父类来自其他包：包未加载到符号表时无法校验
+func (t *Transpiler) lookupClass(name string) (info *symbol.ClassInfo, imported bool) {
+	ref, ok := t.resolveTypeRef(name)
+	if !ok || ref.goPath != "" {
+		return nil, false
+	}
+	return t.table.GetClass(ref.pkg, ref.name), ref.pkg != t.pkg
+}
+
+// goPackage 从 Go 源码加载包的类型信息（按路径缓存），无法加载时返回 nil
+func (t *Transpiler) goPackage(path string) *types.Package {
+	path = strings.Trim(path, "\"")
+	if t.goPackages == nil {
+		t.goPackages = make(map[string]*types.Package)
+		t.goImporter = importer.ForCompiler(token.NewFileSet(), "source", nil)
+	}
+	pkg, cached := t.goPackages[path]
+	if !cached {
+		pkg, _ = t.goImporter.Import(path)
+		t.goPackages[path] = pkg
+	}
+	return pkg
+}
+
+// goInterface 加载 Go 包中的接口，loaded 为 false 表示包的类型信息不可用
+func (t *Transpiler) goInterface(path, name string) (info *symbol.InterfaceInfo, loaded bool) {
+	pkg := t.goPackage(path)
+	if pkg == nil {
+		return nil, false
+	}
+
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok || !obj.Exported() {
+		return nil, true
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, true
+	}
+
+	return goInterfaceInfo(name, path, iface), true
+}
+
+// goTypeHasMember 检查 Go 包中的类型（或其指针）是否有名为 member 的字段或方法
+// 无法加载类型信息时返回 false
+func (t *Transpiler) goTypeHasMember(path, typeName, member string) bool {
+	pkg := t.goPackage(path)
+	if pkg == nil {
+		return false
+	}
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return false
+	}
+	found, _, _ := types.LookupFieldOrMethod(types.NewPointer(obj.Type()), true, pkg, member)
+	return found != nil
+}
+
+// goPackageObject 解析 Go 包成员 pkg.Name，pkg 不是 Go 导入（或被局部变量遮蔽）、类型信息不可用时返回 nil
+func (g *CodeGen) goPackageObject(sel *parser.SelectorExpr) types.Object {
+	pkgIdent, ok := sel.X.(*parser.Identifier)
+	if !ok || g.localNames[pkgIdent.Value] {
+		return nil
+	}
+	for _, spec := range g.userImports {
+		if !spec.IsGoImport || goImportName(spec) != pkgIdent.Value {
+			continue
+		}
+		pkg := g.transpiler.goPackage(spec.Path)
+		if pkg == nil {
+			return nil
+		}
+		return pkg.Scope().Lookup(sel.Sel)
+	}
+	return nil
+}
+
+// goExprType 用 Go 包的类型信息推断表达式的类型，无法推断时返回 nil
+// 支持包级变量和常量（time.Second）、函数调用和类型转换（time.Now()、time.Duration(n)），
+// 以及在这些结果上继续访问字段、调用方法（time.Now().Add(d)）
+func (g *CodeGen) goExprType(expr parser.Expression) types.Type {
+	switch e := expr.(type) {
+	case *parser.ParenExpr:
+		return g.goExprType(e.X)
+	case *parser.SelectorExpr:
+		switch obj := g.goSelectorObject(e).(type) {
+		case *types.Var:
+			return obj.Type()
+		case *types.Const:
+			return obj.Type()
+		}
+	case *parser.CallExpr:
+		sel, ok := e.Function.(*parser.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		switch obj := g.goSelectorObject(sel).(type) {
+		case *types.TypeName:
+			return obj.Type()
+		case *types.Func:
+			if results := obj.Type().(*types.Signature).Results(); results.Len() == 1 {
+				return results.At(0).Type()
+			}
+		}
+	}
+	return nil
+}
+
+// goSelectorObject 解析 x.name：x 是 Go 包时查找包成员，x 的类型来自 Go 包时查找字段或方法
+func (g *CodeGen) goSelectorObject(sel *parser.SelectorExpr) types.Object {
+	if obj := g.goPackageObject(sel); obj != nil {
+		return obj
+	}
+	recv := g.goExprType(sel.X)
+	if recv == nil {
+		return nil
+	}
+	obj, _, _ := types.LookupFieldOrMethod(recv, true, nil, symbol.ToGoName(sel.Sel, true))
+	return obj
+}
+
+// goInterfaceInfo 把 Go 接口转换为 tugo 的接口信息
+func goInterfaceInfo(name, path string, iface *types.Interface) *symbol.InterfaceInfo {
+	info := &symbol.InterfaceInfo{Name: name, GoName: name, Public: true, Package: path}
+	for i := 0; i < iface.NumMethods(); i++ {
+		info.Methods = append(info.Methods, goFuncSignature(iface.Method(i)))
+	}
+	return info
+}
+
+// goFuncSignature 把 Go 方法转换为 tugo 的方法签名
+func goFuncSignature(fn *types.Func) *parser.FuncSignature {
+	sig := fn.Type().(*types.Signature)
+	return &parser.FuncSignature{
+		Name:    fn.Name(),
+		Params:  goFields(sig.Params(), sig.Variadic()),
+		Results: goFields(sig.Results(), false),
+	}
+}
+
+// goFields 把 Go 参数列表转换为 tugo 字段列表，variadic 时最后一个参数转换为 ...T
+func goFields(tuple *types.Tuple, variadic bool) []*parser.Field {
+	var fields []*parser.Field
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		typ := goTypeExpr(v.Type())
+		if variadic && i == tuple.Len()-1 {
+			if slice, ok := v.Type().(*types.Slice); ok {
+				typ = &parser.Ellipsis{Elt: goTypeExpr(slice.Elem())}
+			}
+		}
+		fields = append(fields, &parser.Field{Name: v.Name(), Type: typ})
+	}
+	return fields
+}
+
+// goTypeExpr 把 Go 类型转换为 tugo 类型表达式
+func goTypeExpr(typ types.Type) parser.Expression {
+	switch t := typ.(type) {
+	case *types.Basic:
+		return &parser.Identifier{Value: t.Name()}
+	case *types.Named:
+		return goTypeNameExpr(t.Obj(), t.TypeArgs())
+	case *types.Alias:
+		return goTypeNameExpr(t.Obj(), nil)
+	case *types.TypeParam:
+		return &parser.Identifier{Value: t.Obj().Name()}
+	case *types.Pointer:
+		return &parser.PointerType{Base: goTypeExpr(t.Elem())}
+	case *types.Slice:
+		return &parser.SliceType{Elt: goTypeExpr(t.Elem())}
+	case *types.Array:
+		return &parser.ArrayType{
+			Len: &parser.IntegerLiteral{Value: strconv.FormatInt(t.Len(), 10)},
+			Elt: goTypeExpr(t.Elem()),
+		}
+	case *types.Map:
+		return &parser.MapType{Key: goTypeExpr(t.Key()), Value: goTypeExpr(t.Elem())}
+	case *types.Chan:
+		dir := 0
+		switch t.Dir() {
+		case types.SendOnly:
+			dir = 1
+		case types.RecvOnly:
+			dir = 2
+		}
+		return &parser.ChanType{Dir: dir, Value: goTypeExpr(t.Elem())}
+	case *types.Signature:
+		return &parser.FuncType{
+			Params:  goFields(t.Params(), t.Variadic()),
+			Results: goFields(t.Results(), false),
+		}
+	case *types.Interface:
+		it := &parser.InterfaceType{}
+		for i := 0; i < t.NumMethods(); i++ {
+			it.Methods = append(it.Methods, goFuncSignature(t.Method(i)))
+		}
+		return it
+	case *types.Struct:
+		st := &parser.StructType{}
+		for i := 0; i < t.NumFields(); i++ {
+			f := t.Field(i)
+			st.Fields = append(st.Fields, &parser.StructField{Name: f.Name(), Type: goTypeExpr(f.Type())})
+		}
+		return st
+	}
+	return &parser.Identifier{Value: typ.String()}
+}
+
+// goTypeNameExpr 渲染命名类型：预声明类型（如 error）不带包名，其余为 pkg.Name
+func goTypeNameExpr(obj *types.TypeName, typeArgs *types.TypeList) parser.Expression {
+	var expr parser.Expression = &parser.Identifier{Value: obj.Name()}
+	if obj.Pkg() != nil {
+		expr = &parser.SelectorExpr{X: &parser.Identifier{Value: obj.Pkg().Name()}, Sel: obj.Name()}
+	}
+	if typeArgs != nil && typeArgs.Len() > 0 {
+		generic := &parser.GenericType{Type: expr}
+		for i := 0; i < typeArgs.Len(); i++ {
+			generic.TypeArgs = append(generic.TypeArgs, goTypeExpr(typeArgs.At(i)))
+		}
+		return generic
+	}
+	return expr
+}
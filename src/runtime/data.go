@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// maxHashDepth 哈希递归的最大深度，避免循环引用导致无限递归
+const maxHashDepth = 10
+
+// Hasher 提供 Hash 方法的值，tugo 数据类自动生成该方法
+type Hasher interface {
+	Hash() int
+}
+
+// Hash 组合多个值的哈希，数据类的 Hash 方法使用
+// 与数据类的 Equals 保持一致：实现 Hasher 的值调用其 Hash，指针按指向的值计算，
+// 切片和数组按元素顺序组合，映射的结果与遍历顺序无关
+func Hash(values ...any) int {
+	h := uint64(17)
+	for _, v := range values {
+		h = h*31 + hashValue(reflect.ValueOf(v), 0)
+	}
+	return int(h)
+}
+
+// hashValue 计算单个值的哈希
+func hashValue(v reflect.Value, depth int) uint64 {
+	if !v.IsValid() || depth > maxHashDepth {
+		return 0
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+	}
+	if v.CanInterface() {
+		if h, ok := v.Interface().(Hasher); ok {
+			return uint64(h.Hash())
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return hashValue(v.Elem(), depth+1)
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+		return 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return hashFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return hashFloat(real(c))*31 + hashFloat(imag(c))
+	case reflect.String:
+		h := fnv.New64a()
+		h.Write([]byte(v.String()))
+		return h.Sum64()
+	case reflect.Slice, reflect.Array:
+		h := uint64(1)
+		for i := 0; i < v.Len(); i++ {
+			h = h*31 + hashValue(v.Index(i), depth+1)
+		}
+		return h
+	case reflect.Map:
+		var h uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			h += hashValue(iter.Key(), depth+1)*31 ^ hashValue(iter.Value(), depth+1)
+		}
+		return h
+	case reflect.Struct:
+		h := uint64(1)
+		for i := 0; i < v.NumField(); i++ {
+			h = h*31 + hashValue(v.Field(i), depth+1)
+		}
+		return h
+	}
+	return 0
+}
+
+// hashFloat 计算浮点数的哈希，0 和 -0 相等
+func hashFloat(f float64) uint64 {
+	if f == 0 {
+		return 0
+	}
+	return math.Float64bits(f)
+}
@@ -0,0 +1,58 @@
+package runtime
+
+// Exception 异常基类
+// tugo 中的 class NotFound extends Exception 嵌入 *Exception，
+// 按 tugo 类的约定生成字段、构造函数和类信息，转译器中对应的声明见 internal/transpiler/exception.go
+type Exception struct {
+	Message string // 错误信息
+	Cause   error  // 导致该异常的错误（可选）
+}
+
+// Exception__InitOpts 构造函数参数
+type Exception__InitOpts struct {
+	Message string
+	Cause   error
+}
+
+// NewDefault__Exception__InitOpts 返回构造函数参数的默认值
+func NewDefault__Exception__InitOpts() Exception__InitOpts {
+	return Exception__InitOpts{Message: "", Cause: nil}
+}
+
+// New__Exception 创建异常
+func New__Exception(opts Exception__InitOpts) *Exception {
+	return &Exception{Message: opts.Message, Cause: opts.Cause}
+}
+
+// XException_ClassInfo 异常基类的类信息
+var XException_ClassInfo = &ClassInfo{Name: "Exception", Package: "runtime", FullName: "runtime.Exception"}
+
+// Error 返回错误信息，有 Cause 时附加 Cause 的错误信息
+func (e *Exception) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Cause.Error()
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+// Unwrap 返回 Cause，使 errors.Is / errors.As 可以沿着 Cause 链查找
+func (e *Exception) Unwrap() error {
+	return e.Cause
+}
+
+// As 供 errors.As 使用：子类的 As 方法匹配不到自己时沿继承链调用到这里
+func (e *Exception) As(target any) bool {
+	if p, ok := target.(**Exception); ok {
+		*p = e
+		return true
+	}
+	return false
+}
+
+// Class 返回类信息
+func (e *Exception) Class() *ClassInfo {
+	return XException_ClassInfo
+}
@@ -0,0 +1,13 @@
+package runtime
+
+// As 判断接口值是否是结构体 T，match 的结构体类型模式使用
+// 结构体既可能以值 T 也可能以指针 *T 保存在接口中，两种情况都返回指针
+func As[T any](v any) (*T, bool) {
+	switch x := v.(type) {
+	case T:
+		return &x, true
+	case *T:
+		return x, x != nil
+	}
+	return nil, false
+}